@@ -0,0 +1,72 @@
+package goevent
+
+import (
+	"log/slog"
+	"runtime"
+	"sync"
+)
+
+// WithAbandonedHandleWarnings is a debug mode that logs a warning when a
+// dispatch handle is garbage collected with errors nobody read through
+// GetErrors, catching failures ignored by fire-and-forget code. Warnings
+// go to the WithLogger logger, or slog.Default() if there is none.
+//
+// It adds a finalizer to every handle, so leave it off in production.
+func WithAbandonedHandleWarnings() Option {
+	return func(ge *GoEvent) {
+		ge.abandonWarnings = true
+	}
+}
+
+// abandonCheck reports the unread errors of a collected handle. Only the
+// handle points to it and it does not point back, so the finalizer runs
+// even though the handle is part of a cycle through its own context.
+type abandonCheck struct {
+	ge        *GoEvent
+	id        string
+	eventName string
+
+	mu     sync.Mutex
+	unread []*EventError
+}
+
+// watchAbandoned installs the abandon check on a new handle
+func (ge *GoEvent) watchAbandoned(handle *DispatchHandle, event Event) {
+	check := &abandonCheck{ge: ge, id: handle.id, eventName: event.Name()}
+	runtime.SetFinalizer(check, (*abandonCheck).report)
+	handle.abandon = check
+}
+
+// recordError notes an error that has not been read yet
+func (c *abandonCheck) recordError(err *EventError) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.unread = append(c.unread, err)
+}
+
+// read marks every recorded error as read
+func (c *abandonCheck) read() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.unread = nil
+}
+
+func (c *abandonCheck) report() {
+	c.mu.Lock()
+	unread := c.unread
+	c.mu.Unlock()
+	if len(unread) == 0 {
+		return
+	}
+
+	logger := c.ge.logger
+	if logger == nil {
+		logger = slog.Default()
+	}
+	logger.Warn("dispatch handle collected with unread errors",
+		slog.String("event", c.eventName),
+		slog.String("dispatch_id", c.id),
+		slog.Int("errors", len(unread)),
+		slog.String("error", unread[0].Error()),
+	)
+}
@@ -0,0 +1,70 @@
+package goevent
+
+import (
+	"log/slog"
+	"runtime"
+	"testing"
+	"time"
+)
+
+const abandonedMessage = "dispatch handle collected with unread errors"
+
+// collectAbandoned runs the GC until an abandoned handle is reported or
+// the timeout elapses
+func collectAbandoned(handler *testLogHandler, timeout time.Duration) (slog.Record, bool) {
+	deadline := time.Now().Add(timeout)
+	for time.Now().Before(deadline) {
+		runtime.GC()
+		if record, ok := handler.find(abandonedMessage); ok {
+			return record, true
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+	return slog.Record{}, false
+}
+
+func TestAbandonedHandleWarnings_UnreadErrors(t *testing.T) {
+	handler := &testLogHandler{}
+	evt := New(WithLogger(slog.New(handler)), WithAbandonedHandleWarnings())
+	evt.RegisterListener(&testErrorListener{})
+
+	evt.Dispatch(&TestEvent{})
+
+	record, ok := collectAbandoned(handler, time.Second)
+	if !ok {
+		t.Fatal("Expected an abandoned handle warning")
+	}
+	if got := recordAttr(record, "event"); got != "test.event" {
+		t.Errorf("Expected event test.event, got %v", got)
+	}
+	if got := recordAttr(record, "errors"); got != "1" {
+		t.Errorf("Expected 1 unread error, got %v", got)
+	}
+}
+
+func TestAbandonedHandleWarnings_ReadErrors(t *testing.T) {
+	handler := &testLogHandler{}
+	evt := New(WithLogger(slog.New(handler)), WithAbandonedHandleWarnings())
+	evt.RegisterListener(&testErrorListener{})
+
+	evt.Dispatch(&TestEvent{}).GetErrors()
+	evt.Dispatch(&testMutationEvent{}) // no listeners, so no errors
+
+	if record, ok := collectAbandoned(handler, 100*time.Millisecond); ok {
+		t.Errorf("Expected no warnings, got %v", record)
+	}
+}
+
+func TestAbandonedHandleWarnings_WithStore(t *testing.T) {
+	handler := &testLogHandler{}
+	evt := New(WithLogger(slog.New(handler)), WithAbandonedHandleWarnings(), WithStore(NewMemoryStore()))
+	evt.RegisterListener(&testErrorListener{})
+
+	// The store checks the outcome to decide on the ack, which must not
+	// count as the caller reading the errors
+	evt.Dispatch(&TestEvent{})
+
+	if _, ok := collectAbandoned(handler, time.Second); !ok {
+		t.Fatal("Expected an abandoned handle warning with a store attached")
+	}
+}
@@ -0,0 +1,277 @@
+package goevent
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+)
+
+var (
+	// ErrVersionConflict is returned when an aggregate was changed
+	// concurrently: its stored stream is no longer at the version it was
+	// loaded at
+	ErrVersionConflict = errors.New("goevent: aggregate version conflict")
+
+	// ErrAggregatesUnsupported is returned by SaveAggregate and
+	// LoadAggregate when the bus has no store, or its store does not
+	// implement AggregateStore, and by SnapshotAggregate when it does not
+	// implement SnapshotStore
+	ErrAggregatesUnsupported = errors.New("goevent: store does not support aggregates")
+)
+
+// AggregateStore is implemented by stores that keep the event streams of
+// event-sourced aggregates. Aggregate events are part of the store's log
+// like any other event, and also numbered per aggregate.
+type AggregateStore interface {
+	Store
+	// AppendAggregate persists events raised by an aggregate as one unit,
+	// numbering them from expectedVersion+1. If the aggregate's stream is
+	// not at expectedVersion, nothing is appended and it returns
+	// ErrVersionConflict.
+	AppendAggregate(ctx context.Context, aggregateID string, expectedVersion int, events []Event) ([]StoredEvent, error)
+	// LoadAggregate returns the events of an aggregate with a version
+	// above fromVersion, oldest first
+	LoadAggregate(ctx context.Context, aggregateID string, fromVersion int) ([]StoredEvent, error)
+}
+
+// Aggregate is an event-sourced domain object. Implement it by embedding
+// AggregateRoot and providing Apply, which changes the aggregate's state
+// for one of its events.
+type Aggregate interface {
+	AggregateID() string
+	Apply(event Event) error
+	root() *AggregateRoot
+}
+
+// AggregateRoot records the events an aggregate raised since it was
+// loaded. Embed it in aggregate types:
+//
+//	type Order struct {
+//		goevent.AggregateRoot
+//		Status string
+//	}
+//
+//	func (o *Order) Place() {
+//		o.Raise(&OrderPlaced{ID: o.AggregateID()})
+//	}
+//
+// Raise only records the event; pair it with Apply so the aggregate
+// changes the same way when its events are replayed.
+type AggregateRoot struct {
+	id      string
+	version int
+	changes []Event
+}
+
+// AggregateID returns the aggregate's ID
+func (a *AggregateRoot) AggregateID() string {
+	return a.id
+}
+
+// SetAggregateID sets the aggregate's ID, before it is first saved or
+// when loading it
+func (a *AggregateRoot) SetAggregateID(id string) {
+	a.id = id
+}
+
+// Version returns the version of the aggregate's last stored event, or
+// zero for a new aggregate
+func (a *AggregateRoot) Version() int {
+	return a.version
+}
+
+// Raise records an event to be stored and dispatched by SaveAggregate
+func (a *AggregateRoot) Raise(event Event) {
+	a.changes = append(a.changes, event)
+}
+
+// Changes returns the events raised since the aggregate was loaded or
+// last saved
+func (a *AggregateRoot) Changes() []Event {
+	return append([]Event(nil), a.changes...)
+}
+
+func (a *AggregateRoot) root() *AggregateRoot {
+	return a
+}
+
+// SaveAggregate appends the events an aggregate raised to the bus's
+// store as one unit, then dispatches them in order. Nothing is stored or
+// dispatched if the store rejects the events, for example with
+// ErrVersionConflict when the aggregate was saved by someone else since
+// it was loaded. Dispatched events are acknowledged like any dispatch on
+// a durable bus, so Redeliver retries them if a listener fails.
+func (ge *GoEvent) SaveAggregate(ctx context.Context, agg Aggregate) ([]*DispatchHandle, error) {
+	store, ok := ge.store.(AggregateStore)
+	if !ok {
+		return nil, ErrAggregatesUnsupported
+	}
+	root := agg.root()
+	if agg.AggregateID() == "" {
+		return nil, errors.New("goevent: aggregate has no ID")
+	}
+	if len(root.changes) == 0 {
+		return nil, nil
+	}
+
+	stored, err := store.AppendAggregate(ctx, agg.AggregateID(), root.version, root.changes)
+	if err != nil {
+		return nil, fmt.Errorf("goevent: saving aggregate %s: %w", agg.AggregateID(), err)
+	}
+	changes := root.changes
+	root.version += len(changes)
+	root.changes = nil
+
+	handles := make([]*DispatchHandle, 0, len(changes))
+	for i, event := range changes {
+		handles = append(handles, ge.DispatchContext(ctx, event, alreadyStored(stored[i].Seq)))
+	}
+	return handles, nil
+}
+
+// LoadAggregate replays the stored events of the aggregate with the ID
+// agg was given through Apply, from the version it is at, and forgets
+// the events it raised but did not save. A Snapshotter is first moved to
+// its latest snapshot if that is ahead of it.
+func (ge *GoEvent) LoadAggregate(ctx context.Context, agg Aggregate) error {
+	store, ok := ge.store.(AggregateStore)
+	if !ok {
+		return ErrAggregatesUnsupported
+	}
+	root := agg.root()
+	root.changes = nil
+	if err := ge.restoreSnapshot(ctx, agg); err != nil {
+		return err
+	}
+
+	events, err := store.LoadAggregate(ctx, agg.AggregateID(), root.version)
+	if err != nil {
+		return fmt.Errorf("goevent: loading aggregate %s: %w", agg.AggregateID(), err)
+	}
+	for _, stored := range events {
+		if err := agg.Apply(stored.Event()); err != nil {
+			return fmt.Errorf("goevent: applying %s version %d to aggregate %s: %w", stored.Name, stored.Version, agg.AggregateID(), err)
+		}
+		root.version = stored.Version
+	}
+	return nil
+}
+
+// ErrSnapshotNotFound is returned by a SnapshotStore when it holds no
+// snapshot of an aggregate
+var ErrSnapshotNotFound = errors.New("goevent: snapshot not found")
+
+// Snapshot is the state of an aggregate at a version
+type Snapshot struct {
+	AggregateID string
+	Version     int
+	State       []byte
+	Time        time.Time
+}
+
+// SnapshotStore is implemented by aggregate stores that keep snapshots,
+// so aggregates with long histories load without replaying every event
+type SnapshotStore interface {
+	// SaveSnapshot stores a snapshot of an aggregate
+	SaveSnapshot(ctx context.Context, snapshot Snapshot) error
+	// LoadLatestSnapshot returns the snapshot of an aggregate with the
+	// highest version, or ErrSnapshotNotFound
+	LoadLatestSnapshot(ctx context.Context, aggregateID string) (Snapshot, error)
+}
+
+// Snapshotter is implemented by aggregates that can be snapshotted.
+// LoadAggregate restores the latest snapshot before replaying the events
+// that followed it.
+type Snapshotter interface {
+	Aggregate
+	// Snapshot encodes the aggregate's state
+	Snapshot() ([]byte, error)
+	// RestoreSnapshot replaces the aggregate's state with a decoded one
+	RestoreSnapshot(state []byte) error
+}
+
+// SnapshotAggregate stores a snapshot of an aggregate at its current
+// version. Save the aggregate first: a snapshot of unsaved changes is
+// refused.
+func (ge *GoEvent) SnapshotAggregate(ctx context.Context, agg Snapshotter) error {
+	store, ok := ge.store.(SnapshotStore)
+	if !ok {
+		return ErrAggregatesUnsupported
+	}
+	root := agg.root()
+	if len(root.changes) > 0 {
+		return fmt.Errorf("goevent: aggregate %s has unsaved changes", agg.AggregateID())
+	}
+	state, err := agg.Snapshot()
+	if err != nil {
+		return fmt.Errorf("goevent: snapshotting aggregate %s: %w", agg.AggregateID(), err)
+	}
+	return store.SaveSnapshot(ctx, Snapshot{
+		AggregateID: agg.AggregateID(),
+		Version:     root.version,
+		State:       state,
+		Time:        ge.clock.Now(),
+	})
+}
+
+// restoreSnapshot moves agg to its latest snapshot if that is ahead of it
+func (ge *GoEvent) restoreSnapshot(ctx context.Context, agg Aggregate) error {
+	snapshotter, ok := agg.(Snapshotter)
+	if !ok {
+		return nil
+	}
+	store, ok := ge.store.(SnapshotStore)
+	if !ok {
+		return nil
+	}
+
+	snapshot, err := store.LoadLatestSnapshot(ctx, agg.AggregateID())
+	if errors.Is(err, ErrSnapshotNotFound) || (err == nil && snapshot.Version <= agg.root().version) {
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("goevent: loading snapshot of aggregate %s: %w", agg.AggregateID(), err)
+	}
+	if err := snapshotter.RestoreSnapshot(snapshot.State); err != nil {
+		return fmt.Errorf("goevent: restoring snapshot of aggregate %s: %w", agg.AggregateID(), err)
+	}
+	agg.root().version = snapshot.Version
+	return nil
+}
+
+// aggregateVersions tracks the stream version of each aggregate of a
+// store kept in memory
+type aggregateVersions map[string]int
+
+// check returns ErrVersionConflict unless aggregateID is at expected
+func (v aggregateVersions) check(aggregateID string, expected int) error {
+	if current := v[aggregateID]; current != expected {
+		return versionConflict(aggregateID, current, expected)
+	}
+	return nil
+}
+
+// versionConflict reports that an aggregate is not at the expected version
+func versionConflict(aggregateID string, current, expected int) error {
+	return fmt.Errorf("%w: %s is at version %d, not %d", ErrVersionConflict, aggregateID, current, expected)
+}
+
+// aggregateEvents returns the events of an aggregate above fromVersion
+func aggregateEvents(events []StoredEvent, aggregateID string, fromVersion int) []StoredEvent {
+	var stream []StoredEvent
+	for _, stored := range events {
+		if stored.AggregateID == aggregateID && stored.Version > fromVersion {
+			stream = append(stream, stored)
+		}
+	}
+	return stream
+}
+
+// keepLatest records snapshot unless a later one is kept already
+func keepLatest(snapshots map[string]Snapshot, snapshot Snapshot) {
+	if current, ok := snapshots[snapshot.AggregateID]; !ok || snapshot.Version >= current.Version {
+		snapshot.State = append([]byte(nil), snapshot.State...)
+		snapshots[snapshot.AggregateID] = snapshot
+	}
+}
@@ -0,0 +1,221 @@
+package goevent
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"path/filepath"
+	"testing"
+)
+
+// testOrder is an event-sourced aggregate
+type testOrder struct {
+	AggregateRoot
+	Status  string
+	Items   int
+	applied int
+}
+
+func (o *testOrder) Snapshot() ([]byte, error) {
+	return json.Marshal(o)
+}
+
+func (o *testOrder) RestoreSnapshot(state []byte) error {
+	return json.Unmarshal(state, o)
+}
+
+func (o *testOrder) Place() {
+	o.raise(&GenericEvent{EventName: "order.placed"})
+}
+
+func (o *testOrder) AddItem() {
+	o.raise(&GenericEvent{EventName: "order.item_added"})
+}
+
+func (o *testOrder) raise(event Event) {
+	o.Apply(event)
+	o.Raise(event)
+}
+
+func (o *testOrder) Apply(event Event) error {
+	o.applied++
+	switch event.Name() {
+	case "order.placed":
+		o.Status = "placed"
+	case "order.item_added":
+		o.Items++
+	default:
+		return errors.New("unknown event")
+	}
+	return nil
+}
+
+func TestSaveAggregate(t *testing.T) {
+	ctx := context.Background()
+	evt := New(WithStore(NewMemoryStore()))
+	recorder := &testNameRecorder{name: "order.item_added"}
+	evt.RegisterListener(recorder)
+
+	order := &testOrder{}
+	order.SetAggregateID("order-1")
+	order.Place()
+	order.AddItem()
+	order.AddItem()
+
+	handles, err := evt.SaveAggregate(ctx, order)
+	if err != nil {
+		t.Fatalf("SaveAggregate() failed: %v", err)
+	}
+	if len(handles) != 3 || len(recorder.received) != 2 {
+		t.Errorf("Expected 3 dispatches, 2 of them item_added, got %d and %d", len(handles), len(recorder.received))
+	}
+	if order.Version() != 3 || len(order.Changes()) != 0 {
+		t.Errorf("Expected version 3 without changes, got %d with %d", order.Version(), len(order.Changes()))
+	}
+
+	loaded := &testOrder{}
+	loaded.SetAggregateID("order-1")
+	if err := evt.LoadAggregate(ctx, loaded); err != nil {
+		t.Fatalf("LoadAggregate() failed: %v", err)
+	}
+	if loaded.Status != "placed" || loaded.Items != 2 || loaded.Version() != 3 {
+		t.Errorf("Expected the replayed order at version 3, got %+v", loaded)
+	}
+}
+
+func TestSaveAggregate_VersionConflict(t *testing.T) {
+	ctx := context.Background()
+	evt := New(WithStore(NewMemoryStore()))
+	recorder := &testNameRecorder{name: "order.item_added"}
+	evt.RegisterListener(recorder)
+
+	first, second := &testOrder{}, &testOrder{}
+	first.SetAggregateID("order-1")
+	second.SetAggregateID("order-1")
+	first.AddItem()
+	second.AddItem()
+
+	if _, err := evt.SaveAggregate(ctx, first); err != nil {
+		t.Fatalf("SaveAggregate() failed: %v", err)
+	}
+	if _, err := evt.SaveAggregate(ctx, second); !errors.Is(err, ErrVersionConflict) {
+		t.Fatalf("Expected ErrVersionConflict, got %v", err)
+	}
+	if len(recorder.received) != 1 || len(second.Changes()) != 1 {
+		t.Errorf("Expected the rejected changes to be kept and not dispatched, got %d dispatches", len(recorder.received))
+	}
+
+	// Reloading catches up and drops the rejected changes
+	if err := evt.LoadAggregate(ctx, second); err != nil {
+		t.Fatalf("LoadAggregate() failed: %v", err)
+	}
+	if second.Version() != 1 || len(second.Changes()) != 0 {
+		t.Errorf("Expected version 1 without changes, got %d with %d", second.Version(), len(second.Changes()))
+	}
+}
+
+func TestSaveAggregate_FileStore(t *testing.T) {
+	ctx := context.Background()
+	path := filepath.Join(t.TempDir(), "events.wal")
+	store, err := OpenFileStore(path)
+	if err != nil {
+		t.Fatalf("OpenFileStore() failed: %v", err)
+	}
+	evt := New(WithStore(store))
+	evt.RegisterListener(&testNamedListener{name: "order.placed"}, &testNamedListener{name: "order.item_added"})
+
+	order := &testOrder{}
+	order.SetAggregateID("order-1")
+	order.Place()
+	order.AddItem()
+	if _, err := evt.SaveAggregate(ctx, order); err != nil {
+		t.Fatalf("SaveAggregate() failed: %v", err)
+	}
+	unacked(t, store, 0)
+	if err := store.Compact(); err != nil {
+		t.Fatalf("Compact() failed: %v", err)
+	}
+	store.Close()
+
+	store, err = OpenFileStore(path)
+	if err != nil {
+		t.Fatalf("OpenFileStore() failed: %v", err)
+	}
+	defer store.Close()
+	evt = New(WithStore(store))
+
+	loaded := &testOrder{}
+	loaded.SetAggregateID("order-1")
+	if err := evt.LoadAggregate(ctx, loaded); err != nil {
+		t.Fatalf("LoadAggregate() failed: %v", err)
+	}
+	if loaded.Status != "placed" || loaded.Items != 1 || loaded.Version() != 2 {
+		t.Errorf("Expected the order to survive compaction and restart, got %+v", loaded)
+	}
+	if _, err := store.AppendAggregate(ctx, "order-1", 1, []Event{&TestEvent{}}); !errors.Is(err, ErrVersionConflict) {
+		t.Errorf("Expected the stream version to be restored, got %v", err)
+	}
+}
+
+func TestSaveAggregate_Unsupported(t *testing.T) {
+	order := &testOrder{}
+	order.SetAggregateID("order-1")
+	order.Place()
+	if _, err := New().SaveAggregate(context.Background(), order); !errors.Is(err, ErrAggregatesUnsupported) {
+		t.Errorf("Expected ErrAggregatesUnsupported, got %v", err)
+	}
+}
+
+func TestSnapshotAggregate(t *testing.T) {
+	ctx := context.Background()
+	path := filepath.Join(t.TempDir(), "events.wal")
+	store, err := OpenFileStore(path)
+	if err != nil {
+		t.Fatalf("OpenFileStore() failed: %v", err)
+	}
+	evt := New(WithStore(store))
+
+	order := &testOrder{}
+	order.SetAggregateID("order-1")
+	order.Place()
+	order.AddItem()
+	order.AddItem()
+	if err := evt.SnapshotAggregate(ctx, order); err == nil {
+		t.Error("Expected a snapshot of unsaved changes to be refused")
+	}
+	if _, err := evt.SaveAggregate(ctx, order); err != nil {
+		t.Fatalf("SaveAggregate() failed: %v", err)
+	}
+	if err := evt.SnapshotAggregate(ctx, order); err != nil {
+		t.Fatalf("SnapshotAggregate() failed: %v", err)
+	}
+	order.AddItem()
+	if _, err := evt.SaveAggregate(ctx, order); err != nil {
+		t.Fatalf("SaveAggregate() failed: %v", err)
+	}
+	evt.Wait()
+	unacked(t, store, 0)
+	if err := store.Compact(); err != nil {
+		t.Fatalf("Compact() failed: %v", err)
+	}
+	store.Close()
+
+	store, err = OpenFileStore(path)
+	if err != nil {
+		t.Fatalf("OpenFileStore() failed: %v", err)
+	}
+	defer store.Close()
+	evt = New(WithStore(store))
+
+	loaded := &testOrder{}
+	loaded.SetAggregateID("order-1")
+	if err := evt.LoadAggregate(ctx, loaded); err != nil {
+		t.Fatalf("LoadAggregate() failed: %v", err)
+	}
+	if loaded.Status != "placed" || loaded.Items != 3 || loaded.Version() != 4 {
+		t.Errorf("Expected the order at version 4, got %+v", loaded)
+	}
+	if loaded.applied != 1 {
+		t.Errorf("Expected only the event after the snapshot to be replayed, got %d", loaded.applied)
+	}
+}
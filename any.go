@@ -0,0 +1,45 @@
+package goevent
+
+// AnyEvent is the name catch-all listeners are listed under by Listeners
+const AnyEvent = "*"
+
+// AnyListener receives every event dispatched on the bus, whatever its
+// name, for audit logs, metrics and debugging taps. Register it with
+// RegisterAnyListener. Options are read from Options() if the listener
+// provides it.
+type AnyListener interface {
+	OnAnyEvent(event Event) error
+}
+
+// RegisterAnyListener registers listeners that receive every event.
+// They are called after the event's own listeners and go through
+// middleware, retries and error collection like any other listener.
+// They do not count as listeners of an event for RequireListeners.
+func (ge *GoEvent) RegisterAnyListener(listeners ...AnyListener) {
+	for _, listener := range listeners {
+		ge.registerSingleListener(&anyAdapter{listener})
+	}
+}
+
+// anyAdapter subscribes an AnyListener to the catch-all name
+type anyAdapter struct {
+	AnyListener
+}
+
+// EventName returns the catch-all name
+func (a *anyAdapter) EventName() string {
+	return AnyEvent
+}
+
+// OnEvent delivers an event to the wrapped listener
+func (a *anyAdapter) OnEvent(event Event) error {
+	return a.OnAnyEvent(event)
+}
+
+// Options returns the wrapped listener's options
+func (a *anyAdapter) Options() ListenerOptions {
+	if withOpts, ok := a.AnyListener.(interface{ Options() ListenerOptions }); ok {
+		return withOpts.Options()
+	}
+	return ListenerOptions{}
+}
@@ -0,0 +1,86 @@
+package goevent
+
+import (
+	"errors"
+	"sync"
+	"testing"
+)
+
+// testAnyListener records every event it receives
+type testAnyListener struct {
+	async bool
+	err   error
+
+	mu       sync.Mutex
+	received []string
+}
+
+func (l *testAnyListener) OnAnyEvent(event Event) error {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.received = append(l.received, event.Name())
+	return l.err
+}
+
+func (l *testAnyListener) Options() ListenerOptions {
+	return ListenerOptions{Async: l.async}
+}
+
+func TestRegisterAnyListener(t *testing.T) {
+	for _, async := range []bool{false, true} {
+		evt := New()
+		tap := &testAnyListener{async: async}
+		evt.RegisterAnyListener(tap)
+		evt.RegisterListener(&testSyncListener{})
+
+		evt.Dispatch(&TestEvent{}).Wait()
+		evt.Dispatch(&GenericEvent{EventName: "order.placed"}).Wait()
+		evt.Wait()
+
+		if len(tap.received) != 2 || tap.received[0] != "test.event" || tap.received[1] != "order.placed" {
+			t.Errorf("Expected every event (async %v), got %v", async, tap.received)
+		}
+		if progress := evt.pending.snapshot(); len(progress.ByEvent) != 0 {
+			t.Errorf("Expected no pending events (async %v), got %v", async, progress.ByEvent)
+		}
+	}
+}
+
+func TestRegisterAnyListener_RunsAfterEventListeners(t *testing.T) {
+	evt := New()
+	var order []string
+	evt.RegisterAnyListener(&testOrderAnyListener{order: &order})
+	evt.RegisterListener(&testOrderListener{name: "named", order: &order})
+
+	evt.Dispatch(&TestEvent{})
+	if len(order) != 2 || order[0] != "named" || order[1] != "any" {
+		t.Errorf("Expected the catch-all listener last, got %v", order)
+	}
+}
+
+func TestRegisterAnyListener_Errors(t *testing.T) {
+	errAudit := errors.New("audit log unavailable")
+	evt := New()
+	evt.RegisterAnyListener(&testAnyListener{err: errAudit})
+
+	errs := evt.Dispatch(&TestEvent{}).GetErrors()
+	if len(errs) != 1 || !errors.Is(errs[0], errAudit) {
+		t.Fatalf("Expected the listener's error, got %v", errs)
+	}
+	if errs[0].EventName != "test.event" || errs[0].ListenerType != "*goevent.testAnyListener" {
+		t.Errorf("Expected the event and listener type, got %s and %s", errs[0].EventName, errs[0].ListenerType)
+	}
+	if infos := evt.Listeners()[AnyEvent]; len(infos) != 1 {
+		t.Errorf("Expected the listener under AnyEvent, got %+v", infos)
+	}
+}
+
+// testOrderAnyListener appends "any" to order when called
+type testOrderAnyListener struct {
+	order *[]string
+}
+
+func (l *testOrderAnyListener) OnAnyEvent(event Event) error {
+	*l.order = append(*l.order, "any")
+	return nil
+}
@@ -0,0 +1,51 @@
+package goevent
+
+import "context"
+
+// AwareListener is a listener that receives a DispatchContext describing
+// the dispatch it handles. If a listener implements it, OnEventAware is
+// called instead of OnEventContext and OnEvent.
+type AwareListener interface {
+	Listener
+	OnEventAware(dc DispatchContext, event Event) error
+}
+
+// Emitter dispatches events. *GoEvent implements it; tests can pass
+// their own to record what a listener emits.
+type Emitter interface {
+	DispatchContext(ctx context.Context, event Event, opts ...DispatchOption) *DispatchHandle
+}
+
+// DispatchContext describes the dispatch an AwareListener is handling.
+// It is the context the listener would otherwise receive, so it can be
+// passed on wherever a context.Context is expected.
+type DispatchContext struct {
+	context.Context
+
+	// DispatchID is the ID of the dispatch being handled
+	DispatchID string
+
+	// Metadata of the dispatch. Its CorrelationID is the ID of the
+	// dispatch that started the tree, shared by every follow-up event.
+	Metadata Metadata
+
+	// Emitter dispatches the events passed to Emit
+	Emitter Emitter
+}
+
+// Emit dispatches a follow-up event. It joins the tree of the dispatch
+// being handled, inheriting its priority, deadline and correlation.
+func (dc DispatchContext) Emit(event Event, opts ...DispatchOption) *DispatchHandle {
+	return dc.Emitter.DispatchContext(dc, event, opts...)
+}
+
+// dispatchContext builds the DispatchContext of a listener call from the
+// context it receives
+func (ge *GoEvent) dispatchContext(ctx context.Context) DispatchContext {
+	dc := DispatchContext{Context: ctx, Emitter: ge}
+	if handle, ok := HandleFromContext(ctx); ok {
+		dc.DispatchID = handle.id
+		dc.Metadata = handle.Metadata()
+	}
+	return dc
+}
@@ -0,0 +1,119 @@
+package goevent
+
+import (
+	"context"
+	"sync"
+	"testing"
+)
+
+// testAwareListener records the DispatchContext it receives and emits a
+// follow-up event for each order
+type testAwareListener struct {
+	mu       sync.Mutex
+	contexts []DispatchContext
+	async    bool
+}
+
+func (l *testAwareListener) EventName() string {
+	return "order.placed"
+}
+
+func (l *testAwareListener) Options() ListenerOptions {
+	return ListenerOptions{Async: l.async}
+}
+
+func (l *testAwareListener) OnEvent(event Event) error {
+	panic("OnEvent called instead of OnEventAware")
+}
+
+func (l *testAwareListener) OnEventAware(dc DispatchContext, event Event) error {
+	l.mu.Lock()
+	l.contexts = append(l.contexts, dc)
+	l.mu.Unlock()
+	dc.Emit(&GenericEvent{EventName: "invoice.requested"})
+	return nil
+}
+
+// testRecordingEmitter records emitted events instead of dispatching them
+type testRecordingEmitter struct {
+	events []Event
+}
+
+func (e *testRecordingEmitter) DispatchContext(ctx context.Context, event Event, opts ...DispatchOption) *DispatchHandle {
+	e.events = append(e.events, event)
+	return nil
+}
+
+func TestAwareListener_Emit(t *testing.T) {
+	evt := New()
+	aware := &testAwareListener{async: true}
+	evt.RegisterListener(aware)
+
+	var mu sync.Mutex
+	var child DispatchContext
+	evt.RegisterListener(&testAwareFunc{name: "invoice.requested", fn: func(dc DispatchContext) {
+		mu.Lock()
+		child = dc
+		mu.Unlock()
+	}})
+
+	handle := evt.Dispatch(&GenericEvent{EventName: "order.placed"})
+	if err := handle.WaitTree(context.Background()); err != nil {
+		t.Fatalf("WaitTree() failed: %v", err)
+	}
+
+	if len(aware.contexts) != 1 {
+		t.Fatalf("Expected 1 call, got %d", len(aware.contexts))
+	}
+	dc := aware.contexts[0]
+	if dc.DispatchID != handle.DispatchID() || dc.Metadata.ID != handle.DispatchID() {
+		t.Errorf("Expected dispatch ID %s, got %s", handle.DispatchID(), dc.DispatchID)
+	}
+	if dc.Metadata.CorrelationID != handle.DispatchID() {
+		t.Errorf("Expected the root dispatch to start the correlation, got %s", dc.Metadata.CorrelationID)
+	}
+
+	mu.Lock()
+	defer mu.Unlock()
+	if child.DispatchID == "" || child.DispatchID == handle.DispatchID() {
+		t.Errorf("Expected the follow-up event to have its own dispatch, got %q", child.DispatchID)
+	}
+	if child.Metadata.CorrelationID != handle.DispatchID() {
+		t.Errorf("Expected correlation %s, got %s", handle.DispatchID(), child.Metadata.CorrelationID)
+	}
+	if h, ok := HandleFromContext(child); !ok || h.DispatchID() != child.DispatchID {
+		t.Error("Expected the DispatchContext to carry its dispatch handle")
+	}
+}
+
+func TestAwareListener_CustomEmitter(t *testing.T) {
+	emitter := &testRecordingEmitter{}
+	aware := &testAwareListener{}
+
+	err := aware.OnEventAware(DispatchContext{Context: context.Background(), Emitter: emitter}, &GenericEvent{EventName: "order.placed"})
+	if err != nil {
+		t.Fatalf("OnEventAware() failed: %v", err)
+	}
+	if len(emitter.events) != 1 || emitter.events[0].Name() != "invoice.requested" {
+		t.Errorf("Expected invoice.requested to be emitted, got %v", emitter.events)
+	}
+}
+
+// testAwareFunc is a synchronous AwareListener calling fn
+type testAwareFunc struct {
+	name string
+	fn   func(dc DispatchContext)
+}
+
+func (l *testAwareFunc) EventName() string {
+	return l.name
+}
+
+func (l *testAwareFunc) OnEvent(event Event) error {
+	panic("OnEvent called instead of OnEventAware")
+}
+
+func (l *testAwareFunc) OnEventAware(dc DispatchContext, event Event) error {
+	l.fn(dc)
+	return nil
+}
@@ -0,0 +1,444 @@
+// Package aws connects a bus to Amazon SNS and SQS.
+//
+// Events are published to SNS topics, and an SQS polling loop turns
+// messages into local dispatches. A message is deleted once every local
+// listener handled it without error; otherwise it becomes visible again
+// when its visibility timeout runs out and is received again. Messages
+// that keep failing are moved to a dead letter queue after a number of
+// receives.
+//
+//	connector := aws.New(bus,
+//		aws.WithSNS(sns.NewFromConfig(cfg)),
+//		aws.WithSQS(sqs.NewFromConfig(cfg)),
+//		aws.WithMaxReceives(5),
+//		aws.WithDeadLetterQueue(dlqURL),
+//	)
+//	connector.Publish(topicARN, "invoice.created")
+//	connector.Subscribe(ctx, queueURL)
+//	defer connector.Close()
+//
+// Messages may arrive in the SNS notification format or, with raw
+// message delivery enabled on the subscription, as the bare envelope.
+// Events received from SQS are not published back, so an instance can
+// publish and subscribe the same events without looping.
+package aws
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"reflect"
+	"strconv"
+	"sync"
+	"time"
+	"unicode/utf8"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/sns"
+	snstypes "github.com/aws/aws-sdk-go-v2/service/sns/types"
+	"github.com/aws/aws-sdk-go-v2/service/sqs"
+	sqstypes "github.com/aws/aws-sdk-go-v2/service/sqs/types"
+
+	"github.com/openframebox/goevent"
+)
+
+// Message attributes set on published messages
+const (
+	// AttributeEvent holds the event name, for use in SNS filter policies
+	AttributeEvent = "goevent.name"
+	// AttributeEncoding is "base64" when the codec output was not text
+	AttributeEncoding = "goevent.encoding"
+)
+
+// SNSClient is the part of *sns.Client the connector uses
+type SNSClient interface {
+	Publish(ctx context.Context, params *sns.PublishInput, optFns ...func(*sns.Options)) (*sns.PublishOutput, error)
+}
+
+// SQSClient is the part of *sqs.Client the connector uses
+type SQSClient interface {
+	ReceiveMessage(ctx context.Context, params *sqs.ReceiveMessageInput, optFns ...func(*sqs.Options)) (*sqs.ReceiveMessageOutput, error)
+	DeleteMessage(ctx context.Context, params *sqs.DeleteMessageInput, optFns ...func(*sqs.Options)) (*sqs.DeleteMessageOutput, error)
+	ChangeMessageVisibility(ctx context.Context, params *sqs.ChangeMessageVisibilityInput, optFns ...func(*sqs.Options)) (*sqs.ChangeMessageVisibilityOutput, error)
+	SendMessage(ctx context.Context, params *sqs.SendMessageInput, optFns ...func(*sqs.Options)) (*sqs.SendMessageOutput, error)
+}
+
+// Option configures a Connector
+type Option func(*Connector)
+
+// WithSNS sets the client events are published with
+func WithSNS(client SNSClient) Option {
+	return func(c *Connector) {
+		c.sns = client
+	}
+}
+
+// WithSQS sets the client queues are polled with
+func WithSQS(client SQSClient) Option {
+	return func(c *Connector) {
+		c.sqs = client
+	}
+}
+
+// WithCodec sets the codec envelopes are encoded with.
+// Defaults to goevent.JSONCodec.
+func WithCodec(codec goevent.Codec) Option {
+	return func(c *Connector) {
+		c.codec = codec
+	}
+}
+
+// WithVisibilityTimeout sets how long a received message stays hidden
+// from other consumers. The timeout is extended while the message is
+// being dispatched, so slow listeners do not cause duplicate deliveries.
+// Defaults to 30 seconds.
+func WithVisibilityTimeout(timeout time.Duration) Option {
+	return func(c *Connector) {
+		c.visibility = timeout
+	}
+}
+
+// WithWaitTime sets how long a receive waits for messages to arrive.
+// Defaults to 20 seconds, the maximum SQS allows.
+func WithWaitTime(wait time.Duration) Option {
+	return func(c *Connector) {
+		c.wait = wait
+	}
+}
+
+// WithMaxMessages sets how many messages one receive returns at most.
+// Defaults to 10, the maximum SQS allows.
+func WithMaxMessages(n int32) Option {
+	return func(c *Connector) {
+		c.maxMessages = n
+	}
+}
+
+// WithMaxReceives moves a message that failed on its nth receive to the
+// dead letter queue. Zero, the default, retries failed messages until
+// the queue's own redrive policy removes them.
+func WithMaxReceives(n int) Option {
+	return func(c *Connector) {
+		c.maxReceives = n
+	}
+}
+
+// WithDeadLetterQueue sets the queue poison messages are moved to. Without
+// it, messages that exceed WithMaxReceives or cannot be decoded are deleted.
+func WithDeadLetterQueue(queueURL string) Option {
+	return func(c *Connector) {
+		c.deadLetterURL = queueURL
+	}
+}
+
+// Connector connects a bus to SNS and SQS
+type Connector struct {
+	bus           *goevent.GoEvent
+	sns           SNSClient
+	sqs           SQSClient
+	codec         goevent.Codec
+	visibility    time.Duration
+	wait          time.Duration
+	maxMessages   int32
+	maxReceives   int
+	deadLetterURL string
+
+	mu     sync.Mutex
+	cancel []context.CancelFunc
+	wg     sync.WaitGroup
+}
+
+// remoteKey holds the event of a dispatch received from SQS. Dispatches
+// its listeners make inherit the value but carry other events, so they
+// are still forwarded.
+type remoteKey struct{}
+
+// New creates a Connector for bus
+func New(bus *goevent.GoEvent, opts ...Option) *Connector {
+	c := &Connector{
+		bus:         bus,
+		codec:       goevent.JSONCodec{},
+		visibility:  30 * time.Second,
+		wait:        20 * time.Second,
+		maxMessages: 10,
+	}
+	for _, opt := range opts {
+		opt(c)
+	}
+	return c
+}
+
+// Publish forwards every dispatch of the named events to the SNS topic.
+// Forwarding runs as a sync listener, so events are published in
+// dispatch order and a failed publish is recorded on the dispatch.
+func (c *Connector) Publish(topicARN string, eventNames ...string) {
+	for _, name := range eventNames {
+		c.bus.RegisterListener(&forwarder{connector: c, topicARN: topicARN, eventName: name})
+	}
+}
+
+// PublishEvent sends a single event to the SNS topic. When ctx belongs
+// to a dispatch, its metadata travels with the event.
+func (c *Connector) PublishEvent(ctx context.Context, topicARN string, event goevent.Event) error {
+	if c.sns == nil {
+		return errors.New("aws: publish: no SNS client")
+	}
+	env := goevent.NewEnvelope(event)
+	if handle, ok := goevent.HandleFromContext(ctx); ok {
+		env.Metadata = handle.Metadata()
+	}
+	data, err := c.codec.Marshal(env)
+	if err != nil {
+		return err
+	}
+
+	attributes := map[string]snstypes.MessageAttributeValue{
+		AttributeEvent: {DataType: aws.String("String"), StringValue: aws.String(event.Name())},
+	}
+	body := string(data)
+	if !utf8.Valid(data) {
+		body = base64.StdEncoding.EncodeToString(data)
+		attributes[AttributeEncoding] = snstypes.MessageAttributeValue{DataType: aws.String("String"), StringValue: aws.String("base64")}
+	}
+
+	_, err = c.sns.Publish(ctx, &sns.PublishInput{
+		TopicArn:          aws.String(topicARN),
+		Message:           aws.String(body),
+		MessageAttributes: attributes,
+	})
+	if err != nil {
+		return fmt.Errorf("aws: publish %s to %s: %w", event.Name(), topicARN, err)
+	}
+	return nil
+}
+
+// Subscribe polls the SQS queue and dispatches the events it receives
+// into the bus until ctx is done or Close is called. Messages are
+// handled one at a time, in the order they are received.
+func (c *Connector) Subscribe(ctx context.Context, queueURL string) error {
+	if c.sqs == nil {
+		return errors.New("aws: subscribe: no SQS client")
+	}
+
+	ctx, cancel := context.WithCancel(ctx)
+	c.mu.Lock()
+	c.cancel = append(c.cancel, cancel)
+	c.mu.Unlock()
+
+	c.wg.Add(1)
+	go func() {
+		defer c.wg.Done()
+		for ctx.Err() == nil {
+			c.poll(ctx, queueURL)
+		}
+	}()
+	return nil
+}
+
+// Close stops every subscription and waits for messages being dispatched.
+// The clients are left open.
+func (c *Connector) Close() error {
+	c.mu.Lock()
+	cancel := c.cancel
+	c.cancel = nil
+	c.mu.Unlock()
+
+	for _, cancel := range cancel {
+		cancel()
+	}
+	c.wg.Wait()
+	return nil
+}
+
+// poll receives one batch of messages and handles them
+func (c *Connector) poll(ctx context.Context, queueURL string) {
+	out, err := c.sqs.ReceiveMessage(ctx, &sqs.ReceiveMessageInput{
+		QueueUrl:                    aws.String(queueURL),
+		MaxNumberOfMessages:         c.maxMessages,
+		VisibilityTimeout:           seconds(c.visibility),
+		WaitTimeSeconds:             seconds(c.wait),
+		MessageAttributeNames:       []string{"All"},
+		MessageSystemAttributeNames: []sqstypes.MessageSystemAttributeName{sqstypes.MessageSystemAttributeNameApproximateReceiveCount},
+	})
+	if err != nil {
+		if ctx.Err() == nil {
+			// Back off before retrying, for example while SQS is unreachable
+			select {
+			case <-ctx.Done():
+			case <-time.After(time.Second):
+			}
+		}
+		return
+	}
+
+	for _, msg := range out.Messages {
+		if ctx.Err() != nil {
+			// Left for the visibility timeout to return to the queue
+			return
+		}
+		c.handle(ctx, queueURL, msg)
+	}
+}
+
+// handle dispatches a message with the metadata it was sent with and
+// deletes it once its listeners succeeded.
+// Messages that cannot be decoded, and messages that failed on their last
+// allowed receive, are moved to the dead letter queue.
+func (c *Connector) handle(ctx context.Context, queueURL string, msg sqstypes.Message) {
+	// Settle messages that were dispatched even if polling stops meanwhile
+	settleCtx := context.WithoutCancel(ctx)
+
+	env, err := c.decode(msg)
+	if err != nil {
+		c.deadLetter(settleCtx, queueURL, msg)
+		return
+	}
+
+	stop := c.extendVisibility(ctx, queueURL, msg)
+	dispatchCtx := context.WithValue(context.Background(), remoteKey{}, env.Event)
+	handle := c.bus.DispatchContext(dispatchCtx, env.Event, goevent.WithMetadata(env.Metadata.Fields()))
+	handle.Wait()
+	stop()
+
+	if len(handle.GetErrors()) == 0 {
+		c.delete(settleCtx, queueURL, msg)
+		return
+	}
+	if c.maxReceives > 0 && receiveCount(msg) >= c.maxReceives {
+		c.deadLetter(settleCtx, queueURL, msg)
+	}
+}
+
+// extendVisibility keeps msg hidden while it is being dispatched. The
+// returned function stops extending it.
+func (c *Connector) extendVisibility(ctx context.Context, queueURL string, msg sqstypes.Message) func() {
+	if c.visibility < 2*time.Second {
+		return func() {}
+	}
+
+	done := make(chan struct{})
+	var wg sync.WaitGroup
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		ticker := time.NewTicker(c.visibility / 2)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-done:
+				return
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+				c.sqs.ChangeMessageVisibility(ctx, &sqs.ChangeMessageVisibilityInput{
+					QueueUrl:          aws.String(queueURL),
+					ReceiptHandle:     msg.ReceiptHandle,
+					VisibilityTimeout: seconds(c.visibility),
+				})
+			}
+		}
+	}()
+	return func() {
+		close(done)
+		wg.Wait()
+	}
+}
+
+// deadLetter moves msg to the dead letter queue, or deletes it if there
+// is none. The message stays in its queue if it cannot be sent.
+func (c *Connector) deadLetter(ctx context.Context, queueURL string, msg sqstypes.Message) {
+	if c.deadLetterURL != "" {
+		_, err := c.sqs.SendMessage(ctx, &sqs.SendMessageInput{
+			QueueUrl:          aws.String(c.deadLetterURL),
+			MessageBody:       msg.Body,
+			MessageAttributes: msg.MessageAttributes,
+		})
+		if err != nil {
+			return
+		}
+	}
+	c.delete(ctx, queueURL, msg)
+}
+
+func (c *Connector) delete(ctx context.Context, queueURL string, msg sqstypes.Message) {
+	c.sqs.DeleteMessage(ctx, &sqs.DeleteMessageInput{
+		QueueUrl:      aws.String(queueURL),
+		ReceiptHandle: msg.ReceiptHandle,
+	})
+}
+
+// notification is the SNS notification format of messages delivered
+// to SQS without raw message delivery
+type notification struct {
+	Type              string
+	Message           string
+	MessageAttributes map[string]struct {
+		Type  string
+		Value string
+	}
+}
+
+// decode extracts the envelope from a message body
+func (c *Connector) decode(msg sqstypes.Message) (goevent.Envelope, error) {
+	body := aws.ToString(msg.Body)
+	encoding := ""
+	if attr, ok := msg.MessageAttributes[AttributeEncoding]; ok {
+		encoding = aws.ToString(attr.StringValue)
+	}
+
+	var n notification
+	if json.Unmarshal([]byte(body), &n) == nil && n.Type == "Notification" {
+		body = n.Message
+		encoding = n.MessageAttributes[AttributeEncoding].Value
+	}
+
+	data := []byte(body)
+	if encoding == "base64" {
+		var err error
+		if data, err = base64.StdEncoding.DecodeString(body); err != nil {
+			return goevent.Envelope{}, fmt.Errorf("aws: decode: %w", err)
+		}
+	}
+	return c.codec.Unmarshal(data)
+}
+
+// receiveCount returns how often msg was received, including this time
+func receiveCount(msg sqstypes.Message) int {
+	n, _ := strconv.Atoi(msg.Attributes[string(sqstypes.MessageSystemAttributeNameApproximateReceiveCount)])
+	return n
+}
+
+// seconds converts d to whole seconds, rounding up
+func seconds(d time.Duration) int32 {
+	return int32((d + time.Second - 1) / time.Second)
+}
+
+// forwarder publishes the events of one name to an SNS topic
+type forwarder struct {
+	connector *Connector
+	topicARN  string
+	eventName string
+}
+
+func (f *forwarder) EventName() string {
+	return f.eventName
+}
+
+func (f *forwarder) OnEvent(event goevent.Event) error {
+	return f.OnEventContext(context.Background(), event)
+}
+
+func (f *forwarder) OnEventContext(ctx context.Context, event goevent.Event) error {
+	if isRemote(ctx, event) {
+		return nil
+	}
+	return f.connector.PublishEvent(ctx, f.topicARN, event)
+}
+
+// isRemote reports whether event was received from SQS
+func isRemote(ctx context.Context, event goevent.Event) bool {
+	remote, ok := ctx.Value(remoteKey{}).(goevent.Event)
+	return ok && reflect.TypeOf(remote).Comparable() && remote == event
+}
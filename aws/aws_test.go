@@ -0,0 +1,319 @@
+package aws
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"strconv"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/sns"
+	"github.com/aws/aws-sdk-go-v2/service/sqs"
+	sqstypes "github.com/aws/aws-sdk-go-v2/service/sqs/types"
+
+	"github.com/openframebox/goevent"
+)
+
+// fakeSQS keeps queues in memory. A received message is hidden for the
+// requested visibility timeout, so a zero timeout redelivers it on the
+// next receive.
+type fakeSQS struct {
+	mu     sync.Mutex
+	queues map[string][]*fakeMessage
+	nextID int
+}
+
+type fakeMessage struct {
+	id        string
+	body      string
+	receives  int
+	visibleAt time.Time
+}
+
+func newFakeSQS() *fakeSQS {
+	return &fakeSQS{queues: make(map[string][]*fakeMessage)}
+}
+
+func (f *fakeSQS) send(queueURL, body string) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.nextID++
+	f.queues[queueURL] = append(f.queues[queueURL], &fakeMessage{id: strconv.Itoa(f.nextID), body: body})
+}
+
+func (f *fakeSQS) bodies(queueURL string) []string {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	var bodies []string
+	for _, msg := range f.queues[queueURL] {
+		bodies = append(bodies, msg.body)
+	}
+	return bodies
+}
+
+func (f *fakeSQS) ReceiveMessage(ctx context.Context, params *sqs.ReceiveMessageInput, _ ...func(*sqs.Options)) (*sqs.ReceiveMessageOutput, error) {
+	f.mu.Lock()
+	now := time.Now()
+	var out sqs.ReceiveMessageOutput
+	for _, msg := range f.queues[aws.ToString(params.QueueUrl)] {
+		if len(out.Messages) == int(params.MaxNumberOfMessages) || now.Before(msg.visibleAt) {
+			continue
+		}
+		msg.receives++
+		msg.visibleAt = now.Add(time.Duration(params.VisibilityTimeout) * time.Second)
+		out.Messages = append(out.Messages, sqstypes.Message{
+			Body:          aws.String(msg.body),
+			ReceiptHandle: aws.String(msg.id),
+			Attributes:    map[string]string{"ApproximateReceiveCount": strconv.Itoa(msg.receives)},
+		})
+	}
+	f.mu.Unlock()
+
+	if len(out.Messages) == 0 {
+		select {
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		case <-time.After(10 * time.Millisecond):
+		}
+	}
+	return &out, nil
+}
+
+func (f *fakeSQS) DeleteMessage(_ context.Context, params *sqs.DeleteMessageInput, _ ...func(*sqs.Options)) (*sqs.DeleteMessageOutput, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	url := aws.ToString(params.QueueUrl)
+	for i, msg := range f.queues[url] {
+		if msg.id == aws.ToString(params.ReceiptHandle) {
+			f.queues[url] = append(f.queues[url][:i], f.queues[url][i+1:]...)
+			break
+		}
+	}
+	return &sqs.DeleteMessageOutput{}, nil
+}
+
+func (f *fakeSQS) ChangeMessageVisibility(context.Context, *sqs.ChangeMessageVisibilityInput, ...func(*sqs.Options)) (*sqs.ChangeMessageVisibilityOutput, error) {
+	return &sqs.ChangeMessageVisibilityOutput{}, nil
+}
+
+func (f *fakeSQS) SendMessage(_ context.Context, params *sqs.SendMessageInput, _ ...func(*sqs.Options)) (*sqs.SendMessageOutput, error) {
+	f.send(aws.ToString(params.QueueUrl), aws.ToString(params.MessageBody))
+	return &sqs.SendMessageOutput{}, nil
+}
+
+// fakeSNS delivers published messages to a queue in the SNS
+// notification format
+type fakeSNS struct {
+	sqs      *fakeSQS
+	queueURL string
+}
+
+func (f *fakeSNS) Publish(_ context.Context, params *sns.PublishInput, _ ...func(*sns.Options)) (*sns.PublishOutput, error) {
+	attributes := map[string]map[string]string{}
+	for name, value := range params.MessageAttributes {
+		attributes[name] = map[string]string{"Type": aws.ToString(value.DataType), "Value": aws.ToString(value.StringValue)}
+	}
+	body, err := json.Marshal(map[string]any{
+		"Type":              "Notification",
+		"TopicArn":          aws.ToString(params.TopicArn),
+		"Message":           aws.ToString(params.Message),
+		"MessageAttributes": attributes,
+	})
+	if err != nil {
+		return nil, err
+	}
+	f.sqs.send(f.queueURL, string(body))
+	return &sns.PublishOutput{}, nil
+}
+
+type testRecorder struct {
+	eventName string
+	fail      bool
+
+	mu       sync.Mutex
+	events   []goevent.Event
+	metadata []goevent.Metadata
+	calls    chan struct{}
+}
+
+func newTestRecorder(eventName string) *testRecorder {
+	return &testRecorder{eventName: eventName, calls: make(chan struct{}, 16)}
+}
+
+func (r *testRecorder) EventName() string {
+	return r.eventName
+}
+
+func (r *testRecorder) OnEvent(event goevent.Event) error {
+	r.mu.Lock()
+	defer func() {
+		r.mu.Unlock()
+		r.calls <- struct{}{}
+	}()
+	r.events = append(r.events, event)
+	if r.fail {
+		return errors.New("always fails")
+	}
+	return nil
+}
+
+func (r *testRecorder) OnEventContext(ctx context.Context, event goevent.Event) error {
+	if md, ok := goevent.MetadataFromContext(ctx); ok {
+		r.mu.Lock()
+		r.metadata = append(r.metadata, md)
+		r.mu.Unlock()
+	}
+	return r.OnEvent(event)
+}
+
+func (r *testRecorder) Metadata() []goevent.Metadata {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return append([]goevent.Metadata(nil), r.metadata...)
+}
+
+func (r *testRecorder) Events() []goevent.Event {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return append([]goevent.Event(nil), r.events...)
+}
+
+func (r *testRecorder) waitCalls(t *testing.T, n int) {
+	t.Helper()
+	for i := 0; i < n; i++ {
+		select {
+		case <-r.calls:
+		case <-time.After(5 * time.Second):
+			t.Fatalf("Expected %d calls, got %d", n, i)
+		}
+	}
+}
+
+// waitFor polls cond until it holds
+func waitFor(t *testing.T, cond func() bool) {
+	t.Helper()
+	deadline := time.Now().Add(5 * time.Second)
+	for !cond() {
+		if time.Now().After(deadline) {
+			t.Fatal("Timed out waiting for condition")
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+}
+
+func TestConnector_PublishAndSubscribe(t *testing.T) {
+	queue := newFakeSQS()
+	topic := &fakeSNS{sqs: queue, queueURL: "orders"}
+
+	bus := goevent.New()
+	recorder := newTestRecorder("order.placed")
+	bus.RegisterListener(recorder)
+
+	connector := New(bus, WithSNS(topic), WithSQS(queue))
+	connector.Publish("arn:aws:sns:eu-west-1:123:orders", "order.placed")
+	if err := connector.Subscribe(context.Background(), "orders"); err != nil {
+		t.Fatalf("Subscribe() failed: %v", err)
+	}
+	defer connector.Close()
+
+	bus.Dispatch(&goevent.GenericEvent{EventName: "order.placed", Data: map[string]any{"id": "o-1"}})
+
+	// Once locally, once from the queue, and not published back
+	recorder.waitCalls(t, 2)
+	waitFor(t, func() bool { return len(queue.bodies("orders")) == 0 })
+
+	events := recorder.Events()
+	if got := events[1].Payload()["id"]; got != "o-1" {
+		t.Errorf("Expected o-1, got %v", got)
+	}
+	select {
+	case <-recorder.calls:
+		t.Error("Expected the received event not to be published back")
+	case <-time.After(50 * time.Millisecond):
+	}
+}
+
+func TestConnector_RawDelivery(t *testing.T) {
+	queue := newFakeSQS()
+	data, err := goevent.JSONCodec{}.Marshal(goevent.NewEnvelope(&goevent.GenericEvent{EventName: "order.placed", Data: map[string]any{"id": "o-2"}}))
+	if err != nil {
+		t.Fatalf("Marshal() failed: %v", err)
+	}
+	queue.send("orders", string(data))
+
+	bus := goevent.New()
+	recorder := newTestRecorder("order.placed")
+	bus.RegisterListener(recorder)
+
+	connector := New(bus, WithSQS(queue))
+	connector.Subscribe(context.Background(), "orders")
+	defer connector.Close()
+
+	recorder.waitCalls(t, 1)
+	if got := recorder.Events()[0].Payload()["id"]; got != "o-2" {
+		t.Errorf("Expected o-2, got %v", got)
+	}
+}
+
+func TestConnector_MaxReceives(t *testing.T) {
+	queue := newFakeSQS()
+	data, _ := goevent.JSONCodec{}.Marshal(goevent.NewEnvelope(&goevent.GenericEvent{EventName: "order.placed"}))
+	queue.send("orders", string(data))
+	queue.send("orders", "not an envelope")
+
+	bus := goevent.New()
+	recorder := newTestRecorder("order.placed")
+	recorder.fail = true
+	bus.RegisterListener(recorder)
+
+	connector := New(bus, WithSQS(queue),
+		WithVisibilityTimeout(0),
+		WithMaxReceives(3),
+		WithDeadLetterQueue("orders-dlq"),
+	)
+	connector.Subscribe(context.Background(), "orders")
+	defer connector.Close()
+
+	recorder.waitCalls(t, 3)
+	waitFor(t, func() bool { return len(queue.bodies("orders")) == 0 })
+
+	dead := queue.bodies("orders-dlq")
+	if len(dead) != 2 {
+		t.Fatalf("Expected both messages in the dead letter queue, got %d", len(dead))
+	}
+	if dead[0] != "not an envelope" || dead[1] != string(data) {
+		t.Errorf("Expected the original bodies, got %q", dead)
+	}
+	if n := len(recorder.Events()); n != 3 {
+		t.Errorf("Expected 3 deliveries, got %d", n)
+	}
+}
+
+func TestConnector_MetadataRoundTrip(t *testing.T) {
+	queue := newFakeSQS()
+	topic := &fakeSNS{sqs: queue, queueURL: "orders"}
+
+	bus := goevent.New()
+	recorder := newTestRecorder("order.placed")
+	bus.RegisterListener(recorder)
+
+	connector := New(bus, WithSNS(topic), WithSQS(queue))
+	connector.Publish("arn:aws:sns:eu-west-1:123:orders", "order.placed")
+	if err := connector.Subscribe(context.Background(), "orders"); err != nil {
+		t.Fatalf("Subscribe() failed: %v", err)
+	}
+	defer connector.Close()
+
+	bus.Dispatch(&goevent.GenericEvent{EventName: "order.placed"},
+		goevent.WithMetadata(map[string]string{goevent.MetadataCorrelationID: "c-1", goevent.MetadataTenant: "acme", "trace": "t-1"}))
+
+	// Once locally, once from the queue
+	recorder.waitCalls(t, 2)
+	md := recorder.Metadata()[1]
+	if md.CorrelationID != "c-1" || md.Tenant != "acme" || md.Header("trace") != "t-1" {
+		t.Errorf("Expected the sent metadata, got %+v", md)
+	}
+}
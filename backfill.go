@@ -0,0 +1,128 @@
+package goevent
+
+import (
+	"context"
+	"slices"
+	"time"
+)
+
+// BackfillOptions configures a Backfill
+type BackfillOptions struct {
+	// From is the first sequence number read. Pass BackfillProgress.Next
+	// of an interrupted backfill to resume it.
+	From uint64
+
+	// To is the last sequence number read. Zero reads to the end of the
+	// store.
+	To uint64
+
+	// Rate limits delivery to this many events per second. Zero
+	// delivers as fast as the listener handles them.
+	Rate float64
+
+	// PageSize is how many events are read from the store at once.
+	// Defaults to 256.
+	PageSize int
+
+	// StopOnError stops at the first event the listener fails on, so a
+	// resumed backfill starts with it. Otherwise failures are recorded
+	// and the backfill moves on.
+	StopOnError bool
+
+	// OnProgress is called after every page
+	OnProgress func(BackfillProgress)
+}
+
+// BackfillProgress reports how far a backfill got
+type BackfillProgress struct {
+	Delivered int    // events the listener handled
+	Failed    int    // events the listener returned an error for
+	Next      uint64 // sequence number to resume from
+}
+
+// Backfill passes the historical events of source matching the listener's
+// event names to listener alone, oldest first, so a new projection can
+// catch up without a dispatch to every other listener. The listener's
+// Retry and Timeout options apply, and it does not have to be registered.
+//
+// Backfill returns when it reached the end of the store, when ctx is
+// done, or on the first failure with StopOnError. The returned progress
+// says where to resume in every case.
+func (ge *GoEvent) Backfill(ctx context.Context, listener Listener, source Store, opts BackfillOptions) (BackfillProgress, error) {
+	pageSize := opts.PageSize
+	if pageSize <= 0 {
+		pageSize = 256
+	}
+	var interval time.Duration
+	if opts.Rate > 0 {
+		interval = time.Duration(float64(time.Second) / opts.Rate)
+	}
+
+	eventNames := eventNamesOf(listener)
+	listenerType := listenerTypeOf(listener)
+	listenerOpts := optionsOf(listener)
+	progress := BackfillProgress{Next: opts.From}
+	report := func() {
+		if opts.OnProgress != nil {
+			opts.OnProgress(progress)
+		}
+	}
+
+	paced := false
+	for {
+		page, err := source.ReadFrom(ctx, progress.Next, pageSize)
+		if err != nil {
+			return progress, err
+		}
+
+		for _, stored := range page {
+			if opts.To > 0 && stored.Seq > opts.To {
+				report()
+				return progress, nil
+			}
+			if !slices.Contains(eventNames, stored.Name) {
+				progress.Next = stored.Seq + 1
+				continue
+			}
+
+			if paced {
+				select {
+				case <-ctx.Done():
+				case <-ge.clock.After(interval):
+				}
+			}
+			paced = interval > 0
+			if err := ctx.Err(); err != nil {
+				report()
+				return progress, err
+			}
+
+			start := ge.clock.Now()
+			attempts, err := ge.invokeWithRetry(ctx, listener, stored.Event(), listenerOpts.Retry, listenerOpts.Timeout)
+			if err != nil {
+				eventError := &EventError{
+					EventName:    stored.Name,
+					ListenerType: listenerType,
+					DispatchedAt: stored.Time,
+					Err:          err,
+					Attempts:     attempts,
+					Duration:     ge.clock.Now().Sub(start),
+				}
+				ge.recordError(eventError)
+				progress.Failed++
+				if opts.StopOnError {
+					report()
+					return progress, eventError
+				}
+			} else {
+				progress.Delivered++
+			}
+			progress.Next = stored.Seq + 1
+		}
+
+		report()
+		if len(page) < pageSize {
+			return progress, nil
+		}
+	}
+}
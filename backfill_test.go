@@ -0,0 +1,145 @@
+package goevent
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+// testSeqListener records the "n" payload field of the events it handles
+// and fails on failAt
+type testSeqListener struct {
+	seen   []int
+	failAt int
+}
+
+func (l *testSeqListener) EventName() string {
+	return "test.event"
+}
+
+func (l *testSeqListener) OnEvent(event Event) error {
+	n := event.Payload()["n"].(int)
+	if n == l.failAt {
+		return errors.New("projection failed")
+	}
+	l.seen = append(l.seen, n)
+	return nil
+}
+
+// newBackfillStore stores n test events with an unrelated event between each
+func newBackfillStore(t *testing.T, n int) *MemoryStore {
+	t.Helper()
+	store := NewMemoryStore()
+	ctx := context.Background()
+	for i := 1; i <= n; i++ {
+		if _, err := store.Append(ctx, &GenericEvent{EventName: "test.event", Data: map[string]any{"n": i}}); err != nil {
+			t.Fatalf("Append() failed: %v", err)
+		}
+		store.Append(ctx, &GenericEvent{EventName: "other.event"})
+	}
+	return store
+}
+
+func TestBackfill_Progress(t *testing.T) {
+	store := newBackfillStore(t, 5)
+	evt := New()
+	other := &testCountingListener{}
+	evt.RegisterListener(other)
+
+	var reports []BackfillProgress
+	listener := &testSeqListener{}
+	progress, err := evt.Backfill(context.Background(), listener, store, BackfillOptions{
+		PageSize:   4,
+		OnProgress: func(p BackfillProgress) { reports = append(reports, p) },
+	})
+	if err != nil {
+		t.Fatalf("Backfill() failed: %v", err)
+	}
+
+	if len(listener.seen) != 5 || listener.seen[0] != 1 || listener.seen[4] != 5 {
+		t.Errorf("Expected events 1 to 5 in order, got %v", listener.seen)
+	}
+	if other.Count() != 0 {
+		t.Errorf("Expected registered listeners not to be called, got %d calls", other.Count())
+	}
+	if progress.Delivered != 5 || progress.Next != 11 {
+		t.Errorf("Expected 5 delivered and next 11, got %+v", progress)
+	}
+	if len(reports) != 3 || reports[0].Delivered != 2 || reports[0].Next != 5 {
+		t.Errorf("Expected a report per page, got %+v", reports)
+	}
+}
+
+func TestBackfill_StopOnErrorAndResume(t *testing.T) {
+	store := newBackfillStore(t, 5)
+	evt := New()
+
+	listener := &testSeqListener{failAt: 3}
+	progress, err := evt.Backfill(context.Background(), listener, store, BackfillOptions{StopOnError: true})
+	var eventErr *EventError
+	if !errors.As(err, &eventErr) {
+		t.Fatalf("Expected an *EventError, got %v", err)
+	}
+	if progress.Delivered != 2 || progress.Failed != 1 || progress.Next != 5 {
+		t.Errorf("Expected to stop at the failing event, got %+v", progress)
+	}
+	if len(evt.GetErrors()) != 1 {
+		t.Errorf("Expected the failure to be recorded, got %d errors", len(evt.GetErrors()))
+	}
+
+	// Resuming retries the failed event
+	listener.failAt = 0
+	progress, err = evt.Backfill(context.Background(), listener, store, BackfillOptions{From: progress.Next, To: 8})
+	if err != nil {
+		t.Fatalf("Backfill() failed: %v", err)
+	}
+	if len(listener.seen) != 4 || listener.seen[2] != 3 || listener.seen[3] != 4 {
+		t.Errorf("Expected to resume with event 3 and stop after 4, got %v", listener.seen)
+	}
+	if progress.Next != 9 {
+		t.Errorf("Expected next 9, got %d", progress.Next)
+	}
+}
+
+func TestBackfill_Rate(t *testing.T) {
+	store := newBackfillStore(t, 3)
+	clock := newFakeClock(time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC))
+	evt := New(WithClock(clock))
+
+	listener := &testSeqListener{}
+	done := make(chan BackfillProgress)
+	go func() {
+		progress, _ := evt.Backfill(context.Background(), listener, store, BackfillOptions{Rate: 2})
+		done <- progress
+	}()
+
+	// The first event goes out at once, then one every 500ms
+	for i := 0; i < 2; i++ {
+		clock.BlockUntil(t, 1)
+		clock.Advance(500 * time.Millisecond)
+	}
+	select {
+	case progress := <-done:
+		if progress.Delivered != 3 {
+			t.Errorf("Expected 3 delivered, got %d", progress.Delivered)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("Expected the backfill to finish")
+	}
+}
+
+func TestBackfill_Canceled(t *testing.T) {
+	store := newBackfillStore(t, 3)
+	evt := New()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	progress, err := evt.Backfill(ctx, &testSeqListener{}, store, BackfillOptions{})
+	if !errors.Is(err, context.Canceled) {
+		t.Errorf("Expected context.Canceled, got %v", err)
+	}
+	if progress.Delivered != 0 || progress.Next != 0 {
+		t.Errorf("Expected nothing delivered, got %+v", progress)
+	}
+}
@@ -0,0 +1,192 @@
+package goevent
+
+import (
+	"errors"
+	"fmt"
+	"runtime"
+	"sync"
+)
+
+// ErrQueueFull is recorded on a dispatch rejected by OverflowError
+var ErrQueueFull = errors.New("goevent: dispatch queue full")
+
+// OverflowPolicy determines what happens when the dispatch queue is full
+type OverflowPolicy int
+
+const (
+	// OverflowBlock blocks Dispatch until the queue has room
+	OverflowBlock OverflowPolicy = iota
+	// OverflowDropNewest drops the dispatch that did not fit
+	OverflowDropNewest
+	// OverflowDropOldest evicts the oldest queued dispatch to make room
+	OverflowDropOldest
+	// OverflowError rejects the dispatch with ErrQueueFull
+	OverflowError
+)
+
+func (p OverflowPolicy) String() string {
+	switch p {
+	case OverflowBlock:
+		return "block"
+	case OverflowDropNewest:
+		return "drop-newest"
+	case OverflowDropOldest:
+		return "drop-oldest"
+	case OverflowError:
+		return "error"
+	}
+	return fmt.Sprintf("OverflowPolicy(%d)", int(p))
+}
+
+// DispatchQueueOptions configures the bounded dispatch queue
+type DispatchQueueOptions struct {
+	// Size is the number of dispatches the queue holds
+	Size int
+	// Workers is the number of goroutines delivering queued dispatches.
+	// Defaults to GOMAXPROCS.
+	Workers int
+	// Overflow determines what happens when the queue is full
+	Overflow OverflowPolicy
+}
+
+// WithDispatchQueue routes dispatches through a bounded queue drained by
+// a fixed pool of workers. Dispatch then returns once the event is queued
+// and all of its listeners, including synchronous ones, run on a worker.
+// Use the returned handle to wait for delivery. ForceSync dispatches skip
+// the queue. With OverflowBlock, a listener running on a worker that
+// dispatches into the full queue delivers its event inline instead of
+// waiting for the workers, which may all be waiting on it.
+func WithDispatchQueue(opts DispatchQueueOptions) Option {
+	return func(ge *GoEvent) {
+		if opts.Size < 1 {
+			opts.Size = 1
+		}
+		if opts.Workers < 1 {
+			opts.Workers = runtime.GOMAXPROCS(0)
+		}
+		ge.queue = newDispatchQueue(ge, opts)
+	}
+}
+
+type queuedDispatch struct {
+	handle *DispatchHandle
+	event  Event
+}
+
+// dispatchQueue is a bounded FIFO of dispatches awaiting delivery
+type dispatchQueue struct {
+	ge   *GoEvent
+	opts DispatchQueueOptions
+
+	mu       sync.Mutex
+	notEmpty *sync.Cond
+	notFull  *sync.Cond
+	items    []queuedDispatch
+	closed   bool
+}
+
+func newDispatchQueue(ge *GoEvent, opts DispatchQueueOptions) *dispatchQueue {
+	q := &dispatchQueue{ge: ge, opts: opts}
+	q.notEmpty = sync.NewCond(&q.mu)
+	q.notFull = sync.NewCond(&q.mu)
+	for i := 0; i < opts.Workers; i++ {
+		go q.work()
+	}
+	return q
+}
+
+// push queues a dispatch, applying the overflow policy when full
+func (q *dispatchQueue) push(handle *DispatchHandle, event Event) {
+	q.mu.Lock()
+
+	if len(q.items) >= q.opts.Size {
+		handle.setOverflow(q.opts.Overflow)
+
+		switch q.opts.Overflow {
+		case OverflowBlock:
+			if handle.fromWorker {
+				q.mu.Unlock()
+				handle.onWorker = true
+				q.ge.publish(handle, event)
+				return
+			}
+			for len(q.items) >= q.opts.Size {
+				q.notFull.Wait()
+			}
+		case OverflowDropNewest:
+			q.mu.Unlock()
+			q.ge.drop(handle)
+			return
+		case OverflowDropOldest:
+			oldest := q.items[0]
+			q.items = q.items[1:]
+			oldest.handle.setOverflow(OverflowDropOldest)
+			defer func() {
+				oldest.handle.release()
+				q.ge.wg.Done()
+				q.ge.drop(oldest.handle)
+			}()
+		case OverflowError:
+			q.mu.Unlock()
+			eventError := handle.newError(event.Name(), ErrQueueFull)
+			handle.recordError(eventError)
+			q.ge.recordError(eventError)
+			q.ge.drop(handle)
+			return
+		}
+	}
+
+	// Queued dispatches keep the handle and the bus open until delivered
+	handle.hold(1)
+	q.ge.wg.Add(1)
+	q.items = append(q.items, queuedDispatch{handle: handle, event: event})
+	q.notEmpty.Signal()
+	q.mu.Unlock()
+}
+
+// work delivers queued dispatches
+func (q *dispatchQueue) work() {
+	for {
+		q.mu.Lock()
+		for len(q.items) == 0 && !q.closed {
+			q.notEmpty.Wait()
+		}
+		if len(q.items) == 0 {
+			q.mu.Unlock()
+			return
+		}
+		item := q.items[0]
+		q.items = q.items[1:]
+		q.notFull.Signal()
+		q.mu.Unlock()
+
+		item.handle.onWorker = true
+		q.ge.publish(item.handle, item.event)
+		item.handle.release()
+		q.ge.wg.Done()
+	}
+}
+
+// close stops the workers once the queue is empty
+func (q *dispatchQueue) close() {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	q.closed = true
+	q.notEmpty.Broadcast()
+}
+
+// submit delivers a dispatch, through the dispatch queue if one is
+// configured and the dispatch is not ForceSync
+func (ge *GoEvent) submit(handle *DispatchHandle, event Event) {
+	if ge.queue == nil || handle.forceSync {
+		ge.publish(handle, event)
+		return
+	}
+	ge.queue.push(handle, event)
+}
+
+// drop marks a dispatch as not delivered
+func (ge *GoEvent) drop(handle *DispatchHandle) {
+	handle.shed.Store(true)
+	handle.markDone()
+}
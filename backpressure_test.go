@@ -0,0 +1,218 @@
+package goevent
+
+import (
+	"context"
+	"errors"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// testGateListener blocks synchronously until released
+type testGateListener struct {
+	started chan struct{}
+	release chan struct{}
+}
+
+func newTestGateListener() *testGateListener {
+	return &testGateListener{
+		started: make(chan struct{}, 16),
+		release: make(chan struct{}),
+	}
+}
+
+func (l *testGateListener) EventName() string {
+	return "test.event"
+}
+
+func (l *testGateListener) OnEvent(event Event) error {
+	l.started <- struct{}{}
+	<-l.release
+	return nil
+}
+
+// saturate occupies the single worker and fills the queue of size one
+func saturate(t *testing.T, evt *GoEvent, gate *testGateListener) (*DispatchHandle, *DispatchHandle) {
+	t.Helper()
+	running := evt.Dispatch(&TestEvent{data: "running"})
+	select {
+	case <-gate.started:
+	case <-time.After(time.Second):
+		t.Fatal("Worker did not pick up the first dispatch")
+	}
+	queued := evt.Dispatch(&TestEvent{data: "queued"})
+	return running, queued
+}
+
+func TestDispatchQueue_DeliversAsynchronously(t *testing.T) {
+	evt := New(WithDispatchQueue(DispatchQueueOptions{Size: 4}))
+	listener := &testCountingListener{}
+	evt.RegisterListener(listener)
+
+	handle := evt.Dispatch(&TestEvent{})
+	handle.Wait()
+
+	if listener.Count() != 1 {
+		t.Errorf("Expected 1 delivery, got %d", listener.Count())
+	}
+	if _, ok := handle.Overflow(); ok {
+		t.Error("Expected no overflow")
+	}
+}
+
+func TestDispatchQueue_DropNewest(t *testing.T) {
+	evt := New(WithDispatchQueue(DispatchQueueOptions{Size: 1, Workers: 1, Overflow: OverflowDropNewest}))
+	gate := newTestGateListener()
+	evt.RegisterListener(gate)
+
+	_, queued := saturate(t, evt, gate)
+	dropped := evt.Dispatch(&TestEvent{data: "dropped"})
+
+	if policy, ok := dropped.Overflow(); !ok || policy != OverflowDropNewest {
+		t.Errorf("Expected OverflowDropNewest, got %v (%v)", policy, ok)
+	}
+	if !dropped.Shed() {
+		t.Error("Expected newest dispatch to be dropped")
+	}
+
+	close(gate.release)
+	queued.Wait()
+	if queued.Shed() {
+		t.Error("Expected queued dispatch to be delivered")
+	}
+	evt.Wait()
+}
+
+func TestDispatchQueue_DropOldest(t *testing.T) {
+	evt := New(WithDispatchQueue(DispatchQueueOptions{Size: 1, Workers: 1, Overflow: OverflowDropOldest}))
+	gate := newTestGateListener()
+	evt.RegisterListener(gate)
+
+	_, evicted := saturate(t, evt, gate)
+	newest := evt.Dispatch(&TestEvent{data: "newest"})
+
+	evicted.Wait()
+	if policy, ok := evicted.Overflow(); !ok || policy != OverflowDropOldest || !evicted.Shed() {
+		t.Errorf("Expected oldest queued dispatch to be evicted, got %v (%v)", policy, ok)
+	}
+
+	close(gate.release)
+	newest.Wait()
+	if newest.Shed() {
+		t.Error("Expected newest dispatch to be delivered")
+	}
+	evt.Wait()
+}
+
+func TestDispatchQueue_Error(t *testing.T) {
+	evt := New(WithDispatchQueue(DispatchQueueOptions{Size: 1, Workers: 1, Overflow: OverflowError}))
+	gate := newTestGateListener()
+	evt.RegisterListener(gate)
+
+	saturate(t, evt, gate)
+	rejected := evt.Dispatch(&TestEvent{data: "rejected"})
+
+	errs := rejected.GetErrors()
+	if len(errs) != 1 || !errors.Is(errs[0].Err, ErrQueueFull) {
+		t.Errorf("Expected ErrQueueFull, got %v", errs)
+	}
+
+	close(gate.release)
+	evt.Wait()
+}
+
+func TestDispatchQueue_Block(t *testing.T) {
+	evt := New(WithDispatchQueue(DispatchQueueOptions{Size: 1, Workers: 1, Overflow: OverflowBlock}))
+	gate := newTestGateListener()
+	evt.RegisterListener(gate)
+
+	saturate(t, evt, gate)
+
+	dispatched := make(chan *DispatchHandle)
+	go func() {
+		dispatched <- evt.Dispatch(&TestEvent{data: "blocked"})
+	}()
+
+	select {
+	case <-dispatched:
+		t.Fatal("Expected Dispatch to block while the queue is full")
+	case <-time.After(20 * time.Millisecond):
+	}
+
+	close(gate.release)
+	handle := <-dispatched
+	handle.Wait()
+
+	if policy, ok := handle.Overflow(); !ok || policy != OverflowBlock {
+		t.Errorf("Expected OverflowBlock, got %v (%v)", policy, ok)
+	}
+	evt.Wait()
+}
+
+// testReentrantListener dispatches children from the listener handling
+// the "parent" event
+type testReentrantListener struct {
+	evt      *GoEvent
+	children int
+	received atomic.Int32
+}
+
+func (l *testReentrantListener) EventName() string {
+	return "test.event"
+}
+
+func (l *testReentrantListener) OnEvent(event Event) error {
+	return l.OnEventContext(context.Background(), event)
+}
+
+func (l *testReentrantListener) OnEventContext(ctx context.Context, event Event) error {
+	l.received.Add(1)
+	if event.(*TestEvent).data == "parent" {
+		for i := 0; i < l.children; i++ {
+			l.evt.DispatchContext(ctx, &TestEvent{data: "child"})
+		}
+	}
+	return nil
+}
+
+func TestDispatchQueue_BlockFromWorkerRunsInline(t *testing.T) {
+	evt := New(WithDispatchQueue(DispatchQueueOptions{Size: 1, Workers: 1, Overflow: OverflowBlock}))
+	listener := &testReentrantListener{evt: evt, children: 3}
+	evt.RegisterListener(listener)
+
+	evt.Dispatch(&TestEvent{data: "parent"})
+
+	waited := make(chan struct{})
+	go func() {
+		evt.Wait()
+		close(waited)
+	}()
+	select {
+	case <-waited:
+	case <-time.After(2 * time.Second):
+		t.Fatal("Expected a worker dispatching into its full queue not to deadlock")
+	}
+	if received := listener.received.Load(); received != 4 {
+		t.Errorf("Expected the parent and 3 children to be delivered, got %d", received)
+	}
+}
+
+func TestDispatchQueue_ForceSyncSkipsQueue(t *testing.T) {
+	evt := New(WithDispatchQueue(DispatchQueueOptions{Size: 1, Workers: 1, Overflow: OverflowDropNewest}))
+	gate := newTestGateListener()
+	recorder := &testNameRecorder{name: "test.forced"}
+	evt.RegisterListener(gate, recorder)
+	saturate(t, evt, gate)
+
+	// The queue is full, so a queued dispatch would be dropped
+	handle := evt.Dispatch(&GenericEvent{EventName: "test.forced"}, ForceSync())
+	if len(recorder.received) != 1 {
+		t.Errorf("Expected the listener to have run when Dispatch returned, got %d deliveries", len(recorder.received))
+	}
+	if handle.Shed() {
+		t.Error("Expected the dispatch not to be dropped")
+	}
+
+	close(gate.release)
+	evt.Wait()
+}
@@ -0,0 +1,96 @@
+package goevent
+
+import "fmt"
+
+// BatchListener receives events in chunks instead of one at a time,
+// amortizing round trips for database writers and external APIs.
+// Register it with RegisterBatchListener. BatchSize and FlushInterval
+// are read from Options() if the listener provides it.
+type BatchListener interface {
+	EventName() string
+	OnEvents(events []Event) error
+}
+
+// RegisterBatchListener registers listeners that receive events in
+// batches. A batch is delivered once it holds BatchSize events or
+// FlushInterval after its first event, whichever comes first. With
+// neither option set, every event is delivered as its own batch.
+// Middleware sees each batch as a *DigestEvent.
+func (ge *GoEvent) RegisterBatchListener(listeners ...BatchListener) {
+	adapters := batchAdapters(listeners)
+	ge.claimListeners(adapters, false)
+	for _, adapter := range adapters {
+		ge.registerSingleListener(adapter)
+	}
+}
+
+// RegisterBatchListenerE registers batch listeners like
+// RegisterBatchListener, after checking them as RegisterListenerE does.
+// Either every listener is registered or none is.
+func (ge *GoEvent) RegisterBatchListenerE(listeners ...BatchListener) error {
+	adapters := batchAdapters(listeners)
+	if err := ge.claimListeners(adapters, true); err != nil {
+		return err
+	}
+	for _, adapter := range adapters {
+		ge.registerSingleListener(adapter)
+	}
+	return nil
+}
+
+func batchAdapters(listeners []BatchListener) []Listener {
+	adapters := make([]Listener, len(listeners))
+	for i, listener := range listeners {
+		adapters[i] = &batchAdapter{listener}
+	}
+	return adapters
+}
+
+// batchAdapter lets a BatchListener go through the digest machinery
+// and the regular listener invocation path
+type batchAdapter struct {
+	BatchListener
+}
+
+// OnEvent delivers a collected batch
+func (a *batchAdapter) OnEvent(event Event) error {
+	digest, ok := event.(*DigestEvent)
+	if !ok {
+		return a.OnEvents([]Event{event})
+	}
+	return a.OnEvents(digest.Events)
+}
+
+// Options returns the wrapped listener's options
+func (a *batchAdapter) Options() ListenerOptions {
+	if withOpts, ok := a.BatchListener.(interface{ Options() ListenerOptions }); ok {
+		return withOpts.Options()
+	}
+	return ListenerOptions{}
+}
+
+// listenerKey returns the listener the application registered, looking
+// through internal adapters. Registrations and queues are keyed by it.
+func listenerKey(listener Listener) any {
+	switch adapter := listener.(type) {
+	case *batchAdapter:
+		return adapter.BatchListener
+	case *anyAdapter:
+		return adapter.AnyListener
+	}
+	return listener
+}
+
+// listenerTypeOf names a listener by its Go type, looking through
+// internal adapters
+func listenerTypeOf(listener Listener) string {
+	switch adapter := listener.(type) {
+	case *batchAdapter:
+		return fmt.Sprintf("%T", adapter.BatchListener)
+	case *anyAdapter:
+		return fmt.Sprintf("%T", adapter.AnyListener)
+	case interface{ listenerType() string }:
+		return adapter.listenerType()
+	}
+	return fmt.Sprintf("%T", listener)
+}
@@ -0,0 +1,185 @@
+package goevent
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+// testBatchListener records the batches it receives
+type testBatchListener struct {
+	mu      sync.Mutex
+	batches [][]Event
+	opts    ListenerOptions
+	err     error
+}
+
+func (l *testBatchListener) EventName() string {
+	return "test.event"
+}
+
+func (l *testBatchListener) OnEvents(events []Event) error {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.batches = append(l.batches, events)
+	return l.err
+}
+
+func (l *testBatchListener) Options() ListenerOptions {
+	return l.opts
+}
+
+func (l *testBatchListener) sizes() []int {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	sizes := make([]int, len(l.batches))
+	for i, batch := range l.batches {
+		sizes[i] = len(batch)
+	}
+	return sizes
+}
+
+func TestBatchListener_DeliversFullBatches(t *testing.T) {
+	evt := New()
+	listener := &testBatchListener{opts: ListenerOptions{BatchSize: 3}}
+	evt.RegisterBatchListener(listener)
+
+	for i := 0; i < 7; i++ {
+		evt.Dispatch(&TestEvent{data: "row"})
+	}
+
+	sizes := listener.sizes()
+	if len(sizes) != 2 || sizes[0] != 3 || sizes[1] != 3 {
+		t.Fatalf("Expected two batches of 3, got %v", sizes)
+	}
+
+	// Close delivers the partial batch
+	evt.Close(context.Background())
+	if sizes := listener.sizes(); len(sizes) != 3 || sizes[2] != 1 {
+		t.Errorf("Expected the remaining event to be flushed on Close, got %v", sizes)
+	}
+}
+
+func TestBatchListener_FlushInterval(t *testing.T) {
+	evt := New()
+	listener := &testBatchListener{opts: ListenerOptions{BatchSize: 100, FlushInterval: 20 * time.Millisecond}}
+	evt.RegisterBatchListener(listener)
+
+	evt.Dispatch(&TestEvent{data: "a"})
+	evt.Dispatch(&TestEvent{data: "b"}).Wait()
+
+	if sizes := listener.sizes(); len(sizes) != 1 || sizes[0] != 2 {
+		t.Errorf("Expected one batch of 2 after the interval, got %v", sizes)
+	}
+}
+
+func TestBatchListener_DefaultsToSingleEventBatches(t *testing.T) {
+	evt := New()
+	listener := &testBatchListener{}
+	evt.RegisterBatchListener(listener)
+
+	evt.Dispatch(&TestEvent{data: "a"})
+	evt.Dispatch(&TestEvent{data: "b"})
+
+	if sizes := listener.sizes(); len(sizes) != 2 || sizes[0] != 1 {
+		t.Errorf("Expected two batches of 1, got %v", sizes)
+	}
+}
+
+func TestBatchListener_Errors(t *testing.T) {
+	evt := New()
+	evt.RegisterBatchListener(&testBatchListener{err: errors.New("insert failed")})
+
+	evt.Dispatch(&TestEvent{data: "a"})
+
+	errs := evt.GetErrors()
+	if len(errs) != 1 {
+		t.Fatalf("Expected 1 error, got %d", len(errs))
+	}
+	if errs[0].ListenerType != "*goevent.testBatchListener" {
+		t.Errorf("Expected listener type '*goevent.testBatchListener', got '%s'", errs[0].ListenerType)
+	}
+}
+
+func TestBatchListener_RejectsDuplicateRegistration(t *testing.T) {
+	evt := New()
+	listener := &testBatchListener{}
+
+	if err := evt.RegisterBatchListenerE(listener); err != nil {
+		t.Fatalf("RegisterBatchListenerE() failed: %v", err)
+	}
+	err := evt.RegisterBatchListenerE(listener)
+	if !errors.Is(err, ErrInvalidListener) {
+		t.Fatalf("Expected ErrInvalidListener, got %v", err)
+	}
+	if !strings.Contains(err.Error(), "*goevent.testBatchListener") {
+		t.Errorf("Expected the error to name the batch listener, got %q", err)
+	}
+	if err := evt.RegisterBatchListenerE(nil); !errors.Is(err, ErrInvalidListener) {
+		t.Errorf("Expected ErrInvalidListener for a nil listener, got %v", err)
+	}
+
+	evt.Dispatch(&TestEvent{data: "a"})
+	if sizes := listener.sizes(); len(sizes) != 1 {
+		t.Errorf("Expected the listener to be registered once, got %d batches", len(sizes))
+	}
+}
+
+func TestBatchListener_FlushQueue(t *testing.T) {
+	evt := New()
+	listener := &testBatchListener{opts: ListenerOptions{BatchSize: 100}}
+	evt.RegisterBatchListener(listener)
+
+	evt.Dispatch(&TestEvent{data: "a"})
+	evt.Dispatch(&TestEvent{data: "b"})
+	if queued := evt.QueuedEvents(listener); len(queued) != 2 {
+		t.Fatalf("Expected 2 queued events, got %d", len(queued))
+	}
+
+	if flushed := evt.FlushQueue(listener); flushed != 2 {
+		t.Errorf("Expected 2 flushed events, got %d", flushed)
+	}
+	if sizes := listener.sizes(); len(sizes) != 1 || sizes[0] != 2 {
+		t.Errorf("Expected one batch of 2, got %v", sizes)
+	}
+
+	evt.Dispatch(&TestEvent{data: "c"})
+	if purged := evt.PurgeQueue(listener); purged != 1 {
+		t.Errorf("Expected 1 purged event, got %d", purged)
+	}
+	if queued := evt.QueuedEvents(listener); len(queued) != 0 {
+		t.Errorf("Expected an empty queue, got %d events", len(queued))
+	}
+}
+
+func TestBatchListener_WaitDoesNotBlockOnOpenBatch(t *testing.T) {
+	evt := New()
+	listener := &testBatchListener{opts: ListenerOptions{BatchSize: 100, FlushInterval: time.Hour}}
+	evt.RegisterBatchListener(listener)
+	handle := evt.Dispatch(&TestEvent{})
+
+	waited := make(chan struct{})
+	go func() {
+		evt.Wait()
+		close(waited)
+	}()
+	select {
+	case <-waited:
+	case <-time.After(time.Second):
+		t.Fatal("Expected Wait to return while the batch is open")
+	}
+	if len(listener.sizes()) != 0 {
+		t.Fatal("Expected the batch to stay open")
+	}
+
+	if err := evt.Close(context.Background()); err != nil {
+		t.Fatalf("Close() failed: %v", err)
+	}
+	handle.Wait()
+	if sizes := listener.sizes(); len(sizes) != 1 || sizes[0] != 1 {
+		t.Errorf("Expected Close to deliver the open batch, got %v", sizes)
+	}
+}
@@ -0,0 +1,118 @@
+package goevent
+
+import (
+	"context"
+	"errors"
+)
+
+// BridgeOption configures a bridge
+type BridgeOption func(*bridge)
+
+// BridgeEvents sets the events the bridge forwards. At least one is
+// required.
+func BridgeEvents(eventNames ...string) BridgeOption {
+	return func(b *bridge) {
+		b.events = append(b.events, eventNames...)
+	}
+}
+
+// BridgeFilter forwards only the events fn accepts
+func BridgeFilter(fn func(Event) bool) BridgeOption {
+	return func(b *bridge) {
+		b.filter = fn
+	}
+}
+
+// BridgeTransform replaces each forwarded event with the one fn returns,
+// for renaming events or reshaping their payloads. Returning nil drops
+// the event.
+func BridgeTransform(fn func(Event) Event) BridgeOption {
+	return func(b *bridge) {
+		b.transform = fn
+	}
+}
+
+// BridgeRename forwards events under the name fn returns, with their
+// original payload
+func BridgeRename(fn func(eventName string) string) BridgeOption {
+	return BridgeTransform(func(event Event) Event {
+		return &GenericEvent{EventName: fn(event.Name()), Data: event.Payload()}
+	})
+}
+
+// bridgedKey holds the buses a dispatch was bridged through
+type bridgedKey struct{}
+
+type bridge struct {
+	src, dst  *GoEvent
+	events    []string
+	filter    func(Event) bool
+	transform func(Event) Event
+}
+
+// Bridge forwards events dispatched on src to dst, for example from an
+// internal bus to a public one. Forwarded events are dispatched with
+// the context of the original dispatch, so they inherit its priority,
+// deadline and metadata and count as its children. A dispatch is never
+// bridged back to a bus it already went through, so bridges may run in
+// both directions.
+func Bridge(src, dst *GoEvent, opts ...BridgeOption) error {
+	b := &bridge{src: src, dst: dst}
+	for _, opt := range opts {
+		opt(b)
+	}
+	if src == dst {
+		return errors.New("goevent: cannot bridge a bus to itself")
+	}
+	if len(b.events) == 0 {
+		return errors.New("goevent: bridge needs at least one event")
+	}
+
+	for _, eventName := range b.events {
+		src.RegisterListener(&bridgeListener{bridge: b, eventName: eventName})
+	}
+	return nil
+}
+
+// forward dispatches event on the destination bus
+func (b *bridge) forward(ctx context.Context, event Event) {
+	path, _ := ctx.Value(bridgedKey{}).([]*GoEvent)
+	for _, bus := range path {
+		if bus == b.dst {
+			return
+		}
+	}
+	if b.filter != nil && !b.filter(event) {
+		return
+	}
+	if b.transform != nil {
+		if event = b.transform(event); event == nil {
+			return
+		}
+	}
+
+	if len(path) == 0 {
+		path = []*GoEvent{b.src}
+	}
+	path = append(path[:len(path):len(path)], b.dst)
+	b.dst.DispatchContext(context.WithValue(ctx, bridgedKey{}, path), event)
+}
+
+// bridgeListener forwards the events of one name
+type bridgeListener struct {
+	bridge    *bridge
+	eventName string
+}
+
+func (l *bridgeListener) EventName() string {
+	return l.eventName
+}
+
+func (l *bridgeListener) OnEvent(event Event) error {
+	return l.OnEventContext(context.Background(), event)
+}
+
+func (l *bridgeListener) OnEventContext(ctx context.Context, event Event) error {
+	l.bridge.forward(ctx, event)
+	return nil
+}
@@ -0,0 +1,85 @@
+package goevent
+
+import "testing"
+
+// testNameRecorder records the names of the events it receives
+type testNameRecorder struct {
+	name     string
+	received []string
+}
+
+func (l *testNameRecorder) EventName() string {
+	return l.name
+}
+
+func (l *testNameRecorder) OnEvent(event Event) error {
+	l.received = append(l.received, event.Name())
+	return nil
+}
+
+func TestBridge(t *testing.T) {
+	internal, public := New(), New()
+	recorder := &testNameRecorder{name: "order.placed"}
+	public.RegisterListener(recorder)
+
+	err := Bridge(internal, public,
+		BridgeEvents("order.placed"),
+		BridgeFilter(func(e Event) bool { return e.Payload()["public"] == true }),
+	)
+	if err != nil {
+		t.Fatalf("Bridge() failed: %v", err)
+	}
+
+	internal.Dispatch(&GenericEvent{EventName: "order.placed", Data: map[string]any{"public": true}}).Wait()
+	internal.Dispatch(&GenericEvent{EventName: "order.placed", Data: map[string]any{"public": false}}).Wait()
+
+	if len(recorder.received) != 1 {
+		t.Errorf("Expected only the accepted event to be forwarded, got %d", len(recorder.received))
+	}
+}
+
+func TestBridge_Rename(t *testing.T) {
+	internal, public := New(), New()
+	recorder := &testNameRecorder{name: "public.order.placed"}
+	public.RegisterListener(recorder)
+
+	if err := Bridge(internal, public, BridgeEvents("order.placed"), BridgeRename(func(name string) string {
+		return "public." + name
+	})); err != nil {
+		t.Fatalf("Bridge() failed: %v", err)
+	}
+
+	internal.Dispatch(&GenericEvent{EventName: "order.placed"}).Wait()
+	if len(recorder.received) != 1 {
+		t.Errorf("Expected the renamed event to be forwarded, got %v", recorder.received)
+	}
+}
+
+func TestBridge_BothDirections(t *testing.T) {
+	a, b := New(), New()
+	onA, onB := &testNameRecorder{name: "test.event"}, &testNameRecorder{name: "test.event"}
+	a.RegisterListener(onA)
+	b.RegisterListener(onB)
+
+	if err := Bridge(a, b, BridgeEvents("test.event")); err != nil {
+		t.Fatalf("Bridge() failed: %v", err)
+	}
+	if err := Bridge(b, a, BridgeEvents("test.event")); err != nil {
+		t.Fatalf("Bridge() failed: %v", err)
+	}
+
+	dispatchWithin(t, a, &TestEvent{}).Wait()
+	if len(onA.received) != 1 || len(onB.received) != 1 {
+		t.Errorf("Expected each bus to receive the event once, got %d and %d", len(onA.received), len(onB.received))
+	}
+}
+
+func TestBridge_Invalid(t *testing.T) {
+	bus := New()
+	if err := Bridge(bus, bus, BridgeEvents("test.event")); err == nil {
+		t.Error("Expected bridging a bus to itself to fail")
+	}
+	if err := Bridge(bus, New()); err == nil {
+		t.Error("Expected a bridge without events to fail")
+	}
+}
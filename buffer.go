@@ -0,0 +1,68 @@
+package goevent
+
+import (
+	"context"
+	"sync"
+)
+
+// Buffer stages events until Flush dispatches them, or Discard drops
+// them. It lets a request handler collect the domain events of an
+// operation and publish them only once the whole operation succeeded.
+type Buffer struct {
+	ge *GoEvent
+
+	mu     sync.Mutex
+	staged []stagedDispatch
+}
+
+type stagedDispatch struct {
+	ctx   context.Context
+	event Event
+	opts  []DispatchOption
+}
+
+// Buffer returns an empty buffer dispatching on the bus
+func (ge *GoEvent) Buffer() *Buffer {
+	return &Buffer{ge: ge}
+}
+
+// Dispatch stages an event
+func (b *Buffer) Dispatch(event Event, opts ...DispatchOption) {
+	b.DispatchContext(context.Background(), event, opts...)
+}
+
+// DispatchContext stages an event to be dispatched with ctx
+func (b *Buffer) DispatchContext(ctx context.Context, event Event, opts ...DispatchOption) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.staged = append(b.staged, stagedDispatch{ctx: ctx, event: event, opts: opts})
+}
+
+// Len returns the number of staged events
+func (b *Buffer) Len() int {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return len(b.staged)
+}
+
+// Flush dispatches the staged events in the order they were staged and
+// empties the buffer, which can then be used again
+func (b *Buffer) Flush() []*DispatchHandle {
+	b.mu.Lock()
+	staged := b.staged
+	b.staged = nil
+	b.mu.Unlock()
+
+	handles := make([]*DispatchHandle, len(staged))
+	for i, s := range staged {
+		handles[i] = b.ge.DispatchContext(s.ctx, s.event, s.opts...)
+	}
+	return handles
+}
+
+// Discard drops the staged events
+func (b *Buffer) Discard() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.staged = nil
+}
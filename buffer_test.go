@@ -0,0 +1,39 @@
+package goevent
+
+import "testing"
+
+func TestBuffer_Flush(t *testing.T) {
+	evt := New()
+	var order []string
+	evt.RegisterListener(&testOrderListener{name: "listener", order: &order})
+
+	buffer := evt.Buffer()
+	buffer.Dispatch(&TestEvent{data: "first"})
+	buffer.Dispatch(&TestEvent{data: "second"})
+	if len(order) != 0 || buffer.Len() != 2 {
+		t.Fatalf("Expected 2 staged events and no dispatch, got %d staged, %d dispatched", buffer.Len(), len(order))
+	}
+
+	handles := buffer.Flush()
+	if len(handles) != 2 || len(order) != 2 {
+		t.Errorf("Expected 2 dispatches, got %d handles, %d calls", len(handles), len(order))
+	}
+	if buffer.Len() != 0 || len(buffer.Flush()) != 0 {
+		t.Error("Expected the buffer to be empty after Flush")
+	}
+}
+
+func TestBuffer_Discard(t *testing.T) {
+	evt := New()
+	listener := &testSyncListener{}
+	evt.RegisterListener(listener)
+
+	buffer := evt.Buffer()
+	buffer.Dispatch(&TestEvent{})
+	buffer.Discard()
+	buffer.Flush()
+
+	if listener.called {
+		t.Error("Expected discarded events not to be dispatched")
+	}
+}
@@ -0,0 +1,338 @@
+// Package cacheinvalidate deletes cache entries when the events that make
+// them stale are dispatched.
+//
+// Rules map an event name to key patterns. Placeholders in braces are
+// replaced by payload fields, with dots reaching into nested maps:
+//
+//	inv := cacheinvalidate.New(bus, cacheinvalidate.Redis(rdb))
+//	inv.Rule("user.updated", "user:{userId}", "user:{userId}:profile")
+//	inv.Rule("order.shipped", "orders:{customer.id}")
+//
+// Any cache can be used through CacheFunc, for example ristretto:
+//
+//	cache := cacheinvalidate.CacheFunc(func(_ context.Context, keys ...string) error {
+//		for _, key := range keys {
+//			local.Del(key)
+//		}
+//		return nil
+//	})
+//
+// With WithBroadcast, the keys of every rule are also dispatched as a
+// BroadcastEvent by an async listener. Forwarding that event between
+// instances with one of the transports (nats, redis, aws, pubsub) makes
+// the other instances drop the same keys from their local caches.
+package cacheinvalidate
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"sync"
+
+	"github.com/redis/go-redis/v9"
+
+	"github.com/openframebox/goevent"
+)
+
+// BroadcastEvent is the name of the event invalidations are broadcast as
+const BroadcastEvent = "cache.invalidate"
+
+// Cache removes entries by key
+type Cache interface {
+	Invalidate(ctx context.Context, keys ...string) error
+}
+
+// CacheFunc adapts a function to a Cache
+type CacheFunc func(ctx context.Context, keys ...string) error
+
+// Invalidate calls f
+func (f CacheFunc) Invalidate(ctx context.Context, keys ...string) error {
+	return f(ctx, keys...)
+}
+
+// Redis returns a Cache that deletes keys from Redis
+func Redis(client redis.Cmdable) Cache {
+	return CacheFunc(func(ctx context.Context, keys ...string) error {
+		return client.Del(ctx, keys...).Err()
+	})
+}
+
+// Option configures an Invalidator
+type Option func(*Invalidator)
+
+// WithBroadcast dispatches every invalidation as a BroadcastEvent and
+// applies broadcasts received from other instances. node must be unique
+// among the instances, so an instance ignores its own broadcasts.
+func WithBroadcast(node string) Option {
+	return func(inv *Invalidator) {
+		inv.node = node
+	}
+}
+
+// WithRetry retries failed invalidations
+func WithRetry(policy goevent.RetryPolicy) Option {
+	return func(inv *Invalidator) {
+		inv.options.Retry = policy
+	}
+}
+
+// Async invalidates on a separate goroutine instead of the dispatching
+// one. By default keys are gone by the time Dispatch returns, so reads
+// that follow a dispatch do not see stale entries.
+func Async() Option {
+	return func(inv *Invalidator) {
+		inv.options.Async = true
+	}
+}
+
+// Invalidator registers listeners that invalidate cache keys
+type Invalidator struct {
+	bus     *goevent.GoEvent
+	cache   Cache
+	node    string
+	options goevent.ListenerOptions
+
+	mu    sync.RWMutex
+	rules map[string][]func(event goevent.Event) ([]string, error)
+}
+
+// New creates an Invalidator for the events dispatched on bus
+func New(bus *goevent.GoEvent, cache Cache, opts ...Option) *Invalidator {
+	inv := &Invalidator{
+		bus:   bus,
+		cache: cache,
+		rules: make(map[string][]func(event goevent.Event) ([]string, error)),
+	}
+	for _, opt := range opts {
+		opt(inv)
+	}
+	if inv.node != "" {
+		bus.RegisterListener(&broadcastListener{inv: inv})
+	}
+	return inv
+}
+
+// Rule invalidates the keys produced by patterns whenever eventName is
+// dispatched. It fails if a pattern has an unclosed placeholder.
+func (inv *Invalidator) Rule(eventName string, patterns ...string) error {
+	parsed := make([]pattern, 0, len(patterns))
+	for _, text := range patterns {
+		p, err := parsePattern(text)
+		if err != nil {
+			return fmt.Errorf("cacheinvalidate: %w", err)
+		}
+		parsed = append(parsed, p)
+	}
+
+	inv.RuleFunc(eventName, func(event goevent.Event) ([]string, error) {
+		keys := make([]string, 0, len(parsed))
+		for _, p := range parsed {
+			key, err := p.expand(event.Payload())
+			if err != nil {
+				return nil, fmt.Errorf("cacheinvalidate: %s: %w", eventName, err)
+			}
+			keys = append(keys, key)
+		}
+		return keys, nil
+	})
+	return nil
+}
+
+// RuleFunc invalidates the keys returned by keys whenever eventName is
+// dispatched
+func (inv *Invalidator) RuleFunc(eventName string, keys func(event goevent.Event) ([]string, error)) {
+	inv.mu.Lock()
+	_, registered := inv.rules[eventName]
+	inv.rules[eventName] = append(inv.rules[eventName], keys)
+	inv.mu.Unlock()
+
+	if registered {
+		return
+	}
+	inv.bus.RegisterListener(&listener{inv: inv, eventName: eventName})
+	if inv.node != "" {
+		// Broadcasts are sent by an async listener so a slow transport
+		// does not hold up the dispatch
+		inv.bus.RegisterListener(&listener{inv: inv, eventName: eventName, broadcast: true})
+	}
+}
+
+// Keys returns the keys the rules of the event name produce for event
+func (inv *Invalidator) Keys(event goevent.Event) ([]string, error) {
+	inv.mu.RLock()
+	rules := inv.rules[event.Name()]
+	inv.mu.RUnlock()
+
+	var keys []string
+	for _, rule := range rules {
+		ruleKeys, err := rule(event)
+		if err != nil {
+			return nil, err
+		}
+		keys = append(keys, ruleKeys...)
+	}
+	return keys, nil
+}
+
+// Invalidate removes keys from the cache and broadcasts them if
+// WithBroadcast is set
+func (inv *Invalidator) Invalidate(ctx context.Context, keys ...string) error {
+	if err := inv.invalidate(ctx, keys); err != nil {
+		return err
+	}
+	if inv.node != "" {
+		return inv.broadcast(ctx, keys)
+	}
+	return nil
+}
+
+func (inv *Invalidator) invalidate(ctx context.Context, keys []string) error {
+	if len(keys) == 0 {
+		return nil
+	}
+	if err := inv.cache.Invalidate(ctx, keys...); err != nil {
+		return fmt.Errorf("cacheinvalidate: %w", err)
+	}
+	return nil
+}
+
+func (inv *Invalidator) broadcast(ctx context.Context, keys []string) error {
+	if len(keys) == 0 {
+		return nil
+	}
+	event := &goevent.GenericEvent{
+		EventName: BroadcastEvent,
+		Data:      map[string]any{"keys": keys, "node": inv.node},
+	}
+	if errs := inv.bus.DispatchContext(ctx, event).GetErrors(); len(errs) > 0 {
+		return fmt.Errorf("cacheinvalidate: broadcast: %w", errs[0])
+	}
+	return nil
+}
+
+// listener invalidates or broadcasts the keys of one event name
+type listener struct {
+	inv       *Invalidator
+	eventName string
+	broadcast bool
+}
+
+func (l *listener) EventName() string {
+	return l.eventName
+}
+
+func (l *listener) Options() goevent.ListenerOptions {
+	options := l.inv.options
+	if l.broadcast {
+		options.Async = true
+	}
+	return options
+}
+
+func (l *listener) OnEvent(event goevent.Event) error {
+	return l.OnEventContext(context.Background(), event)
+}
+
+func (l *listener) OnEventContext(ctx context.Context, event goevent.Event) error {
+	keys, err := l.inv.Keys(event)
+	if err != nil {
+		return err
+	}
+	if l.broadcast {
+		return l.inv.broadcast(ctx, keys)
+	}
+	return l.inv.invalidate(ctx, keys)
+}
+
+// broadcastListener applies invalidations broadcast by other instances
+type broadcastListener struct {
+	inv *Invalidator
+}
+
+func (l *broadcastListener) EventName() string {
+	return BroadcastEvent
+}
+
+func (l *broadcastListener) Options() goevent.ListenerOptions {
+	return l.inv.options
+}
+
+func (l *broadcastListener) OnEvent(event goevent.Event) error {
+	return l.OnEventContext(context.Background(), event)
+}
+
+func (l *broadcastListener) OnEventContext(ctx context.Context, event goevent.Event) error {
+	payload := event.Payload()
+	if node, _ := payload["node"].(string); node == l.inv.node {
+		return nil
+	}
+
+	var keys []string
+	switch raw := payload["keys"].(type) {
+	case []string:
+		keys = raw
+	case []any:
+		for _, key := range raw {
+			keys = append(keys, fmt.Sprint(key))
+		}
+	}
+	return l.inv.invalidate(ctx, keys)
+}
+
+// pattern is a parsed key pattern: literal parts interleaved with
+// payload field paths
+type pattern struct {
+	literals []string   // len(fields)+1 literal parts
+	fields   [][]string // dotted paths split into map keys
+}
+
+func parsePattern(text string) (pattern, error) {
+	var p pattern
+	rest := text
+	for {
+		open := strings.IndexByte(rest, '{')
+		if open < 0 {
+			p.literals = append(p.literals, rest)
+			return p, nil
+		}
+		end := strings.IndexByte(rest[open:], '}')
+		if end < 0 {
+			return pattern{}, fmt.Errorf("unclosed placeholder in %q", text)
+		}
+		field := rest[open+1 : open+end]
+		if field == "" {
+			return pattern{}, fmt.Errorf("empty placeholder in %q", text)
+		}
+		p.literals = append(p.literals, rest[:open])
+		p.fields = append(p.fields, strings.Split(field, "."))
+		rest = rest[open+end+1:]
+	}
+}
+
+func (p pattern) expand(payload map[string]any) (string, error) {
+	var b strings.Builder
+	for i, path := range p.fields {
+		b.WriteString(p.literals[i])
+		value, ok := lookup(payload, path)
+		if !ok {
+			return "", fmt.Errorf("payload has no field %q", strings.Join(path, "."))
+		}
+		fmt.Fprint(&b, value)
+	}
+	b.WriteString(p.literals[len(p.fields)])
+	return b.String(), nil
+}
+
+func lookup(payload map[string]any, path []string) (any, bool) {
+	var value any = payload
+	for _, key := range path {
+		m, ok := value.(map[string]any)
+		if !ok {
+			return nil, false
+		}
+		if value, ok = m[key]; !ok || value == nil {
+			return nil, false
+		}
+	}
+	return value, true
+}
@@ -0,0 +1,142 @@
+package cacheinvalidate
+
+import (
+	"context"
+	"encoding/json"
+	"sort"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/redis/go-redis/v9"
+
+	"github.com/openframebox/goevent"
+)
+
+// testCache records the keys it was asked to invalidate
+type testCache struct {
+	mu   sync.Mutex
+	keys []string
+}
+
+func (c *testCache) Invalidate(ctx context.Context, keys ...string) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.keys = append(c.keys, keys...)
+	return nil
+}
+
+func (c *testCache) invalidated() []string {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	keys := append([]string(nil), c.keys...)
+	sort.Strings(keys)
+	return keys
+}
+
+// testForwarder relays broadcasts to another bus the way a transport
+// would: the payload goes through JSON and arrives as a GenericEvent
+type testForwarder struct {
+	to *goevent.GoEvent
+}
+
+func (f *testForwarder) EventName() string {
+	return BroadcastEvent
+}
+
+func (f *testForwarder) OnEvent(event goevent.Event) error {
+	data, _ := json.Marshal(event.Payload())
+	var payload map[string]any
+	json.Unmarshal(data, &payload)
+	f.to.Dispatch(&goevent.GenericEvent{EventName: BroadcastEvent, Data: payload})
+	return nil
+}
+
+func TestRule_ExpandsPayloadFields(t *testing.T) {
+	bus := goevent.New()
+	cache := &testCache{}
+	inv := New(bus, cache)
+	if err := inv.Rule("user.updated", "user:{userId}", "user:{userId}:org:{org.id}", "users:list"); err != nil {
+		t.Fatalf("Rule() failed: %v", err)
+	}
+
+	handle := bus.Dispatch(&goevent.GenericEvent{EventName: "user.updated", Data: map[string]any{
+		"userId": 42,
+		"org":    map[string]any{"id": "acme"},
+	}})
+	if errs := handle.GetErrors(); len(errs) > 0 {
+		t.Fatalf("Expected no errors, got %v", errs)
+	}
+
+	want := "user:42,user:42:org:acme,users:list"
+	if got := strings.Join(cache.invalidated(), ","); got != want {
+		t.Errorf("Expected keys %s, got %s", want, got)
+	}
+}
+
+func TestRule_MissingField(t *testing.T) {
+	bus := goevent.New()
+	cache := &testCache{}
+	New(bus, cache).Rule("user.updated", "user:{userId}")
+
+	handle := bus.Dispatch(&goevent.GenericEvent{EventName: "user.updated", Data: map[string]any{}})
+	if errs := handle.GetErrors(); len(errs) != 1 || !strings.Contains(errs[0].Error(), `"userId"`) {
+		t.Errorf("Expected a missing field error, got %v", errs)
+	}
+	if keys := cache.invalidated(); len(keys) != 0 {
+		t.Errorf("Expected nothing to be invalidated, got %v", keys)
+	}
+}
+
+func TestRule_InvalidPattern(t *testing.T) {
+	inv := New(goevent.New(), &testCache{})
+	if err := inv.Rule("user.updated", "user:{userId"); err == nil {
+		t.Error("Expected an unclosed placeholder to fail")
+	}
+	if err := inv.Rule("user.updated", "user:{}"); err == nil {
+		t.Error("Expected an empty placeholder to fail")
+	}
+}
+
+func TestWithBroadcast_InvalidatesOtherNodes(t *testing.T) {
+	busA, busB := goevent.New(), goevent.New()
+	cacheA, cacheB := &testCache{}, &testCache{}
+	invA := New(busA, cacheA, WithBroadcast("a"))
+	New(busB, cacheB, WithBroadcast("b"))
+	invA.Rule("user.updated", "user:{userId}")
+	busA.RegisterListener(&testForwarder{to: busB})
+
+	handle := busA.Dispatch(&goevent.GenericEvent{EventName: "user.updated", Data: map[string]any{"userId": "7"}})
+	if keys := cacheA.invalidated(); len(keys) != 1 {
+		t.Errorf("Expected the local cache to be invalidated before Dispatch returns, got %v", keys)
+	}
+	handle.Wait()
+	busA.Wait()
+
+	if keys := cacheA.invalidated(); len(keys) != 1 || keys[0] != "user:7" {
+		t.Errorf("Expected the local cache to ignore its own broadcast, got %v", keys)
+	}
+	if keys := cacheB.invalidated(); len(keys) != 1 || keys[0] != "user:7" {
+		t.Errorf("Expected the remote cache to be invalidated, got %v", keys)
+	}
+}
+
+func TestRedis_DeletesKeys(t *testing.T) {
+	server := miniredis.RunT(t)
+	client := redis.NewClient(&redis.Options{Addr: server.Addr()})
+	defer client.Close()
+	server.Set("user:1", "cached")
+	server.Set("user:2", "cached")
+
+	bus := goevent.New()
+	New(bus, Redis(client)).Rule("user.updated", "user:{id}")
+	bus.Dispatch(&goevent.GenericEvent{EventName: "user.updated", Data: map[string]any{"id": 1}})
+
+	if server.Exists("user:1") {
+		t.Error("Expected user:1 to be deleted")
+	}
+	if !server.Exists("user:2") {
+		t.Error("Expected user:2 to be kept")
+	}
+}
@@ -0,0 +1,49 @@
+package goevent
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+// Calendar excludes days from a schedule, such as public holidays
+type Calendar interface {
+	// Excludes reports whether a run at t should be skipped. t is in
+	// the location of the schedule.
+	Excludes(t time.Time) bool
+}
+
+// CalendarFunc adapts a function to a Calendar
+type CalendarFunc func(t time.Time) bool
+
+// Excludes calls f(t)
+func (f CalendarFunc) Excludes(t time.Time) bool {
+	return f(t)
+}
+
+// Dates is a Calendar excluding whole days, keyed as "2006-01-02"
+type Dates map[string]bool
+
+// ExcludeDates returns a Calendar excluding the given days, written as
+// "2006-01-02". Days are compared on the schedule's wall clock.
+func ExcludeDates(days ...string) (Dates, error) {
+	dates := make(Dates, len(days))
+	for _, day := range days {
+		day = strings.TrimSpace(day)
+		if _, err := time.Parse(time.DateOnly, day); err != nil {
+			return nil, fmt.Errorf("goevent: calendar: %w", err)
+		}
+		dates[day] = true
+	}
+	return dates, nil
+}
+
+// Excludes reports whether the day of t is in the set
+func (d Dates) Excludes(t time.Time) bool {
+	return d[t.Format(time.DateOnly)]
+}
+
+// ExcludeWeekends is a Calendar skipping Saturdays and Sundays
+var ExcludeWeekends Calendar = CalendarFunc(func(t time.Time) bool {
+	return t.Weekday() == time.Saturday || t.Weekday() == time.Sunday
+})
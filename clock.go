@@ -0,0 +1,112 @@
+package goevent
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// Clock tells time for everything the bus schedules: the scheduler,
+// debounce, digests and batches, throttling, rate limits, retry backoff
+// and listener timeouts. Tests can supply a fake clock with WithClock to
+// exercise them without waiting for real time to pass.
+type Clock interface {
+	Now() time.Time
+	After(d time.Duration) <-chan time.Time
+	// AfterFunc calls f on its own goroutine once d has elapsed
+	AfterFunc(d time.Duration, f func()) Timer
+}
+
+// Timer is a call scheduled with Clock.AfterFunc
+type Timer interface {
+	// Stop prevents the call, reporting false if it already happened
+	Stop() bool
+}
+
+type realClock struct{}
+
+func (realClock) Now() time.Time {
+	return time.Now()
+}
+
+func (realClock) After(d time.Duration) <-chan time.Time {
+	return time.After(d)
+}
+
+func (realClock) AfterFunc(d time.Duration, f func()) Timer {
+	return time.AfterFunc(d, f)
+}
+
+// WithClock sets the clock the bus tells time with
+func WithClock(clock Clock) Option {
+	return func(ge *GoEvent) {
+		ge.clock = clock
+	}
+}
+
+// withTimeout is context.WithTimeout on the bus clock
+func (ge *GoEvent) withTimeout(parent context.Context, timeout time.Duration) (context.Context, context.CancelFunc) {
+	if _, ok := ge.clock.(realClock); ok {
+		return context.WithTimeout(parent, timeout)
+	}
+
+	ctx := &clockContext{
+		Context:  parent,
+		deadline: ge.clock.Now().Add(timeout),
+		done:     make(chan struct{}),
+	}
+	if d, ok := parent.Deadline(); ok && d.Before(ctx.deadline) {
+		ctx.deadline = d
+	}
+	if timeout <= 0 {
+		ctx.cancel(context.DeadlineExceeded)
+	}
+	timer := ge.clock.AfterFunc(timeout, func() { ctx.cancel(context.DeadlineExceeded) })
+	stop := context.AfterFunc(parent, func() { ctx.cancel(parent.Err()) })
+	return ctx, func() {
+		timer.Stop()
+		stop()
+		ctx.cancel(context.Canceled)
+	}
+}
+
+// withDeadline is context.WithDeadline on the bus clock
+func (ge *GoEvent) withDeadline(parent context.Context, deadline time.Time) (context.Context, context.CancelFunc) {
+	if _, ok := ge.clock.(realClock); ok {
+		return context.WithDeadline(parent, deadline)
+	}
+	return ge.withTimeout(parent, deadline.Sub(ge.clock.Now()))
+}
+
+// clockContext is a context whose deadline is measured by a Clock
+type clockContext struct {
+	context.Context
+	deadline time.Time
+	done     chan struct{}
+
+	mu  sync.Mutex
+	err error
+}
+
+func (c *clockContext) Deadline() (time.Time, bool) {
+	return c.deadline, true
+}
+
+func (c *clockContext) Done() <-chan struct{} {
+	return c.done
+}
+
+func (c *clockContext) Err() error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.err
+}
+
+func (c *clockContext) cancel(err error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.err == nil {
+		c.err = err
+		close(c.done)
+	}
+}
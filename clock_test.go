@@ -0,0 +1,106 @@
+package goevent
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+// testTimeoutListener blocks until its context is done
+type testTimeoutListener struct {
+	timeout time.Duration
+}
+
+func (l *testTimeoutListener) EventName() string {
+	return "test.event"
+}
+
+func (l *testTimeoutListener) OnEvent(event Event) error {
+	return l.OnEventContext(context.Background(), event)
+}
+
+func (l *testTimeoutListener) OnEventContext(ctx context.Context, event Event) error {
+	select {
+	case <-ctx.Done():
+		return nil
+	case <-time.After(5 * time.Second):
+		return errors.New("context was not canceled")
+	}
+}
+
+func (l *testTimeoutListener) Options() ListenerOptions {
+	return ListenerOptions{Timeout: l.timeout}
+}
+
+func TestClock_Debounce(t *testing.T) {
+	clock := newFakeClock(time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC))
+	evt := New(WithClock(clock))
+	listener := &testDebouncedListener{delay: time.Minute}
+	evt.RegisterListener(listener)
+
+	for _, data := range []string{"a", "b", "c"} {
+		evt.Dispatch(&TestEvent{data: data})
+	}
+	clock.Advance(59 * time.Second)
+	if len(listener.received()) != 0 {
+		t.Fatalf("Expected no call before the quiet period, got %d", len(listener.received()))
+	}
+
+	clock.Advance(time.Second)
+	evt.Wait()
+	if events := listener.received(); len(events) != 1 || events[0].Payload()["data"] != "c" {
+		t.Errorf("Expected one call with the latest event, got %v", events)
+	}
+}
+
+func TestClock_Throttle(t *testing.T) {
+	clock := newFakeClock(time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC))
+	evt := New(WithClock(clock))
+	listener := &testSampledListener{throttle: time.Minute}
+	evt.RegisterListener(listener)
+
+	evt.Dispatch(&TestEvent{})
+	evt.Dispatch(&TestEvent{})
+	clock.Advance(time.Minute)
+	evt.Dispatch(&TestEvent{})
+
+	if listener.Count() != 2 {
+		t.Errorf("Expected 2 calls, got %d", listener.Count())
+	}
+}
+
+func TestClock_Timeout(t *testing.T) {
+	clock := newFakeClock(time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC))
+	evt := New(WithClock(clock))
+	evt.RegisterListener(&testTimeoutListener{timeout: time.Hour})
+
+	handles := make(chan *DispatchHandle)
+	go func() { handles <- evt.Dispatch(&TestEvent{}) }()
+	clock.BlockUntil(t, 1)
+	clock.Advance(time.Hour)
+
+	errs := (<-handles).GetErrors()
+	if len(errs) != 1 || !errors.Is(errs[0].Err, context.DeadlineExceeded) {
+		t.Errorf("Expected context.DeadlineExceeded, got %v", errs)
+	}
+}
+
+func TestClock_RetryBackoff(t *testing.T) {
+	clock := newFakeClock(time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC))
+	evt := New(WithClock(clock))
+	listener := &testFlakyListener{
+		failures: 1,
+		policy:   RetryPolicy{MaxAttempts: 2, Backoff: ConstantBackoff(time.Hour)},
+	}
+	evt.RegisterListener(listener)
+
+	handles := make(chan *DispatchHandle)
+	go func() { handles <- evt.Dispatch(&TestEvent{}) }()
+	clock.BlockUntil(t, 1)
+	clock.Advance(time.Hour)
+
+	if errs := (<-handles).GetErrors(); len(errs) != 0 {
+		t.Errorf("Expected the retry to succeed, got %v", errs)
+	}
+}
@@ -0,0 +1,113 @@
+package goevent
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+)
+
+// Envelope carries an event together with the details a store or
+// transport needs to move it between processes
+type Envelope struct {
+	Metadata
+	Name  string
+	Event Event
+}
+
+// NewEnvelope wraps an event with a new ID and the current time
+func NewEnvelope(event Event) Envelope {
+	return Envelope{
+		Metadata: Metadata{ID: newID(), Time: time.Now()},
+		Name:     event.Name(),
+		Event:    event,
+	}
+}
+
+// Codec converts envelopes to and from bytes
+type Codec interface {
+	Marshal(env Envelope) ([]byte, error)
+	Unmarshal(data []byte) (Envelope, error)
+}
+
+// JSONCodec encodes envelopes as JSON objects, with the event payload
+// under "payload". Events are decoded with DecodeEvent.
+type JSONCodec struct{}
+
+type jsonEnvelope struct {
+	ID            string            `json:"id"`
+	Name          string            `json:"name"`
+	Time          time.Time         `json:"time"`
+	Deadline      *time.Time        `json:"deadline,omitempty"`
+	CorrelationID string            `json:"correlation_id,omitempty"`
+	CausationID   string            `json:"causation_id,omitempty"`
+	Source        string            `json:"source,omitempty"`
+	Tenant        string            `json:"tenant,omitempty"`
+	Priority      Priority          `json:"priority,omitempty"`
+	Headers       map[string]string `json:"headers,omitempty"`
+	Payload       map[string]any    `json:"payload,omitempty"`
+}
+
+// Marshal encodes env as JSON
+func (JSONCodec) Marshal(env Envelope) ([]byte, error) {
+	if env.Event == nil {
+		return nil, errors.New("goevent: marshal: envelope has no event")
+	}
+	name := env.Name
+	if name == "" {
+		name = env.Event.Name()
+	}
+	raw := jsonEnvelope{
+		ID:            env.ID,
+		Name:          name,
+		Time:          env.Time,
+		CorrelationID: env.CorrelationID,
+		CausationID:   env.CausationID,
+		Source:        env.Source,
+		Tenant:        env.Tenant,
+		Priority:      env.Priority,
+		Headers:       env.Headers,
+		Payload:       env.Event.Payload(),
+	}
+	if !env.Deadline.IsZero() {
+		raw.Deadline = &env.Deadline
+	}
+	data, err := json.Marshal(raw)
+	if err != nil {
+		return nil, fmt.Errorf("goevent: marshal %s: %w", name, err)
+	}
+	return data, nil
+}
+
+// Unmarshal decodes a JSON envelope
+func (JSONCodec) Unmarshal(data []byte) (Envelope, error) {
+	var raw jsonEnvelope
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return Envelope{}, fmt.Errorf("goevent: unmarshal: %w", err)
+	}
+	if raw.Name == "" {
+		return Envelope{}, errors.New("goevent: unmarshal: envelope has no event name")
+	}
+	event, err := DecodeEvent(raw.Name, raw.Payload)
+	if err != nil {
+		return Envelope{}, err
+	}
+	env := Envelope{
+		Metadata: Metadata{
+			ID:            raw.ID,
+			Time:          raw.Time,
+			CorrelationID: raw.CorrelationID,
+			CausationID:   raw.CausationID,
+			Source:        raw.Source,
+			Tenant:        raw.Tenant,
+			Priority:      raw.Priority,
+			Headers:       raw.Headers,
+		},
+		Name:  raw.Name,
+		Event: event,
+	}
+	if raw.Deadline != nil {
+		env.Deadline = *raw.Deadline
+	}
+	return env, nil
+}
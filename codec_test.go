@@ -0,0 +1,49 @@
+package goevent
+
+import (
+	"testing"
+	"time"
+)
+
+func TestJSONCodec_RoundTrip(t *testing.T) {
+	env := NewEnvelope(&TestEvent{data: "hello"})
+	env.CorrelationID = "req-1"
+	env.CausationID = "evt-0"
+	env.Priority = PriorityHigh
+	env.Deadline = env.Time.Add(time.Minute)
+	env.Source = "billing"
+	env.SetHeader("region", "eu")
+
+	var codec JSONCodec
+	data, err := codec.Marshal(env)
+	if err != nil {
+		t.Fatalf("Marshal() failed: %v", err)
+	}
+	decoded, err := codec.Unmarshal(data)
+	if err != nil {
+		t.Fatalf("Unmarshal() failed: %v", err)
+	}
+
+	if decoded.ID != env.ID || decoded.Name != "test.event" || !decoded.Time.Equal(env.Time) {
+		t.Errorf("Expected %s %s %v, got %s %s %v", env.ID, env.Name, env.Time, decoded.ID, decoded.Name, decoded.Time)
+	}
+	if decoded.CorrelationID != "req-1" || decoded.CausationID != "evt-0" || decoded.Priority != PriorityHigh || !decoded.Deadline.Equal(env.Deadline) || decoded.Source != "billing" || decoded.Header("region") != "eu" {
+		t.Errorf("Expected metadata to survive, got %+v", decoded.Metadata)
+	}
+	if decoded.Event.Name() != "test.event" || decoded.Event.Payload()["data"] != "hello" {
+		t.Errorf("Expected test.event with data hello, got %s %v", decoded.Event.Name(), decoded.Event.Payload())
+	}
+}
+
+func TestJSONCodec_Invalid(t *testing.T) {
+	var codec JSONCodec
+	if _, err := codec.Marshal(Envelope{}); err == nil {
+		t.Error("Expected an error for an envelope without an event")
+	}
+	if _, err := codec.Unmarshal([]byte(`{"id":"1"}`)); err == nil {
+		t.Error("Expected an error for an envelope without a name")
+	}
+	if _, err := codec.Unmarshal([]byte(`not json`)); err == nil {
+		t.Error("Expected an error for invalid JSON")
+	}
+}
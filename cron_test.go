@@ -0,0 +1,108 @@
+package goevent
+
+import (
+	"testing"
+	"time"
+)
+
+func TestCron_Next(t *testing.T) {
+	from := time.Date(2024, time.January, 31, 10, 17, 30, 0, time.UTC) // a Wednesday
+
+	tests := []struct {
+		spec string
+		want time.Time
+	}{
+		{"* * * * *", time.Date(2024, time.January, 31, 10, 18, 0, 0, time.UTC)},
+		{"0 * * * *", time.Date(2024, time.January, 31, 11, 0, 0, 0, time.UTC)},
+		{"*/15 * * * *", time.Date(2024, time.January, 31, 10, 30, 0, 0, time.UTC)},
+		{"30 9 * * *", time.Date(2024, time.February, 1, 9, 30, 0, 0, time.UTC)},
+		{"0 0 1 * *", time.Date(2024, time.February, 1, 0, 0, 0, 0, time.UTC)},
+		{"0 0 29 feb *", time.Date(2024, time.February, 29, 0, 0, 0, 0, time.UTC)},
+		{"0 9 * * mon-fri", time.Date(2024, time.February, 1, 9, 0, 0, 0, time.UTC)},
+		{"0 12 * * 7", time.Date(2024, time.February, 4, 12, 0, 0, 0, time.UTC)},
+		{"0 0 15 * mon", time.Date(2024, time.February, 5, 0, 0, 0, 0, time.UTC)},
+		{"5,45 10 * * *", time.Date(2024, time.January, 31, 10, 45, 0, 0, time.UTC)},
+		{"@daily", time.Date(2024, time.February, 1, 0, 0, 0, 0, time.UTC)},
+	}
+	for _, tt := range tests {
+		schedule, err := parseCron(tt.spec)
+		if err != nil {
+			t.Fatalf("parseCron(%q) failed: %v", tt.spec, err)
+		}
+		if got := schedule.next(from); !got.Equal(tt.want) {
+			t.Errorf("%q: Expected %v, got %v", tt.spec, tt.want, got)
+		}
+	}
+}
+
+func TestCron_Invalid(t *testing.T) {
+	for _, spec := range []string{"", "* * * *", "60 * * * *", "* 24 * * *", "*/0 * * * *", "5-1 * * * *", "* * * foo *"} {
+		if _, err := parseCron(spec); err == nil {
+			t.Errorf("Expected parseCron(%q) to fail", spec)
+		}
+	}
+}
+
+func TestCron_DaylightSaving(t *testing.T) {
+	berlin, err := time.LoadLocation("Europe/Berlin")
+	if err != nil {
+		t.Skipf("time zone data unavailable: %v", err)
+	}
+	newYork, err := time.LoadLocation("America/New_York")
+	if err != nil {
+		t.Skipf("time zone data unavailable: %v", err)
+	}
+
+	tests := []struct {
+		name string
+		spec string
+		from time.Time
+		want time.Time
+	}{
+		{
+			"skipped time fires at the transition",
+			"30 2 * * *",
+			time.Date(2024, time.March, 31, 0, 0, 0, 0, berlin),
+			time.Date(2024, time.March, 31, 1, 0, 0, 0, time.UTC),
+		},
+		{
+			"repeated time fires at its first occurrence",
+			"30 2 * * *",
+			time.Date(2024, time.October, 27, 0, 0, 0, 0, berlin),
+			time.Date(2024, time.October, 27, 0, 30, 0, 0, time.UTC),
+		},
+		{
+			"repeated time fires once",
+			"30 2 * * *",
+			time.Date(2024, time.October, 27, 0, 30, 0, 0, time.UTC).In(berlin),
+			time.Date(2024, time.October, 28, 1, 30, 0, 0, time.UTC),
+		},
+		{
+			"repeated hour is not run twice",
+			"* * * * *",
+			time.Date(2024, time.October, 27, 0, 59, 0, 0, time.UTC).In(berlin),
+			time.Date(2024, time.October, 27, 2, 0, 0, 0, time.UTC),
+		},
+		{
+			"first occurrence in other zones",
+			"30 1 * * *",
+			time.Date(2024, time.November, 3, 0, 0, 0, 0, newYork),
+			time.Date(2024, time.November, 3, 5, 30, 0, 0, time.UTC),
+		},
+		{
+			"local time across the change",
+			"0 9 * * *",
+			time.Date(2024, time.March, 30, 12, 0, 0, 0, berlin),
+			time.Date(2024, time.March, 31, 7, 0, 0, 0, time.UTC),
+		},
+	}
+	for _, tt := range tests {
+		schedule, err := parseCron(tt.spec)
+		if err != nil {
+			t.Fatalf("parseCron(%q) failed: %v", tt.spec, err)
+		}
+		if got := schedule.next(tt.from); !got.Equal(tt.want) {
+			t.Errorf("%s: Expected %v, got %v", tt.name, tt.want, got.UTC())
+		}
+	}
+}
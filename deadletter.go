@@ -0,0 +1,508 @@
+package goevent
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+)
+
+// ErrDeadLetterNotFound is returned when a dead letter ID is unknown
+var ErrDeadLetterNotFound = errors.New("goevent: dead letter not found")
+
+// ErrArchiveUnsupported is returned by archive operations when the
+// dead-letter store does not implement DeadLetterArchiver
+var ErrArchiveUnsupported = errors.New("goevent: dead-letter store does not support archiving")
+
+// DeadLetter is an event that a listener failed to handle after
+// exhausting its retries or panicking
+type DeadLetter struct {
+	ID    string
+	Event Event
+	Error *EventError
+	Time  time.Time
+
+	// Reason and ArchivedAt are set once the letter is archived
+	Reason     string
+	ArchivedAt time.Time
+}
+
+// Archived reports whether the letter was archived
+func (l DeadLetter) Archived() bool {
+	return !l.ArchivedAt.IsZero()
+}
+
+// DeadLetterStore persists dead letters
+type DeadLetterStore interface {
+	// Add stores a dead letter
+	Add(letter DeadLetter) error
+	// List returns all stored dead letters, oldest first
+	List() ([]DeadLetter, error)
+	// Remove deletes a dead letter, returning ErrDeadLetterNotFound if absent
+	Remove(id string) error
+}
+
+// DeadLetterArchiver is implemented by dead-letter stores that keep
+// resolved letters in an archive instead of deleting them
+type DeadLetterArchiver interface {
+	// Archive moves a dead letter to the archive, recording why and when
+	Archive(id, reason string, at time.Time) error
+	// ListArchived returns all archived dead letters, oldest first
+	ListArchived() ([]DeadLetter, error)
+	// RemoveArchived deletes an archived dead letter, returning
+	// ErrDeadLetterNotFound if absent
+	RemoveArchived(id string) error
+}
+
+// ReasonExpired is the reason recorded for dead letters archived by
+// WithDeadLetterAutoArchive
+const ReasonExpired = "expired"
+
+// DeadLetterQueue provides access to the dead letters of a bus
+type DeadLetterQueue struct {
+	ge          *GoEvent
+	store       DeadLetterStore
+	autoArchive time.Duration
+}
+
+// WithDeadLetterStore replaces the default in-memory dead-letter storage
+func WithDeadLetterStore(store DeadLetterStore) Option {
+	return func(ge *GoEvent) {
+		ge.deadLetters.store = store
+	}
+}
+
+// WithDeadLetterAutoArchive archives dead letters that are still open
+// after the given age with the reason ReasonExpired. Letters are checked
+// whenever one is added or the queue is listed. The store must implement
+// DeadLetterArchiver.
+func WithDeadLetterAutoArchive(after time.Duration) Option {
+	return func(ge *GoEvent) {
+		ge.deadLetters.autoArchive = after
+	}
+}
+
+// DeadLetters returns the dead-letter queue of the bus
+func (ge *GoEvent) DeadLetters() *DeadLetterQueue {
+	return &ge.deadLetters
+}
+
+// List returns all open dead letters, oldest first
+func (q *DeadLetterQueue) List() ([]DeadLetter, error) {
+	if err := q.expire(); err != nil {
+		return nil, err
+	}
+	return q.store.List()
+}
+
+// Resolve archives an open dead letter, recording why it needs no
+// further attention
+func (q *DeadLetterQueue) Resolve(id, reason string) error {
+	archiver, ok := q.store.(DeadLetterArchiver)
+	if !ok {
+		return ErrArchiveUnsupported
+	}
+	return archiver.Archive(id, reason, q.ge.clock.Now())
+}
+
+// Archived returns all archived dead letters, oldest first
+func (q *DeadLetterQueue) Archived() ([]DeadLetter, error) {
+	archiver, ok := q.store.(DeadLetterArchiver)
+	if !ok {
+		return nil, ErrArchiveUnsupported
+	}
+	return archiver.ListArchived()
+}
+
+// ExportArchived writes every archived dead letter to w as one JSON
+// object per line and removes the exported letters from the archive.
+// It returns the number of letters exported. Letters are only removed
+// once they were written.
+func (q *DeadLetterQueue) ExportArchived(w io.Writer) (int, error) {
+	archiver, ok := q.store.(DeadLetterArchiver)
+	if !ok {
+		return 0, ErrArchiveUnsupported
+	}
+	letters, err := archiver.ListArchived()
+	if err != nil {
+		return 0, err
+	}
+
+	enc := json.NewEncoder(w)
+	for i, letter := range letters {
+		if err := enc.Encode(recordOf(letter)); err != nil {
+			return i, err
+		}
+	}
+	for i, letter := range letters {
+		if err := archiver.RemoveArchived(letter.ID); err != nil {
+			return i, err
+		}
+	}
+	return len(letters), nil
+}
+
+// expire archives open letters older than the auto-archive age
+func (q *DeadLetterQueue) expire() error {
+	if q.autoArchive <= 0 {
+		return nil
+	}
+	archiver, ok := q.store.(DeadLetterArchiver)
+	if !ok {
+		return ErrArchiveUnsupported
+	}
+	letters, err := q.store.List()
+	if err != nil {
+		return err
+	}
+
+	now := q.ge.clock.Now()
+	for _, letter := range letters {
+		if now.Sub(letter.Time) < q.autoArchive {
+			continue
+		}
+		if err := archiver.Archive(letter.ID, ReasonExpired, now); err != nil && !errors.Is(err, ErrDeadLetterNotFound) {
+			return err
+		}
+	}
+	return nil
+}
+
+// Redispatch removes a dead letter and dispatches its event again
+func (q *DeadLetterQueue) Redispatch(id string) (*DispatchHandle, error) {
+	letters, err := q.store.List()
+	if err != nil {
+		return nil, err
+	}
+
+	for _, letter := range letters {
+		if letter.ID != id {
+			continue
+		}
+		if err := q.store.Remove(id); err != nil {
+			return nil, err
+		}
+		return q.ge.Dispatch(letter.Event), nil
+	}
+
+	return nil, ErrDeadLetterNotFound
+}
+
+// Drain removes and returns all dead letters
+func (q *DeadLetterQueue) Drain() ([]DeadLetter, error) {
+	letters, err := q.store.List()
+	if err != nil {
+		return nil, err
+	}
+
+	for _, letter := range letters {
+		if err := q.store.Remove(letter.ID); err != nil {
+			return nil, err
+		}
+	}
+	return letters, nil
+}
+
+// isDeadLetter reports whether a listener failure should be dead-lettered:
+// the listener panicked or exhausted its retry policy
+func isDeadLetter(eventErr *EventError, policy RetryPolicy) bool {
+	if _, ok := eventErr.Err.(*PanicError); ok {
+		return true
+	}
+	return policy.MaxAttempts > 1 && eventErr.Attempts >= policy.MaxAttempts
+}
+
+// deadLetter routes a failed event into the dead-letter queue
+func (ge *GoEvent) deadLetter(event Event, eventErr *EventError) {
+	err := ge.deadLetters.store.Add(DeadLetter{
+		ID:    newID(),
+		Event: event,
+		Error: eventErr,
+		Time:  ge.clock.Now(),
+	})
+	if err == nil {
+		err = ge.deadLetters.expire()
+	}
+	if err != nil {
+		ge.recordError(&EventError{
+			EventName:    eventErr.EventName,
+			ListenerType: eventErr.ListenerType,
+			DispatchID:   eventErr.DispatchID,
+			EventID:      eventErr.EventID,
+			DispatchedAt: eventErr.DispatchedAt,
+			Err:          fmt.Errorf("dead-letter store: %w", err),
+		})
+	}
+}
+
+// MemoryDeadLetterStore keeps dead letters in memory
+type MemoryDeadLetterStore struct {
+	mu       sync.Mutex
+	letters  []DeadLetter
+	archived []DeadLetter
+}
+
+// NewMemoryDeadLetterStore creates an empty in-memory dead-letter store
+func NewMemoryDeadLetterStore() *MemoryDeadLetterStore {
+	return &MemoryDeadLetterStore{}
+}
+
+// Add stores a dead letter
+func (s *MemoryDeadLetterStore) Add(letter DeadLetter) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.letters = append(s.letters, letter)
+	return nil
+}
+
+// List returns all stored dead letters, oldest first
+func (s *MemoryDeadLetterStore) List() ([]DeadLetter, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	lettersCopy := make([]DeadLetter, len(s.letters))
+	copy(lettersCopy, s.letters)
+	return lettersCopy, nil
+}
+
+// Remove deletes a dead letter
+func (s *MemoryDeadLetterStore) Remove(id string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	var ok bool
+	s.letters, _, ok = removeLetter(s.letters, id)
+	if !ok {
+		return ErrDeadLetterNotFound
+	}
+	return nil
+}
+
+// Archive moves a dead letter to the archive
+func (s *MemoryDeadLetterStore) Archive(id, reason string, at time.Time) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.archive(id, reason, at)
+}
+
+// archive moves a dead letter to the archive. The caller must hold mu.
+func (s *MemoryDeadLetterStore) archive(id, reason string, at time.Time) error {
+	letters, letter, ok := removeLetter(s.letters, id)
+	if !ok {
+		return ErrDeadLetterNotFound
+	}
+	letter.Reason = reason
+	letter.ArchivedAt = at
+	s.letters = letters
+	s.archived = append(s.archived, letter)
+	return nil
+}
+
+// ListArchived returns all archived dead letters, oldest first
+func (s *MemoryDeadLetterStore) ListArchived() ([]DeadLetter, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return append([]DeadLetter(nil), s.archived...), nil
+}
+
+// RemoveArchived deletes an archived dead letter
+func (s *MemoryDeadLetterStore) RemoveArchived(id string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	var ok bool
+	s.archived, _, ok = removeLetter(s.archived, id)
+	if !ok {
+		return ErrDeadLetterNotFound
+	}
+	return nil
+}
+
+// removeLetter removes the letter with the given ID from letters
+func removeLetter(letters []DeadLetter, id string) ([]DeadLetter, DeadLetter, bool) {
+	for i, letter := range letters {
+		if letter.ID == id {
+			return append(letters[:i], letters[i+1:]...), letter, true
+		}
+	}
+	return letters, DeadLetter{}, false
+}
+
+// FileDeadLetterStore keeps dead letters in memory and mirrors them to a
+// JSON file so they survive restarts. Events loaded from the file are
+// reconstructed as their registered type, or as *GenericEvent, and errors
+// keep only their message.
+type FileDeadLetterStore struct {
+	path   string
+	memory MemoryDeadLetterStore
+}
+
+type fileDeadLetter struct {
+	ID           string         `json:"id"`
+	EventName    string         `json:"event_name"`
+	Payload      map[string]any `json:"payload"`
+	ListenerType string         `json:"listener_type"`
+	Error        string         `json:"error"`
+	Attempts     int            `json:"attempts"`
+	Time         time.Time      `json:"time"`
+	Reason       string         `json:"reason,omitempty"`
+	ArchivedAt   *time.Time     `json:"archived_at,omitempty"`
+}
+
+// recordOf converts a dead letter to its JSON form
+func recordOf(letter DeadLetter) fileDeadLetter {
+	record := fileDeadLetter{
+		ID:           letter.ID,
+		EventName:    letter.Event.Name(),
+		Payload:      letter.Event.Payload(),
+		ListenerType: letter.Error.ListenerType,
+		Error:        letter.Error.Err.Error(),
+		Attempts:     letter.Error.Attempts,
+		Time:         letter.Time,
+		Reason:       letter.Reason,
+	}
+	if letter.Archived() {
+		record.ArchivedAt = &letter.ArchivedAt
+	}
+	return record
+}
+
+// letter converts a record back to a dead letter
+func (r fileDeadLetter) letter() DeadLetter {
+	letter := DeadLetter{
+		ID:    r.ID,
+		Event: decodeOrGeneric(r.EventName, r.Payload),
+		Error: &EventError{
+			EventName:    r.EventName,
+			ListenerType: r.ListenerType,
+			Err:          errors.New(r.Error),
+			Attempts:     r.Attempts,
+		},
+		Time:   r.Time,
+		Reason: r.Reason,
+	}
+	if r.ArchivedAt != nil {
+		letter.ArchivedAt = *r.ArchivedAt
+	}
+	return letter
+}
+
+// NewFileDeadLetterStore opens the store at path, loading any dead letters
+// already stored there
+func NewFileDeadLetterStore(path string) (*FileDeadLetterStore, error) {
+	s := &FileDeadLetterStore{path: path}
+
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return s, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	var records []fileDeadLetter
+	if err := json.Unmarshal(data, &records); err != nil {
+		return nil, fmt.Errorf("goevent: decoding dead letters from %s: %w", path, err)
+	}
+
+	for _, r := range records {
+		letter := r.letter()
+		if letter.Archived() {
+			s.memory.archived = append(s.memory.archived, letter)
+		} else {
+			s.memory.letters = append(s.memory.letters, letter)
+		}
+	}
+	return s, nil
+}
+
+// Add stores a dead letter
+func (s *FileDeadLetterStore) Add(letter DeadLetter) error {
+	s.memory.mu.Lock()
+	defer s.memory.mu.Unlock()
+
+	s.memory.letters = append(s.memory.letters, letter)
+	return s.persist()
+}
+
+// List returns all stored dead letters, oldest first
+func (s *FileDeadLetterStore) List() ([]DeadLetter, error) {
+	return s.memory.List()
+}
+
+// Remove deletes a dead letter
+func (s *FileDeadLetterStore) Remove(id string) error {
+	s.memory.mu.Lock()
+	defer s.memory.mu.Unlock()
+
+	var ok bool
+	s.memory.letters, _, ok = removeLetter(s.memory.letters, id)
+	if !ok {
+		return ErrDeadLetterNotFound
+	}
+	return s.persist()
+}
+
+// Archive moves a dead letter to the archive
+func (s *FileDeadLetterStore) Archive(id, reason string, at time.Time) error {
+	s.memory.mu.Lock()
+	defer s.memory.mu.Unlock()
+
+	if err := s.memory.archive(id, reason, at); err != nil {
+		return err
+	}
+	return s.persist()
+}
+
+// ListArchived returns all archived dead letters, oldest first
+func (s *FileDeadLetterStore) ListArchived() ([]DeadLetter, error) {
+	return s.memory.ListArchived()
+}
+
+// RemoveArchived deletes an archived dead letter
+func (s *FileDeadLetterStore) RemoveArchived(id string) error {
+	s.memory.mu.Lock()
+	defer s.memory.mu.Unlock()
+
+	var ok bool
+	s.memory.archived, _, ok = removeLetter(s.memory.archived, id)
+	if !ok {
+		return ErrDeadLetterNotFound
+	}
+	return s.persist()
+}
+
+// persist atomically rewrites the file. The caller must hold memory.mu.
+func (s *FileDeadLetterStore) persist() error {
+	records := make([]fileDeadLetter, 0, len(s.memory.letters)+len(s.memory.archived))
+	for _, letter := range s.memory.letters {
+		records = append(records, recordOf(letter))
+	}
+	for _, letter := range s.memory.archived {
+		records = append(records, recordOf(letter))
+	}
+
+	data, err := json.MarshalIndent(records, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	tmp, err := os.CreateTemp(filepath.Dir(s.path), filepath.Base(s.path)+".*.tmp")
+	if err != nil {
+		return err
+	}
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		os.Remove(tmp.Name())
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		os.Remove(tmp.Name())
+		return err
+	}
+	return os.Rename(tmp.Name(), s.path)
+}
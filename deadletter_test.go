@@ -0,0 +1,222 @@
+package goevent
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestDeadLetters_PanicsAreDeadLettered(t *testing.T) {
+	evt := New()
+	evt.RegisterListener(&testPanicListener{})
+	evt.Dispatch(&TestEvent{data: "panic"})
+
+	letters, err := evt.DeadLetters().List()
+	if err != nil {
+		t.Fatalf("List() failed: %v", err)
+	}
+	if len(letters) != 1 {
+		t.Fatalf("Expected 1 dead letter, got %d", len(letters))
+	}
+	if letters[0].Event.Name() != "test.event" {
+		t.Errorf("Expected dead letter for 'test.event', got '%s'", letters[0].Event.Name())
+	}
+	if _, ok := letters[0].Error.Err.(*PanicError); !ok {
+		t.Errorf("Expected *PanicError, got %T", letters[0].Error.Err)
+	}
+}
+
+func TestDeadLetters_OnlyExhaustedRetries(t *testing.T) {
+	evt := New()
+	evt.RegisterListener(
+		&testErrorListener{},
+		&testFlakyListener{failures: 5, policy: RetryPolicy{MaxAttempts: 2}},
+	)
+	evt.Dispatch(&TestEvent{data: "fail"})
+
+	letters, _ := evt.DeadLetters().List()
+	if len(letters) != 1 {
+		t.Fatalf("Expected 1 dead letter, got %d", len(letters))
+	}
+	if letters[0].Error.Attempts != 2 {
+		t.Errorf("Expected 2 attempts, got %d", letters[0].Error.Attempts)
+	}
+}
+
+func TestDeadLetters_Redispatch(t *testing.T) {
+	evt := New()
+	listener := &testFlakyListener{failures: 2, policy: RetryPolicy{MaxAttempts: 2}}
+	evt.RegisterListener(listener)
+	evt.Dispatch(&TestEvent{data: "redispatch"})
+
+	letters, _ := evt.DeadLetters().List()
+	if len(letters) != 1 {
+		t.Fatalf("Expected 1 dead letter, got %d", len(letters))
+	}
+
+	handle, err := evt.DeadLetters().Redispatch(letters[0].ID)
+	if err != nil {
+		t.Fatalf("Redispatch() failed: %v", err)
+	}
+	if errs := handle.GetErrors(); len(errs) != 0 {
+		t.Errorf("Expected redispatch to succeed, got %v", errs)
+	}
+
+	if letters, _ := evt.DeadLetters().List(); len(letters) != 0 {
+		t.Errorf("Expected dead letter to be removed, got %d", len(letters))
+	}
+
+	if _, err := evt.DeadLetters().Redispatch("unknown"); !errors.Is(err, ErrDeadLetterNotFound) {
+		t.Errorf("Expected ErrDeadLetterNotFound, got %v", err)
+	}
+}
+
+func TestDeadLetters_Drain(t *testing.T) {
+	evt := New()
+	evt.RegisterListener(&testPanicListener{})
+	evt.Dispatch(&TestEvent{data: "one"})
+	evt.Dispatch(&TestEvent{data: "two"})
+
+	drained, err := evt.DeadLetters().Drain()
+	if err != nil {
+		t.Fatalf("Drain() failed: %v", err)
+	}
+	if len(drained) != 2 {
+		t.Errorf("Expected 2 drained dead letters, got %d", len(drained))
+	}
+	if letters, _ := evt.DeadLetters().List(); len(letters) != 0 {
+		t.Errorf("Expected empty queue after Drain(), got %d", len(letters))
+	}
+}
+
+func TestFileDeadLetterStore_SurvivesReopen(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "dead-letters.json")
+
+	store, err := NewFileDeadLetterStore(path)
+	if err != nil {
+		t.Fatalf("NewFileDeadLetterStore() failed: %v", err)
+	}
+	evt := New(WithDeadLetterStore(store))
+	evt.RegisterListener(&testPanicListener{})
+	evt.Dispatch(&TestEvent{data: "persisted"})
+
+	reopened, err := NewFileDeadLetterStore(path)
+	if err != nil {
+		t.Fatalf("Reopening store failed: %v", err)
+	}
+	letters, _ := reopened.List()
+	if len(letters) != 1 {
+		t.Fatalf("Expected 1 dead letter after reopen, got %d", len(letters))
+	}
+	if letters[0].Event.Payload()["data"] != "persisted" {
+		t.Errorf("Expected payload data 'persisted', got '%v'", letters[0].Event.Payload()["data"])
+	}
+	if letters[0].Error.Err.Error() != "panic: listener exploded" {
+		t.Errorf("Expected error message to survive, got '%s'", letters[0].Error.Err)
+	}
+
+	if err := reopened.Remove(letters[0].ID); err != nil {
+		t.Fatalf("Remove() failed: %v", err)
+	}
+	again, _ := NewFileDeadLetterStore(path)
+	if letters, _ := again.List(); len(letters) != 0 {
+		t.Errorf("Expected removal to be persisted, got %d", len(letters))
+	}
+}
+
+func TestDeadLetters_ResolveAndExport(t *testing.T) {
+	evt := New()
+	evt.RegisterListener(&testPanicListener{})
+	evt.Dispatch(&TestEvent{data: "one"})
+	evt.Dispatch(&TestEvent{data: "two"})
+
+	letters, _ := evt.DeadLetters().List()
+	if err := evt.DeadLetters().Resolve(letters[0].ID, "fixed upstream"); err != nil {
+		t.Fatalf("Resolve() failed: %v", err)
+	}
+	if err := evt.DeadLetters().Resolve("unknown", "n/a"); !errors.Is(err, ErrDeadLetterNotFound) {
+		t.Errorf("Expected ErrDeadLetterNotFound, got %v", err)
+	}
+
+	if open, _ := evt.DeadLetters().List(); len(open) != 1 || open[0].ID != letters[1].ID {
+		t.Errorf("Expected only the unresolved letter to stay open, got %v", open)
+	}
+	archived, err := evt.DeadLetters().Archived()
+	if err != nil {
+		t.Fatalf("Archived() failed: %v", err)
+	}
+	if len(archived) != 1 || archived[0].Reason != "fixed upstream" || !archived[0].Archived() {
+		t.Fatalf("Expected the resolved letter in the archive, got %+v", archived)
+	}
+
+	var buf bytes.Buffer
+	n, err := evt.DeadLetters().ExportArchived(&buf)
+	if err != nil || n != 1 {
+		t.Fatalf("Expected 1 exported letter, got %d (%v)", n, err)
+	}
+	var record map[string]any
+	if err := json.Unmarshal(buf.Bytes(), &record); err != nil {
+		t.Fatalf("Expected a JSON line, got %q", buf.String())
+	}
+	if record["id"] != letters[0].ID || record["reason"] != "fixed upstream" {
+		t.Errorf("Expected the exported letter and its reason, got %v", record)
+	}
+	if archived, _ := evt.DeadLetters().Archived(); len(archived) != 0 {
+		t.Errorf("Expected exported letters to leave the archive, got %d", len(archived))
+	}
+}
+
+func TestDeadLetters_AutoArchive(t *testing.T) {
+	clock := newFakeClock(time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC))
+	evt := New(WithClock(clock), WithDeadLetterAutoArchive(7*24*time.Hour))
+	evt.RegisterListener(&testPanicListener{})
+	evt.Dispatch(&TestEvent{data: "old"})
+
+	clock.Advance(6 * 24 * time.Hour)
+	evt.Dispatch(&TestEvent{data: "new"})
+	clock.Advance(24 * time.Hour)
+
+	open, _ := evt.DeadLetters().List()
+	if len(open) != 1 || open[0].Event.Payload()["data"] != "new" {
+		t.Errorf("Expected only the newer letter to stay open, got %v", open)
+	}
+	archived, _ := evt.DeadLetters().Archived()
+	if len(archived) != 1 || archived[0].Reason != ReasonExpired || !archived[0].ArchivedAt.Equal(clock.Now()) {
+		t.Errorf("Expected the older letter to expire, got %+v", archived)
+	}
+}
+
+func TestFileDeadLetterStore_Archive(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "dead-letters.json")
+	store, _ := NewFileDeadLetterStore(path)
+	evt := New(WithDeadLetterStore(store))
+	evt.RegisterListener(&testPanicListener{})
+	evt.Dispatch(&TestEvent{data: "archived"})
+
+	letters, _ := evt.DeadLetters().List()
+	if err := evt.DeadLetters().Resolve(letters[0].ID, "duplicate"); err != nil {
+		t.Fatalf("Resolve() failed: %v", err)
+	}
+
+	reopened, _ := NewFileDeadLetterStore(path)
+	if open, _ := reopened.List(); len(open) != 0 {
+		t.Errorf("Expected no open letters after reopen, got %d", len(open))
+	}
+	archived, _ := reopened.ListArchived()
+	if len(archived) != 1 || archived[0].Reason != "duplicate" || !archived[0].Archived() {
+		t.Fatalf("Expected the archived letter to survive reopen, got %+v", archived)
+	}
+
+	var buf strings.Builder
+	if _, err := New(WithDeadLetterStore(reopened)).DeadLetters().ExportArchived(&buf); err != nil {
+		t.Fatalf("ExportArchived() failed: %v", err)
+	}
+	again, _ := NewFileDeadLetterStore(path)
+	if archived, _ := again.ListArchived(); len(archived) != 0 {
+		t.Errorf("Expected the export to be persisted, got %d", len(archived))
+	}
+}
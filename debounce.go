@@ -0,0 +1,117 @@
+package goevent
+
+import (
+	"sync"
+	"time"
+)
+
+// debouncer collapses bursts of events for a single listener into one
+// call with the latest event, made once no event arrived for the delay
+type debouncer struct {
+	ge    *GoEvent
+	delay time.Duration
+	call  func(handle *DispatchHandle, event Event)
+
+	mu      sync.Mutex
+	handles []*DispatchHandle // dispatches in the current burst, oldest first
+	latest  Event
+	timer   Timer
+	gen     uint64 // incremented by every add
+}
+
+func newDebouncer(ge *GoEvent, delay time.Duration, call func(handle *DispatchHandle, event Event)) *debouncer {
+	return &debouncer{ge: ge, delay: delay, call: call}
+}
+
+// add makes event the latest of the burst and restarts the quiet period.
+// Every dispatch in the burst stays open until the burst is delivered.
+func (d *debouncer) add(handle *DispatchHandle, event Event) {
+	handle.hold(1)
+	d.ge.wg.Add(1)
+
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	d.handles = append(d.handles, handle)
+	d.latest = event
+	if d.timer != nil {
+		d.timer.Stop()
+	}
+	d.gen++
+	gen := d.gen
+	d.timer = d.ge.clock.AfterFunc(d.delay, func() { d.expire(gen) })
+}
+
+// take detaches the current burst. The caller must hold mu and must
+// release the returned handles.
+func (d *debouncer) take() ([]*DispatchHandle, Event) {
+	handles, latest := d.handles, d.latest
+	d.handles, d.latest = nil, nil
+	if d.timer != nil {
+		d.timer.Stop()
+		d.timer = nil
+	}
+	return handles, latest
+}
+
+// expire delivers the burst once its quiet period elapses, unless more
+// events arrived or it was already flushed or purged
+func (d *debouncer) expire(gen uint64) {
+	d.mu.Lock()
+	if d.gen != gen || len(d.handles) == 0 {
+		d.mu.Unlock()
+		return
+	}
+	handles, latest := d.take()
+	d.mu.Unlock()
+
+	d.deliver(handles, latest)
+}
+
+// deliver calls the listener for the latest dispatch of a burst; the
+// dispatches it superseded complete without errors of their own
+func (d *debouncer) deliver(handles []*DispatchHandle, latest Event) {
+	if len(handles) == 0 {
+		return
+	}
+	d.call(handles[len(handles)-1], latest)
+	d.release(handles)
+}
+
+func (d *debouncer) release(handles []*DispatchHandle) {
+	for _, handle := range handles {
+		handle.release()
+		d.ge.wg.Done()
+	}
+}
+
+// pendingEvents returns the event that will be delivered for the burst
+func (d *debouncer) pendingEvents() []Event {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	if d.latest == nil {
+		return nil
+	}
+	return []Event{d.latest}
+}
+
+// purge discards the current burst
+func (d *debouncer) purge() int {
+	d.mu.Lock()
+	handles, _ := d.take()
+	d.mu.Unlock()
+
+	d.release(handles)
+	return min(len(handles), 1)
+}
+
+// flush delivers the current burst immediately
+func (d *debouncer) flush() int {
+	d.mu.Lock()
+	handles, latest := d.take()
+	d.mu.Unlock()
+
+	d.deliver(handles, latest)
+	return min(len(handles), 1)
+}
@@ -0,0 +1,100 @@
+package goevent
+
+import (
+	"sync"
+	"testing"
+	"time"
+)
+
+// testDebouncedListener records the events it receives after debouncing
+type testDebouncedListener struct {
+	mu     sync.Mutex
+	events []Event
+	delay  time.Duration
+}
+
+func (l *testDebouncedListener) EventName() string {
+	return "test.event"
+}
+
+func (l *testDebouncedListener) OnEvent(event Event) error {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.events = append(l.events, event)
+	return nil
+}
+
+func (l *testDebouncedListener) Options() ListenerOptions {
+	return ListenerOptions{Debounce: l.delay}
+}
+
+func (l *testDebouncedListener) received() []Event {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return append([]Event(nil), l.events...)
+}
+
+func TestDebounce_CollapsesBurst(t *testing.T) {
+	evt := New()
+	listener := &testDebouncedListener{delay: 30 * time.Millisecond}
+	evt.RegisterListener(listener)
+
+	var handles []*DispatchHandle
+	for _, data := range []string{"a", "b", "c"} {
+		handles = append(handles, evt.Dispatch(&TestEvent{data: data}))
+	}
+	for _, handle := range handles {
+		handle.Wait()
+	}
+
+	events := listener.received()
+	if len(events) != 1 {
+		t.Fatalf("Expected 1 call, got %d", len(events))
+	}
+	if events[0].Payload()["data"] != "c" {
+		t.Errorf("Expected the latest payload 'c', got '%v'", events[0].Payload()["data"])
+	}
+}
+
+func TestDebounce_SeparateBursts(t *testing.T) {
+	evt := New()
+	listener := &testDebouncedListener{delay: 10 * time.Millisecond}
+	evt.RegisterListener(listener)
+
+	evt.Dispatch(&TestEvent{data: "a"}).Wait()
+	evt.Dispatch(&TestEvent{data: "b"}).Wait()
+
+	if len(listener.received()) != 2 {
+		t.Errorf("Expected 2 calls, got %d", len(listener.received()))
+	}
+}
+
+func TestDebounce_FlushAndPurge(t *testing.T) {
+	evt := New()
+	listener := &testDebouncedListener{delay: time.Hour}
+	evt.RegisterListener(listener)
+
+	evt.Dispatch(&TestEvent{data: "a"})
+	evt.Dispatch(&TestEvent{data: "b"})
+	if queued := evt.QueuedEvents(listener); len(queued) != 1 || queued[0].Payload()["data"] != "b" {
+		t.Fatalf("Expected only the latest event queued, got %v", queued)
+	}
+	if n := evt.FlushQueue(listener); n != 1 {
+		t.Errorf("Expected 1 flushed event, got %d", n)
+	}
+	if len(listener.received()) != 1 {
+		t.Errorf("Expected 1 call after flush, got %d", len(listener.received()))
+	}
+
+	handle := evt.Dispatch(&TestEvent{data: "c"})
+	if n := evt.PurgeQueue(listener); n != 1 {
+		t.Errorf("Expected 1 purged event, got %d", n)
+	}
+	if err := handle.WaitTimeout(time.Second); err != nil {
+		t.Errorf("Expected purged dispatch to complete, got %v", err)
+	}
+	if len(listener.received()) != 1 {
+		t.Errorf("Expected purged event not to be delivered, got %d calls", len(listener.received()))
+	}
+	evt.Wait()
+}
@@ -0,0 +1,176 @@
+package goevent
+
+import (
+	"sync"
+	"time"
+)
+
+// Tags recognized by degradation mode
+const (
+	// TagBestEffort marks an event that may be shed while degraded
+	TagBestEffort = "best-effort"
+	// TagCritical marks an event that is always delivered
+	TagCritical = "critical"
+)
+
+// ShedMode determines what happens to best-effort events while degraded
+type ShedMode int
+
+const (
+	// ShedDrop discards best-effort events
+	ShedDrop ShedMode = iota
+	// ShedDefer holds best-effort events and dispatches them once
+	// the bus leaves degradation mode
+	ShedDefer
+)
+
+// DegradationPolicy configures degradation mode
+type DegradationPolicy struct {
+	// Mode determines whether best-effort events are dropped or deferred
+	Mode ShedMode
+
+	// MaxInFlight enables automatic degradation when greater than zero.
+	// The bus degrades once more than MaxInFlight async handlers have been
+	// pending for at least SustainFor, and recovers when the load falls
+	// back to MaxInFlight or below.
+	MaxInFlight int
+	SustainFor  time.Duration
+}
+
+// ShedStats counts events affected by degradation mode
+type ShedStats struct {
+	Dropped  uint64
+	Deferred uint64
+}
+
+type deferredDispatch struct {
+	handle *DispatchHandle
+	event  Event
+}
+
+type degradation struct {
+	mu            sync.Mutex
+	policy        DegradationPolicy
+	manual        bool
+	auto          bool
+	overloadSince time.Time
+	deferred      []deferredDispatch
+	stats         ShedStats
+}
+
+// active reports whether the bus is degraded. The caller must hold mu.
+func (d *degradation) active() bool {
+	return d.manual || d.auto
+}
+
+// takeDeferred returns the deferred events if the bus is no longer
+// degraded. The caller must hold mu.
+func (d *degradation) takeDeferred() []deferredDispatch {
+	if d.active() {
+		return nil
+	}
+	deferred := d.deferred
+	d.deferred = nil
+	return deferred
+}
+
+// WithDegradation configures how the bus sheds best-effort events
+// while degraded and whether it degrades automatically under load
+func WithDegradation(policy DegradationPolicy) Option {
+	return func(ge *GoEvent) {
+		ge.degradation.policy = policy
+	}
+}
+
+// SetDegraded manually switches degradation mode on or off.
+// Deferred events are dispatched when the bus leaves degradation mode.
+func (ge *GoEvent) SetDegraded(degraded bool) {
+	ge.degradation.mu.Lock()
+	ge.degradation.manual = degraded
+	deferred := ge.degradation.takeDeferred()
+	ge.degradation.mu.Unlock()
+
+	ge.releaseDeferred(deferred)
+}
+
+// Degraded reports whether the bus is currently in degradation mode,
+// either manually or because of sustained overload
+func (ge *GoEvent) Degraded() bool {
+	ge.degradation.mu.Lock()
+	defer ge.degradation.mu.Unlock()
+	return ge.degradation.active()
+}
+
+// ShedStats returns how many best-effort events were dropped or deferred
+func (ge *GoEvent) ShedStats() ShedStats {
+	ge.degradation.mu.Lock()
+	defer ge.degradation.mu.Unlock()
+	return ge.degradation.stats
+}
+
+// shed drops or defers a best-effort event while degraded.
+// It reports whether the event was withheld from delivery.
+func (ge *GoEvent) shed(handle *DispatchHandle, event Event, cfg dispatchConfig) bool {
+	if !cfg.hasTag(TagBestEffort) || cfg.hasTag(TagCritical) {
+		return false
+	}
+
+	d := &ge.degradation
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	if !d.active() {
+		return false
+	}
+
+	if d.policy.Mode == ShedDefer {
+		// Hold the handle open until the event is actually published
+		handle.hold(1)
+		d.deferred = append(d.deferred, deferredDispatch{handle: handle, event: event})
+		d.stats.Deferred++
+		return true
+	}
+
+	ge.drop(handle)
+	d.stats.Dropped++
+	return true
+}
+
+// updateLoad enters or leaves automatic degradation based on the
+// number of in-flight async handlers
+func (ge *GoEvent) updateLoad() {
+	d := &ge.degradation
+	if d.policy.MaxInFlight <= 0 {
+		return
+	}
+	overloaded := ge.inFlight.Load() > int64(d.policy.MaxInFlight)
+
+	d.mu.Lock()
+	var deferred []deferredDispatch
+	switch {
+	case overloaded && !d.auto:
+		if d.overloadSince.IsZero() {
+			d.overloadSince = ge.clock.Now()
+		}
+		if ge.clock.Now().Sub(d.overloadSince) >= d.policy.SustainFor {
+			d.auto = true
+		}
+	case !overloaded:
+		d.overloadSince = time.Time{}
+		if d.auto {
+			d.auto = false
+			deferred = d.takeDeferred()
+		}
+	}
+	d.mu.Unlock()
+
+	ge.releaseDeferred(deferred)
+}
+
+// releaseDeferred publishes deferred events in their original order
+func (ge *GoEvent) releaseDeferred(deferred []deferredDispatch) {
+	for _, d := range deferred {
+		ge.submit(d.handle, d.event)
+		d.handle.release()
+	}
+}
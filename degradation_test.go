@@ -0,0 +1,128 @@
+package goevent
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+)
+
+type testCountingListener struct {
+	mu    sync.Mutex
+	count int
+}
+
+func (l *testCountingListener) EventName() string {
+	return "test.event"
+}
+
+func (l *testCountingListener) OnEvent(event Event) error {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.count++
+	return nil
+}
+
+func (l *testCountingListener) Count() int {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return l.count
+}
+
+type testBlockingListener struct {
+	release chan struct{}
+}
+
+func (l *testBlockingListener) EventName() string {
+	return "test.event"
+}
+
+func (l *testBlockingListener) OnEvent(event Event) error {
+	<-l.release
+	return nil
+}
+
+func (l *testBlockingListener) Options() ListenerOptions {
+	return ListenerOptions{Async: true}
+}
+
+func TestDegradation_DropsBestEffortEvents(t *testing.T) {
+	evt := New()
+	listener := &testCountingListener{}
+	evt.RegisterListener(listener)
+
+	evt.SetDegraded(true)
+	if !evt.Degraded() {
+		t.Fatal("Expected bus to be degraded")
+	}
+
+	bestEffort := evt.DispatchContext(context.Background(), &TestEvent{}, WithTags(TagBestEffort))
+	critical := evt.DispatchContext(context.Background(), &TestEvent{}, WithTags(TagBestEffort, TagCritical))
+	untagged := evt.Dispatch(&TestEvent{})
+
+	if !bestEffort.Shed() {
+		t.Error("Expected best-effort event to be shed")
+	}
+	if critical.Shed() || untagged.Shed() {
+		t.Error("Expected critical and untagged events to be delivered")
+	}
+	if listener.Count() != 2 {
+		t.Errorf("Expected 2 deliveries, got %d", listener.Count())
+	}
+	if stats := evt.ShedStats(); stats.Dropped != 1 {
+		t.Errorf("Expected 1 dropped event, got %d", stats.Dropped)
+	}
+}
+
+func TestDegradation_DefersBestEffortEvents(t *testing.T) {
+	evt := New(WithDegradation(DegradationPolicy{Mode: ShedDefer}))
+	listener := &testCountingListener{}
+	evt.RegisterListener(listener)
+
+	evt.SetDegraded(true)
+	handle := evt.DispatchContext(context.Background(), &TestEvent{}, WithTags(TagBestEffort))
+
+	if listener.Count() != 0 {
+		t.Fatal("Deferred event was delivered while degraded")
+	}
+	select {
+	case <-handle.Done():
+		t.Fatal("Deferred handle was marked done before delivery")
+	default:
+	}
+
+	evt.SetDegraded(false)
+	handle.Wait()
+
+	if listener.Count() != 1 {
+		t.Errorf("Expected deferred event to be delivered, got %d deliveries", listener.Count())
+	}
+	if stats := evt.ShedStats(); stats.Deferred != 1 {
+		t.Errorf("Expected 1 deferred event, got %d", stats.Deferred)
+	}
+}
+
+func TestDegradation_AutomaticOnOverload(t *testing.T) {
+	evt := New(WithDegradation(DegradationPolicy{MaxInFlight: 1}))
+	blocking := &testBlockingListener{release: make(chan struct{})}
+	evt.RegisterListener(blocking)
+
+	evt.Dispatch(&TestEvent{})
+	evt.Dispatch(&TestEvent{})
+
+	handle := evt.DispatchContext(context.Background(), &TestEvent{}, WithTags(TagBestEffort))
+	if !handle.Shed() || !evt.Degraded() {
+		t.Fatal("Expected bus to degrade automatically under overload")
+	}
+
+	close(blocking.release)
+	evt.Wait()
+
+	deadline := time.Now().Add(time.Second)
+	for evt.Degraded() && time.Now().Before(deadline) {
+		time.Sleep(time.Millisecond)
+	}
+	if evt.Degraded() {
+		t.Error("Expected bus to recover once load dropped")
+	}
+}
@@ -0,0 +1,184 @@
+package goevent
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"reflect"
+	"sync"
+)
+
+// DeltaWatch configures WatchDelta
+type DeltaWatch struct {
+	// Event is the name of the events to watch
+	Event string
+
+	// Field is the numeric payload field to follow
+	Field string
+
+	// Key is the payload field that separates independent series, such
+	// as a host or sensor ID. Empty follows a single series.
+	Key string
+
+	// Thresholds trigger a DeltaEvent when the value crosses any of them
+	// in either direction
+	Thresholds []float64
+
+	// ChangePercent triggers a DeltaEvent when the value changes by more
+	// than this percentage from the previous value. Zero disables it.
+	ChangePercent float64
+
+	// Emit is the name of the dispatched DeltaEvents.
+	// Defaults to Event + ".delta".
+	Emit string
+}
+
+// DeltaEvent is dispatched by WatchDelta when a watched value crosses a
+// threshold or changes by more than the configured percentage
+type DeltaEvent struct {
+	EventName     string
+	Source        Event
+	Key           any
+	Field         string
+	Previous      float64
+	Current       float64
+	ChangePercent float64   // signed change relative to Previous, ±Inf if Previous was 0
+	Crossed       []float64 // thresholds crossed, in the order they were passed
+}
+
+// Name returns the configured Emit name
+func (e *DeltaEvent) Name() string {
+	return e.EventName
+}
+
+// Payload returns the change details
+func (e *DeltaEvent) Payload() map[string]any {
+	return map[string]any{
+		"source":         e.Source.Name(),
+		"key":            e.Key,
+		"field":          e.Field,
+		"previous":       e.Previous,
+		"current":        e.Current,
+		"change_percent": e.ChangePercent,
+		"crossed":        e.Crossed,
+	}
+}
+
+// WatchDelta follows a numeric payload field across events and
+// dispatches a DeltaEvent when it crosses a threshold or changes by more
+// than a percentage. The first value of each series only sets a baseline.
+// Events without the field are ignored.
+func (ge *GoEvent) WatchDelta(watch DeltaWatch) {
+	if watch.Emit == "" {
+		watch.Emit = watch.Event + ".delta"
+	}
+	ge.RegisterListener(&deltaListener{ge: ge, watch: watch, last: make(map[any]float64)})
+}
+
+// deltaListener is sync so every series sees its values in dispatch order
+type deltaListener struct {
+	ge    *GoEvent
+	watch DeltaWatch
+
+	mu   sync.Mutex
+	last map[any]float64
+}
+
+func (l *deltaListener) EventName() string {
+	return l.watch.Event
+}
+
+func (l *deltaListener) OnEvent(event Event) error {
+	return l.OnEventContext(context.Background(), event)
+}
+
+func (l *deltaListener) OnEventContext(ctx context.Context, event Event) error {
+	payload := event.Payload()
+	raw, ok := payload[l.watch.Field]
+	if !ok || raw == nil {
+		return nil
+	}
+	current, ok := toFloat(raw)
+	if !ok {
+		return fmt.Errorf("delta: field %q is %T, not a number", l.watch.Field, raw)
+	}
+
+	var key any
+	if l.watch.Key != "" {
+		key = payload[l.watch.Key]
+	}
+
+	l.mu.Lock()
+	previous, seen := l.last[key]
+	l.last[key] = current
+	l.mu.Unlock()
+	if !seen {
+		return nil
+	}
+
+	delta := &DeltaEvent{
+		EventName:     l.watch.Emit,
+		Source:        event,
+		Key:           key,
+		Field:         l.watch.Field,
+		Previous:      previous,
+		Current:       current,
+		ChangePercent: changePercent(previous, current),
+		Crossed:       crossed(l.watch.Thresholds, previous, current),
+	}
+	changed := l.watch.ChangePercent > 0 && math.Abs(delta.ChangePercent) > l.watch.ChangePercent
+	if len(delta.Crossed) == 0 && !changed {
+		return nil
+	}
+
+	l.ge.DispatchContext(ctx, delta)
+	return nil
+}
+
+// changePercent returns the signed change from previous to current in
+// percent of previous
+func changePercent(previous, current float64) float64 {
+	if previous == 0 {
+		switch {
+		case current > 0:
+			return math.Inf(1)
+		case current < 0:
+			return math.Inf(-1)
+		}
+		return 0
+	}
+	return (current - previous) / math.Abs(previous) * 100
+}
+
+// crossed returns the thresholds passed when moving from previous to
+// current. Reaching a threshold counts as crossing it on the way up,
+// leaving it counts on the way down.
+func crossed(thresholds []float64, previous, current float64) []float64 {
+	var passed []float64
+	for _, t := range thresholds {
+		if previous < t && current >= t || previous >= t && current < t {
+			passed = append(passed, t)
+		}
+	}
+	if current < previous {
+		// Report in the order they were passed on the way down
+		for i, j := 0, len(passed)-1; i < j; i, j = i+1, j-1 {
+			passed[i], passed[j] = passed[j], passed[i]
+		}
+	}
+	return passed
+}
+
+// toFloat converts any numeric value to float64
+func toFloat(value any) (float64, bool) {
+	rv := reflect.ValueOf(value)
+	switch rv.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return float64(rv.Int()), true
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return float64(rv.Uint()), true
+	case reflect.Float32, reflect.Float64:
+		return rv.Float(), true
+	}
+	return 0, false
+}
@@ -0,0 +1,118 @@
+package goevent
+
+import (
+	"context"
+	"math"
+	"sync"
+	"testing"
+)
+
+type testDeltaListener struct {
+	mu     sync.Mutex
+	events []*DeltaEvent
+}
+
+func (l *testDeltaListener) EventName() string {
+	return "metric.cpu.delta"
+}
+
+func (l *testDeltaListener) OnEvent(event Event) error {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.events = append(l.events, event.(*DeltaEvent))
+	return nil
+}
+
+func (l *testDeltaListener) Events() []*DeltaEvent {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return append([]*DeltaEvent(nil), l.events...)
+}
+
+func cpuEvent(host string, value any) Event {
+	return &GenericEvent{EventName: "metric.cpu", Data: map[string]any{"host": host, "value": value}}
+}
+
+func dispatchTree(t *testing.T, evt *GoEvent, event Event) *DispatchHandle {
+	t.Helper()
+	handle := evt.Dispatch(event)
+	if err := handle.WaitTree(context.Background()); err != nil {
+		t.Fatalf("WaitTree() failed: %v", err)
+	}
+	return handle
+}
+
+func TestWatchDelta_Thresholds(t *testing.T) {
+	evt := New()
+	deltas := &testDeltaListener{}
+	evt.RegisterListener(deltas)
+	evt.WatchDelta(DeltaWatch{Event: "metric.cpu", Field: "value", Key: "host", Thresholds: []float64{80, 90}})
+
+	dispatchTree(t, evt, cpuEvent("a", 50))
+	dispatchTree(t, evt, cpuEvent("a", 70))
+	if len(deltas.Events()) != 0 {
+		t.Fatalf("Expected no deltas below thresholds, got %d", len(deltas.Events()))
+	}
+
+	dispatchTree(t, evt, cpuEvent("a", 95))
+	dispatchTree(t, evt, cpuEvent("a", 85))
+
+	events := deltas.Events()
+	if len(events) != 2 {
+		t.Fatalf("Expected 2 deltas, got %d", len(events))
+	}
+	if got := events[0].Crossed; len(got) != 2 || got[0] != 80 || got[1] != 90 {
+		t.Errorf("Expected [80 90] crossed going up, got %v", got)
+	}
+	if got := events[1].Crossed; len(got) != 1 || got[0] != 90 {
+		t.Errorf("Expected [90] crossed going down, got %v", got)
+	}
+	if events[1].Previous != 95 || events[1].Current != 85 || events[1].Key != "a" {
+		t.Errorf("Expected a: 95 -> 85, got %v: %v -> %v", events[1].Key, events[1].Previous, events[1].Current)
+	}
+}
+
+func TestWatchDelta_ChangePercentPerKey(t *testing.T) {
+	evt := New()
+	deltas := &testDeltaListener{}
+	evt.RegisterListener(deltas)
+	evt.WatchDelta(DeltaWatch{Event: "metric.cpu", Field: "value", Key: "host", ChangePercent: 50})
+
+	dispatchTree(t, evt, cpuEvent("a", 10))
+	dispatchTree(t, evt, cpuEvent("b", 100))
+	dispatchTree(t, evt, cpuEvent("a", 14))
+	dispatchTree(t, evt, cpuEvent("b", 40))
+
+	events := deltas.Events()
+	if len(events) != 1 {
+		t.Fatalf("Expected 1 delta, got %d", len(events))
+	}
+	if events[0].Key != "b" || events[0].ChangePercent != -60 {
+		t.Errorf("Expected b to change by -60%%, got %v by %v%%", events[0].Key, events[0].ChangePercent)
+	}
+}
+
+func TestWatchDelta_FromZero(t *testing.T) {
+	if got := changePercent(0, 5); !math.IsInf(got, 1) {
+		t.Errorf("Expected +Inf, got %v", got)
+	}
+	if got := changePercent(0, 0); got != 0 {
+		t.Errorf("Expected 0, got %v", got)
+	}
+}
+
+func TestWatchDelta_NonNumeric(t *testing.T) {
+	evt := New()
+	evt.WatchDelta(DeltaWatch{Event: "metric.cpu", Field: "value"})
+
+	handle := dispatchTree(t, evt, cpuEvent("a", "high"))
+
+	if len(handle.GetErrors()) != 1 {
+		t.Errorf("Expected 1 error for a non-numeric field, got %d", len(handle.GetErrors()))
+	}
+
+	handle = dispatchTree(t, evt, &GenericEvent{EventName: "metric.cpu"})
+	if errs := handle.GetErrors(); len(errs) != 0 {
+		t.Errorf("Expected events without the field to be ignored, got %v", errs)
+	}
+}
@@ -0,0 +1,25 @@
+package goevent
+
+// OnFirstSubscriber registers fn to be called when an event name gains
+// its first listener, so expensive producers such as pollers and
+// watchers can start once there is demand for their events
+func (ge *GoEvent) OnFirstSubscriber(fn func(eventName string)) {
+	ge.registryMu.Lock()
+	defer ge.registryMu.Unlock()
+	ge.onFirst = append(ge.onFirst, fn)
+}
+
+// OnLastUnsubscriber registers fn to be called when the last listener
+// of an event name is removed, so producers can stop when demand ends
+func (ge *GoEvent) OnLastUnsubscriber(fn func(eventName string)) {
+	ge.registryMu.Lock()
+	defer ge.registryMu.Unlock()
+	ge.onLast = append(ge.onLast, fn)
+}
+
+// notifyDemand calls hooks outside of registryMu so they may use the bus
+func notifyDemand(hooks []func(eventName string), eventName string) {
+	for _, fn := range hooks {
+		fn(eventName)
+	}
+}
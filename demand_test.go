@@ -0,0 +1,36 @@
+package goevent
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestOnFirstSubscriber(t *testing.T) {
+	evt := New()
+	var started []string
+	evt.OnFirstSubscriber(func(eventName string) {
+		started = append(started, eventName)
+	})
+
+	evt.RegisterListener(&testSyncListener{}, &testAsyncListener{}, &testHandleRecorder{})
+
+	want := []string{"test.event", "test.child"}
+	if !reflect.DeepEqual(started, want) {
+		t.Errorf("Expected hooks for %v, got %v", want, started)
+	}
+}
+
+func TestOnFirstSubscriber_ListenerIsSubscribed(t *testing.T) {
+	evt := New()
+	listener := &testCountingListener{}
+	evt.OnFirstSubscriber(func(eventName string) {
+		// A producer started by the hook can dispatch right away
+		evt.Dispatch(&TestEvent{data: "first"})
+	})
+
+	evt.RegisterListener(listener)
+
+	if listener.Count() != 1 {
+		t.Errorf("Expected the hook's dispatch to be delivered, got %d calls", listener.Count())
+	}
+}
@@ -0,0 +1,123 @@
+package goevent
+
+import (
+	"reflect"
+	"sort"
+)
+
+// ChangeOp describes how a payload field changed
+type ChangeOp string
+
+const (
+	FieldAdded   ChangeOp = "added"
+	FieldRemoved ChangeOp = "removed"
+	FieldChanged ChangeOp = "changed"
+)
+
+// FieldChange is a difference between two payloads
+type FieldChange struct {
+	Field string // dotted path for nested maps, e.g. "address.city"
+	Op    ChangeOp
+	Old   any // nil if added
+	New   any // nil if removed
+}
+
+// DiffPayloads compares the payloads of two events and returns the
+// fields that differ, sorted by field. Nested map[string]any values are
+// compared field by field; other values are compared as a whole, with
+// numbers of different types equal if their values are.
+func DiffPayloads(old, new Event) []FieldChange {
+	var oldPayload, newPayload map[string]any
+	if old != nil {
+		oldPayload = old.Payload()
+	}
+	if new != nil {
+		newPayload = new.Payload()
+	}
+	return diffMaps(oldPayload, newPayload)
+}
+
+func diffMaps(old, new map[string]any) []FieldChange {
+	var changes []FieldChange
+	diffInto(&changes, "", old, new)
+	sort.Slice(changes, func(i, j int) bool {
+		return changes[i].Field < changes[j].Field
+	})
+	return changes
+}
+
+func diffInto(changes *[]FieldChange, prefix string, old, new map[string]any) {
+	for key, oldValue := range old {
+		field := prefix + key
+		newValue, ok := new[key]
+		if !ok {
+			*changes = append(*changes, FieldChange{Field: field, Op: FieldRemoved, Old: oldValue})
+			continue
+		}
+
+		oldMap, oldIsMap := oldValue.(map[string]any)
+		newMap, newIsMap := newValue.(map[string]any)
+		if oldIsMap && newIsMap {
+			diffInto(changes, field+".", oldMap, newMap)
+			continue
+		}
+		if !equalValues(oldValue, newValue) {
+			*changes = append(*changes, FieldChange{Field: field, Op: FieldChanged, Old: oldValue, New: newValue})
+		}
+	}
+	for key, newValue := range new {
+		if _, ok := old[key]; !ok {
+			*changes = append(*changes, FieldChange{Field: prefix + key, Op: FieldAdded, New: newValue})
+		}
+	}
+}
+
+// equalValues compares payload values, treating numbers of different
+// types as equal when their values are, as after a JSON round trip
+func equalValues(a, b any) bool {
+	if x, ok := toFloat(a); ok {
+		if y, ok := toFloat(b); ok {
+			return x == y
+		}
+	}
+	return reflect.DeepEqual(a, b)
+}
+
+// UpdatedEvent is an update event carrying the state before and after
+// the update. Its payload holds "before", "after" and a computed
+// "changes" list, so every *.updated event reports changes the same way.
+type UpdatedEvent struct {
+	EventName string
+	Before    map[string]any
+	After     map[string]any
+}
+
+// Name returns the event name
+func (e *UpdatedEvent) Name() string {
+	return e.EventName
+}
+
+// Payload returns the before and after states with the changes between
+// them. Each change is a map with "field", "op", "old" and "new" keys.
+func (e *UpdatedEvent) Payload() map[string]any {
+	changes := e.Changes()
+	list := make([]map[string]any, len(changes))
+	for i, change := range changes {
+		list[i] = map[string]any{
+			"field": change.Field,
+			"op":    string(change.Op),
+			"old":   change.Old,
+			"new":   change.New,
+		}
+	}
+	return map[string]any{
+		"before":  e.Before,
+		"after":   e.After,
+		"changes": list,
+	}
+}
+
+// Changes returns the fields that differ between Before and After
+func (e *UpdatedEvent) Changes() []FieldChange {
+	return diffMaps(e.Before, e.After)
+}
@@ -0,0 +1,57 @@
+package goevent
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestDiffPayloads(t *testing.T) {
+	old := &GenericEvent{EventName: "user.updated", Data: map[string]any{
+		"name":    "Ada",
+		"age":     36,
+		"email":   "ada@example.com",
+		"address": map[string]any{"city": "London", "zip": "N1"},
+	}}
+	new := &GenericEvent{EventName: "user.updated", Data: map[string]any{
+		"name":    "Ada",
+		"age":     float64(37),
+		"phone":   "555",
+		"address": map[string]any{"city": "Cambridge", "zip": "N1"},
+	}}
+
+	got := DiffPayloads(old, new)
+	want := []FieldChange{
+		{Field: "address.city", Op: FieldChanged, Old: "London", New: "Cambridge"},
+		{Field: "age", Op: FieldChanged, Old: 36, New: float64(37)},
+		{Field: "email", Op: FieldRemoved, Old: "ada@example.com"},
+		{Field: "phone", Op: FieldAdded, New: "555"},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("Expected %v, got %v", want, got)
+	}
+}
+
+func TestDiffPayloads_NumbersAcrossTypes(t *testing.T) {
+	old := &GenericEvent{Data: map[string]any{"count": 3}}
+	new := &GenericEvent{Data: map[string]any{"count": float64(3)}}
+
+	if changes := DiffPayloads(old, new); len(changes) != 0 {
+		t.Errorf("Expected equal numbers to match, got %v", changes)
+	}
+}
+
+func TestUpdatedEvent_Payload(t *testing.T) {
+	event := &UpdatedEvent{
+		EventName: "user.updated",
+		Before:    map[string]any{"name": "Ada"},
+		After:     map[string]any{"name": "Ada Lovelace"},
+	}
+
+	changes, ok := event.Payload()["changes"].([]map[string]any)
+	if !ok || len(changes) != 1 {
+		t.Fatalf("Expected 1 change, got %v", event.Payload()["changes"])
+	}
+	if changes[0]["field"] != "name" || changes[0]["op"] != "changed" || changes[0]["new"] != "Ada Lovelace" {
+		t.Errorf("Expected name to change, got %v", changes[0])
+	}
+}
@@ -0,0 +1,238 @@
+package goevent
+
+import (
+	"context"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// DigestEvent is delivered to listeners registered with a DigestInterval.
+// It summarizes every matching event dispatched during one interval.
+type DigestEvent struct {
+	EventName string
+	Start     time.Time
+	End       time.Time
+	Events    []Event
+	Payloads  []map[string]any
+}
+
+// Name returns the name of the digested events
+func (d *DigestEvent) Name() string {
+	return d.EventName
+}
+
+// Payload returns the event count and the collected payloads
+func (d *DigestEvent) Payload() map[string]any {
+	return map[string]any{
+		"count":    d.Count(),
+		"payloads": d.Payloads,
+	}
+}
+
+// Count returns the number of events collected in this digest
+func (d *DigestEvent) Count() int {
+	return len(d.Events)
+}
+
+// digester buffers events for a single digest or batch listener and
+// delivers them as one DigestEvent when the interval elapses or, for
+// batches, once size events were collected
+type digester struct {
+	ge           *GoEvent
+	eventName    string
+	listener     Listener
+	listenerType string
+	active       *atomic.Int64 // see activeCounter
+	interval     time.Duration
+	size         int  // deliver once this many events were collected, 0 for no limit
+	async        bool // deliver full batches on their own goroutine
+	hold         bool // keep dispatches open until delivered, for batches
+	retry        RetryPolicy
+	timeout      time.Duration
+
+	mu      sync.Mutex
+	pending *DigestEvent
+	handles []*DispatchHandle // dispatches of the pending window, if held open
+	timer   Timer
+}
+
+func newDigester(ge *GoEvent, eventName string, listener Listener, opts ListenerOptions) *digester {
+	d := &digester{
+		ge:           ge,
+		eventName:    eventName,
+		listener:     listener,
+		listenerType: listenerTypeOf(listener),
+		active:       ge.activeCounter(listenerTypeOf(listener)),
+		interval:     opts.DigestInterval,
+		retry:        opts.Retry,
+		timeout:      opts.Timeout,
+	}
+	if _, ok := listener.(*batchAdapter); ok {
+		d.interval = opts.FlushInterval
+		d.size = opts.BatchSize
+		d.async = opts.Async
+		d.hold = true
+		if d.size <= 0 && d.interval <= 0 {
+			d.size = 1
+		}
+	}
+	return d
+}
+
+// add appends an event to the current window, opening a new one if
+// needed. A batch that becomes full is delivered before add returns,
+// unless the listener is async. The dispatch of a batched event stays
+// open until its batch is delivered, so a durable bus only acknowledges
+// it once the listener handled it. Digests may cover long intervals, so
+// their dispatches are done once the event is queued.
+func (d *digester) add(handle *DispatchHandle, event Event) {
+	if d.hold {
+		handle.hold(1)
+	}
+	d.mu.Lock()
+
+	if d.pending == nil {
+		window := &DigestEvent{
+			EventName: d.eventName,
+			Start:     d.ge.clock.Now(),
+		}
+		d.pending = window
+		if d.interval > 0 {
+			d.timer = d.ge.clock.AfterFunc(d.interval, func() { d.expire(window) })
+		}
+	}
+
+	d.pending.Events = append(d.pending.Events, event)
+	d.pending.Payloads = append(d.pending.Payloads, event.Payload())
+	if d.hold {
+		d.handles = append(d.handles, handle)
+	}
+
+	if d.size == 0 || len(d.pending.Events) < d.size {
+		d.mu.Unlock()
+		return
+	}
+	batch, handles := d.take()
+	d.ge.wg.Add(1)
+	d.mu.Unlock()
+
+	if d.async {
+		go d.deliver(batch, handles)
+		return
+	}
+	d.deliver(batch, handles)
+}
+
+// take detaches the current window and the dispatches it holds. The
+// caller must hold mu and must either deliver or discard a non-nil
+// result.
+func (d *digester) take() (*DigestEvent, []*DispatchHandle) {
+	digest, handles := d.pending, d.handles
+	d.pending, d.handles = nil, nil
+	if d.timer != nil {
+		d.timer.Stop()
+		d.timer = nil
+	}
+	return digest, handles
+}
+
+// expire delivers window when its interval elapses, unless it was
+// already flushed or purged
+func (d *digester) expire(window *DigestEvent) {
+	d.mu.Lock()
+	if d.pending != window {
+		d.mu.Unlock()
+		return
+	}
+	digest, handles := d.take()
+	d.ge.wg.Add(1)
+	d.mu.Unlock()
+
+	d.deliver(digest, handles)
+}
+
+// deliver sends a detached window to the listener, then releases the
+// dispatches it held. A failure is recorded on each of them and on the
+// bus. Windows only count
+// as in-flight work for Wait once they are being delivered, so an open
+// window does not block Wait for its whole interval; Close flushes them.
+func (d *digester) deliver(digest *DigestEvent, handles []*DispatchHandle) {
+	defer releaseAll(handles)
+	defer d.ge.wg.Done()
+	d.active.Add(1)
+	defer d.active.Add(-1)
+	defer d.ge.logSlow(digest.EventName, d.listenerType, "", time.Now())
+
+	digest.End = d.ge.clock.Now()
+
+	start := d.ge.clock.Now()
+	attempts, err := d.ge.invokeWithRetry(context.Background(), d.listener, digest, d.retry, d.timeout)
+	if err != nil {
+		eventError := &EventError{
+			EventName:    digest.EventName,
+			ListenerType: d.listenerType,
+			Err:          err,
+			Attempts:     attempts,
+			Duration:     d.ge.clock.Now().Sub(start),
+		}
+		for _, handle := range handles {
+			handleError := handle.newError(digest.EventName, err)
+			handleError.ListenerType = eventError.ListenerType
+			handleError.Attempts = eventError.Attempts
+			handleError.Duration = eventError.Duration
+			handle.recordError(handleError)
+		}
+		d.ge.recordError(eventError)
+		if isDeadLetter(eventError, d.retry) {
+			d.ge.deadLetter(digest, eventError)
+		}
+	}
+}
+
+// pendingEvents returns the events collected in the current window
+func (d *digester) pendingEvents() []Event {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	if d.pending == nil {
+		return nil
+	}
+	events := make([]Event, len(d.pending.Events))
+	copy(events, d.pending.Events)
+	return events
+}
+
+// purge discards the current window
+func (d *digester) purge() int {
+	d.mu.Lock()
+	digest, handles := d.take()
+	d.mu.Unlock()
+
+	if digest == nil {
+		return 0
+	}
+	releaseAll(handles)
+	return digest.Count()
+}
+
+// flush delivers the current window immediately
+func (d *digester) flush() int {
+	d.mu.Lock()
+	digest, handles := d.take()
+	d.mu.Unlock()
+
+	if digest == nil {
+		return 0
+	}
+	d.ge.wg.Add(1)
+	d.deliver(digest, handles)
+	return digest.Count()
+}
+
+// releaseAll releases the dispatches a window held
+func releaseAll(handles []*DispatchHandle) {
+	for _, handle := range handles {
+		handle.release()
+	}
+}
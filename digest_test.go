@@ -0,0 +1,133 @@
+package goevent
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+)
+
+type testDigestListener struct {
+	interval time.Duration // defaults to 20ms
+
+	mu      sync.Mutex
+	digests []*DigestEvent
+}
+
+func (l *testDigestListener) EventName() string {
+	return "test.event"
+}
+
+func (l *testDigestListener) OnEvent(event Event) error {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if d, ok := event.(*DigestEvent); ok {
+		l.digests = append(l.digests, d)
+	}
+	return nil
+}
+
+func (l *testDigestListener) Options() ListenerOptions {
+	if l.interval > 0 {
+		return ListenerOptions{DigestInterval: l.interval}
+	}
+	return ListenerOptions{DigestInterval: 20 * time.Millisecond}
+}
+
+func (l *testDigestListener) count() int {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return len(l.digests)
+}
+
+// waitFor polls until n digests were delivered, since neither the
+// dispatch nor Wait blocks on an open window
+func (l *testDigestListener) waitFor(t *testing.T, n int) {
+	t.Helper()
+	deadline := time.Now().Add(time.Second)
+	for l.count() < n {
+		if time.Now().After(deadline) {
+			t.Fatalf("Expected %d digests, got %d", n, l.count())
+		}
+		time.Sleep(time.Millisecond)
+	}
+}
+
+func TestDigestListener_CollectsEventsPerInterval(t *testing.T) {
+	evt := New()
+	listener := &testDigestListener{}
+	evt.RegisterListener(listener)
+
+	evt.Dispatch(&TestEvent{data: "one"})
+	evt.Dispatch(&TestEvent{data: "two"})
+	evt.Dispatch(&TestEvent{data: "three"})
+	listener.waitFor(t, 1)
+
+	listener.mu.Lock()
+	defer listener.mu.Unlock()
+
+	if len(listener.digests) != 1 {
+		t.Fatalf("Expected 1 digest, got %d", len(listener.digests))
+	}
+
+	digest := listener.digests[0]
+	if digest.Count() != 3 {
+		t.Errorf("Expected digest count 3, got %d", digest.Count())
+	}
+	if digest.Name() != "test.event" {
+		t.Errorf("Expected digest name 'test.event', got '%s'", digest.Name())
+	}
+	if digest.Payloads[1]["data"] != "two" {
+		t.Errorf("Expected second payload data 'two', got '%v'", digest.Payloads[1]["data"])
+	}
+	if digest.Payload()["count"] != 3 {
+		t.Errorf("Expected payload count 3, got '%v'", digest.Payload()["count"])
+	}
+}
+
+func TestDigestListener_NewWindowAfterFlush(t *testing.T) {
+	evt := New()
+	listener := &testDigestListener{}
+	evt.RegisterListener(listener)
+
+	evt.Dispatch(&TestEvent{data: "first window"})
+	listener.waitFor(t, 1)
+	evt.Dispatch(&TestEvent{data: "second window"})
+	listener.waitFor(t, 2)
+
+	listener.mu.Lock()
+	defer listener.mu.Unlock()
+
+	if len(listener.digests) != 2 {
+		t.Fatalf("Expected 2 digests, got %d", len(listener.digests))
+	}
+}
+
+func TestDigestListener_WaitDoesNotBlockOnOpenWindow(t *testing.T) {
+	evt := New()
+	listener := &testDigestListener{interval: time.Hour}
+	evt.RegisterListener(listener)
+	handle := evt.Dispatch(&TestEvent{})
+
+	waited := make(chan struct{})
+	go func() {
+		handle.Wait()
+		evt.Wait()
+		close(waited)
+	}()
+	select {
+	case <-waited:
+	case <-time.After(time.Second):
+		t.Fatal("Expected the dispatch and Wait to return while the window is open")
+	}
+	if listener.count() != 0 {
+		t.Fatal("Expected the window to stay open")
+	}
+
+	if err := evt.Close(context.Background()); err != nil {
+		t.Fatalf("Close() failed: %v", err)
+	}
+	if listener.count() != 1 || listener.digests[0].Count() != 1 {
+		t.Errorf("Expected Close to deliver the open window with 1 event, got %d digests", listener.count())
+	}
+}
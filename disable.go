@@ -0,0 +1,56 @@
+package goevent
+
+import "sort"
+
+// DisableListeners stops delivering events to listeners of the given
+// types, named as ListenerInfo.Type names them, e.g. "*main.EmailListener".
+// The listeners stay registered; events dispatched while they are
+// disabled are not delivered later.
+func (ge *GoEvent) DisableListeners(types ...string) {
+	ge.dispatcher.setDisabled(types, true)
+}
+
+// EnableListeners delivers events to listeners of the given types again
+func (ge *GoEvent) EnableListeners(types ...string) {
+	ge.dispatcher.setDisabled(types, false)
+}
+
+// DisabledListeners returns the sorted types of disabled listeners
+func (ge *GoEvent) DisabledListeners() []string {
+	disabled := ge.dispatcher.disabled.Load()
+	if disabled == nil {
+		return nil
+	}
+	types := make([]string, 0, len(*disabled))
+	for listenerType := range *disabled {
+		types = append(types, listenerType)
+	}
+	sort.Strings(types)
+	return types
+}
+
+// setDisabled copies the disabled set with types added or removed, so
+// snapshots read it without taking a lock
+func (d *dispatcher) setDisabled(types []string, disable bool) {
+	d.disabledMu.Lock()
+	defer d.disabledMu.Unlock()
+
+	next := make(map[string]bool)
+	if current := d.disabled.Load(); current != nil {
+		for listenerType := range *current {
+			next[listenerType] = true
+		}
+	}
+	for _, listenerType := range types {
+		if disable {
+			next[listenerType] = true
+		} else {
+			delete(next, listenerType)
+		}
+	}
+	if len(next) == 0 {
+		d.disabled.Store(nil)
+		return
+	}
+	d.disabled.Store(&next)
+}
@@ -0,0 +1,143 @@
+package goevent
+
+import (
+	"context"
+	"slices"
+	"time"
+)
+
+// Priority indicates how urgent a dispatch is. Higher values are more urgent.
+type Priority int
+
+const (
+	PriorityLow    Priority = -10
+	PriorityNormal Priority = 0
+	PriorityHigh   Priority = 10
+)
+
+// DispatchOption configures a single dispatch
+type DispatchOption func(*dispatchConfig)
+
+type dispatchConfig struct {
+	priority  Priority
+	deadline  time.Time
+	tags      []string
+	replay    bool
+	storedSeq uint64 // set when dispatching an event the store already holds
+	failFast  bool
+	metadata  map[string]string // set by WithMetadata
+	request   bool              // dispatched by Request
+	timeout   time.Duration     // turned into deadline by DispatchContext
+	only      []string          // listener types, see WithOnlyListeners
+	exclude   []string          // listener types, see WithExcludeListeners
+	forceSync bool
+
+	requireListeners bool
+}
+
+// WithPriority sets the priority of the dispatch, overriding any
+// priority inherited from a parent dispatch
+func WithPriority(priority Priority) DispatchOption {
+	return func(c *dispatchConfig) {
+		c.priority = priority
+	}
+}
+
+// WithDeadline sets the deadline of the dispatch, overriding any
+// deadline inherited from a parent dispatch. Listeners that have not
+// started by the deadline are skipped with context.DeadlineExceeded.
+func WithDeadline(deadline time.Time) DispatchOption {
+	return func(c *dispatchConfig) {
+		c.deadline = deadline
+	}
+}
+
+// WithTimeout sets the deadline of the dispatch to timeout from now, like
+// WithDeadline
+func WithTimeout(timeout time.Duration) DispatchOption {
+	return func(c *dispatchConfig) {
+		c.timeout = timeout
+	}
+}
+
+// WithTags attaches tags to the dispatch, such as TagBestEffort or TagCritical
+func WithTags(tags ...string) DispatchOption {
+	return func(c *dispatchConfig) {
+		c.tags = append(c.tags, tags...)
+	}
+}
+
+// FailFast stops calling the remaining synchronous listeners once one of
+// them fails. Async listeners are not affected. Use DispatchE to receive
+// the error directly.
+func FailFast() DispatchOption {
+	return func(c *dispatchConfig) {
+		c.failFast = true
+	}
+}
+
+// WithOnlyListeners delivers the dispatch only to listeners of the given
+// types, named as ListenerInfo.Type names them, e.g. "*main.EmailListener"
+func WithOnlyListeners(types ...string) DispatchOption {
+	return func(c *dispatchConfig) {
+		c.only = append(c.only, types...)
+	}
+}
+
+// WithExcludeListeners skips listeners of the given types, named as
+// ListenerInfo.Type names them
+func WithExcludeListeners(types ...string) DispatchOption {
+	return func(c *dispatchConfig) {
+		c.exclude = append(c.exclude, types...)
+	}
+}
+
+// ForceSync runs the dispatch's async listeners on the dispatching
+// goroutine, one after another in registration order, so all listeners
+// have run when Dispatch returns. It also bypasses WithDispatchQueue. Their errors do not stop a fail-fast
+// dispatch.
+func ForceSync() DispatchOption {
+	return func(c *dispatchConfig) {
+		c.forceSync = true
+	}
+}
+
+// hasTag reports whether the dispatch was tagged with tag
+func (c *dispatchConfig) hasTag(tag string) bool {
+	for _, t := range c.tags {
+		if t == tag {
+			return true
+		}
+	}
+	return false
+}
+
+type handleContextKey struct{}
+
+// HandleFromContext returns the dispatch handle of the dispatch a listener
+// is currently handling. The context passed to ContextListener and to
+// middleware carries it.
+func HandleFromContext(ctx context.Context) (*DispatchHandle, bool) {
+	handle, ok := ctx.Value(handleContextKey{}).(*DispatchHandle)
+	return handle, ok
+}
+
+// selectSubscribers applies WithOnlyListeners and WithExcludeListeners,
+// returning the selected subscribers and how many of them are async
+func (dh *DispatchHandle) selectSubscribers(subs []subscriber) ([]subscriber, int) {
+	selected := make([]subscriber, 0, len(subs))
+	asyncCount := 0
+	for _, sub := range subs {
+		if len(dh.only) > 0 && !slices.Contains(dh.only, sub.listenerType) {
+			continue
+		}
+		if slices.Contains(dh.exclude, sub.listenerType) {
+			continue
+		}
+		selected = append(selected, sub)
+		if sub.async {
+			asyncCount++
+		}
+	}
+	return selected, asyncCount
+}
@@ -0,0 +1,245 @@
+package goevent
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+)
+
+type testChildEvent struct{}
+
+func (e *testChildEvent) Name() string {
+	return "test.child"
+}
+
+func (e *testChildEvent) Payload() map[string]any {
+	return nil
+}
+
+// testCascadeListener dispatches a child event while handling a parent
+type testCascadeListener struct {
+	bus    *GoEvent
+	opts   []DispatchOption
+	detach bool // dispatch the child without waiting for it
+}
+
+func (l *testCascadeListener) EventName() string {
+	return "test.event"
+}
+
+func (l *testCascadeListener) OnEvent(event Event) error {
+	return errors.New("OnEvent should not be called for a ContextListener")
+}
+
+func (l *testCascadeListener) OnEventContext(ctx context.Context, event Event) error {
+	child := l.bus.DispatchContext(ctx, &testChildEvent{}, l.opts...)
+	if !l.detach {
+		child.Wait()
+	}
+	return nil
+}
+
+func (l *testCascadeListener) Options() ListenerOptions {
+	return ListenerOptions{Async: true}
+}
+
+// testHandleRecorder records the dispatch handle it was invoked for
+type testHandleRecorder struct {
+	mu     sync.Mutex
+	handle *DispatchHandle
+}
+
+func (l *testHandleRecorder) EventName() string {
+	return "test.child"
+}
+
+func (l *testHandleRecorder) OnEvent(event Event) error {
+	return nil
+}
+
+func (l *testHandleRecorder) OnEventContext(ctx context.Context, event Event) error {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.handle, _ = HandleFromContext(ctx)
+	return nil
+}
+
+func TestDispatchContext_InheritsPriorityAndDeadline(t *testing.T) {
+	evt := New()
+	recorder := &testHandleRecorder{}
+	evt.RegisterListener(&testCascadeListener{bus: evt}, recorder)
+
+	deadline := time.Now().Add(time.Minute)
+	parent := evt.DispatchContext(context.Background(), &TestEvent{data: "parent"},
+		WithPriority(PriorityHigh), WithDeadline(deadline))
+	parent.Wait()
+
+	if errs := parent.GetErrors(); len(errs) != 0 {
+		t.Fatalf("Expected no errors, got %v", errs)
+	}
+
+	recorder.mu.Lock()
+	defer recorder.mu.Unlock()
+
+	if recorder.handle == nil {
+		t.Fatal("Child listener did not receive a dispatch handle")
+	}
+	if recorder.handle.Priority() != PriorityHigh {
+		t.Errorf("Expected inherited priority %d, got %d", PriorityHigh, recorder.handle.Priority())
+	}
+	if got, ok := recorder.handle.Deadline(); !ok || !got.Equal(deadline) {
+		t.Errorf("Expected inherited deadline %v, got %v", deadline, got)
+	}
+}
+
+func TestDispatchContext_OverridesInheritedPriority(t *testing.T) {
+	evt := New()
+	recorder := &testHandleRecorder{}
+	evt.RegisterListener(&testCascadeListener{bus: evt, opts: []DispatchOption{WithPriority(PriorityLow)}}, recorder)
+
+	evt.DispatchContext(context.Background(), &TestEvent{data: "parent"}, WithPriority(PriorityHigh)).Wait()
+
+	recorder.mu.Lock()
+	defer recorder.mu.Unlock()
+
+	if recorder.handle.Priority() != PriorityLow {
+		t.Errorf("Expected overridden priority %d, got %d", PriorityLow, recorder.handle.Priority())
+	}
+	if _, ok := recorder.handle.Deadline(); ok {
+		t.Error("Expected no deadline")
+	}
+}
+
+func TestDispatchContext_SkipsListenersAfterDeadline(t *testing.T) {
+	evt := New()
+	listener := &testSyncListener{}
+	evt.RegisterListener(listener)
+
+	handle := evt.DispatchContext(context.Background(), &TestEvent{data: "late"},
+		WithDeadline(time.Now().Add(-time.Second)))
+
+	if listener.called {
+		t.Error("Listener was called after the dispatch deadline")
+	}
+
+	errs := handle.GetErrors()
+	if len(errs) != 1 || !errors.Is(errs[0].Err, context.DeadlineExceeded) {
+		t.Errorf("Expected a deadline exceeded error, got %v", errs)
+	}
+}
+
+// testBlockingChildListener blocks on child events until released
+type testBlockingChildListener struct {
+	release chan struct{}
+}
+
+func (l *testBlockingChildListener) EventName() string {
+	return "test.child"
+}
+
+func (l *testBlockingChildListener) OnEvent(event Event) error {
+	<-l.release
+	return nil
+}
+
+func (l *testBlockingChildListener) Options() ListenerOptions {
+	return ListenerOptions{Async: true}
+}
+
+func TestDispatchHandle_WaitTree(t *testing.T) {
+	evt := New()
+	child := &testBlockingChildListener{release: make(chan struct{})}
+	evt.RegisterListener(&testCascadeListener{bus: evt, detach: true}, child)
+
+	parent := evt.Dispatch(&TestEvent{data: "parent"})
+	parent.Wait()
+
+	// The parent is done but the child it caused is still running
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	if err := parent.WaitTree(ctx); !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("Expected WaitTree to wait for the child, got %v", err)
+	}
+
+	close(child.release)
+	if err := parent.WaitTree(context.Background()); err != nil {
+		t.Errorf("Expected nil once the cascade settled, got %v", err)
+	}
+}
+
+func TestDispatchHandle_WaitTreeWithoutChildren(t *testing.T) {
+	evt := New()
+	evt.RegisterListener(&testSyncListener{})
+
+	if err := evt.Dispatch(&TestEvent{data: "test"}).WaitTree(context.Background()); err != nil {
+		t.Errorf("Expected nil, got %v", err)
+	}
+}
+
+func TestDispatch_SelectListeners(t *testing.T) {
+	evt := New()
+	counting := &testCountingListener{}
+	recorder := &testNameRecorder{name: "test.event"}
+	evt.RegisterListener(counting, recorder)
+
+	evt.Dispatch(&TestEvent{}, WithOnlyListeners("*goevent.testCountingListener"))
+	if counting.Count() != 1 || len(recorder.received) != 0 {
+		t.Errorf("Expected only the counting listener, got %d and %d", counting.Count(), len(recorder.received))
+	}
+
+	evt.Dispatch(&TestEvent{}, WithExcludeListeners("*goevent.testCountingListener"))
+	if counting.Count() != 1 || len(recorder.received) != 1 {
+		t.Errorf("Expected the counting listener to be skipped, got %d and %d", counting.Count(), len(recorder.received))
+	}
+}
+
+func TestDispatch_ForceSync(t *testing.T) {
+	evt := New()
+	async := &testAsyncListener{}
+	evt.RegisterListener(async)
+
+	handle := evt.Dispatch(&TestEvent{data: "now"}, ForceSync())
+	if !async.called || async.data != "now" {
+		t.Fatal("Expected the async listener to have run when Dispatch returned")
+	}
+	select {
+	case <-handle.Done():
+	default:
+		t.Error("Expected the dispatch to be done")
+	}
+}
+
+func TestDispatch_WithTimeout(t *testing.T) {
+	evt := New()
+	before := time.Now()
+	handle := evt.Dispatch(&TestEvent{}, WithTimeout(time.Minute))
+	deadline, ok := handle.Deadline()
+	if !ok || deadline.Before(before.Add(time.Minute)) || deadline.After(time.Now().Add(time.Minute)) {
+		t.Errorf("Expected a deadline a minute from now, got %v", deadline)
+	}
+}
+
+func TestDispatch_WithTimeoutFakeClock(t *testing.T) {
+	clock := newFakeClock(time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC))
+	evt := New(WithClock(clock))
+	listener := &testSyncListener{}
+	evt.RegisterListener(listener)
+
+	// The deadline is measured on the fake clock, which is far behind
+	// the wall clock, so the listener must still run
+	handle := evt.Dispatch(&TestEvent{}, WithTimeout(time.Minute))
+	if err := handle.Err(); err != nil || !listener.called {
+		t.Fatalf("Expected the listener to run before the fake deadline, got %v", err)
+	}
+	if deadline, _ := handle.Deadline(); !deadline.Equal(clock.Now().Add(time.Minute)) {
+		t.Errorf("Expected the deadline a minute after the fake now, got %v", deadline)
+	}
+
+	// A deadline already past on the fake clock skips listeners
+	handle = evt.Dispatch(&TestEvent{}, WithDeadline(clock.Now().Add(-time.Second)))
+	if err := handle.Err(); !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("Expected the listener to be skipped with DeadlineExceeded, got %v", err)
+	}
+}
@@ -0,0 +1,164 @@
+package goevent
+
+import (
+	"sync"
+	"sync/atomic"
+)
+
+// dispatcherShards is the number of shards of the subscription table
+const dispatcherShards = 32
+
+// subscriber is the entry point of a registered listener
+type subscriber struct {
+	listenerType string
+	async        bool
+	serial       *serialQueue  // runs async calls one at a time, if set
+	owner        *Subscription // set for listeners registered with Subscribe
+	call         func(handle *DispatchHandle, event Event)
+}
+
+// dispatcher keeps the subscribers of each event in registration order.
+// The table is sharded by event name so dispatches of different events
+// do not contend on one lock. Publishing works on a snapshot taken under
+// the read lock, so listeners are free to dispatch events and register
+// listeners while they run.
+type dispatcher struct {
+	shards [dispatcherShards]dispatcherShard
+
+	disabledMu sync.Mutex                      // serializes setDisabled
+	disabled   atomic.Pointer[map[string]bool] // listener types, see DisableListeners
+}
+
+type dispatcherShard struct {
+	mu          sync.RWMutex
+	subscribers map[string][]subscriber
+	_           [64]byte // keeps neighbouring shard locks off one cache line
+}
+
+// shard returns the shard of eventName, picked by its FNV-1a hash
+func (d *dispatcher) shard(eventName string) *dispatcherShard {
+	return &d.shards[fnv32(eventName)%dispatcherShards]
+}
+
+// fnv32 returns the FNV-1a hash of s
+func fnv32(s string) uint32 {
+	h := uint32(2166136261)
+	for i := 0; i < len(s); i++ {
+		h ^= uint32(s[i])
+		h *= 16777619
+	}
+	return h
+}
+
+// subscribe appends sub to the subscribers of eventName
+func (d *dispatcher) subscribe(eventName string, sub subscriber) {
+	s := d.shard(eventName)
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.subscribers == nil {
+		s.subscribers = make(map[string][]subscriber)
+	}
+	s.subscribers[eventName] = append(s.subscribers[eventName], sub)
+}
+
+// unsubscribe removes the subscribers of eventName owned by owner. The
+// remaining subscribers are copied to a new slice, so snapshots taken
+// before are not changed.
+func (d *dispatcher) unsubscribe(eventName string, owner *Subscription) {
+	s := d.shard(eventName)
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	var kept []subscriber
+	for _, sub := range s.subscribers[eventName] {
+		if sub.owner != owner {
+			kept = append(kept, sub)
+		}
+	}
+	if len(kept) == 0 {
+		delete(s.subscribers, eventName)
+		return
+	}
+	s.subscribers[eventName] = kept
+}
+
+// snapshot returns the active subscribers of eventName and how many of
+// them are async. Subscriber slices are only ever appended to, so capping
+// the snapshot at its length keeps later appends out of it. Paused and
+// disabled subscribers are left out, copying the slice only if there are
+// any.
+func (d *dispatcher) snapshot(eventName string) ([]subscriber, int) {
+	s := d.shard(eventName)
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	subs := s.subscribers[eventName]
+	subs = subs[:len(subs):len(subs)]
+	disabled := d.disabled.Load()
+	asyncCount := 0
+	for i, sub := range subs {
+		if sub.skipped(disabled) {
+			return activeSubscribers(subs[i:], subs[:i], asyncCount, disabled)
+		}
+		if sub.async {
+			asyncCount++
+		}
+	}
+	return subs, asyncCount
+}
+
+// activeSubscribers appends the subscribers of rest that are not skipped
+// to active, of which asyncCount are async
+func activeSubscribers(rest, active []subscriber, asyncCount int, disabled *map[string]bool) ([]subscriber, int) {
+	active = append([]subscriber(nil), active...)
+	for _, sub := range rest {
+		if sub.skipped(disabled) {
+			continue
+		}
+		active = append(active, sub)
+		if sub.async {
+			asyncCount++
+		}
+	}
+	return active, asyncCount
+}
+
+// skipped reports whether sub is paused or its type is disabled
+func (sub subscriber) skipped(disabled *map[string]bool) bool {
+	return sub.owner.isPaused() || (disabled != nil && (*disabled)[sub.listenerType])
+}
+
+// serialQueue runs the calls pushed to it one at a time, in push order,
+// on a goroutine that lives while the queue is not empty
+type serialQueue struct {
+	mu      sync.Mutex
+	calls   []func()
+	running bool
+}
+
+// push queues call, starting a goroutine to drain the queue if none runs
+func (q *serialQueue) push(call func()) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	q.calls = append(q.calls, call)
+	if !q.running {
+		q.running = true
+		go q.drain()
+	}
+}
+
+func (q *serialQueue) drain() {
+	for {
+		q.mu.Lock()
+		if len(q.calls) == 0 {
+			q.running = false
+			q.calls = nil
+			q.mu.Unlock()
+			return
+		}
+		call := q.calls[0]
+		q.calls[0] = nil
+		q.calls = q.calls[1:]
+		q.mu.Unlock()
+
+		call()
+	}
+}
@@ -0,0 +1,252 @@
+package goevent
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// testOrderListener appends its name to order when called
+type testOrderListener struct {
+	name  string
+	order *[]string
+}
+
+func (l *testOrderListener) EventName() string {
+	return "test.event"
+}
+
+func (l *testOrderListener) OnEvent(event Event) error {
+	*l.order = append(*l.order, l.name)
+	return nil
+}
+
+// testSyncCascadeListener dispatches a child event from a sync listener
+type testSyncCascadeListener struct {
+	bus *GoEvent
+}
+
+func (l *testSyncCascadeListener) EventName() string {
+	return "test.event"
+}
+
+func (l *testSyncCascadeListener) OnEvent(event Event) error {
+	return nil
+}
+
+func (l *testSyncCascadeListener) OnEventContext(ctx context.Context, event Event) error {
+	l.bus.DispatchContext(ctx, &testChildEvent{}).Wait()
+	return nil
+}
+
+// testRegisteringListener registers another listener while handling
+type testRegisteringListener struct {
+	bus      *GoEvent
+	listener Listener
+}
+
+func (l *testRegisteringListener) EventName() string {
+	return "test.event"
+}
+
+func (l *testRegisteringListener) OnEvent(event Event) error {
+	l.bus.RegisterListener(l.listener)
+	return nil
+}
+
+// testNamedListener listens to an arbitrary event name
+type testNamedListener struct {
+	name string
+}
+
+func (l *testNamedListener) EventName() string {
+	return l.name
+}
+
+func (l *testNamedListener) OnEvent(event Event) error {
+	return nil
+}
+
+// testOrderedListener records the sequence numbers of the events it
+// handles, taking longer for earlier ones
+type testOrderedListener struct {
+	mu      sync.Mutex
+	active  int
+	seen    []int
+	overlap bool
+}
+
+func (l *testOrderedListener) EventName() string {
+	return "test.ordered"
+}
+
+func (l *testOrderedListener) Options() ListenerOptions {
+	return ListenerOptions{Async: true, Ordered: true}
+}
+
+func (l *testOrderedListener) OnEvent(event Event) error {
+	l.mu.Lock()
+	l.active++
+	l.overlap = l.overlap || l.active > 1
+	l.mu.Unlock()
+
+	seq := event.Payload()["seq"].(int)
+	time.Sleep(time.Duration(10-seq%10) * 100 * time.Microsecond)
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.active--
+	l.seen = append(l.seen, seq)
+	return nil
+}
+
+// dispatchWithin fails the test if the dispatch does not finish in time
+func dispatchWithin(t *testing.T, evt *GoEvent, event Event) *DispatchHandle {
+	t.Helper()
+	done := make(chan *DispatchHandle)
+	go func() {
+		done <- evt.Dispatch(event)
+	}()
+	select {
+	case handle := <-done:
+		return handle
+	case <-time.After(time.Second):
+		t.Fatal("Dispatch deadlocked")
+		return nil
+	}
+}
+
+func TestDispatch_RegistrationOrder(t *testing.T) {
+	evt := New()
+	var order []string
+	evt.RegisterListener(
+		&testOrderListener{name: "first", order: &order},
+		&testOrderListener{name: "second", order: &order},
+		&testOrderListener{name: "third", order: &order},
+	)
+
+	evt.Dispatch(&TestEvent{})
+
+	if len(order) != 3 || order[0] != "first" || order[1] != "second" || order[2] != "third" {
+		t.Errorf("Expected listeners in registration order, got %v", order)
+	}
+}
+
+func TestDispatch_FromSyncListener(t *testing.T) {
+	evt := New()
+	recorder := &testHandleRecorder{}
+	evt.RegisterListener(&testSyncCascadeListener{bus: evt}, recorder)
+
+	parent := dispatchWithin(t, evt, &TestEvent{})
+	parent.Wait()
+
+	recorder.mu.Lock()
+	defer recorder.mu.Unlock()
+	if recorder.handle == nil {
+		t.Fatal("Child event was not delivered")
+	}
+	parent.childrenMu.Lock()
+	defer parent.childrenMu.Unlock()
+	if len(parent.children) != 1 || parent.children[0] != recorder.handle {
+		t.Errorf("Expected the child dispatch in the parent's tree, got %v", parent.children)
+	}
+}
+
+func TestRegisterListener_FromSyncListener(t *testing.T) {
+	evt := New()
+	late := &testSyncListener{}
+	evt.RegisterListener(&testRegisteringListener{bus: evt, listener: late})
+
+	dispatchWithin(t, evt, &TestEvent{})
+	if late.called {
+		t.Error("Listener registered during a dispatch was called for it")
+	}
+
+	evt.Dispatch(&TestEvent{})
+	if !late.called {
+		t.Error("Listener registered during a dispatch was not called for the next one")
+	}
+}
+
+func TestDispatch_Ordered(t *testing.T) {
+	evt := New()
+	listener := &testOrderedListener{}
+	evt.RegisterListener(listener)
+
+	handles := make([]*DispatchHandle, 0, 50)
+	for i := 0; i < 50; i++ {
+		handles = append(handles, evt.Dispatch(&GenericEvent{EventName: "test.ordered", Data: map[string]any{"seq": i}}))
+	}
+	for _, handle := range handles {
+		handle.Wait()
+	}
+
+	if listener.overlap {
+		t.Error("Ordered listener was called concurrently")
+	}
+	for i, seq := range listener.seen {
+		if seq != i {
+			t.Fatalf("Expected events in dispatch order, got %v", listener.seen)
+		}
+	}
+	if len(listener.seen) != 50 {
+		t.Errorf("Expected 50 events, got %d", len(listener.seen))
+	}
+}
+
+func TestDispatcher_ShardsKeepEventsApart(t *testing.T) {
+	var d dispatcher
+	for i := 0; i < 100; i++ {
+		d.subscribe(fmt.Sprintf("event.%d", i), subscriber{async: i%2 == 0})
+	}
+	for i := 0; i < 100; i++ {
+		subs, asyncCount := d.snapshot(fmt.Sprintf("event.%d", i))
+		if len(subs) != 1 || (asyncCount == 1) != (i%2 == 0) {
+			t.Fatalf("event.%d: expected its own subscriber, got %d (%d async)", i, len(subs), asyncCount)
+		}
+	}
+}
+
+// BenchmarkParallelDispatch dispatches a different event from each
+// goroutine; run with -cpu 1,2,4,8 to see how dispatch scales
+func BenchmarkParallelDispatch(b *testing.B) {
+	evt := New()
+	const events = 64
+	for i := 0; i < events; i++ {
+		evt.RegisterListener(&testNamedListener{name: fmt.Sprintf("bench.%d", i)})
+	}
+
+	var next atomic.Int64
+	b.ReportAllocs()
+	b.ResetTimer()
+	b.RunParallel(func(pb *testing.PB) {
+		event := &GenericEvent{EventName: fmt.Sprintf("bench.%d", next.Add(1)%events)}
+		for pb.Next() {
+			evt.Dispatch(event)
+		}
+	})
+}
+
+// BenchmarkParallelDispatchGated is BenchmarkParallelDispatch with a
+// gate that lets every event through
+func BenchmarkParallelDispatchGated(b *testing.B) {
+	evt := New()
+	evt.SetGate(func(Event) error { return nil })
+	const events = 64
+	for i := 0; i < events; i++ {
+		evt.RegisterListener(&testNamedListener{name: fmt.Sprintf("bench.%d", i)})
+	}
+
+	var next atomic.Int64
+	b.ReportAllocs()
+	b.ResetTimer()
+	b.RunParallel(func(pb *testing.PB) {
+		event := &GenericEvent{EventName: fmt.Sprintf("bench.%d", next.Add(1)%events)}
+		for pb.Next() {
+			evt.Dispatch(event)
+		}
+	})
+}
@@ -1,28 +1,189 @@
 package goevent
 
 import (
+	"context"
+	"errors"
 	"fmt"
 	"sync"
+	"sync/atomic"
+	"time"
 )
 
+// ErrWaitTimeout is returned by WaitTimeout when the dispatch has not
+// completed in time
+var ErrWaitTimeout = errors.New("goevent: timed out waiting for dispatch")
+
+// GenericEvent is an Event defined entirely by its name and payload.
+// Events read back from storage are reconstructed as GenericEvent.
+type GenericEvent struct {
+	EventName string
+	Data      map[string]any
+}
+
+// Name returns the event name
+func (e *GenericEvent) Name() string {
+	return e.EventName
+}
+
+// Payload returns the event data
+func (e *GenericEvent) Payload() map[string]any {
+	return e.Data
+}
+
 // EventError wraps errors that occur during event handling
 type EventError struct {
 	EventName    string
 	ListenerType string
+	DispatchID   string    // ID of the dispatch that failed, empty for digests
+	EventID      string    // Metadata.ID of the event, empty for digests
+	DispatchedAt time.Time // when the event was dispatched, zero for digests
 	Err          error
+	Attempts     int           // number of attempts made, greater than 1 if retried
+	Duration     time.Duration // time spent in the listener across all attempts
 }
 
 func (e *EventError) Error() string {
+	if e.ListenerType == "" {
+		return fmt.Sprintf("event '%s': %v", e.EventName, e.Err)
+	}
+	if e.Attempts > 1 {
+		return fmt.Sprintf("event '%s' listener '%s' (after %d attempts): %v", e.EventName, e.ListenerType, e.Attempts, e.Err)
+	}
 	return fmt.Sprintf("event '%s' listener '%s': %v", e.EventName, e.ListenerType, e.Err)
 }
 
+// Unwrap returns the underlying error, so errors.Is and errors.As see
+// through an EventError
+func (e *EventError) Unwrap() error {
+	return e.Err
+}
+
+// PanicError is recorded as the Err of an EventError when a listener panics
+type PanicError struct {
+	Value any    // value passed to panic
+	Stack []byte // stack trace captured at the point of recovery
+}
+
+func (e *PanicError) Error() string {
+	return fmt.Sprintf("panic: %v", e.Value)
+}
+
 // DispatchHandle represents a handle to a specific event dispatch
 // It allows waiting for and collecting errors from that specific dispatch
 type DispatchHandle struct {
+	id       string
 	wg       sync.WaitGroup
 	errorsMu sync.Mutex
 	errors   []*EventError
-	done     chan struct{}
+	holds    atomic.Int64 // pending handler calls and holds, see hold
+	doneMu   sync.Mutex
+	done     chan struct{} // created on demand, see Done
+	ctx      context.Context
+	cancel   context.CancelFunc
+	priority Priority
+	deadline time.Time
+	metadata Metadata
+	shed     atomic.Bool
+	orphan   atomic.Bool  // published to no listener, see NoListeners
+	overflow atomic.Int32 // OverflowPolicy+1 once the dispatch queue overflowed
+
+	failFast   bool
+	forceSync  bool
+	onWorker   bool                       // delivered on a dispatch queue worker
+	fromWorker bool                       // dispatched by a listener of a dispatch delivered on a worker
+	only       []string                   // listener types to deliver to, if set
+	exclude    []string                   // listener types to skip
+	syncErr    atomic.Pointer[EventError] // first error from a sync listener
+	published  chan struct{}              // closed once sync listeners have run
+	subs       []subscriber               // own subscribers checked by checkListeners, if any
+	subsAsync  int                        // async subscribers among subs
+
+	childrenMu sync.Mutex
+	children   []*DispatchHandle // dispatches made from this dispatch's listeners
+
+	resultsMu sync.Mutex
+	results   map[string]ListenerResult // values returned by listeners, see Results
+
+	reply   chan Response // nil unless dispatched by Request
+	abandon *abandonCheck // nil unless WithAbandonedHandleWarnings is used
+	pinned  atomic.Bool   // read by the bus after done, so never pooled
+}
+
+// newDispatchHandle creates a handle whose context keeps the values of
+// parent but not its cancellation, so async listeners outlive the caller.
+// Its deadline is enforced on the bus clock.
+func (ge *GoEvent) newDispatchHandle(parent context.Context, cfg dispatchConfig) *DispatchHandle {
+	handle := handlePool.Get().(*DispatchHandle)
+	handle.id = newID()
+	handle.priority = cfg.priority
+	handle.deadline = cfg.deadline
+	handle.failFast = cfg.failFast
+	handle.forceSync = cfg.forceSync
+	handle.only = cfg.only
+	handle.exclude = cfg.exclude
+	handle.published = make(chan struct{})
+	if cfg.request {
+		handle.reply = make(chan Response, 1)
+	}
+	if handle.errors == nil {
+		handle.errors = make([]*EventError, 0)
+	}
+
+	handle.metadata = Metadata{
+		ID:            handle.id,
+		Time:          time.Now(),
+		Deadline:      cfg.deadline,
+		CorrelationID: handle.id,
+		Priority:      cfg.priority,
+	}
+	// Follow-up dispatches share the correlation of their root dispatch
+	// and are caused by the dispatch being handled
+	if p, ok := HandleFromContext(parent); ok {
+		handle.metadata.CorrelationID = p.metadata.CorrelationID
+		handle.metadata.CausationID = p.metadata.ID
+		handle.metadata.Tenant = p.metadata.Tenant
+	}
+	handle.metadata.apply(cfg.metadata)
+
+	ctx := context.WithValue(context.WithoutCancel(parent), handleContextKey{}, handle)
+	if !cfg.deadline.IsZero() {
+		ctx, handle.cancel = ge.withDeadline(ctx, cfg.deadline)
+	}
+	handle.ctx = ctx
+
+	return handle
+}
+
+// handlePool holds handles given back through Release
+var handlePool = sync.Pool{
+	New: func() any { return new(DispatchHandle) },
+}
+
+// Release gives the handle back for reuse by later dispatches, saving
+// allocations in services dispatching at high rates. It blocks until the
+// dispatch is done. The handle must not be used afterwards, and neither
+// must WaitTree of a dispatch it is a child of. Errors read through
+// GetErrors stay valid.
+func (dh *DispatchHandle) Release() {
+	<-dh.Done()
+	dh.wg.Wait()
+	if dh.pinned.Load() {
+		return
+	}
+	// Let markDone return before the handle is cleared
+	dh.doneMu.Lock()
+	dh.doneMu.Unlock()
+
+	errs := dh.errors
+	clear(errs)
+	*dh = DispatchHandle{errors: errs[:0]}
+	handlePool.Put(dh)
+}
+
+// DispatchID returns the unique ID of this dispatch. It is also set on
+// every EventError the dispatch records and on log records about it.
+func (dh *DispatchHandle) DispatchID() string {
+	return dh.id
 }
 
 // Wait blocks until all async handlers for this specific dispatch complete
@@ -30,30 +191,242 @@ func (dh *DispatchHandle) Wait() {
 	dh.wg.Wait()
 }
 
+// WaitTimeout blocks until all async handlers for this dispatch complete
+// or the timeout elapses, in which case it returns ErrWaitTimeout.
+// The handle remains usable afterwards.
+func (dh *DispatchHandle) WaitTimeout(timeout time.Duration) error {
+	timer := time.NewTimer(timeout)
+	defer timer.Stop()
+
+	select {
+	case <-dh.Done():
+		return nil
+	case <-timer.C:
+		return ErrWaitTimeout
+	}
+}
+
+// WaitContext blocks until all async handlers for this dispatch complete
+// or ctx is done, in which case it returns ctx.Err().
+// The handle remains usable afterwards.
+func (dh *DispatchHandle) WaitContext(ctx context.Context) error {
+	select {
+	case <-dh.Done():
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// WaitTree blocks until this dispatch and every dispatch its listeners
+// made through the context they received, directly or further down the
+// cascade, have completed, or until ctx is done.
+func (dh *DispatchHandle) WaitTree(ctx context.Context) error {
+	pending := []*DispatchHandle{dh}
+	for len(pending) > 0 {
+		handle := pending[len(pending)-1]
+		pending = pending[:len(pending)-1]
+
+		if err := handle.WaitContext(ctx); err != nil {
+			return err
+		}
+
+		// Children are only added while the handle's listeners run,
+		// so the list is complete once the handle is done
+		handle.childrenMu.Lock()
+		pending = append(pending, handle.children...)
+		handle.childrenMu.Unlock()
+	}
+	return nil
+}
+
+// addChild records a dispatch caused by this one
+func (dh *DispatchHandle) addChild(child *DispatchHandle) {
+	dh.childrenMu.Lock()
+	defer dh.childrenMu.Unlock()
+	dh.children = append(dh.children, child)
+}
+
 // Done returns a channel that closes when all handlers complete
 // Useful for select statements
 func (dh *DispatchHandle) Done() <-chan struct{} {
+	dh.doneMu.Lock()
+	defer dh.doneMu.Unlock()
+	if dh.done == nil {
+		dh.done = make(chan struct{})
+	}
 	return dh.done
 }
 
+// Priority returns the priority of this dispatch
+func (dh *DispatchHandle) Priority() Priority {
+	return dh.priority
+}
+
+// Deadline returns the deadline of this dispatch, if any
+func (dh *DispatchHandle) Deadline() (time.Time, bool) {
+	return dh.deadline, !dh.deadline.IsZero()
+}
+
+// Metadata returns the metadata of this dispatch. Its ID is the dispatch
+// ID and its CorrelationID the ID of the dispatch that started the tree,
+// unless set with WithMetadata.
+func (dh *DispatchHandle) Metadata() Metadata {
+	md := dh.metadata
+	if md.Headers != nil {
+		md.Headers = make(map[string]string, len(dh.metadata.Headers))
+		for key, value := range dh.metadata.Headers {
+			md.Headers[key] = value
+		}
+	}
+	return md
+}
+
+// Shed reports whether the event was dropped by load shedding, a
+// bus-level rate limit, or dispatch queue overflow instead of being delivered
+func (dh *DispatchHandle) Shed() bool {
+	return dh.shed.Load()
+}
+
+// NoListeners reports whether the event reached none of its own
+// listeners, because none was registered for it or all were skipped, or
+// whether the dispatch was rejected with ErrNoListeners. AnyListeners
+// do not count, as for RequireListeners. It is false until the event is
+// published, so check it after Wait for dispatches that may be queued.
+func (dh *DispatchHandle) NoListeners() bool {
+	return dh.orphan.Load()
+}
+
+// Overflow reports which overflow policy affected this dispatch because
+// the dispatch queue was full, if any
+func (dh *DispatchHandle) Overflow() (OverflowPolicy, bool) {
+	policy := dh.overflow.Load()
+	return OverflowPolicy(policy - 1), policy != 0
+}
+
+// setOverflow records the overflow policy that affected this dispatch
+func (dh *DispatchHandle) setOverflow(policy OverflowPolicy) {
+	dh.overflow.Store(int32(policy) + 1)
+}
+
 // GetErrors returns errors that occurred during this specific dispatch
 func (dh *DispatchHandle) GetErrors() []*EventError {
 	dh.errorsMu.Lock()
 	defer dh.errorsMu.Unlock()
 
+	dh.markRead()
 	errorsCopy := make([]*EventError, len(dh.errors))
 	copy(errorsCopy, dh.errors)
 	return errorsCopy
 }
 
+// errorsSnapshot returns the errors of this dispatch like GetErrors,
+// without marking them read. The bus uses it to act on a dispatch's
+// outcome, which is not the caller reading the errors.
+func (dh *DispatchHandle) errorsSnapshot() []*EventError {
+	dh.errorsMu.Lock()
+	defer dh.errorsMu.Unlock()
+	return append([]*EventError(nil), dh.errors...)
+}
+
+// Err returns the errors of this dispatch joined with errors.Join, or
+// nil if there were none. Use errors.As to get at each *EventError.
+func (dh *DispatchHandle) Err() error {
+	return joinErrors(dh.GetErrors())
+}
+
+// joinErrors joins errs into one error, or returns nil if errs is empty
+func joinErrors(errs []*EventError) error {
+	if len(errs) == 0 {
+		return nil
+	}
+	joined := make([]error, len(errs))
+	for i, err := range errs {
+		joined[i] = err
+	}
+	return errors.Join(joined...)
+}
+
+// markRead tells the abandon check, if any, that the caller has seen
+// the errors recorded so far
+func (dh *DispatchHandle) markRead() {
+	if dh.abandon != nil {
+		dh.abandon.read()
+	}
+}
+
 // recordError stores an error for this specific dispatch
 func (dh *DispatchHandle) recordError(err *EventError) {
 	dh.errorsMu.Lock()
 	defer dh.errorsMu.Unlock()
 	dh.errors = append(dh.errors, err)
+	if dh.abandon != nil {
+		dh.abandon.recordError(err)
+	}
+}
+
+// newError returns an EventError for this dispatch
+func (dh *DispatchHandle) newError(eventName string, err error) *EventError {
+	return &EventError{
+		EventName:    eventName,
+		DispatchID:   dh.id,
+		EventID:      dh.metadata.ID,
+		DispatchedAt: dh.metadata.Time,
+		Err:          err,
+	}
 }
 
+// aborted reports whether a fail-fast dispatch should skip the
+// remaining sync listeners
+func (dh *DispatchHandle) aborted() bool {
+	return dh.failFast && dh.syncErr.Load() != nil
+}
+
+// hold keeps the dispatch open for n more pending calls, such as async
+// listeners or deliveries held by a queue, each ended by release
+func (dh *DispatchHandle) hold(n int) {
+	dh.holds.Add(int64(n))
+	dh.wg.Add(n)
+}
+
+// release ends a hold
+func (dh *DispatchHandle) release() {
+	dh.settle()
+	dh.wg.Done()
+}
+
+// settle drops one hold, marking the dispatch done when it was the last
+// one and the event has been published. Dispatches that end without
+// being published are marked done by whatever ended them.
+func (dh *DispatchHandle) settle() {
+	if dh.holds.Add(-1) != 0 {
+		return
+	}
+	select {
+	case <-dh.published:
+		dh.markDone()
+	default:
+	}
+}
+
+// closedDone is the done channel of handles that completed before
+// anyone asked for it
+var closedDone = func() chan struct{} {
+	c := make(chan struct{})
+	close(c)
+	return c
+}()
+
 // markDone signals that all handlers have completed
 func (dh *DispatchHandle) markDone() {
+	if dh.cancel != nil {
+		dh.cancel()
+	}
+	dh.doneMu.Lock()
+	defer dh.doneMu.Unlock()
+	if dh.done == nil {
+		dh.done = closedDone
+		return
+	}
 	close(dh.done)
 }
@@ -0,0 +1,186 @@
+package goevent
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestDispatchHandle_WaitTimeout(t *testing.T) {
+	evt := New()
+	blocking := &testBlockingListener{release: make(chan struct{})}
+	evt.RegisterListener(blocking)
+
+	handle := evt.Dispatch(&TestEvent{data: "hang"})
+
+	if err := handle.WaitTimeout(10 * time.Millisecond); !errors.Is(err, ErrWaitTimeout) {
+		t.Fatalf("Expected ErrWaitTimeout, got %v", err)
+	}
+
+	close(blocking.release)
+	if err := handle.WaitTimeout(time.Second); err != nil {
+		t.Errorf("Expected handle to stay usable after a timeout, got %v", err)
+	}
+}
+
+func TestDispatchHandle_WaitContext(t *testing.T) {
+	evt := New()
+	blocking := &testBlockingListener{release: make(chan struct{})}
+	evt.RegisterListener(blocking)
+
+	handle := evt.Dispatch(&TestEvent{data: "hang"})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if err := handle.WaitContext(ctx); !errors.Is(err, context.Canceled) {
+		t.Fatalf("Expected context.Canceled, got %v", err)
+	}
+
+	close(blocking.release)
+	if err := handle.WaitContext(context.Background()); err != nil {
+		t.Errorf("Expected nil after completion, got %v", err)
+	}
+}
+
+func TestDispatchHandle_DispatchID(t *testing.T) {
+	evt := New()
+	evt.RegisterListener(&testErrorListener{})
+
+	first := evt.Dispatch(&TestEvent{data: "first"})
+	second := evt.Dispatch(&TestEvent{data: "second"})
+
+	if first.DispatchID() == "" || first.DispatchID() == second.DispatchID() {
+		t.Fatalf("Expected unique dispatch IDs, got '%s' and '%s'", first.DispatchID(), second.DispatchID())
+	}
+
+	errs := evt.GetErrors()
+	if len(errs) != 2 {
+		t.Fatalf("Expected 2 errors, got %d", len(errs))
+	}
+	if errs[0].DispatchID != first.DispatchID() || errs[1].DispatchID != second.DispatchID() {
+		t.Errorf("Expected errors to carry their dispatch IDs, got '%s' and '%s'", errs[0].DispatchID, errs[1].DispatchID)
+	}
+}
+
+func TestDispatchHandle_SyncOnlyDoneOnReturn(t *testing.T) {
+	evt := New()
+	evt.RegisterListener(&testSyncListener{})
+
+	handle := evt.Dispatch(&TestEvent{})
+	select {
+	case <-handle.Done():
+	default:
+		t.Fatal("Expected a sync-only dispatch to be done when Dispatch returns")
+	}
+}
+
+func TestDispatchHandle_DoneAfterAsyncListeners(t *testing.T) {
+	evt := New()
+	listener := &testBlockingListener{release: make(chan struct{})}
+	evt.RegisterListener(&testSyncListener{}, listener)
+
+	handle := evt.Dispatch(&TestEvent{})
+	select {
+	case <-handle.Done():
+		t.Fatal("Dispatch done before its async listener finished")
+	default:
+	}
+
+	close(listener.release)
+	if err := handle.WaitTimeout(time.Second); err != nil {
+		t.Fatalf("WaitTimeout() failed: %v", err)
+	}
+}
+
+func TestDispatchHandle_Release(t *testing.T) {
+	evt := New()
+	evt.RegisterListener(&testErrorListener{})
+
+	handle := evt.Dispatch(&TestEvent{})
+	errs := handle.GetErrors()
+	handle.Release()
+
+	if len(errs) != 1 || errs[0].Err == nil {
+		t.Fatalf("Expected errors read before Release to stay valid, got %v", errs)
+	}
+
+	// A reused handle starts out clean
+	evt.RegisterListener(&testSyncListener{})
+	next := evt.Dispatch(&GenericEvent{EventName: "other.event"})
+	if next.DispatchID() == "" || len(next.GetErrors()) != 0 || next.Shed() {
+		t.Errorf("Expected a fresh handle, got id %q errors %v", next.DispatchID(), next.GetErrors())
+	}
+}
+
+func TestDispatchHandle_ReleaseWaitsForAsyncListeners(t *testing.T) {
+	evt := New()
+	listener := &testBlockingListener{release: make(chan struct{})}
+	evt.RegisterListener(listener)
+	handle := evt.Dispatch(&TestEvent{})
+
+	released := make(chan struct{})
+	go func() {
+		handle.Release()
+		close(released)
+	}()
+
+	select {
+	case <-released:
+		t.Fatal("Release returned before the dispatch was done")
+	case <-time.After(20 * time.Millisecond):
+	}
+	close(listener.release)
+	<-released
+}
+
+func TestDispatchHandle_Err(t *testing.T) {
+	evt := New()
+	evt.RegisterListener(&testSyncListener{})
+	if err := evt.Dispatch(&TestEvent{}).Err(); err != nil {
+		t.Fatalf("Expected nil Err() without errors, got %v", err)
+	}
+
+	evt.RegisterListener(&testErrorListener{}, &testErrorListener{})
+	err := evt.Dispatch(&TestEvent{}).Err()
+	var eventErr *EventError
+	if !errors.As(err, &eventErr) || eventErr.EventName != "test.event" {
+		t.Fatalf("Expected an *EventError through errors.As, got %v", err)
+	}
+	if joined, ok := err.(interface{ Unwrap() []error }); !ok || len(joined.Unwrap()) != 2 {
+		t.Errorf("Expected both listener errors joined, got %v", err)
+	}
+}
+
+func TestEventError_UnwrapAndContext(t *testing.T) {
+	evt := New()
+	evt.RegisterListener(&testErrorListener{})
+
+	handle := evt.Dispatch(&TestEvent{})
+	errs := handle.GetErrors()
+	if len(errs) != 1 {
+		t.Fatalf("Expected 1 error, got %d", len(errs))
+	}
+	eventErr := errs[0]
+	if errors.Unwrap(eventErr) != eventErr.Err {
+		t.Error("Expected Unwrap to return the listener error")
+	}
+
+	metadata := handle.Metadata()
+	if eventErr.EventID != metadata.ID || !eventErr.DispatchedAt.Equal(metadata.Time) {
+		t.Errorf("Expected event ID %q and time %v, got %q and %v",
+			metadata.ID, metadata.Time, eventErr.EventID, eventErr.DispatchedAt)
+	}
+	if eventErr.Attempts != 1 || eventErr.Duration <= 0 {
+		t.Errorf("Expected 1 attempt with a duration, got %d attempts in %v", eventErr.Attempts, eventErr.Duration)
+	}
+}
+
+func TestEventError_IsThroughErr(t *testing.T) {
+	evt := New()
+	evt.Close(context.Background())
+
+	if err := evt.Dispatch(&TestEvent{}).Err(); !errors.Is(err, ErrClosed) {
+		t.Errorf("Expected errors.Is to find ErrClosed, got %v", err)
+	}
+}
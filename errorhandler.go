@@ -0,0 +1,20 @@
+package goevent
+
+// WithErrorHandler calls handler with every error as it is recorded, for
+// pushing errors straight to an error tracker instead of polling
+// GetErrors. Errors are still collected. The handler runs on the
+// goroutine that hit the error, after the error was collected, so it
+// should return quickly; it may call back into the bus. Several handlers
+// are called in the order they were added.
+func WithErrorHandler(handler func(*EventError)) Option {
+	return func(ge *GoEvent) {
+		ge.errorHandlers = append(ge.errorHandlers, handler)
+	}
+}
+
+// handleError passes err to the error handlers
+func (ge *GoEvent) handleError(err *EventError) {
+	for _, handler := range ge.errorHandlers {
+		handler(err)
+	}
+}
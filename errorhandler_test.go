@@ -0,0 +1,32 @@
+package goevent
+
+import (
+	"sync"
+	"testing"
+)
+
+func TestWithErrorHandler(t *testing.T) {
+	var (
+		mu      sync.Mutex
+		handled []*EventError
+	)
+	var evt *GoEvent
+	evt = New(WithErrorHandler(func(err *EventError) {
+		mu.Lock()
+		defer mu.Unlock()
+		handled = append(handled, err)
+		// The error is collected before handlers run
+		if len(evt.GetErrors()) != len(handled) {
+			t.Error("Expected the error to be collected before the handler runs")
+		}
+	}))
+	evt.RegisterListener(&testErrorListener{})
+
+	handle := evt.Dispatch(&TestEvent{})
+	if len(handled) != 1 || handled[0] != handle.GetErrors()[0] {
+		t.Fatalf("Expected the dispatch's error to be handled, got %v", handled)
+	}
+	if handled[0].EventName != "test.event" {
+		t.Errorf("Expected the error of test.event, got %s", handled[0].EventName)
+	}
+}
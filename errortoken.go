@@ -0,0 +1,77 @@
+package goevent
+
+// ErrorToken marks a position in the stream of errors recorded by the
+// bus. The zero token is the start of the stream.
+type ErrorToken uint64
+
+// GetErrorsSince returns the errors recorded at or after token, along
+// with the token to pass on the next call. Each consumer keeps its own
+// token, so several of them can follow the error stream without
+// clearing it for one another:
+//
+//	var token goevent.ErrorToken
+//	for range ticker.C {
+//		var errs []*goevent.EventError
+//		errs, token = bus.GetErrorsSince(token)
+//		report(errs)
+//	}
+func (ge *GoEvent) GetErrorsSince(token ErrorToken) ([]*EventError, ErrorToken) {
+	ge.errorsMu.Lock()
+	defer ge.errorsMu.Unlock()
+
+	next := ge.errorsBase + ErrorToken(len(ge.errors))
+	if token < ge.errorsBase {
+		token = ge.errorsBase // errors before it were cleared
+	}
+	if token >= next {
+		return nil, next
+	}
+
+	retained := ge.errors[token-ge.errorsBase:]
+	errs := make([]*EventError, len(retained))
+	copy(errs, retained)
+	return errs, next
+}
+
+// ErrorsToken returns the token of the next error to be recorded, for
+// consumers that are only interested in errors from now on
+func (ge *GoEvent) ErrorsToken() ErrorToken {
+	ge.errorsMu.Lock()
+	defer ge.errorsMu.Unlock()
+	return ge.errorsBase + ErrorToken(len(ge.errors))
+}
+
+// WithMaxErrors keeps only the n most recent errors, so the error
+// collection stays bounded in long-running services. Older errors are
+// evicted as new ones are recorded and counted by DroppedErrors; a
+// GetErrorsSince consumer that fell behind resumes at the oldest error
+// still kept. Error handlers and dispatch handles see every error.
+func WithMaxErrors(n int) Option {
+	return func(ge *GoEvent) {
+		ge.maxErrors = n
+	}
+}
+
+// DroppedErrors returns the number of errors evicted because of
+// WithMaxErrors. Errors removed by ClearErrors are not counted.
+func (ge *GoEvent) DroppedErrors() uint64 {
+	ge.errorsMu.Lock()
+	defer ge.errorsMu.Unlock()
+	return ge.droppedErrors
+}
+
+// evictErrors drops the oldest errors beyond maxErrors. The caller must
+// hold errorsMu.
+func (ge *GoEvent) evictErrors() {
+	if ge.maxErrors <= 0 {
+		return
+	}
+	for len(ge.errors) > ge.maxErrors {
+		// Reslicing lets append move the kept errors to a new array as
+		// the old one fills up, so memory stays proportional to maxErrors
+		ge.errors[0] = nil
+		ge.errors = ge.errors[1:]
+		ge.errorsBase++
+		ge.droppedErrors++
+	}
+}
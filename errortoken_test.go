@@ -0,0 +1,81 @@
+package goevent
+
+import "testing"
+
+func TestGetErrorsSince_IndependentConsumers(t *testing.T) {
+	evt := New()
+	evt.RegisterListener(&testErrorListener{})
+
+	evt.Dispatch(&TestEvent{})
+	errsA, tokenA := evt.GetErrorsSince(0)
+	if len(errsA) != 1 {
+		t.Fatalf("Expected 1 error, got %d", len(errsA))
+	}
+
+	tokenB := evt.ErrorsToken()
+	evt.Dispatch(&TestEvent{})
+	evt.Dispatch(&TestEvent{})
+
+	errsA, tokenA = evt.GetErrorsSince(tokenA)
+	errsB, tokenB := evt.GetErrorsSince(tokenB)
+	if len(errsA) != 2 || len(errsB) != 2 {
+		t.Errorf("Expected both consumers to see 2 new errors, got %d and %d", len(errsA), len(errsB))
+	}
+
+	if errs, _ := evt.GetErrorsSince(tokenA); len(errs) != 0 {
+		t.Errorf("Expected no new errors, got %d", len(errs))
+	}
+	if tokenA != tokenB || tokenA != 3 {
+		t.Errorf("Expected both tokens at 3, got %d and %d", tokenA, tokenB)
+	}
+	if len(evt.GetErrors()) != 3 {
+		t.Errorf("Expected reads to leave errors in place, got %d", len(evt.GetErrors()))
+	}
+}
+
+func TestGetErrorsSince_AfterClear(t *testing.T) {
+	evt := New()
+	evt.RegisterListener(&testErrorListener{})
+
+	evt.Dispatch(&TestEvent{})
+	_, token := evt.GetErrorsSince(0)
+	evt.Dispatch(&TestEvent{})
+	evt.ClearErrors()
+	evt.Dispatch(&TestEvent{})
+
+	errs, next := evt.GetErrorsSince(token)
+	if len(errs) != 1 {
+		t.Errorf("Expected only the error recorded after clearing, got %d", len(errs))
+	}
+	if next != 3 {
+		t.Errorf("Expected token 3, got %d", next)
+	}
+}
+
+func TestWithMaxErrors(t *testing.T) {
+	evt := New(WithMaxErrors(2))
+	evt.RegisterListener(&testErrorListener{})
+
+	token := evt.ErrorsToken()
+	for i := 0; i < 5; i++ {
+		evt.Dispatch(&TestEvent{})
+	}
+
+	if errs := evt.GetErrors(); len(errs) != 2 {
+		t.Errorf("Expected 2 retained errors, got %d", len(errs))
+	}
+	if dropped := evt.DroppedErrors(); dropped != 3 {
+		t.Errorf("Expected 3 dropped errors, got %d", dropped)
+	}
+
+	// A consumer that fell behind resumes at the oldest retained error
+	errs, token := evt.GetErrorsSince(token)
+	if len(errs) != 2 || token != 5 {
+		t.Errorf("Expected 2 errors up to token 5, got %d up to %d", len(errs), token)
+	}
+
+	evt.ClearErrors()
+	if evt.DroppedErrors() != 3 {
+		t.Error("Expected ClearErrors not to count as dropped")
+	}
+}
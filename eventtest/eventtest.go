@@ -0,0 +1,195 @@
+// Package eventtest helps testing code that dispatches events.
+//
+// A RecordingBus is a real bus, so registered listeners still run, that
+// also records every dispatch made through it:
+//
+//	bus := eventtest.New()
+//	service := NewUserService(bus)
+//	service.Register("ada@example.com")
+//
+//	bus.AssertDispatched(t, "user.created", eventtest.Payload("email", "ada@example.com"))
+//	bus.AssertNotDispatched(t, "user.deleted")
+//
+// Code under test should depend on an interface with the dispatch methods
+// it uses, such as goevent.Emitter, so it accepts a RecordingBus.
+// Dispatches made directly on the wrapped *goevent.GoEvent, including
+// those of DispatchContext.Emit, are not recorded; pass the RecordingBus
+// as the Emitter of a goevent.DispatchContext to record what a listener
+// emits.
+package eventtest
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/openframebox/goevent"
+)
+
+// Recorded is a dispatch captured by a RecordingBus
+type Recorded struct {
+	Event  goevent.Event
+	Handle *goevent.DispatchHandle // nil for DispatchE
+	Time   time.Time
+}
+
+// RecordingBus is a bus that records the events dispatched through it
+type RecordingBus struct {
+	*goevent.GoEvent
+
+	mu      sync.Mutex
+	records []Recorded
+}
+
+// New creates a RecordingBus around a new bus configured with opts
+func New(opts ...goevent.Option) *RecordingBus {
+	return Wrap(goevent.New(opts...))
+}
+
+// Wrap creates a RecordingBus around an existing bus
+func Wrap(bus *goevent.GoEvent) *RecordingBus {
+	return &RecordingBus{GoEvent: bus}
+}
+
+// Dispatch records and dispatches an event
+func (r *RecordingBus) Dispatch(event goevent.Event, opts ...goevent.DispatchOption) *goevent.DispatchHandle {
+	handle := r.GoEvent.Dispatch(event, opts...)
+	r.record(event, handle)
+	return handle
+}
+
+// DispatchContext records and dispatches an event
+func (r *RecordingBus) DispatchContext(ctx context.Context, event goevent.Event, opts ...goevent.DispatchOption) *goevent.DispatchHandle {
+	handle := r.GoEvent.DispatchContext(ctx, event, opts...)
+	r.record(event, handle)
+	return handle
+}
+
+// DispatchE records and dispatches an event
+func (r *RecordingBus) DispatchE(event goevent.Event, opts ...goevent.DispatchOption) error {
+	err := r.GoEvent.DispatchE(event, opts...)
+	r.record(event, nil)
+	return err
+}
+
+func (r *RecordingBus) record(event goevent.Event, handle *goevent.DispatchHandle) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.records = append(r.records, Recorded{Event: event, Handle: handle, Time: time.Now()})
+}
+
+// Recorded returns every recorded dispatch, oldest first
+func (r *RecordingBus) Recorded() []Recorded {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return append([]Recorded(nil), r.records...)
+}
+
+// Dispatched returns the recorded events with the given name that match
+// every matcher, oldest first
+func (r *RecordingBus) Dispatched(eventName string, matchers ...Matcher) []goevent.Event {
+	var events []goevent.Event
+	for _, rec := range r.Recorded() {
+		if rec.Event.Name() == eventName && matchAll(rec.Event, matchers) {
+			events = append(events, rec.Event)
+		}
+	}
+	return events
+}
+
+// Reset forgets the recorded dispatches
+func (r *RecordingBus) Reset() {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.records = nil
+}
+
+// AssertDispatched fails the test unless an event with the given name
+// matching every matcher was dispatched. It returns the first such event.
+func (r *RecordingBus) AssertDispatched(t testing.TB, eventName string, matchers ...Matcher) goevent.Event {
+	t.Helper()
+	events := r.Dispatched(eventName, matchers...)
+	if len(events) == 0 {
+		t.Errorf("Expected %s to be dispatched, got %s", eventName, r.summary())
+		return nil
+	}
+	return events[0]
+}
+
+// AssertDispatchedTimes fails the test unless exactly n events with the
+// given name matching every matcher were dispatched
+func (r *RecordingBus) AssertDispatchedTimes(t testing.TB, eventName string, n int, matchers ...Matcher) {
+	t.Helper()
+	if got := len(r.Dispatched(eventName, matchers...)); got != n {
+		t.Errorf("Expected %s to be dispatched %d times, got %d in %s", eventName, n, got, r.summary())
+	}
+}
+
+// AssertNotDispatched fails the test if an event with the given name
+// matching every matcher was dispatched
+func (r *RecordingBus) AssertNotDispatched(t testing.TB, eventName string, matchers ...Matcher) {
+	t.Helper()
+	if events := r.Dispatched(eventName, matchers...); len(events) > 0 {
+		t.Errorf("Expected %s not to be dispatched, got %v", eventName, events[0].Payload())
+	}
+}
+
+// summary describes the recorded dispatches for failure messages
+func (r *RecordingBus) summary() string {
+	records := r.Recorded()
+	if len(records) == 0 {
+		return "no events"
+	}
+	lines := make([]string, len(records))
+	for i, rec := range records {
+		lines[i] = fmt.Sprintf("%s %v", rec.Event.Name(), rec.Event.Payload())
+	}
+	return "[" + strings.Join(lines, ", ") + "]"
+}
+
+// Matcher selects recorded events
+type Matcher func(event goevent.Event) bool
+
+// Payload matches events whose payload has key set to value
+func Payload(key string, value any) Matcher {
+	return func(event goevent.Event) bool {
+		actual, ok := event.Payload()[key]
+		return ok && reflect.DeepEqual(actual, value)
+	}
+}
+
+// PayloadSubset matches events whose payload contains every entry of
+// subset
+func PayloadSubset(subset map[string]any) Matcher {
+	return func(event goevent.Event) bool {
+		payload := event.Payload()
+		for key, value := range subset {
+			actual, ok := payload[key]
+			if !ok || !reflect.DeepEqual(actual, value) {
+				return false
+			}
+		}
+		return true
+	}
+}
+
+// OfType matches events of the concrete type T
+func OfType[T goevent.Event]() Matcher {
+	return func(event goevent.Event) bool {
+		_, ok := event.(T)
+		return ok
+	}
+}
+
+func matchAll(event goevent.Event, matchers []Matcher) bool {
+	for _, match := range matchers {
+		if !match(event) {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,95 @@
+package eventtest
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/openframebox/goevent"
+)
+
+// fakeT records failures instead of failing the test
+type fakeT struct {
+	testing.TB
+	errors []string
+}
+
+func (f *fakeT) Helper() {}
+
+func (f *fakeT) Errorf(format string, args ...any) {
+	f.errors = append(f.errors, fmt.Sprintf(format, args...))
+}
+
+type userCreated struct {
+	Email string
+}
+
+func (e *userCreated) Name() string {
+	return "user.created"
+}
+
+func (e *userCreated) Payload() map[string]any {
+	return map[string]any{"email": e.Email}
+}
+
+type testListener struct {
+	called bool
+}
+
+func (l *testListener) EventName() string {
+	return "user.created"
+}
+
+func (l *testListener) OnEvent(event goevent.Event) error {
+	l.called = true
+	return nil
+}
+
+func TestRecordingBus_Assertions(t *testing.T) {
+	bus := New()
+	bus.Dispatch(&userCreated{Email: "ada@example.com"})
+	bus.DispatchContext(context.Background(), &goevent.GenericEvent{EventName: "user.login", Data: map[string]any{"attempt": 1}})
+
+	event := bus.AssertDispatched(t, "user.created", Payload("email", "ada@example.com"), OfType[*userCreated]())
+	if event.(*userCreated).Email != "ada@example.com" {
+		t.Errorf("Expected the matching event, got %v", event)
+	}
+	bus.AssertDispatchedTimes(t, "user.login", 1, PayloadSubset(map[string]any{"attempt": 1}))
+	bus.AssertNotDispatched(t, "user.deleted")
+
+	fake := &fakeT{}
+	bus.AssertDispatched(fake, "user.created", Payload("email", "bob@example.com"))
+	bus.AssertNotDispatched(fake, "user.login")
+	bus.AssertDispatchedTimes(fake, "user.created", 2)
+	if len(fake.errors) != 3 {
+		t.Fatalf("Expected 3 failures, got %v", fake.errors)
+	}
+	if !strings.Contains(fake.errors[0], "ada@example.com") {
+		t.Errorf("Expected the failure to list dispatched events, got %q", fake.errors[0])
+	}
+}
+
+func TestRecordingBus_ListenersStillRun(t *testing.T) {
+	bus := New()
+	listener := &testListener{}
+	bus.RegisterListener(listener)
+
+	bus.Dispatch(&userCreated{})
+	if !listener.called {
+		t.Error("Expected the registered listener to be called")
+	}
+
+	bus.Reset()
+	if len(bus.Recorded()) != 0 {
+		t.Errorf("Expected Reset to forget dispatches, got %d", len(bus.Recorded()))
+	}
+}
+
+func TestRecordingBus_RecordsEmits(t *testing.T) {
+	bus := New()
+	dc := goevent.DispatchContext{Context: context.Background(), Emitter: bus}
+	dc.Emit(&goevent.GenericEvent{EventName: "welcome.sent"})
+
+	bus.AssertDispatched(t, "welcome.sent")
+}
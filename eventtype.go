@@ -0,0 +1,77 @@
+package goevent
+
+import (
+	"encoding/json"
+	"fmt"
+	"sync"
+)
+
+// PayloadUnmarshaler is implemented by registered event types that
+// restore their fields from a payload themselves. Other types are
+// restored by decoding the payload as JSON into the value returned by
+// their factory.
+type PayloadUnmarshaler interface {
+	UnmarshalPayload(payload map[string]any) error
+}
+
+var eventTypes struct {
+	mu        sync.RWMutex
+	factories map[string]func() Event
+}
+
+// RegisterEventType registers the concrete type of the events with the
+// given name, so events read back from a Codec, Store or dead-letter file
+// are reconstructed as that type instead of *GenericEvent. factory must
+// return a new pointer on every call. Registering a name again replaces
+// its factory.
+func RegisterEventType(name string, factory func() Event) {
+	if factory == nil {
+		panic("goevent: RegisterEventType: nil factory for " + name)
+	}
+
+	eventTypes.mu.Lock()
+	defer eventTypes.mu.Unlock()
+	if eventTypes.factories == nil {
+		eventTypes.factories = make(map[string]func() Event)
+	}
+	eventTypes.factories[name] = factory
+}
+
+// DecodeEvent reconstructs an event from its name and payload, using the
+// type registered with RegisterEventType or *GenericEvent if there is none
+func DecodeEvent(name string, payload map[string]any) (Event, error) {
+	eventTypes.mu.RLock()
+	factory := eventTypes.factories[name]
+	eventTypes.mu.RUnlock()
+
+	if factory == nil {
+		return &GenericEvent{EventName: name, Data: payload}, nil
+	}
+
+	event := factory()
+	if unmarshaler, ok := event.(PayloadUnmarshaler); ok {
+		if err := unmarshaler.UnmarshalPayload(payload); err != nil {
+			return nil, fmt.Errorf("goevent: decode %s: %w", name, err)
+		}
+		return event, nil
+	}
+
+	data, err := json.Marshal(payload)
+	if err == nil {
+		err = json.Unmarshal(data, event)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("goevent: decode %s into %T: %w", name, event, err)
+	}
+	return event, nil
+}
+
+// decodeOrGeneric reconstructs an event like DecodeEvent, falling back
+// to *GenericEvent when the payload no longer fits the registered type
+func decodeOrGeneric(name string, payload map[string]any) Event {
+	event, err := DecodeEvent(name, payload)
+	if err != nil {
+		return &GenericEvent{EventName: name, Data: payload}
+	}
+	return event
+}
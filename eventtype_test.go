@@ -0,0 +1,94 @@
+package goevent
+
+import (
+	"fmt"
+	"testing"
+)
+
+type testOrderEvent struct {
+	OrderID string  `json:"order_id"`
+	Total   float64 `json:"total"`
+}
+
+func (e *testOrderEvent) Name() string {
+	return "test.order"
+}
+
+func (e *testOrderEvent) Payload() map[string]any {
+	return map[string]any{"order_id": e.OrderID, "total": e.Total}
+}
+
+type testTaggedEvent struct {
+	tag string
+}
+
+func (e *testTaggedEvent) Name() string {
+	return "test.tagged"
+}
+
+func (e *testTaggedEvent) Payload() map[string]any {
+	return map[string]any{"tag": e.tag}
+}
+
+func (e *testTaggedEvent) UnmarshalPayload(payload map[string]any) error {
+	tag, ok := payload["tag"].(string)
+	if !ok {
+		return fmt.Errorf("tag is %T", payload["tag"])
+	}
+	e.tag = tag
+	return nil
+}
+
+func TestRegisterEventType_Codec(t *testing.T) {
+	RegisterEventType("test.order", func() Event { return &testOrderEvent{} })
+
+	var codec JSONCodec
+	data, err := codec.Marshal(NewEnvelope(&testOrderEvent{OrderID: "o-1", Total: 12.5}))
+	if err != nil {
+		t.Fatalf("Marshal() failed: %v", err)
+	}
+	env, err := codec.Unmarshal(data)
+	if err != nil {
+		t.Fatalf("Unmarshal() failed: %v", err)
+	}
+
+	order, ok := env.Event.(*testOrderEvent)
+	if !ok {
+		t.Fatalf("Expected *testOrderEvent, got %T", env.Event)
+	}
+	if order.OrderID != "o-1" || order.Total != 12.5 {
+		t.Errorf("Expected o-1 with total 12.5, got %+v", order)
+	}
+}
+
+func TestRegisterEventType_PayloadUnmarshaler(t *testing.T) {
+	RegisterEventType("test.tagged", func() Event { return &testTaggedEvent{} })
+
+	event, err := DecodeEvent("test.tagged", map[string]any{"tag": "blue"})
+	if err != nil {
+		t.Fatalf("DecodeEvent() failed: %v", err)
+	}
+	if tagged, ok := event.(*testTaggedEvent); !ok || tagged.tag != "blue" {
+		t.Errorf("Expected tagged event with tag blue, got %#v", event)
+	}
+
+	if _, err := DecodeEvent("test.tagged", map[string]any{"tag": 1}); err == nil {
+		t.Error("Expected an error for a payload that does not fit")
+	}
+
+	// Stored events fall back to GenericEvent instead of failing
+	stored := StoredEvent{Name: "test.tagged", Payload: map[string]any{"tag": 1}}
+	if _, ok := stored.Event().(*GenericEvent); !ok {
+		t.Errorf("Expected *GenericEvent fallback, got %T", stored.Event())
+	}
+}
+
+func TestDecodeEvent_Unregistered(t *testing.T) {
+	event, err := DecodeEvent("test.unregistered", map[string]any{"a": 1})
+	if err != nil {
+		t.Fatalf("DecodeEvent() failed: %v", err)
+	}
+	if _, ok := event.(*GenericEvent); !ok {
+		t.Errorf("Expected *GenericEvent, got %T", event)
+	}
+}
@@ -0,0 +1,33 @@
+package goevent
+
+import "context"
+
+// DispatchE dispatches a command-style event in fail-fast mode and
+// returns the first error from a synchronous listener, after which no
+// further synchronous listeners are called. If the dispatch is rejected
+// before delivery, for example by the gate or after Close, that error is
+// returned instead. It does not wait for async listeners.
+func (ge *GoEvent) DispatchE(event Event, opts ...DispatchOption) error {
+	handle := ge.DispatchContext(context.Background(), event, append(opts, FailFast())...)
+
+	// Wait for the sync listeners, or for the dispatch to end without
+	// being published
+	select {
+	case <-handle.published:
+	case <-handle.Done():
+	}
+
+	if err := handle.syncErr.Load(); err != nil {
+		handle.markRead()
+		return err
+	}
+	select {
+	case <-handle.published:
+		return nil
+	default:
+		if errs := handle.GetErrors(); len(errs) > 0 {
+			return errs[0]
+		}
+		return nil
+	}
+}
@@ -0,0 +1,77 @@
+package goevent
+
+import (
+	"context"
+	"errors"
+	"testing"
+)
+
+func TestDispatchE_StopsAtFirstSyncError(t *testing.T) {
+	evt := New()
+	failing := &testErrorListener{}
+	after := &testSyncListener{}
+	evt.RegisterListener(failing, after)
+
+	err := evt.DispatchE(&TestEvent{data: "command"})
+
+	var eventErr *EventError
+	if !errors.As(err, &eventErr) || eventErr.ListenerType != "*goevent.testErrorListener" {
+		t.Fatalf("Expected *EventError from testErrorListener, got %v", err)
+	}
+	if after.called {
+		t.Error("Expected listeners after the failure to be skipped")
+	}
+}
+
+func TestDispatchE_Success(t *testing.T) {
+	evt := New()
+	listener := &testSyncListener{}
+	evt.RegisterListener(listener)
+
+	if err := evt.DispatchE(&TestEvent{data: "command"}); err != nil {
+		t.Errorf("Expected nil, got %v", err)
+	}
+	if !listener.called {
+		t.Error("Expected listener to be called")
+	}
+}
+
+func TestDispatchE_AsyncListenersUnaffected(t *testing.T) {
+	evt := New()
+	async := &testAsyncListener{}
+	evt.RegisterListener(&testErrorListener{}, async)
+
+	if err := evt.DispatchE(&TestEvent{data: "command"}); err == nil {
+		t.Fatal("Expected an error")
+	}
+	evt.Wait()
+
+	if !async.called {
+		t.Error("Expected async listener to still run")
+	}
+}
+
+func TestDispatchE_Rejected(t *testing.T) {
+	evt := New()
+	evt.RegisterListener(&testSyncListener{})
+	evt.Close(context.Background())
+
+	err := evt.DispatchE(&TestEvent{data: "command"})
+
+	var eventErr *EventError
+	if !errors.As(err, &eventErr) || eventErr.Err != ErrClosed {
+		t.Errorf("Expected ErrClosed, got %v", err)
+	}
+}
+
+func TestFailFast_WithoutOptionAllListenersRun(t *testing.T) {
+	evt := New()
+	after := &testSyncListener{}
+	evt.RegisterListener(&testErrorListener{}, after)
+
+	evt.Dispatch(&TestEvent{data: "event"})
+
+	if !after.called {
+		t.Error("Expected all sync listeners to run without FailFast")
+	}
+}
@@ -0,0 +1,191 @@
+// Package federation connects several buses and shares selected events
+// between them, so a modular monolith being split into services can keep
+// its modules talking while they move out one by one.
+//
+// Each bus joins under a name. Routes say which events flow from which
+// bus to which others; Sync shares an event among buses in every
+// direction:
+//
+//	fed := federation.New()
+//	fed.Join("monolith", monolithBus)
+//	fed.Join("billing", billingBus)
+//	fed.Route("order.placed", "monolith", "billing")
+//	fed.Sync("customer.updated", "monolith", "billing")
+//
+// Forwarded dispatches are stamped with the buses the event was already
+// sent to, so no bus receives it twice, however the routes are drawn. A bus in another process joins through a local
+// bus connected with one of the transports (nats, redis, aws, pubsub),
+// which keep events they received from being published back.
+package federation
+
+import (
+	"context"
+	"fmt"
+	"sync"
+
+	"github.com/openframebox/goevent"
+)
+
+// originKey holds the names of the buses a dispatch was sent to, first
+// the one it was originally dispatched on
+type originKey struct{}
+
+// Origin returns the name of the bus an event forwarded by a federation
+// was originally dispatched on. It reports false for events dispatched
+// locally.
+func Origin(ctx context.Context) (string, bool) {
+	path, _ := ctx.Value(originKey{}).([]string)
+	if len(path) == 0 {
+		return "", false
+	}
+	return path[0], true
+}
+
+// Federation shares events between buses
+type Federation struct {
+	mu      sync.RWMutex
+	buses   map[string]*goevent.GoEvent
+	routes  map[string]map[string][]string // event name → source → targets
+	watched map[string]map[string]bool     // source → event names forwarded
+}
+
+// New creates an empty Federation
+func New() *Federation {
+	return &Federation{
+		buses:   make(map[string]*goevent.GoEvent),
+		routes:  make(map[string]map[string][]string),
+		watched: make(map[string]map[string]bool),
+	}
+}
+
+// Join adds a bus under a name unique within the federation
+func (f *Federation) Join(name string, bus *goevent.GoEvent) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if _, ok := f.buses[name]; ok {
+		return fmt.Errorf("federation: %s already joined", name)
+	}
+	f.buses[name] = bus
+	return nil
+}
+
+// Route forwards the events named eventName dispatched on the bus from
+// to the buses to
+func (f *Federation) Route(eventName, from string, to ...string) error {
+	f.mu.Lock()
+	source, err := f.addRoute(eventName, from, to)
+	f.mu.Unlock()
+	if err != nil {
+		return err
+	}
+
+	if source != nil {
+		source.RegisterListener(&forwarder{federation: f, source: from, eventName: eventName})
+	}
+	return nil
+}
+
+// Sync shares the events named eventName among the given buses in every
+// direction
+func (f *Federation) Sync(eventName string, members ...string) error {
+	for _, from := range members {
+		var to []string
+		for _, member := range members {
+			if member != from {
+				to = append(to, member)
+			}
+		}
+		if err := f.Route(eventName, from, to...); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// addRoute records a route and returns the source bus if it needs a
+// forwarder for the event. The caller must hold mu.
+func (f *Federation) addRoute(eventName, from string, to []string) (*goevent.GoEvent, error) {
+	source, ok := f.buses[from]
+	if !ok {
+		return nil, fmt.Errorf("federation: unknown bus %s", from)
+	}
+	for _, target := range to {
+		if _, ok := f.buses[target]; !ok {
+			return nil, fmt.Errorf("federation: unknown bus %s", target)
+		}
+		if target == from {
+			return nil, fmt.Errorf("federation: %s routed to itself", from)
+		}
+	}
+
+	if f.routes[eventName] == nil {
+		f.routes[eventName] = make(map[string][]string)
+	}
+	for _, target := range to {
+		if !contains(f.routes[eventName][from], target) {
+			f.routes[eventName][from] = append(f.routes[eventName][from], target)
+		}
+	}
+
+	if f.watched[from] == nil {
+		f.watched[from] = make(map[string]bool)
+	}
+	if f.watched[from][eventName] {
+		return nil, nil
+	}
+	f.watched[from][eventName] = true
+	return source, nil
+}
+
+// forward dispatches an event from source on every target bus it was not
+// sent to yet
+func (f *Federation) forward(ctx context.Context, source string, event goevent.Event) {
+	path, _ := ctx.Value(originKey{}).([]string)
+	path = append(path[:len(path):len(path)], source)
+
+	// Buses reached by this hop are stamped too, so they do not forward
+	// the event to each other
+	f.mu.RLock()
+	var targets []*goevent.GoEvent
+	for _, name := range f.routes[event.Name()][source] {
+		if !contains(path, name) {
+			path = append(path, name)
+			targets = append(targets, f.buses[name])
+		}
+	}
+	f.mu.RUnlock()
+
+	ctx = context.WithValue(ctx, originKey{}, path)
+	for _, target := range targets {
+		target.DispatchContext(ctx, event)
+	}
+}
+
+// forwarder forwards the events of one name dispatched on a member bus
+type forwarder struct {
+	federation *Federation
+	source     string
+	eventName  string
+}
+
+func (f *forwarder) EventName() string {
+	return f.eventName
+}
+
+func (f *forwarder) OnEvent(event goevent.Event) error {
+	return f.OnEventContext(context.Background(), event)
+}
+
+func (f *forwarder) OnEventContext(ctx context.Context, event goevent.Event) error {
+	f.federation.forward(ctx, f.source, event)
+	return nil
+}
+
+func contains(names []string, name string) bool {
+	for _, n := range names {
+		if n == name {
+			return true
+		}
+	}
+	return false
+}
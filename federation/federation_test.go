@@ -0,0 +1,125 @@
+package federation
+
+import (
+	"context"
+	"sync"
+	"testing"
+
+	"github.com/openframebox/goevent"
+)
+
+// testRecorder counts the events it receives and their origins
+type testRecorder struct {
+	name string
+
+	mu      sync.Mutex
+	count   int
+	origins []string
+}
+
+func (r *testRecorder) EventName() string {
+	return r.name
+}
+
+func (r *testRecorder) OnEvent(event goevent.Event) error {
+	return r.OnEventContext(context.Background(), event)
+}
+
+func (r *testRecorder) OnEventContext(ctx context.Context, event goevent.Event) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.count++
+	if origin, ok := Origin(ctx); ok {
+		r.origins = append(r.origins, origin)
+	}
+	return nil
+}
+
+func (r *testRecorder) Count() int {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.count
+}
+
+func newMembers(t *testing.T, eventName string, names ...string) (*Federation, map[string]*goevent.GoEvent, map[string]*testRecorder) {
+	fed := New()
+	buses := make(map[string]*goevent.GoEvent)
+	recorders := make(map[string]*testRecorder)
+	for _, name := range names {
+		buses[name] = goevent.New()
+		recorders[name] = &testRecorder{name: eventName}
+		buses[name].RegisterListener(recorders[name])
+		if err := fed.Join(name, buses[name]); err != nil {
+			t.Fatalf("Join() failed: %v", err)
+		}
+	}
+	return fed, buses, recorders
+}
+
+func TestRoute_OneWay(t *testing.T) {
+	fed, buses, recorders := newMembers(t, "order.placed", "monolith", "billing")
+	if err := fed.Route("order.placed", "monolith", "billing"); err != nil {
+		t.Fatalf("Route() failed: %v", err)
+	}
+
+	buses["monolith"].Dispatch(&goevent.GenericEvent{EventName: "order.placed"})
+	if recorders["billing"].Count() != 1 {
+		t.Errorf("Expected billing to receive the event, got %d", recorders["billing"].Count())
+	}
+	if origins := recorders["billing"].origins; len(origins) != 1 || origins[0] != "monolith" {
+		t.Errorf("Expected origin monolith, got %v", origins)
+	}
+
+	buses["billing"].Dispatch(&goevent.GenericEvent{EventName: "order.placed"})
+	if recorders["monolith"].Count() != 1 {
+		t.Errorf("Expected events not to flow against the route, got %d", recorders["monolith"].Count())
+	}
+}
+
+func TestSync_NoLoops(t *testing.T) {
+	fed, buses, recorders := newMembers(t, "customer.updated", "a", "b", "c")
+	if err := fed.Sync("customer.updated", "a", "b", "c"); err != nil {
+		t.Fatalf("Sync() failed: %v", err)
+	}
+
+	handle := buses["b"].Dispatch(&goevent.GenericEvent{EventName: "customer.updated"})
+	handle.Wait()
+	for name, recorder := range recorders {
+		if recorder.Count() != 1 {
+			t.Errorf("Expected %s to receive the event once, got %d", name, recorder.Count())
+		}
+	}
+}
+
+func TestRoute_Ring(t *testing.T) {
+	fed, buses, recorders := newMembers(t, "tick", "a", "b", "c")
+	fed.Route("tick", "a", "b")
+	fed.Route("tick", "b", "c")
+	fed.Route("tick", "c", "a")
+
+	buses["a"].Dispatch(&goevent.GenericEvent{EventName: "tick"})
+	for name, recorder := range recorders {
+		if recorder.Count() != 1 {
+			t.Errorf("Expected %s to receive the event once, got %d", name, recorder.Count())
+		}
+	}
+	if origins := recorders["c"].origins; len(origins) != 1 || origins[0] != "a" {
+		t.Errorf("Expected the origin to survive several hops, got %v", origins)
+	}
+}
+
+func TestRoute_UnknownBus(t *testing.T) {
+	fed, _, _ := newMembers(t, "tick", "a")
+	if err := fed.Route("tick", "a", "missing"); err == nil {
+		t.Error("Expected an error for an unknown target")
+	}
+	if err := fed.Route("tick", "missing", "a"); err == nil {
+		t.Error("Expected an error for an unknown source")
+	}
+	if err := fed.Route("tick", "a", "a"); err == nil {
+		t.Error("Expected an error for a route to itself")
+	}
+	if err := fed.Join("a", goevent.New()); err == nil {
+		t.Error("Expected an error for a duplicate name")
+	}
+}
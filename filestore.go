@@ -0,0 +1,337 @@
+package goevent
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"sync"
+	"time"
+)
+
+// FileStore is a Store backed by an append-only write-ahead log.
+// Each append and ack is written as a JSON line and synced to disk
+// before returning. Compact rewrites the log without acknowledged events.
+type FileStore struct {
+	path string
+
+	mu        sync.Mutex
+	file      *os.File
+	events    []StoredEvent
+	index     map[uint64]int // seq -> position in events
+	lastSeq   uint64
+	versions  aggregateVersions
+	snapshots map[string]Snapshot // latest per aggregate
+}
+
+type walRecord struct {
+	Op        string         `json:"op"` // "append", "batch", "ack" or "snapshot"
+	Seq       uint64         `json:"seq"`
+	Name      string         `json:"name,omitempty"`
+	Payload   map[string]any `json:"payload,omitempty"`
+	Time      time.Time      `json:"time,omitempty"`
+	Aggregate string         `json:"aggregate,omitempty"`
+	Version   int            `json:"version,omitempty"`
+	Events    []walRecord    `json:"events,omitempty"` // appends of a batch, written as one line
+	State     []byte         `json:"state,omitempty"`  // snapshot state
+}
+
+// OpenFileStore opens the log at path, creating it if needed, and
+// rebuilds the store from the records already written
+func OpenFileStore(path string) (*FileStore, error) {
+	s := &FileStore{path: path, index: make(map[uint64]int), versions: make(aggregateVersions), snapshots: make(map[string]Snapshot)}
+
+	file, err := os.OpenFile(path, os.O_RDWR|os.O_CREATE, 0o644)
+	if err != nil {
+		return nil, err
+	}
+	size, err := s.load(file)
+	if err != nil {
+		file.Close()
+		return nil, fmt.Errorf("goevent: reading %s: %w", path, err)
+	}
+	// Drop any torn record so new records start on a fresh line
+	if err := file.Truncate(size); err != nil {
+		file.Close()
+		return nil, err
+	}
+	if _, err := file.Seek(size, io.SeekStart); err != nil {
+		file.Close()
+		return nil, err
+	}
+	s.file = file
+	return s, nil
+}
+
+// load replays the log into memory and returns the size of its valid
+// prefix. A torn final line left by a crash during a write is ignored.
+func (s *FileStore) load(r io.Reader) (int64, error) {
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 64*1024), 64*1024*1024)
+	var (
+		size    int64
+		pending error
+	)
+	for scanner.Scan() {
+		if pending != nil {
+			return 0, pending
+		}
+		var record walRecord
+		if err := json.Unmarshal(scanner.Bytes(), &record); err != nil {
+			pending = err
+			continue
+		}
+		s.apply(record)
+		size += int64(len(scanner.Bytes())) + 1
+	}
+	return size, scanner.Err()
+}
+
+func (s *FileStore) apply(record walRecord) {
+	switch record.Op {
+	case "append":
+		s.index[record.Seq] = len(s.events)
+		s.events = append(s.events, StoredEvent{
+			Seq:         record.Seq,
+			Name:        record.Name,
+			Payload:     record.Payload,
+			Time:        record.Time,
+			AggregateID: record.Aggregate,
+			Version:     record.Version,
+		})
+		s.lastSeq = max(s.lastSeq, record.Seq)
+		if record.Aggregate != "" {
+			s.versions[record.Aggregate] = max(s.versions[record.Aggregate], record.Version)
+		}
+	case "batch":
+		for _, appended := range record.Events {
+			s.apply(appended)
+		}
+	case "snapshot":
+		keepLatest(s.snapshots, Snapshot{
+			AggregateID: record.Aggregate,
+			Version:     record.Version,
+			State:       record.State,
+			Time:        record.Time,
+		})
+	case "ack":
+		if i, ok := s.index[record.Seq]; ok {
+			s.events[i].Acked = true
+		}
+	}
+}
+
+// write appends a record to the log and syncs it. The caller must hold mu.
+func (s *FileStore) write(record walRecord) error {
+	if err := writeRecord(s.file, record); err != nil {
+		return err
+	}
+	return s.file.Sync()
+}
+
+// Append persists an event and assigns it the next sequence number
+func (s *FileStore) Append(ctx context.Context, event Event) (StoredEvent, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	record := walRecord{
+		Op:      "append",
+		Seq:     s.lastSeq + 1,
+		Name:    event.Name(),
+		Payload: event.Payload(),
+		Time:    time.Now(),
+	}
+	if err := s.write(record); err != nil {
+		return StoredEvent{}, err
+	}
+	s.apply(record)
+	return s.events[s.index[record.Seq]], nil
+}
+
+// AppendAggregate persists the events raised by an aggregate as one unit.
+// They are written as a single record, so a crash leaves either all or
+// none of them in the log.
+func (s *FileStore) AppendAggregate(ctx context.Context, aggregateID string, expectedVersion int, events []Event) ([]StoredEvent, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if err := s.versions.check(aggregateID, expectedVersion); err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	batch := walRecord{Op: "batch", Events: make([]walRecord, len(events))}
+	for i, event := range events {
+		batch.Events[i] = walRecord{
+			Op:        "append",
+			Seq:       s.lastSeq + uint64(i) + 1,
+			Name:      event.Name(),
+			Payload:   event.Payload(),
+			Time:      now,
+			Aggregate: aggregateID,
+			Version:   expectedVersion + i + 1,
+		}
+	}
+	if err := s.write(batch); err != nil {
+		return nil, err
+	}
+	s.apply(batch)
+
+	stored := make([]StoredEvent, len(events))
+	for i, record := range batch.Events {
+		stored[i] = s.events[s.index[record.Seq]]
+	}
+	return stored, nil
+}
+
+// SaveSnapshot stores a snapshot of an aggregate
+func (s *FileStore) SaveSnapshot(ctx context.Context, snapshot Snapshot) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	record := snapshotRecord(snapshot)
+	if err := s.write(record); err != nil {
+		return err
+	}
+	s.apply(record)
+	return nil
+}
+
+// LoadLatestSnapshot returns the latest snapshot of an aggregate
+func (s *FileStore) LoadLatestSnapshot(ctx context.Context, aggregateID string) (Snapshot, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	snapshot, ok := s.snapshots[aggregateID]
+	if !ok {
+		return Snapshot{}, ErrSnapshotNotFound
+	}
+	return snapshot, nil
+}
+
+func snapshotRecord(snapshot Snapshot) walRecord {
+	return walRecord{
+		Op:        "snapshot",
+		Aggregate: snapshot.AggregateID,
+		Version:   snapshot.Version,
+		State:     snapshot.State,
+		Time:      snapshot.Time,
+	}
+}
+
+// LoadAggregate returns the events of an aggregate above fromVersion
+func (s *FileStore) LoadAggregate(ctx context.Context, aggregateID string, fromVersion int) ([]StoredEvent, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return aggregateEvents(s.events, aggregateID, fromVersion), nil
+}
+
+// ReadFrom returns up to limit events starting at seq
+func (s *FileStore) ReadFrom(ctx context.Context, seq uint64, limit int) ([]StoredEvent, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return readFrom(s.events, seq, limit), nil
+}
+
+// Ack marks an event as fully handled
+func (s *FileStore) Ack(ctx context.Context, seq uint64) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if _, ok := s.index[seq]; !ok {
+		return fmt.Errorf("goevent: unknown sequence number %d", seq)
+	}
+	record := walRecord{Op: "ack", Seq: seq}
+	if err := s.write(record); err != nil {
+		return err
+	}
+	s.apply(record)
+	return nil
+}
+
+// Compact rewrites the log keeping only unacknowledged events, the
+// events of aggregates and their latest snapshots. Sequence numbers are
+// preserved.
+func (s *FileStore) Compact() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	tmpPath := s.path + ".compact"
+	tmp, err := os.Create(tmpPath)
+	if err != nil {
+		return err
+	}
+
+	writer := bufio.NewWriter(tmp)
+	var kept []StoredEvent
+	for _, stored := range s.events {
+		if stored.Acked && stored.AggregateID == "" {
+			continue
+		}
+		err := writeRecord(writer, walRecord{
+			Op:        "append",
+			Seq:       stored.Seq,
+			Name:      stored.Name,
+			Payload:   stored.Payload,
+			Time:      stored.Time,
+			Aggregate: stored.AggregateID,
+			Version:   stored.Version,
+		})
+		if err == nil && stored.Acked {
+			err = writeRecord(writer, walRecord{Op: "ack", Seq: stored.Seq})
+		}
+		if err != nil {
+			tmp.Close()
+			os.Remove(tmpPath)
+			return err
+		}
+		kept = append(kept, stored)
+	}
+	for _, snapshot := range s.snapshots {
+		if err := writeRecord(writer, snapshotRecord(snapshot)); err != nil {
+			tmp.Close()
+			os.Remove(tmpPath)
+			return err
+		}
+	}
+	if err := errors.Join(writer.Flush(), tmp.Sync(), tmp.Close()); err != nil {
+		os.Remove(tmpPath)
+		return err
+	}
+	if err := os.Rename(tmpPath, s.path); err != nil {
+		return err
+	}
+
+	file, err := os.OpenFile(s.path, os.O_WRONLY|os.O_APPEND, 0o644)
+	if err != nil {
+		return err
+	}
+	s.file.Close()
+	s.file = file
+
+	s.events = kept
+	s.index = make(map[uint64]int, len(kept))
+	for i, stored := range kept {
+		s.index[stored.Seq] = i
+	}
+	return nil
+}
+
+// writeRecord writes a record as a JSON line
+func writeRecord(w io.Writer, record walRecord) error {
+	data, err := json.Marshal(record)
+	if err != nil {
+		return err
+	}
+	_, err = w.Write(append(data, '\n'))
+	return err
+}
+
+// Close closes the log file
+func (s *FileStore) Close() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.file.Close()
+}
@@ -0,0 +1,96 @@
+// Package fxmodule wires a goevent bus into an Uber fx application.
+//
+// Module provides a *goevent.GoEvent, registers every listener that
+// constructors put in the listener group, and closes the bus when the
+// application stops:
+//
+//	fx.New(
+//		fxmodule.Module,
+//		fxmodule.Options(goevent.WithLogger(logger)),
+//		fx.Provide(
+//			fxmodule.AsListener(NewWelcomeMailer),
+//			fxmodule.AsListener(NewAuditLog),
+//		),
+//	)
+//
+// Listeners are registered when the application is built, before any
+// OnStart hook runs, so they may themselves depend on the bus.
+package fxmodule
+
+import (
+	"context"
+
+	"go.uber.org/fx"
+
+	"github.com/openframebox/goevent"
+)
+
+const (
+	// ListenerGroup is the fx value group Module collects listeners from
+	ListenerGroup = "goevent.listeners"
+
+	// OptionGroup is the fx value group Module collects bus options from
+	OptionGroup = "goevent.options"
+)
+
+// Module provides the bus and registers the listeners of ListenerGroup
+var Module = fx.Module("goevent",
+	fx.Provide(NewBus),
+	fx.Invoke(RegisterListeners),
+)
+
+// BusParams are the dependencies of NewBus
+type BusParams struct {
+	fx.In
+
+	Lifecycle fx.Lifecycle
+	Options   []goevent.Option `group:"goevent.options"`
+}
+
+// NewBus creates a bus with the options of OptionGroup and closes it when
+// the application stops, waiting for in-flight dispatches until the stop
+// context is done
+func NewBus(p BusParams) *goevent.GoEvent {
+	bus := goevent.New(p.Options...)
+	p.Lifecycle.Append(fx.Hook{
+		OnStop: func(ctx context.Context) error {
+			return bus.Close(ctx)
+		},
+	})
+	return bus
+}
+
+// ListenerParams are the dependencies of RegisterListeners
+type ListenerParams struct {
+	fx.In
+
+	Bus       *goevent.GoEvent
+	Listeners []goevent.Listener `group:"goevent.listeners"`
+}
+
+// RegisterListeners registers the listeners of ListenerGroup on the bus
+func RegisterListeners(p ListenerParams) {
+	p.Bus.RegisterListener(p.Listeners...)
+}
+
+// AsListener annotates a constructor so the listener it returns is put in
+// ListenerGroup. The constructor's result must implement goevent.Listener.
+func AsListener(constructor any) any {
+	return fx.Annotate(constructor,
+		fx.As(new(goevent.Listener)),
+		fx.ResultTags(`group:"goevent.listeners"`),
+	)
+}
+
+// Options supplies options for the bus provided by Module
+func Options(opts ...goevent.Option) fx.Option {
+	provides := make([]fx.Option, len(opts))
+	for i, opt := range opts {
+		opt := opt
+		provides[i] = fx.Provide(fx.Annotate(
+			func() goevent.Option { return opt },
+			fx.ResultTags(`group:"goevent.options"`),
+		))
+	}
+	return fx.Options(provides...)
+}
@@ -0,0 +1,79 @@
+package fxmodule
+
+import (
+	"errors"
+	"sync/atomic"
+	"testing"
+
+	"go.uber.org/fx"
+	"go.uber.org/fx/fxtest"
+
+	"github.com/openframebox/goevent"
+)
+
+type greeter struct {
+	greeted atomic.Int32
+}
+
+func (g *greeter) EventName() string {
+	return "user.created"
+}
+
+func (g *greeter) OnEvent(event goevent.Event) error {
+	g.greeted.Add(1)
+	return nil
+}
+
+// notifier depends on the bus it listens on
+type notifier struct {
+	bus      *goevent.GoEvent
+	notified atomic.Int32
+}
+
+func (n *notifier) EventName() string {
+	return "user.created"
+}
+
+func (n *notifier) OnEvent(event goevent.Event) error {
+	n.notified.Add(1)
+	return nil
+}
+
+func TestModule(t *testing.T) {
+	g := &greeter{}
+	var (
+		bus *goevent.GoEvent
+		n   *notifier
+	)
+	newNotifier := func(bus *goevent.GoEvent) *notifier {
+		n = &notifier{bus: bus}
+		return n
+	}
+	app := fxtest.New(t,
+		Module,
+		Options(goevent.WithHistory(10)),
+		fx.Provide(
+			AsListener(func() *greeter { return g }),
+			AsListener(newNotifier),
+		),
+		fx.Populate(&bus),
+	)
+	app.RequireStart()
+
+	if got := len(bus.Listeners()["user.created"]); got != 2 {
+		t.Fatalf("Expected 2 registered listeners, got %d", got)
+	}
+	bus.Dispatch(&goevent.GenericEvent{EventName: "user.created"}).Wait()
+	if g.greeted.Load() != 1 || n.notified.Load() != 1 {
+		t.Errorf("Expected both listeners to be called once, got %d and %d", g.greeted.Load(), n.notified.Load())
+	}
+	if len(bus.History()) != 1 {
+		t.Error("Expected the supplied option to be applied")
+	}
+
+	app.RequireStop()
+	err := bus.Dispatch(&goevent.GenericEvent{EventName: "user.created"}).Err()
+	if !errors.Is(err, goevent.ErrClosed) {
+		t.Errorf("Expected the bus to be closed with the app, got %v", err)
+	}
+}
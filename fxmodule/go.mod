@@ -0,0 +1,16 @@
+module github.com/openframebox/goevent/fxmodule
+
+go 1.21
+
+require (
+	github.com/openframebox/goevent v0.1.0
+	go.uber.org/fx v1.22.2
+)
+
+require (
+	github.com/santhosh-tekuri/jsonschema/v5 v5.3.1 // indirect
+	go.uber.org/dig v1.18.0 // indirect
+	go.uber.org/multierr v1.10.0 // indirect
+	go.uber.org/zap v1.26.0 // indirect
+	golang.org/x/sys v0.0.0-20220412211240-33da011f77ad // indirect
+)
@@ -0,0 +1,177 @@
+package goevent
+
+import (
+	"errors"
+	"fmt"
+	"maps"
+	"sync"
+	"sync/atomic"
+)
+
+var (
+	// ErrDeferred is returned, or wrapped, by a gate to hold an event
+	// until the gate lets it through
+	ErrDeferred = errors.New("goevent: dispatch deferred by gate")
+
+	// ErrRejected wraps the gate error recorded on a rejected dispatch
+	ErrRejected = errors.New("goevent: dispatch rejected by gate")
+)
+
+type gatedDispatch struct {
+	handle *DispatchHandle
+	event  Event
+	cfg    dispatchConfig
+}
+
+// gate holds deferred dispatches and the current gateState. Dispatches
+// read the state without locking; mu serializes changes to it and to
+// deferred.
+type gate struct {
+	mu       sync.Mutex
+	state    atomic.Pointer[gateState]
+	deferred []gatedDispatch
+}
+
+// gateState is the gate function and pause state. It is never modified
+// once stored, see update.
+type gateState struct {
+	fn           func(Event) error
+	paused       bool            // whole bus, see Pause
+	pausedEvents map[string]bool // see PauseEvents
+	rejectPaused bool            // see WithRejectWhilePaused
+}
+
+// update stores a copy of the state changed by fn. The caller must
+// hold mu.
+func (g *gate) update(fn func(*gateState)) {
+	next := gateState{}
+	if current := g.state.Load(); current != nil {
+		next = *current
+		next.pausedEvents = maps.Clone(current.pausedEvents)
+	}
+	fn(&next)
+	g.state.Store(&next)
+}
+
+// open reports whether the state lets every event through
+func (s *gateState) open() bool {
+	return s == nil || (s.fn == nil && !s.paused && len(s.pausedEvents) == 0)
+}
+
+// SetGate installs a function consulted before every dispatch. Returning
+// nil lets the event through. Returning ErrDeferred, or an error wrapping
+// it, holds the event until the gate lets it through; any other error
+// rejects the dispatch with an EventError wrapping ErrRejected.
+//
+// Deferred events are re-evaluated whenever the gate is replaced and on
+// ReleaseDeferred. SetGate(nil) opens the gate and releases all of them.
+func (ge *GoEvent) SetGate(fn func(Event) error) {
+	ge.gate.mu.Lock()
+	ge.gate.update(func(s *gateState) { s.fn = fn })
+	ge.gate.mu.Unlock()
+
+	ge.ReleaseDeferred()
+}
+
+// ReleaseDeferred re-evaluates events deferred by the gate, dispatching
+// those it now lets through in their original order
+func (ge *GoEvent) ReleaseDeferred() {
+	ge.gate.mu.Lock()
+	pending := ge.gate.deferred
+	ge.gate.deferred = nil
+	state := ge.gate.state.Load()
+
+	var released []gatedDispatch
+	var rejected []gatedDispatch
+	var rejections []error
+	for _, d := range pending {
+		err := state.evaluate(d.event)
+		switch {
+		case err == nil:
+			released = append(released, d)
+		case errors.Is(err, ErrDeferred):
+			ge.gate.deferred = append(ge.gate.deferred, d)
+		default:
+			rejected = append(rejected, d)
+			rejections = append(rejections, err)
+		}
+	}
+	ge.gate.mu.Unlock()
+
+	for i, d := range rejected {
+		ge.rejectGated(d.handle, d.event, rejections[i])
+		d.handle.release()
+	}
+	for _, d := range released {
+		ge.route(d.handle, d.event, d.cfg)
+		d.handle.release()
+	}
+}
+
+// DeferredCount returns the number of events held by the gate
+func (ge *GoEvent) DeferredCount() int {
+	ge.gate.mu.Lock()
+	defer ge.gate.mu.Unlock()
+	return len(ge.gate.deferred)
+}
+
+// evaluate checks the pause state and the gate function
+func (s *gateState) evaluate(event Event) error {
+	if s == nil {
+		return nil
+	}
+	if s.paused || s.pausedEvents[event.Name()] {
+		if s.rejectPaused {
+			return ErrPaused
+		}
+		return ErrDeferred
+	}
+	if s.fn == nil {
+		return nil
+	}
+	return s.fn(event)
+}
+
+// checkGate consults the gate. It reports whether the event was
+// deferred or rejected instead of being let through. The gate function
+// runs without holding mu, so concurrent dispatches do not wait for each
+// other; an event is only deferred if the state it was evaluated against
+// is still current, otherwise it is evaluated again.
+func (ge *GoEvent) checkGate(handle *DispatchHandle, event Event, cfg dispatchConfig) bool {
+	for {
+		state := ge.gate.state.Load()
+		if state.open() {
+			return false
+		}
+
+		err := state.evaluate(event)
+		if err == nil {
+			return false
+		}
+		if !errors.Is(err, ErrDeferred) {
+			ge.rejectGated(handle, event, err)
+			return true
+		}
+
+		ge.gate.mu.Lock()
+		if ge.gate.state.Load() != state {
+			ge.gate.mu.Unlock()
+			continue
+		}
+		// Hold the handle open until the event is released or rejected,
+		// which publishes it to the subscribers of that time
+		handle.hold(1)
+		handle.subs, handle.subsAsync = nil, 0
+		ge.gate.deferred = append(ge.gate.deferred, gatedDispatch{handle: handle, event: event, cfg: cfg})
+		ge.gate.mu.Unlock()
+		return true
+	}
+}
+
+// rejectGated records a gate rejection on the dispatch
+func (ge *GoEvent) rejectGated(handle *DispatchHandle, event Event, err error) {
+	eventError := handle.newError(event.Name(), fmt.Errorf("%w: %w", ErrRejected, err))
+	handle.recordError(eventError)
+	ge.recordError(eventError)
+	handle.markDone()
+}
@@ -0,0 +1,162 @@
+package goevent
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+)
+
+type testMutationEvent struct{}
+
+func (e *testMutationEvent) Name() string {
+	return "test.mutation"
+}
+
+func (e *testMutationEvent) Payload() map[string]any {
+	return nil
+}
+
+type testMutationListener struct {
+	testCountingListener
+}
+
+func (l *testMutationListener) EventName() string {
+	return "test.mutation"
+}
+
+// maintenanceGate defers mutating events and lets everything else through
+func maintenanceGate(event Event) error {
+	if event.Name() == "test.mutation" {
+		return ErrDeferred
+	}
+	return nil
+}
+
+func TestGate_DefersAndReleases(t *testing.T) {
+	evt := New()
+	reads := &testCountingListener{}
+	mutations := &testMutationListener{}
+	evt.RegisterListener(reads, mutations)
+
+	evt.SetGate(maintenanceGate)
+
+	evt.Dispatch(&TestEvent{})
+	handle := evt.Dispatch(&testMutationEvent{})
+
+	if reads.Count() != 1 {
+		t.Errorf("Expected read-model event to flow, got %d deliveries", reads.Count())
+	}
+	if mutations.Count() != 0 || evt.DeferredCount() != 1 {
+		t.Fatal("Expected mutating event to be deferred")
+	}
+	select {
+	case <-handle.Done():
+		t.Fatal("Deferred handle was marked done before delivery")
+	default:
+	}
+
+	evt.SetGate(nil)
+	handle.Wait()
+
+	if mutations.Count() != 1 {
+		t.Errorf("Expected deferred event to be delivered once the gate opened, got %d", mutations.Count())
+	}
+	if evt.DeferredCount() != 0 {
+		t.Errorf("Expected no deferred events, got %d", evt.DeferredCount())
+	}
+}
+
+func TestGate_Rejects(t *testing.T) {
+	evt := New()
+	listener := &testSyncListener{}
+	evt.RegisterListener(listener)
+
+	errMaintenance := errors.New("maintenance")
+	evt.SetGate(func(Event) error { return errMaintenance })
+
+	handle := evt.Dispatch(&TestEvent{})
+
+	if listener.called {
+		t.Error("Rejected event was delivered")
+	}
+	errs := handle.GetErrors()
+	if len(errs) != 1 || !errors.Is(errs[0].Err, ErrRejected) || !errors.Is(errs[0].Err, errMaintenance) {
+		t.Errorf("Expected rejection wrapping the gate error, got %v", errs)
+	}
+}
+
+func TestGate_ReleaseDeferredReevaluates(t *testing.T) {
+	evt := New()
+	mutations := &testMutationListener{}
+	evt.RegisterListener(mutations)
+
+	open := false
+	evt.SetGate(func(Event) error {
+		if !open {
+			return ErrDeferred
+		}
+		return nil
+	})
+	evt.Dispatch(&testMutationEvent{})
+
+	evt.ReleaseDeferred()
+	if mutations.Count() != 0 {
+		t.Fatal("Event was released while the gate still defers it")
+	}
+
+	open = true
+	evt.ReleaseDeferred()
+	if mutations.Count() != 1 {
+		t.Errorf("Expected event to be released, got %d deliveries", mutations.Count())
+	}
+}
+
+func TestGate_CloseRejectsDeferred(t *testing.T) {
+	evt := New()
+	evt.SetGate(maintenanceGate)
+	handle := evt.Dispatch(&testMutationEvent{})
+
+	if err := evt.Close(context.Background()); err != nil {
+		t.Fatalf("Close() failed: %v", err)
+	}
+	handle.Wait()
+
+	errs := handle.GetErrors()
+	if len(errs) != 1 || !errors.Is(errs[0].Err, ErrClosed) {
+		t.Errorf("Expected ErrClosed on deferred dispatch, got %v", errs)
+	}
+}
+
+func TestGate_ConcurrentPauseAndResume(t *testing.T) {
+	evt := New()
+	listener := &testCountingListener{}
+	evt.RegisterListener(listener)
+
+	const dispatchers, perDispatcher = 8, 200
+	var wg sync.WaitGroup
+	handles := make(chan *DispatchHandle, dispatchers*perDispatcher)
+	for i := 0; i < dispatchers; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for j := 0; j < perDispatcher; j++ {
+				handles <- evt.Dispatch(&TestEvent{})
+			}
+		}()
+	}
+	for i := 0; i < 50; i++ {
+		evt.Pause()
+		evt.Resume()
+	}
+	wg.Wait()
+	evt.Resume()
+	close(handles)
+
+	for handle := range handles {
+		handle.Wait()
+	}
+	if listener.Count() != dispatchers*perDispatcher || evt.DeferredCount() != 0 {
+		t.Errorf("Expected every event delivered once and none held, got %d delivered and %d held", listener.Count(), evt.DeferredCount())
+	}
+}
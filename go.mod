@@ -2,4 +2,4 @@ module github.com/openframebox/goevent
 
 go 1.21
 
-require github.com/asaskevich/EventBus v0.0.0-20200907212545-49d423059eef
+require github.com/santhosh-tekuri/jsonschema/v5 v5.3.1
@@ -1,6 +1,6 @@
-// Package goevent provides a type-safe, flexible event bus wrapper for Go.
+// Package goevent provides a type-safe, flexible event bus for Go.
 //
-// GoEvent wraps the EventBus library with enhanced features:
+// Features:
 //   - Type-safe interfaces instead of reflection-based handlers
 //   - Configurable sync/async execution per listener
 //   - Per-event waiting with DispatchHandle
@@ -16,139 +16,441 @@
 package goevent
 
 import (
-	"fmt"
+	"context"
+	"log/slog"
 	"sync"
-
-	"github.com/asaskevich/EventBus"
+	"sync/atomic"
+	"time"
 )
 
-// GoEvent is a wrapper around EventBus with enhanced error handling and synchronization
+// GoEvent is an event bus with error handling and synchronization
 type GoEvent struct {
-	bus              EventBus.Bus
+	dispatcher       dispatcher
 	wg               sync.WaitGroup
 	errorsMu         sync.Mutex
 	errors           []*EventError
-	asyncListenersMu sync.RWMutex
-	asyncListeners   map[string]int // tracks count of async listeners per event
+	errorsBase       ErrorToken // token of errors[0]; grows as errors are cleared
+	maxErrors        int        // 0 keeps every error
+	droppedErrors    uint64     // errors evicted to stay within maxErrors
+	middlewareMu     sync.RWMutex
+	middleware       []Middleware
+	inFlight         atomic.Int64 // async handlers currently pending
+	pending          pendingHandlers
+	degradation      degradation
+	deadLetters      DeadLetterQueue
+	rateLimitsMu     sync.RWMutex
+	rateLimits       map[string]*rateLimiter // bus-level limits per event
+	queuesMu         sync.Mutex
+	queues           []registeredQueue
+	queue            *dispatchQueue // nil unless WithDispatchQueue is used
+	closed           atomic.Bool
+	activeMu         sync.Mutex
+	active           map[string]*atomic.Int64 // running invocations per listener type, see activeCounter
+	history          *history                 // nil unless WithHistory is used
+	gate             gate
+	store            Store        // nil unless WithStore is used
+	logger           *slog.Logger // nil unless WithLogger is used
+	slowListener     time.Duration
+	registryMu       sync.RWMutex
+	registry         map[string][]ListenerInfo // registered listeners per event
+	instances        map[any]bool              // registered listener pointers, see listenerKey
+	subscriptions    map[string]*Subscription  // by ID
+	rulesMu          sync.Mutex
+	rules            map[string]RuleSet // by name, see RegisterRules
+	onFirst          []func(eventName string)
+	onLast           []func(eventName string)
+	clock            Clock
+	scheduler        *Scheduler
+	abandonWarnings  bool
+	schemas          schemas
+	requireListeners bool
+	requestTimeout   time.Duration
+	sagaStore        SagaStore
+	hooks            dispatchHooks
+	errorHandlers    []func(*EventError)
 }
 
+// Option configures a GoEvent instance
+type Option func(*GoEvent)
+
 // New creates a new GoEvent instance
-func New() *GoEvent {
-	return &GoEvent{
-		bus:            EventBus.New(),
-		errors:         make([]*EventError, 0),
-		asyncListeners: make(map[string]int),
+func New(opts ...Option) *GoEvent {
+	ge := &GoEvent{
+		errors:     make([]*EventError, 0),
+		rateLimits: make(map[string]*rateLimiter),
+		active:     make(map[string]*atomic.Int64),
+		registry:   make(map[string][]ListenerInfo),
+		clock:      realClock{},
+	}
+	ge.scheduler = &Scheduler{ge: ge}
+	ge.deadLetters = DeadLetterQueue{ge: ge, store: NewMemoryDeadLetterStore()}
+	ge.sagaStore = NewMemorySagaStore()
+	for _, opt := range opts {
+		opt(ge)
 	}
+	return ge
 }
 
 // RegisterListener registers one or more listeners to the event bus
 // If a listener implements ListenerWithOptions and Options().Async is true,
 // it will execute asynchronously. Otherwise, it executes synchronously.
+// Use RegisterListenerE to have invalid listeners rejected.
 func (ge *GoEvent) RegisterListener(listeners ...Listener) {
+	ge.claimListeners(listeners, false)
 	for _, listener := range listeners {
 		ge.registerSingleListener(listener)
 	}
 }
 
 func (ge *GoEvent) registerSingleListener(listener Listener) {
-	// Check if listener has custom options
-	isAsync := false
-	if listenerWithOpts, ok := listener.(ListenerWithOptions); ok {
-		isAsync = listenerWithOpts.Options().Async
+	ge.subscribeAll(listener, nil, optionsOf(listener))
+}
+
+// subscribeAll subscribes a listener to each of its events on behalf of
+// owner, which is nil unless it was registered with Subscribe. opts
+// are used in place of the listener's own options.
+func (ge *GoEvent) subscribeAll(listener Listener, owner *Subscription, opts ListenerOptions) {
+	for _, eventName := range eventNamesOf(listener) {
+		ge.subscribeListener(eventName, listener, owner, opts)
 	}
+}
 
-	eventName := listener.EventName()
+// eventNamesOf returns the events a listener is registered for
+func eventNamesOf(listener Listener) []string {
+	if multi, ok := listener.(MultiEventListener); ok {
+		return multi.EventNames()
+	}
+	return []string{listener.EventName()}
+}
 
-	// Create a wrapper function that matches EventBus signature
-	// and handles error collection for both handle and global errors
-	handler := func(args ...any) {
-		if len(args) < 2 {
-			return
-		}
+// subscribeListener subscribes a listener to one event. A listener of
+// several events is subscribed to each separately, so queues such as
+// rate limits and digests are kept per event.
+func (ge *GoEvent) subscribeListener(eventName string, listener Listener, owner *Subscription, opts ListenerOptions) {
+	isAsync := opts.Async
 
-		// Extract dispatch handle and event from args
-		handle, okHandle := args[0].(*DispatchHandle)
-		event, okEvent := args[1].(Event)
+	listenerType := listenerTypeOf(listener)
+	_, isResponder := listener.(Responder)
+	ge.log(slog.LevelDebug, "listener registered",
+		slog.String("event", eventName),
+		slog.String("listener", listenerType),
+		slog.Bool("async", isAsync),
+	)
+	// Registered once subscribed, so first-subscriber hooks can dispatch
+	defer ge.register(eventName, ListenerInfo{
+		Type:           listenerType,
+		Async:          isAsync,
+		Responder:      isResponder,
+		Options:        opts,
+		RegisteredAt:   time.Now(),
+		SubscriptionID: owner.ID(),
+	})
 
-		if !okHandle || !okEvent {
-			return
-		}
+	// Digest and batch listeners only buffer on dispatch; delivery
+	// happens on a timer or once a batch is full
+	_, isBatch := listener.(*batchAdapter)
+	if opts.DigestInterval > 0 || isBatch {
+		d := newDigester(ge, eventName, listener, opts)
+		ge.addQueue(listener, d)
+		ge.dispatcher.subscribe(eventName, subscriber{
+			listenerType: listenerType,
+			owner:        owner,
+			call: func(handle *DispatchHandle, event Event) {
+				d.add(handle, event)
+			},
+		})
+		return
+	}
+
+	// deliver calls the listener and handles error collection
+	// for both handle and global errors
+	active := ge.activeCounter(listenerType)
+	deliver := func(handle *DispatchHandle, event Event) {
+		active.Add(1)
+		defer active.Add(-1)
+		defer ge.logSlow(event.Name(), listenerType, handle.id, time.Now())
 
-		// Call the listener's OnEvent handler
-		if err := listener.OnEvent(event); err != nil {
-			eventError := &EventError{
-				EventName:    eventName,
-				ListenerType: fmt.Sprintf("%T", listener),
-				Err:          err,
+		// Skip listeners that would start after the dispatch deadline,
+		// otherwise call the listener through the middleware chain
+		start := ge.clock.Now()
+		attempts, err := 0, handle.ctx.Err()
+		if err == nil {
+			attempts, err = ge.invokeWithRetry(handle.ctx, listener, event, opts.Retry, opts.Timeout)
+		}
+		if err != nil {
+			eventError := handle.newError(event.Name(), err)
+			eventError.ListenerType = listenerType
+			eventError.Attempts = attempts
+			eventError.Duration = ge.clock.Now().Sub(start)
+			if !isAsync {
+				handle.syncErr.CompareAndSwap(nil, eventError)
 			}
 
 			// Record error to both the dispatch handle and global errors
 			handle.recordError(eventError)
 			ge.recordError(eventError)
+			if isDeadLetter(eventError, opts.Retry) {
+				ge.deadLetter(event, eventError)
+			}
 		}
 	}
 
-	// Subscribe based on async flag
-	if isAsync {
-		// Track async listener count for this event
-		ge.asyncListenersMu.Lock()
-		ge.asyncListeners[eventName]++
-		ge.asyncListenersMu.Unlock()
-
-		// Wrap async handler with WaitGroup tracking
-		asyncHandler := func(args ...any) {
-			// Extract handle to decrement its WaitGroup too
-			if len(args) >= 1 {
-				if handle, ok := args[0].(*DispatchHandle); ok {
-					defer handle.wg.Done()
-				}
-			}
-			defer ge.wg.Done() // Global WaitGroup was incremented during Dispatch
-			handler(args...)
+	var limiter *rateLimiter
+	if opts.RateLimit.MaxPerSecond > 0 {
+		limiter = newRateLimiter(ge.clock, opts.RateLimit)
+		ge.addQueue(listener, limiter)
+	}
+
+	// call delivers through the rate limiter, if any
+	call := func(handle *DispatchHandle, event Event) {
+		if limiter == nil {
+			deliver(handle, event)
+			return
+		}
+
+		// Rate-limited deliveries may happen later, so they hold both
+		// the handle and the bus open until they run or are dropped
+		handle.hold(1)
+		ge.wg.Add(1)
+		done := func() {
+			handle.release()
+			ge.wg.Done()
 		}
-		ge.bus.SubscribeAsync(eventName, asyncHandler, false)
-	} else {
-		// Synchronous subscription
-		ge.bus.Subscribe(eventName, handler)
+		limiter.submit(limitedCall{
+			event: event,
+			run: func() {
+				defer done()
+				deliver(handle, event)
+			},
+			drop: done,
+		})
 	}
+
+	var debounced *debouncer
+	if opts.Debounce > 0 {
+		debounced = newDebouncer(ge, opts.Debounce, call)
+		ge.addQueue(listener, debounced)
+	}
+
+	var throttled *throttle
+	if opts.Throttle > 0 {
+		throttled = &throttle{interval: opts.Throttle, clock: ge.clock}
+	}
+
+	handler := func(handle *DispatchHandle, event Event) {
+		// Sampled-out and throttled events are skipped without an error
+		if !sampled(opts.SampleRate) || (throttled != nil && !throttled.allow()) {
+			return
+		}
+
+		if debounced != nil {
+			debounced.add(handle, event)
+			return
+		}
+		call(handle, event)
+	}
+
+	if !isAsync {
+		ge.dispatcher.subscribe(eventName, subscriber{listenerType: listenerType, owner: owner, call: handler})
+		return
+	}
+
+	var serial *serialQueue
+	if opts.Ordered {
+		serial = &serialQueue{}
+	}
+
+	// Async calls release the wait groups and load counters that
+	// publish took for them
+	ge.dispatcher.subscribe(eventName, subscriber{
+		listenerType: listenerType,
+		async:        true,
+		serial:       serial,
+		owner:        owner,
+		call: func(handle *DispatchHandle, event Event) {
+			defer handle.release()
+			defer ge.wg.Done()
+			defer ge.updateLoad()
+			defer ge.inFlight.Add(-1)
+			defer ge.pending.add(event.Name(), handle.priority, -1)
+			handler(handle, event)
+		},
+	})
 }
 
 // Dispatch publishes an event to all registered listeners and returns a handle
 // The handle can be used to wait for this specific dispatch to complete
 // and retrieve errors that occurred during this dispatch
-func (ge *GoEvent) Dispatch(event Event) *DispatchHandle {
-	eventName := event.Name()
+func (ge *GoEvent) Dispatch(event Event, opts ...DispatchOption) *DispatchHandle {
+	return ge.DispatchContext(context.Background(), event, opts...)
+}
+
+// DispatchContext publishes an event like Dispatch. When ctx is the context
+// a listener received for another dispatch, the new dispatch inherits that
+// dispatch's priority and deadline unless they are overridden by opts,
+// and becomes part of that dispatch's tree for WaitTree.
+func (ge *GoEvent) DispatchContext(ctx context.Context, event Event, opts ...DispatchOption) *DispatchHandle {
+	cfg := dispatchConfig{}
+	parent, hasParent := HandleFromContext(ctx)
+	if hasParent {
+		cfg.priority = parent.priority
+		cfg.deadline = parent.deadline
+	}
+	for _, opt := range opts {
+		opt(&cfg)
+	}
+	if cfg.timeout > 0 {
+		cfg.deadline = ge.clock.Now().Add(cfg.timeout)
+	}
 
 	// Create a dispatch handle for this specific dispatch
-	handle := &DispatchHandle{
-		errors: make([]*EventError, 0),
-		done:   make(chan struct{}),
+	handle := ge.newDispatchHandle(ctx, cfg)
+	if ge.abandonWarnings {
+		ge.watchAbandoned(handle, event)
+	}
+	if hasParent {
+		parent.addChild(handle)
+		handle.fromWorker = parent.onWorker
+	}
+	if after := ge.afterHooks(); len(after) > 0 {
+		defer runAfterHooks(after, event, handle)
+	}
+
+	if ge.closed.Load() {
+		ge.rejectClosed(handle, event)
+		return handle
+	}
+	if ge.checkBeforeHooks(handle, event) || ge.validate(handle, event) || ge.checkSchema(handle, event) || ge.checkListeners(handle, event, cfg) {
+		return handle
+	}
+
+	ge.log(slog.LevelDebug, "dispatching event",
+		slog.String("event", event.Name()),
+		slog.String("dispatch_id", handle.id),
+		slog.Int("priority", int(cfg.priority)),
+	)
+
+	if ge.history != nil && !cfg.replay {
+		ge.history.record(event, ge.clock.Now())
+	}
+
+	if ge.checkGate(handle, event, cfg) {
+		return handle
 	}
 
-	// Check if there are async listeners for this event
-	ge.asyncListenersMu.RLock()
-	asyncCount := ge.asyncListeners[eventName]
-	ge.asyncListenersMu.RUnlock()
+	ge.route(handle, event, cfg)
+	return handle
+}
+
+// route persists an accepted dispatch and applies load shedding and
+// bus-level rate limits before submitting it for delivery
+func (ge *GoEvent) route(handle *DispatchHandle, event Event, cfg dispatchConfig) {
+	if !ge.persist(handle, event, cfg) {
+		return
+	}
+
+	ge.updateLoad()
+	if ge.shed(handle, event, cfg) {
+		return
+	}
+
+	limiter := ge.rateLimiterFor(event.Name())
+	if limiter == nil {
+		ge.submit(handle, event)
+		return
+	}
+
+	// Hold the handle open until the limiter publishes or drops the event
+	handle.hold(1)
+	ge.wg.Add(1)
+	limiter.submit(limitedCall{
+		event: event,
+		run: func() {
+			defer ge.wg.Done()
+			ge.submit(handle, event)
+			handle.release()
+		},
+		drop: func() {
+			defer ge.wg.Done()
+			handle.release()
+			ge.drop(handle)
+		},
+	})
+}
+
+// publish delivers an event to its listeners. The handle is marked done
+// by the last async listener to finish, or before publish returns when
+// there is nothing left pending.
+func (ge *GoEvent) publish(handle *DispatchHandle, event Event) {
+	eventName := event.Name()
+	subs, asyncCount := handle.subs, handle.subsAsync
+	if subs == nil {
+		subs, asyncCount = ge.ownSubscribers(handle, eventName)
+	}
+	if len(subs) == 0 {
+		handle.orphan.Store(true)
+	}
+	if eventName != AnyEvent {
+		anySubs, anyAsync := ge.dispatcher.snapshot(AnyEvent)
+		if len(anySubs) > 0 && (len(handle.only) > 0 || len(handle.exclude) > 0) {
+			anySubs, anyAsync = handle.selectSubscribers(anySubs)
+		}
+		if len(anySubs) > 0 {
+			subs = append(subs[:len(subs):len(subs)], anySubs...)
+			asyncCount += anyAsync
+		}
+	}
+
+	// Hold the handle open while sync listeners run, so it is marked
+	// done inline when nothing else is pending once they returned
+	handle.holds.Add(1)
 
 	// Increment WaitGroups before publishing (prevents race with Wait())
 	if asyncCount > 0 {
-		ge.wg.Add(asyncCount)     // Global wait group
-		handle.wg.Add(asyncCount) // Handle-specific wait group
+		ge.wg.Add(asyncCount)
+		handle.hold(asyncCount)
+		ge.inFlight.Add(int64(asyncCount))
+		ge.pending.add(eventName, handle.priority, asyncCount)
 	}
 
-	// Publish the event with the handle as first argument
-	ge.bus.Publish(eventName, handle, event)
-
-	// Start a goroutine to mark the handle as done when complete
-	go func() {
-		handle.wg.Wait()
-		handle.markDone()
-	}()
+	// Listeners run in registration order; sync ones are skipped once
+	// a fail-fast dispatch failed
+	for _, sub := range subs {
+		if sub.async && handle.forceSync {
+			sub.call(handle, event)
+			continue
+		}
+		if sub.async {
+			if sub.serial != nil {
+				call := sub.call
+				sub.serial.push(func() { call(handle, event) })
+			} else {
+				go sub.call(handle, event)
+			}
+			continue
+		}
+		if !handle.aborted() {
+			sub.call(handle, event)
+		}
+	}
+	close(handle.published)
+	handle.settle()
+}
 
-	return handle
+// ownSubscribers returns the subscribers an event is delivered to,
+// other than AnyListeners, and how many of them are async
+func (ge *GoEvent) ownSubscribers(handle *DispatchHandle, eventName string) ([]subscriber, int) {
+	subs, asyncCount := ge.dispatcher.snapshot(eventName)
+	if len(handle.only) > 0 || len(handle.exclude) > 0 {
+		subs, asyncCount = handle.selectSubscribers(subs)
+	}
+	return subs, asyncCount
 }
 
-// Wait blocks until all asynchronous event handlers have completed
+// Wait blocks until all asynchronous event handlers have completed.
+// Digest and batch windows that are still collecting events are not
+// waited for; Close and FlushQueue deliver them, and the handles of
+// their dispatches complete once they are delivered.
 func (ge *GoEvent) Wait() {
 	ge.wg.Wait()
 }
@@ -165,16 +467,31 @@ func (ge *GoEvent) GetErrors() []*EventError {
 	return errorsCopy
 }
 
-// ClearErrors clears all recorded errors
+// Err returns all recorded errors joined with errors.Join, or nil if
+// there were none
+func (ge *GoEvent) Err() error {
+	return joinErrors(ge.GetErrors())
+}
+
+// ClearErrors clears all recorded errors.
+// Tokens handed out by GetErrorsSince stay valid, but errors cleared
+// before a consumer read them are lost to it; consumers that only need
+// new errors should read with GetErrorsSince instead of clearing.
 func (ge *GoEvent) ClearErrors() {
 	ge.errorsMu.Lock()
 	defer ge.errorsMu.Unlock()
+	ge.errorsBase += ErrorToken(len(ge.errors))
 	ge.errors = make([]*EventError, 0)
 }
 
 // recordError stores an error in a thread-safe manner
 func (ge *GoEvent) recordError(err *EventError) {
+	ge.logError(err)
+
 	ge.errorsMu.Lock()
-	defer ge.errorsMu.Unlock()
 	ge.errors = append(ge.errors, err)
+	ge.evictErrors()
+	ge.errorsMu.Unlock()
+
+	ge.handleError(err)
 }
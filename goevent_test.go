@@ -2,6 +2,7 @@ package goevent
 
 import (
 	"errors"
+	"sync"
 	"testing"
 	"time"
 )
@@ -77,15 +78,9 @@ func TestNew(t *testing.T) {
 	if evt == nil {
 		t.Fatal("New() returned nil")
 	}
-	if evt.bus == nil {
-		t.Error("EventBus not initialized")
-	}
 	if evt.errors == nil {
 		t.Error("Errors slice not initialized")
 	}
-	if evt.asyncListeners == nil {
-		t.Error("Async listeners map not initialized")
-	}
 }
 
 func TestSyncListener(t *testing.T) {
@@ -242,18 +237,57 @@ func TestEventPayload(t *testing.T) {
 	}
 }
 
+func TestGoEventErr(t *testing.T) {
+	evt := New()
+	if err := evt.Err(); err != nil {
+		t.Fatalf("Expected nil Err() on a new bus, got %v", err)
+	}
+
+	evt.RegisterListener(&testErrorListener{})
+	evt.Dispatch(&TestEvent{})
+	evt.Dispatch(&TestEvent{})
+
+	err := evt.Err()
+	var eventErr *EventError
+	if !errors.As(err, &eventErr) {
+		t.Fatalf("Expected an *EventError through errors.As, got %v", err)
+	}
+	if joined, ok := err.(interface{ Unwrap() []error }); !ok || len(joined.Unwrap()) != 2 {
+		t.Errorf("Expected the errors of both dispatches, got %v", err)
+	}
+
+	evt.ClearErrors()
+	if err := evt.Err(); err != nil {
+		t.Errorf("Expected nil Err() after ClearErrors, got %v", err)
+	}
+}
+
 func BenchmarkSyncDispatch(b *testing.B) {
 	evt := New()
 	listener := &testSyncListener{}
 	evt.RegisterListener(listener)
 	event := &TestEvent{data: "benchmark"}
 
+	b.ReportAllocs()
 	b.ResetTimer()
 	for i := 0; i < b.N; i++ {
 		evt.Dispatch(event)
 	}
 }
 
+func BenchmarkSyncDispatchRelease(b *testing.B) {
+	evt := New()
+	listener := &testSyncListener{}
+	evt.RegisterListener(listener)
+	event := &TestEvent{data: "benchmark"}
+
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		evt.Dispatch(event).Release()
+	}
+}
+
 func BenchmarkAsyncDispatch(b *testing.B) {
 	evt := New()
 	listener := &testAsyncListener{}
@@ -266,3 +300,41 @@ func BenchmarkAsyncDispatch(b *testing.B) {
 		handle.Wait()
 	}
 }
+
+// testProjection follows several order events
+type testProjection struct {
+	mu       sync.Mutex
+	received []string
+}
+
+func (p *testProjection) EventName() string {
+	return "order.ignored"
+}
+
+func (p *testProjection) EventNames() []string {
+	return []string{"order.placed", "order.shipped"}
+}
+
+func (p *testProjection) OnEvent(event Event) error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.received = append(p.received, event.Name())
+	return nil
+}
+
+func TestRegisterListener_MultipleEvents(t *testing.T) {
+	evt := New()
+	projection := &testProjection{}
+	evt.RegisterListener(projection)
+
+	for _, name := range []string{"order.placed", "order.shipped", "order.ignored"} {
+		evt.Dispatch(&GenericEvent{EventName: name})
+	}
+
+	if len(projection.received) != 2 || projection.received[0] != "order.placed" || projection.received[1] != "order.shipped" {
+		t.Errorf("Expected the events named by EventNames, got %v", projection.received)
+	}
+	if events := evt.Events(); len(events) != 2 {
+		t.Errorf("Expected the listener under both events, got %v", events)
+	}
+}
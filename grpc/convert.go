@@ -0,0 +1,89 @@
+package grpc
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"google.golang.org/protobuf/types/known/structpb"
+	"google.golang.org/protobuf/types/known/timestamppb"
+
+	"github.com/openframebox/goevent"
+	"github.com/openframebox/goevent/grpc/eventpb"
+)
+
+// ToProto converts an envelope to its protobuf message. Payload values
+// that protobuf Struct cannot hold directly are converted through JSON.
+func ToProto(env goevent.Envelope) (*eventpb.Event, error) {
+	payload, err := toStruct(env.Event.Payload())
+	if err != nil {
+		return nil, fmt.Errorf("grpc: payload of %s: %w", env.Name, err)
+	}
+
+	msg := &eventpb.Event{
+		Id:            env.ID,
+		Name:          env.Name,
+		Headers:       env.Headers,
+		Payload:       payload,
+		CorrelationId: env.CorrelationID,
+		Tenant:        env.Tenant,
+		Priority:      int64(env.Priority),
+		Source:        env.Source,
+		CausationId:   env.CausationID,
+	}
+	if !env.Time.IsZero() {
+		msg.Time = timestamppb.New(env.Time)
+	}
+	if !env.Deadline.IsZero() {
+		msg.Deadline = timestamppb.New(env.Deadline)
+	}
+	return msg, nil
+}
+
+// FromProto converts a protobuf message to an envelope. The event is
+// reconstructed with goevent.DecodeEvent.
+func FromProto(msg *eventpb.Event) (goevent.Envelope, error) {
+	event, err := goevent.DecodeEvent(msg.GetName(), msg.GetPayload().AsMap())
+	if err != nil {
+		return goevent.Envelope{}, err
+	}
+
+	env := goevent.Envelope{
+		Metadata: goevent.Metadata{
+			ID:            msg.GetId(),
+			CorrelationID: msg.GetCorrelationId(),
+			CausationID:   msg.GetCausationId(),
+			Source:        msg.GetSource(),
+			Tenant:        msg.GetTenant(),
+			Priority:      goevent.Priority(msg.GetPriority()),
+			Headers:       msg.GetHeaders(),
+		},
+		Name:  msg.GetName(),
+		Event: event,
+	}
+	if msg.GetTime() != nil {
+		env.Time = msg.GetTime().AsTime()
+	}
+	if msg.GetDeadline() != nil {
+		env.Deadline = msg.GetDeadline().AsTime()
+	}
+	return env, nil
+}
+
+func toStruct(payload map[string]any) (*structpb.Struct, error) {
+	if payload == nil {
+		return nil, nil
+	}
+	if s, err := structpb.NewStruct(payload); err == nil {
+		return s, nil
+	}
+
+	data, err := json.Marshal(payload)
+	if err != nil {
+		return nil, err
+	}
+	var generic map[string]any
+	if err := json.Unmarshal(data, &generic); err != nil {
+		return nil, err
+	}
+	return structpb.NewStruct(generic)
+}
@@ -0,0 +1,6 @@
+// Package eventpb contains the protobuf messages and gRPC stubs of the
+// goevent EventService, generated from goevent.proto. Clients in other
+// languages can be generated from the same file.
+package eventpb
+
+//go:generate protoc --go_out=. --go_opt=paths=source_relative --go-grpc_out=. --go-grpc_opt=paths=source_relative goevent.proto
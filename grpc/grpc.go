@@ -0,0 +1,272 @@
+// Package grpc serves a bus over gRPC, so clients in any language can
+// publish events to it and stream the events they are interested in.
+//
+// The service is defined in eventpb/goevent.proto:
+//
+//	server := grpc.NewServer(bus)
+//	defer server.Close()
+//	s := googlegrpc.NewServer()
+//	server.Register(s)
+//	s.Serve(lis)
+//
+// Subscribers that fall behind by more than the buffer size are
+// disconnected with codes.ResourceExhausted instead of slowing down the
+// bus; they can subscribe again.
+//
+// Clients may send a goevent.WireHello in the "goevent-wire" metadata
+// of a call. Calls from clients sharing no wire version with the server
+// fail with codes.FailedPrecondition; otherwise the server's hello is
+// returned in the response header, so clients can tell what the server
+// understands during a rolling upgrade. Clients that send no hello are
+// served as before.
+package grpc
+
+import (
+	"context"
+	"sync"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+
+	"github.com/openframebox/goevent"
+	"github.com/openframebox/goevent/grpc/eventpb"
+)
+
+// WireMetadataKey is the metadata key of the wire hellos exchanged on
+// each call
+const WireMetadataKey = "goevent-wire"
+
+// serverHello advertises the wire versions of the eventpb messages the
+// server reads
+var serverHello = goevent.WireHello{MinVersion: 1, MaxVersion: goevent.WireVersion}
+
+// Option configures a Server
+type Option func(*Server)
+
+// WithBufferSize sets how many events may be queued for a subscriber
+// before it is disconnected. Defaults to 256.
+func WithBufferSize(n int) Option {
+	return func(s *Server) {
+		s.bufferSize = n
+	}
+}
+
+// Server implements eventpb.EventServiceServer on top of a bus
+type Server struct {
+	eventpb.UnimplementedEventServiceServer
+
+	bus        *goevent.GoEvent
+	bufferSize int
+
+	mu          sync.Mutex
+	forwarded   map[string]bool
+	subscribers map[*subscriber]struct{}
+	closed      chan struct{}
+	closeOnce   sync.Once
+}
+
+// NewServer creates a Server for bus
+func NewServer(bus *goevent.GoEvent, opts ...Option) *Server {
+	s := &Server{
+		bus:         bus,
+		bufferSize:  256,
+		forwarded:   make(map[string]bool),
+		subscribers: make(map[*subscriber]struct{}),
+		closed:      make(chan struct{}),
+	}
+	for _, opt := range opts {
+		opt(s)
+	}
+	return s
+}
+
+// Register registers the service with a gRPC server
+func (s *Server) Register(registrar grpc.ServiceRegistrar) {
+	eventpb.RegisterEventServiceServer(registrar, s)
+}
+
+// Close ends all Subscribe streams
+func (s *Server) Close() {
+	s.closeOnce.Do(func() { close(s.closed) })
+}
+
+// Publish dispatches the event of the request. The dispatch outlives the
+// call unless Wait is set, in which case listener errors are returned.
+func (s *Server) Publish(ctx context.Context, req *eventpb.PublishRequest) (*eventpb.PublishResponse, error) {
+	if err := negotiate(ctx, func(md metadata.MD) error { return grpc.SetHeader(ctx, md) }); err != nil {
+		return nil, err
+	}
+	if req.GetEvent().GetName() == "" {
+		return nil, status.Error(codes.InvalidArgument, "event name is required")
+	}
+	env, err := FromProto(req.GetEvent())
+	if err != nil {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+
+	var opts []goevent.DispatchOption
+	if env.Priority != goevent.PriorityNormal {
+		opts = append(opts, goevent.WithPriority(env.Priority))
+	}
+	if !env.Deadline.IsZero() {
+		opts = append(opts, goevent.WithDeadline(env.Deadline))
+	}
+	if md := env.Metadata.Fields(); len(md) > 0 {
+		opts = append(opts, goevent.WithMetadata(md))
+	}
+	handle := s.bus.DispatchContext(context.WithoutCancel(ctx), env.Event, opts...)
+
+	resp := &eventpb.PublishResponse{DispatchId: handle.DispatchID()}
+	if req.GetWait() {
+		for _, err := range handle.GetErrors() {
+			resp.Errors = append(resp.Errors, err.Error())
+		}
+	}
+	return resp, nil
+}
+
+// Subscribe streams the matching events until the client goes away or
+// the server is closed
+func (s *Server) Subscribe(req *eventpb.SubscribeRequest, stream eventpb.EventService_SubscribeServer) error {
+	if err := negotiate(stream.Context(), stream.SetHeader); err != nil {
+		return err
+	}
+	if len(req.GetEvents()) == 0 {
+		return status.Error(codes.InvalidArgument, "at least one event name is required")
+	}
+
+	sub := &subscriber{
+		events:   make(map[string]bool, len(req.GetEvents())),
+		queue:    make(chan *eventpb.Event, s.bufferSize),
+		overflow: make(chan struct{}),
+	}
+	for _, name := range req.GetEvents() {
+		sub.events[name] = true
+	}
+	s.subscribe(sub)
+	defer s.unsubscribe(sub)
+
+	for {
+		select {
+		case <-stream.Context().Done():
+			return nil
+		case <-s.closed:
+			return nil
+		case <-sub.overflow:
+			return status.Error(codes.ResourceExhausted, "subscriber fell behind")
+		case msg := <-sub.queue:
+			if err := stream.Send(msg); err != nil {
+				return err
+			}
+		}
+	}
+}
+
+// negotiate checks the wire hello a client sent, if any, and answers
+// with the server's through setHeader
+func negotiate(ctx context.Context, setHeader func(metadata.MD) error) error {
+	md, _ := metadata.FromIncomingContext(ctx)
+	values := md.Get(WireMetadataKey)
+	if len(values) == 0 {
+		return nil
+	}
+	hello, err := goevent.ParseWireHello(values[0])
+	if err != nil {
+		return status.Error(codes.InvalidArgument, err.Error())
+	}
+	if _, err := goevent.NegotiateWireVersion(serverHello, hello); err != nil {
+		return status.Error(codes.FailedPrecondition, err.Error())
+	}
+	return setHeader(metadata.Pairs(WireMetadataKey, serverHello.String()))
+}
+
+func (s *Server) subscribe(sub *subscriber) {
+	s.mu.Lock()
+	s.subscribers[sub] = struct{}{}
+	var names []string
+	for name := range sub.events {
+		if !s.forwarded[name] {
+			s.forwarded[name] = true
+			names = append(names, name)
+		}
+	}
+	s.mu.Unlock()
+
+	for _, name := range names {
+		s.bus.RegisterListener(&forwarder{server: s, eventName: name})
+	}
+}
+
+func (s *Server) unsubscribe(sub *subscriber) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	delete(s.subscribers, sub)
+}
+
+// deliver queues an event for every matching subscriber
+func (s *Server) deliver(ctx context.Context, event goevent.Event) error {
+	env := goevent.NewEnvelope(event)
+	if handle, ok := goevent.HandleFromContext(ctx); ok {
+		env.Metadata = handle.Metadata()
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	var msg *eventpb.Event
+	for sub := range s.subscribers {
+		if !sub.events[env.Name] {
+			continue
+		}
+		if msg == nil {
+			var err error
+			if msg, err = ToProto(env); err != nil {
+				return err
+			}
+		}
+		sub.send(msg)
+	}
+	return nil
+}
+
+// subscriber is an open Subscribe stream
+type subscriber struct {
+	events   map[string]bool
+	queue    chan *eventpb.Event
+	overflow chan struct{}
+	dropped  bool
+}
+
+// send queues msg, disconnecting the subscriber if its queue is full.
+// The caller must hold the server's mutex.
+func (sub *subscriber) send(msg *eventpb.Event) {
+	if sub.dropped {
+		return
+	}
+	select {
+	case sub.queue <- msg:
+	default:
+		sub.dropped = true
+		close(sub.overflow)
+	}
+}
+
+// forwarder streams the events of one name to the subscribers
+type forwarder struct {
+	server    *Server
+	eventName string
+}
+
+func (f *forwarder) EventName() string {
+	return f.eventName
+}
+
+func (f *forwarder) OnEvent(event goevent.Event) error {
+	return f.OnEventContext(context.Background(), event)
+}
+
+func (f *forwarder) OnEventContext(ctx context.Context, event goevent.Event) error {
+	return f.server.deliver(ctx, event)
+}
@@ -0,0 +1,247 @@
+package grpc
+
+import (
+	"context"
+	"net"
+	"testing"
+	"time"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"google.golang.org/grpc/test/bufconn"
+	"google.golang.org/protobuf/types/known/structpb"
+
+	"github.com/openframebox/goevent"
+	"github.com/openframebox/goevent/grpc/eventpb"
+)
+
+// testRecorder collects the events it receives, and their metadata if
+// metadata is set
+type testRecorder struct {
+	name     string
+	events   chan goevent.Event
+	metadata chan goevent.Metadata
+	err      error
+}
+
+func (r *testRecorder) EventName() string {
+	return r.name
+}
+
+func (r *testRecorder) OnEvent(event goevent.Event) error {
+	return r.OnEventContext(context.Background(), event)
+}
+
+func (r *testRecorder) OnEventContext(ctx context.Context, event goevent.Event) error {
+	r.events <- event
+	if r.metadata != nil {
+		md, _ := goevent.MetadataFromContext(ctx)
+		r.metadata <- md
+	}
+	return r.err
+}
+
+func startServer(t *testing.T, bus *goevent.GoEvent, opts ...Option) (*Server, eventpb.EventServiceClient) {
+	lis := bufconn.Listen(1 << 20)
+	server := NewServer(bus, opts...)
+	s := grpc.NewServer()
+	server.Register(s)
+	go s.Serve(lis)
+	t.Cleanup(func() {
+		server.Close()
+		s.Stop()
+	})
+
+	conn, err := grpc.NewClient("passthrough:///bufnet",
+		grpc.WithContextDialer(func(ctx context.Context, _ string) (net.Conn, error) { return lis.DialContext(ctx) }),
+		grpc.WithTransportCredentials(insecure.NewCredentials()))
+	if err != nil {
+		t.Fatalf("NewClient() failed: %v", err)
+	}
+	t.Cleanup(func() { conn.Close() })
+	return server, eventpb.NewEventServiceClient(conn)
+}
+
+func TestPublish_DispatchesOnBus(t *testing.T) {
+	bus := goevent.New()
+	recorder := &testRecorder{name: "order.created", events: make(chan goevent.Event, 1)}
+	bus.RegisterListener(recorder)
+	_, client := startServer(t, bus)
+
+	payload, _ := structpb.NewStruct(map[string]any{"id": "42"})
+	resp, err := client.Publish(context.Background(), &eventpb.PublishRequest{
+		Event: &eventpb.Event{Name: "order.created", Payload: payload},
+		Wait:  true,
+	})
+	if err != nil {
+		t.Fatalf("Publish() failed: %v", err)
+	}
+	if resp.DispatchId == "" || len(resp.Errors) != 0 {
+		t.Errorf("Expected a dispatch ID and no errors, got %+v", resp)
+	}
+	if event := <-recorder.events; event.Payload()["id"] != "42" {
+		t.Errorf("Expected payload id 42, got %v", event.Payload())
+	}
+}
+
+func TestPublish_PassesMetadata(t *testing.T) {
+	bus := goevent.New()
+	recorder := &testRecorder{
+		name:     "order.created",
+		events:   make(chan goevent.Event, 1),
+		metadata: make(chan goevent.Metadata, 1),
+	}
+	bus.RegisterListener(recorder)
+	_, client := startServer(t, bus)
+
+	_, err := client.Publish(context.Background(), &eventpb.PublishRequest{
+		Event: &eventpb.Event{
+			Name:          "order.created",
+			CorrelationId: "req-1",
+			CausationId:   "evt-0",
+			Source:        "checkout",
+			Tenant:        "acme",
+			Headers:       map[string]string{"region": "eu"},
+		},
+	})
+	if err != nil {
+		t.Fatalf("Publish() failed: %v", err)
+	}
+	<-recorder.events
+	md := <-recorder.metadata
+	if md.CorrelationID != "req-1" || md.CausationID != "evt-0" || md.Source != "checkout" || md.Tenant != "acme" || md.Header("region") != "eu" {
+		t.Errorf("Expected the request metadata on the dispatch, got %+v", md)
+	}
+}
+
+func TestPublish_ReportsErrors(t *testing.T) {
+	bus := goevent.New()
+	bus.RegisterListener(&testRecorder{name: "order.created", events: make(chan goevent.Event, 1), err: context.Canceled})
+	_, client := startServer(t, bus)
+
+	resp, err := client.Publish(context.Background(), &eventpb.PublishRequest{
+		Event: &eventpb.Event{Name: "order.created"},
+		Wait:  true,
+	})
+	if err != nil {
+		t.Fatalf("Publish() failed: %v", err)
+	}
+	if len(resp.Errors) != 1 {
+		t.Errorf("Expected 1 listener error, got %v", resp.Errors)
+	}
+
+	_, err = client.Publish(context.Background(), &eventpb.PublishRequest{Event: &eventpb.Event{}})
+	if status.Code(err) != codes.InvalidArgument {
+		t.Errorf("Expected InvalidArgument for a nameless event, got %v", err)
+	}
+}
+
+func TestSubscribe_StreamsMatchingEvents(t *testing.T) {
+	bus := goevent.New()
+	_, client := startServer(t, bus)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	stream, err := client.Subscribe(ctx, &eventpb.SubscribeRequest{Events: []string{"order.created"}})
+	if err != nil {
+		t.Fatalf("Subscribe() failed: %v", err)
+	}
+
+	// The subscription is registered once the server handled the call
+	for len(bus.Listeners()["order.created"]) == 0 {
+		time.Sleep(time.Millisecond)
+	}
+	bus.Dispatch(&goevent.GenericEvent{EventName: "order.ignored"})
+	handle := bus.DispatchContext(context.Background(),
+		&goevent.GenericEvent{EventName: "order.created", Data: map[string]any{"total": 9.5}},
+		goevent.WithPriority(goevent.PriorityHigh))
+
+	msg, err := stream.Recv()
+	if err != nil {
+		t.Fatalf("Recv() failed: %v", err)
+	}
+	if msg.Name != "order.created" || msg.Id != handle.DispatchID() || msg.Priority != int64(goevent.PriorityHigh) {
+		t.Errorf("Expected the dispatched event with its metadata, got %v", msg)
+	}
+
+	env, err := FromProto(msg)
+	if err != nil {
+		t.Fatalf("FromProto() failed: %v", err)
+	}
+	if env.Event.Payload()["total"] != 9.5 {
+		t.Errorf("Expected total 9.5, got %v", env.Event.Payload())
+	}
+}
+
+func TestSubscribe_SlowSubscriberDisconnected(t *testing.T) {
+	bus := goevent.New()
+	_, client := startServer(t, bus, WithBufferSize(1))
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	stream, _ := client.Subscribe(ctx, &eventpb.SubscribeRequest{Events: []string{"tick"}})
+	for len(bus.Listeners()["tick"]) == 0 {
+		time.Sleep(time.Millisecond)
+	}
+
+	for i := 0; i < 1000; i++ {
+		bus.Dispatch(&goevent.GenericEvent{EventName: "tick"})
+	}
+
+	var err error
+	for err == nil {
+		_, err = stream.Recv()
+	}
+	if status.Code(err) != codes.ResourceExhausted {
+		t.Errorf("Expected ResourceExhausted, got %v", err)
+	}
+}
+
+func TestSubscribe_RequiresEvents(t *testing.T) {
+	_, client := startServer(t, goevent.New())
+
+	stream, err := client.Subscribe(context.Background(), &eventpb.SubscribeRequest{})
+	if err == nil {
+		_, err = stream.Recv()
+	}
+	if status.Code(err) != codes.InvalidArgument {
+		t.Errorf("Expected InvalidArgument, got %v", err)
+	}
+}
+
+func TestNegotiatesWireVersion(t *testing.T) {
+	bus := goevent.New()
+	_, client := startServer(t, bus)
+	event := &eventpb.Event{Name: "order.created"}
+
+	hello := goevent.WireHello{MinVersion: 1, MaxVersion: goevent.WireVersion + 1}
+	ctx := metadata.AppendToOutgoingContext(context.Background(), WireMetadataKey, hello.String())
+	var header metadata.MD
+	if _, err := client.Publish(ctx, &eventpb.PublishRequest{Event: event}, grpc.Header(&header)); err != nil {
+		t.Fatalf("Publish() failed: %v", err)
+	}
+	values := header.Get(WireMetadataKey)
+	if len(values) != 1 {
+		t.Fatalf("Expected the server's hello in the header, got %v", header)
+	}
+	if server, err := goevent.ParseWireHello(values[0]); err != nil || server.MaxVersion != goevent.WireVersion {
+		t.Errorf("Expected the server to read up to version %d, got %+v (%v)", goevent.WireVersion, server, err)
+	}
+
+	// A client that only reads newer versions is turned away
+	hello = goevent.WireHello{MinVersion: goevent.WireVersion + 1, MaxVersion: goevent.WireVersion + 2}
+	ctx = metadata.AppendToOutgoingContext(context.Background(), WireMetadataKey, hello.String())
+	if _, err := client.Publish(ctx, &eventpb.PublishRequest{Event: event}); status.Code(err) != codes.FailedPrecondition {
+		t.Errorf("Expected FailedPrecondition from Publish, got %v", err)
+	}
+	stream, err := client.Subscribe(ctx, &eventpb.SubscribeRequest{Events: []string{"order.created"}})
+	if err == nil {
+		_, err = stream.Recv()
+	}
+	if status.Code(err) != codes.FailedPrecondition {
+		t.Errorf("Expected FailedPrecondition from Subscribe, got %v", err)
+	}
+}
@@ -0,0 +1,166 @@
+package goevent
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// HistoryEntry is an event recorded in the dispatch history
+type HistoryEntry struct {
+	Event Event
+	Time  time.Time
+}
+
+// history is a fixed-size ring buffer of dispatched events
+type history struct {
+	mu      sync.Mutex
+	entries []HistoryEntry
+	next    int
+	full    bool
+}
+
+// WithHistory records the last n dispatched events for inspection and replay
+func WithHistory(n int) Option {
+	return func(ge *GoEvent) {
+		if n > 0 {
+			ge.history = &history{entries: make([]HistoryEntry, n)}
+		}
+	}
+}
+
+func (h *history) record(event Event, at time.Time) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	h.entries[h.next] = HistoryEntry{Event: event, Time: at}
+	h.next = (h.next + 1) % len(h.entries)
+	if h.next == 0 {
+		h.full = true
+	}
+}
+
+// snapshot returns the recorded entries, oldest first
+func (h *history) snapshot() []HistoryEntry {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	if !h.full {
+		entries := make([]HistoryEntry, h.next)
+		copy(entries, h.entries[:h.next])
+		return entries
+	}
+
+	entries := make([]HistoryEntry, 0, len(h.entries))
+	entries = append(entries, h.entries[h.next:]...)
+	entries = append(entries, h.entries[:h.next]...)
+	return entries
+}
+
+// History returns the recorded events, oldest first.
+// It returns nil unless the bus was created with WithHistory.
+func (ge *GoEvent) History() []HistoryEntry {
+	if ge.history == nil {
+		return nil
+	}
+	return ge.history.snapshot()
+}
+
+// ExpiringEvent is an event that is obsolete once its TTL has passed
+// since it was dispatched. Replay with SkipExpired does not dispatch it
+// again after that.
+type ExpiringEvent interface {
+	Event
+	TTL() time.Duration
+}
+
+// ReplayOption configures Replay
+type ReplayOption func(*replayConfig)
+
+type replayConfig struct {
+	skipExpired bool
+	defaultTTL  time.Duration
+	keyOf       func(Event) string
+}
+
+// SkipExpired skips events whose TTL has passed since they were
+// recorded. Events implementing ExpiringEvent use their own TTL, others
+// use defaultTTL; a TTL of 0 never expires.
+func SkipExpired(defaultTTL time.Duration) ReplayOption {
+	return func(c *replayConfig) {
+		c.skipExpired = true
+		c.defaultTTL = defaultTTL
+	}
+}
+
+// SkipSuperseded skips events followed in the history by a later event
+// of the same name and key, so only the last write per key is replayed.
+// Events for which key returns "" are never superseded.
+func SkipSuperseded(key func(Event) string) ReplayOption {
+	return func(c *replayConfig) {
+		c.keyOf = key
+	}
+}
+
+// Replay dispatches recorded events matching filter again, oldest first,
+// and returns their handles. A nil filter replays every recorded event.
+// Replayed events are not recorded in the history a second time.
+func (ge *GoEvent) Replay(filter func(HistoryEntry) bool, opts ...ReplayOption) []*DispatchHandle {
+	cfg := replayConfig{}
+	for _, opt := range opts {
+		opt(&cfg)
+	}
+
+	entries := ge.History()
+	superseded := cfg.superseded(entries)
+	now := ge.clock.Now()
+
+	var handles []*DispatchHandle
+	for i, entry := range entries {
+		if (superseded != nil && superseded[i]) || (cfg.skipExpired && cfg.expired(entry, now)) {
+			continue
+		}
+		if filter != nil && !filter(entry) {
+			continue
+		}
+		handles = append(handles, ge.DispatchContext(context.Background(), entry.Event, replayed()))
+	}
+	return handles
+}
+
+// expired reports whether an entry's TTL has passed at now
+func (c replayConfig) expired(entry HistoryEntry, now time.Time) bool {
+	ttl := c.defaultTTL
+	if expiring, ok := entry.Event.(ExpiringEvent); ok {
+		ttl = expiring.TTL()
+	}
+	return ttl > 0 && now.Sub(entry.Time) >= ttl
+}
+
+// superseded marks the entries followed by a later entry with the same
+// event name and key, or returns nil unless SkipSuperseded is used
+func (c replayConfig) superseded(entries []HistoryEntry) []bool {
+	if c.keyOf == nil {
+		return nil
+	}
+	type partition struct{ eventName, key string }
+	seen := make(map[partition]bool)
+	superseded := make([]bool, len(entries))
+	for i := len(entries) - 1; i >= 0; i-- {
+		key := c.keyOf(entries[i].Event)
+		if key == "" {
+			continue
+		}
+		p := partition{entries[i].Event.Name(), key}
+		superseded[i] = seen[p]
+		seen[p] = true
+	}
+	return superseded
+}
+
+// replayed marks a dispatch as a replay of a recorded event
+func replayed() DispatchOption {
+	return func(c *dispatchConfig) {
+		c.replay = true
+	}
+}
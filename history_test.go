@@ -0,0 +1,122 @@
+package goevent
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestHistory_RingBufferKeepsMostRecent(t *testing.T) {
+	evt := New(WithHistory(2))
+
+	evt.Dispatch(&TestEvent{data: "one"})
+	evt.Dispatch(&TestEvent{data: "two"})
+	evt.Dispatch(&TestEvent{data: "three"})
+
+	entries := evt.History()
+	if len(entries) != 2 {
+		t.Fatalf("Expected 2 entries, got %d", len(entries))
+	}
+	if entries[0].Event.(*TestEvent).data != "two" || entries[1].Event.(*TestEvent).data != "three" {
+		t.Errorf("Expected oldest-first [two three], got [%s %s]",
+			entries[0].Event.(*TestEvent).data, entries[1].Event.(*TestEvent).data)
+	}
+	if entries[0].Time.After(entries[1].Time) {
+		t.Error("Expected timestamps in dispatch order")
+	}
+}
+
+func TestHistory_DisabledByDefault(t *testing.T) {
+	evt := New()
+	evt.Dispatch(&TestEvent{data: "one"})
+
+	if entries := evt.History(); entries != nil {
+		t.Errorf("Expected no history, got %d entries", len(entries))
+	}
+}
+
+func TestReplay_FiltersAndDoesNotRecordAgain(t *testing.T) {
+	evt := New(WithHistory(10))
+	evt.Dispatch(&TestEvent{data: "keep"})
+	evt.Dispatch(&TestEvent{data: "skip"})
+
+	late := &testSyncListener{}
+	evt.RegisterListener(late)
+
+	handles := evt.Replay(func(entry HistoryEntry) bool {
+		return entry.Event.(*TestEvent).data == "keep"
+	})
+
+	if len(handles) != 1 {
+		t.Fatalf("Expected 1 replayed event, got %d", len(handles))
+	}
+	if late.data != "keep" {
+		t.Errorf("Expected late listener to receive 'keep', got '%s'", late.data)
+	}
+	if len(evt.History()) != 2 {
+		t.Errorf("Expected replay not to grow the history, got %d entries", len(evt.History()))
+	}
+}
+
+// testDataRecorder records the data of the test events it receives
+type testDataRecorder struct {
+	received []string
+}
+
+func (l *testDataRecorder) EventName() string {
+	return "test.event"
+}
+
+func (l *testDataRecorder) OnEvent(event Event) error {
+	l.received = append(l.received, event.Payload()["data"].(string))
+	return nil
+}
+
+// testExpiringEvent is a TestEvent with its own TTL
+type testExpiringEvent struct {
+	TestEvent
+	ttl time.Duration
+}
+
+func (e *testExpiringEvent) TTL() time.Duration {
+	return e.ttl
+}
+
+func TestReplay_SkipExpired(t *testing.T) {
+	clock := newFakeClock(time.Now())
+	evt := New(WithHistory(10), WithClock(clock))
+	evt.Dispatch(&TestEvent{data: "old"})
+	evt.Dispatch(&testExpiringEvent{TestEvent: TestEvent{data: "short"}, ttl: time.Minute})
+	evt.Dispatch(&testExpiringEvent{TestEvent: TestEvent{data: "long"}, ttl: 3 * time.Hour})
+	clock.Advance(2 * time.Hour)
+	evt.Dispatch(&TestEvent{data: "recent"})
+
+	listener := &testDataRecorder{}
+	evt.RegisterListener(listener)
+	evt.Replay(nil, SkipExpired(time.Hour))
+
+	if got := strings.Join(listener.received, " "); got != "long recent" {
+		t.Errorf("Expected [long recent] to be replayed, got [%s]", got)
+	}
+}
+
+func TestReplay_SkipSuperseded(t *testing.T) {
+	evt := New(WithHistory(10))
+	for _, data := range []string{"a:1", "b:1", "a:2", "unkeyed", "unkeyed", "b:2"} {
+		evt.Dispatch(&TestEvent{data: data})
+	}
+
+	listener := &testDataRecorder{}
+	evt.RegisterListener(listener)
+	evt.Replay(nil, SkipSuperseded(func(event Event) string {
+		key, _, _ := strings.Cut(event.(*TestEvent).data, ":")
+		if key == "unkeyed" {
+			return ""
+		}
+		return key
+	}))
+
+	if got := strings.Join(listener.received, " "); got != "a:2 unkeyed unkeyed b:2" {
+		t.Errorf("Expected the last write per key, got [%s]", got)
+	}
+}
@@ -0,0 +1,71 @@
+package goevent
+
+import (
+	"errors"
+	"fmt"
+	"sync"
+)
+
+// ErrVetoed wraps the error recorded on a dispatch vetoed by a
+// before-dispatch hook
+var ErrVetoed = errors.New("goevent: dispatch vetoed by hook")
+
+type dispatchHooks struct {
+	mu     sync.RWMutex
+	before []func(Event) error
+	after  []func(Event, *DispatchHandle)
+}
+
+// OnBeforeDispatch registers fn to be called before every dispatch, in
+// registration order, before the event is validated. It may enrich the
+// event, or veto the dispatch by returning an error: no listener is
+// called and the dispatch records an EventError wrapping ErrVetoed and
+// that error.
+func (ge *GoEvent) OnBeforeDispatch(fn func(Event) error) {
+	ge.hooks.mu.Lock()
+	defer ge.hooks.mu.Unlock()
+	ge.hooks.before = append(ge.hooks.before, fn)
+}
+
+// OnAfterDispatch registers fn to be called as every dispatch returns,
+// with the dispatch's handle. Sync listeners have run by then; async ones
+// may still be running. It is also called for dispatches that were
+// vetoed or rejected, whose handle holds the reason.
+func (ge *GoEvent) OnAfterDispatch(fn func(Event, *DispatchHandle)) {
+	ge.hooks.mu.Lock()
+	defer ge.hooks.mu.Unlock()
+	ge.hooks.after = append(ge.hooks.after, fn)
+}
+
+// afterHooks returns the after-dispatch hooks
+func (ge *GoEvent) afterHooks() []func(Event, *DispatchHandle) {
+	ge.hooks.mu.RLock()
+	defer ge.hooks.mu.RUnlock()
+	return ge.hooks.after
+}
+
+// runAfterHooks calls the after-dispatch hooks
+func runAfterHooks(hooks []func(Event, *DispatchHandle), event Event, handle *DispatchHandle) {
+	for _, fn := range hooks {
+		fn(event, handle)
+	}
+}
+
+// checkBeforeHooks runs the before-dispatch hooks. It reports whether
+// one of them vetoed the dispatch.
+func (ge *GoEvent) checkBeforeHooks(handle *DispatchHandle, event Event) bool {
+	ge.hooks.mu.RLock()
+	before := ge.hooks.before
+	ge.hooks.mu.RUnlock()
+
+	for _, fn := range before {
+		if err := fn(event); err != nil {
+			eventError := handle.newError(event.Name(), fmt.Errorf("%w: %w", ErrVetoed, err))
+			handle.recordError(eventError)
+			ge.recordError(eventError)
+			handle.markDone()
+			return true
+		}
+	}
+	return false
+}
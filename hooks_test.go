@@ -0,0 +1,74 @@
+package goevent
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestOnBeforeDispatch_Veto(t *testing.T) {
+	errForbidden := errors.New("forbidden")
+	evt := New()
+	listener := &testSyncListener{}
+	evt.RegisterListener(listener)
+	evt.OnBeforeDispatch(func(e Event) error {
+		if e.(*TestEvent).data == "forbidden" {
+			return errForbidden
+		}
+		return nil
+	})
+
+	err := evt.Dispatch(&TestEvent{data: "forbidden"}).Err()
+	if !errors.Is(err, ErrVetoed) || !errors.Is(err, errForbidden) {
+		t.Errorf("Expected ErrVetoed wrapping the hook's error, got %v", err)
+	}
+	if listener.called {
+		t.Error("Expected a vetoed dispatch not to reach listeners")
+	}
+
+	evt.Dispatch(&TestEvent{data: "allowed"})
+	if !listener.called {
+		t.Error("Expected an allowed dispatch to reach listeners")
+	}
+}
+
+func TestOnBeforeDispatch_Enrich(t *testing.T) {
+	evt := New()
+	evt.OnBeforeDispatch(func(e Event) error {
+		if te, ok := e.(*TestEvent); ok && te.data == "" {
+			te.data = "default"
+		}
+		return nil
+	})
+
+	event := &TestEvent{}
+	evt.Dispatch(event)
+	if event.data != "default" {
+		t.Errorf("Expected the hook to enrich the event, got %q", event.data)
+	}
+}
+
+func TestOnAfterDispatch(t *testing.T) {
+	evt := New()
+	evt.RegisterListener(&testErrorListener{})
+	evt.OnBeforeDispatch(func(e Event) error {
+		if e.Name() == "vetoed" {
+			return errors.New("no")
+		}
+		return nil
+	})
+
+	var seen []string
+	var failed int
+	evt.OnAfterDispatch(func(e Event, handle *DispatchHandle) {
+		seen = append(seen, e.Name())
+		if handle.Err() != nil {
+			failed++
+		}
+	})
+
+	evt.Dispatch(&TestEvent{})
+	evt.Dispatch(&GenericEvent{EventName: "vetoed"})
+	if len(seen) != 2 || seen[0] != "test.event" || seen[1] != "vetoed" || failed != 2 {
+		t.Errorf("Expected both dispatches with their errors, got %v (%d failed)", seen, failed)
+	}
+}
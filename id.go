@@ -0,0 +1,15 @@
+package goevent
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+)
+
+// newID returns a random 128-bit identifier encoded as hex
+func newID() string {
+	var b [16]byte
+	if _, err := rand.Read(b[:]); err != nil {
+		panic("goevent: unable to generate id: " + err.Error())
+	}
+	return hex.EncodeToString(b[:])
+}
@@ -1,5 +1,10 @@
 package goevent
 
+import (
+	"context"
+	"time"
+)
+
 // Event represents an event that can be dispatched
 type Event interface {
 	Name() string
@@ -13,10 +18,74 @@ type Listener interface {
 	OnEvent(event Event) error
 }
 
+// MultiEventListener is a listener of several events, such as a
+// projection following a family of related events. If a listener
+// implements it, it is registered for every name EventNames returns
+// instead of EventName.
+type MultiEventListener interface {
+	Listener
+	EventNames() []string
+}
+
+// ContextListener is a listener that receives the dispatch context.
+// If a listener implements it, OnEventContext is called instead of OnEvent.
+// Passing the context to DispatchContext links follow-up events to the
+// dispatch being handled.
+type ContextListener interface {
+	Listener
+	OnEventContext(ctx context.Context, event Event) error
+}
+
 // ListenerOptions provides configuration for how a listener should execute
 type ListenerOptions struct {
 	// Async determines if the listener should execute asynchronously
 	Async bool
+
+	// Ordered makes an async listener handle the events of each name one
+	// at a time, in dispatch order, instead of concurrently. It is meant
+	// for stateful listeners such as projections.
+	Ordered bool
+
+	// DigestInterval enables rollup mode when greater than zero. Instead of
+	// receiving every event, the listener receives a single *DigestEvent per
+	// interval summarizing all matching events dispatched during it.
+	DigestInterval time.Duration
+
+	// Retry retries a failing listener before its error is recorded
+	Retry RetryPolicy
+
+	// Timeout bounds each call through the context passed to
+	// ContextListeners. A call that runs longer fails with
+	// context.DeadlineExceeded unless it returned its own error.
+	Timeout time.Duration
+
+	// RateLimit limits how often the listener is called
+	RateLimit RateLimit
+
+	// Debounce collapses bursts of events into a single call with the
+	// latest event, made once no further event arrived for this long.
+	// It is ignored for digest listeners.
+	Debounce time.Duration
+
+	// Throttle calls the listener at most once per interval; events
+	// arriving sooner are skipped
+	Throttle time.Duration
+
+	// SampleRate is the fraction of events handled, between 0 and 1.
+	// Zero handles every event.
+	SampleRate float64
+
+	// BatchSize is the number of events after which a batch is delivered
+	// to a BatchListener
+	BatchSize int
+
+	// FlushInterval is how long after its first event a batch is
+	// delivered to a BatchListener, even if it is not full
+	FlushInterval time.Duration
+
+	// Group labels the listener, e.g. "billing", for introspection and
+	// for enabling or disabling listeners together in a Manifest
+	Group string
 }
 
 // ListenerWithOptions represents a listener with custom execution options
@@ -0,0 +1,59 @@
+package goevent
+
+import (
+	"sort"
+	"time"
+)
+
+// ListenerInfo describes a registered listener
+type ListenerInfo struct {
+	Type         string // Go type of the listener, e.g. "*main.EmailListener"
+	Async        bool
+	Responder    bool // answers Request, see Responder
+	Options      ListenerOptions
+	RegisteredAt time.Time
+
+	// SubscriptionID is the ID of the listener's subscription, if it
+	// was registered with Subscribe
+	SubscriptionID string
+}
+
+// Listeners returns the registered listeners keyed by event name,
+// in registration order
+func (ge *GoEvent) Listeners() map[string][]ListenerInfo {
+	ge.registryMu.RLock()
+	defer ge.registryMu.RUnlock()
+
+	listeners := make(map[string][]ListenerInfo, len(ge.registry))
+	for eventName, infos := range ge.registry {
+		listeners[eventName] = append([]ListenerInfo(nil), infos...)
+	}
+	return listeners
+}
+
+// Events returns the sorted names of events that have listeners
+func (ge *GoEvent) Events() []string {
+	ge.registryMu.RLock()
+	defer ge.registryMu.RUnlock()
+
+	events := make([]string, 0, len(ge.registry))
+	for eventName := range ge.registry {
+		events = append(events, eventName)
+	}
+	sort.Strings(events)
+	return events
+}
+
+// register records a listener for introspection and reports the event's
+// first subscriber
+func (ge *GoEvent) register(eventName string, info ListenerInfo) {
+	ge.registryMu.Lock()
+	first := len(ge.registry[eventName]) == 0
+	ge.registry[eventName] = append(ge.registry[eventName], info)
+	hooks := ge.onFirst
+	ge.registryMu.Unlock()
+
+	if first {
+		notifyDemand(hooks, eventName)
+	}
+}
@@ -0,0 +1,46 @@
+package goevent
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestListeners(t *testing.T) {
+	evt := New()
+	evt.RegisterListener(&testSyncListener{}, &testAsyncListener{}, &testHandleRecorder{})
+
+	listeners := evt.Listeners()
+	infos := listeners["test.event"]
+	if len(infos) != 2 {
+		t.Fatalf("Expected 2 listeners for 'test.event', got %d", len(infos))
+	}
+	if infos[0].Type != "*goevent.testSyncListener" || infos[0].Async {
+		t.Errorf("Expected sync *goevent.testSyncListener first, got %+v", infos[0])
+	}
+	if infos[1].Type != "*goevent.testAsyncListener" || !infos[1].Async || !infos[1].Options.Async {
+		t.Errorf("Expected async *goevent.testAsyncListener second, got %+v", infos[1])
+	}
+	if infos[0].RegisteredAt.IsZero() {
+		t.Error("Expected registration time to be set")
+	}
+
+	// The returned map is a copy
+	listeners["test.event"][0].Type = "changed"
+	if evt.Listeners()["test.event"][0].Type == "changed" {
+		t.Error("Expected Listeners() to return a copy")
+	}
+}
+
+func TestEvents(t *testing.T) {
+	evt := New()
+	if len(evt.Events()) != 0 {
+		t.Errorf("Expected no events, got %v", evt.Events())
+	}
+
+	evt.RegisterListener(&testSyncListener{}, &testMutationListener{}, &testHandleRecorder{})
+
+	want := []string{"test.child", "test.event", "test.mutation"}
+	if got := evt.Events(); !reflect.DeepEqual(got, want) {
+		t.Errorf("Expected %v, got %v", want, got)
+	}
+}
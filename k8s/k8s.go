@@ -0,0 +1,228 @@
+// Package k8s connects Kubernetes controllers to a goevent bus.
+//
+// Source turns informer notifications into bus events. Its methods match
+// client-go's cache.ResourceEventHandler, so it can be passed straight to
+// an informer without this package depending on client-go:
+//
+//	informer := factory.Core().V1().Pods().Informer()
+//	informer.AddEventHandler(k8s.NewSource(bus, "pods"))
+//
+// Sink is a listener that records bus events as Kubernetes Events on
+// objects through a Recorder, typically wrapping a client-go
+// record.EventRecorder.
+package k8s
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+
+	"github.com/openframebox/goevent"
+)
+
+// ChangeType describes what happened to a watched object
+type ChangeType string
+
+const (
+	Added   ChangeType = "added"
+	Updated ChangeType = "updated"
+	Deleted ChangeType = "deleted"
+)
+
+// ObjectEvent is dispatched for every change to a watched object.
+// Its name is "<resource>.<change>", for example "pods.updated".
+type ObjectEvent struct {
+	Resource string
+	Change   ChangeType
+
+	// Object is the object converted to its JSON representation.
+	// For deletions it is the last known state.
+	Object map[string]any
+
+	// OldObject is the previous state for updates
+	OldObject map[string]any
+
+	// Raw is the object as received from the informer
+	Raw any
+}
+
+// Name returns "<resource>.<change>"
+func (e *ObjectEvent) Name() string {
+	return e.Resource + "." + string(e.Change)
+}
+
+// Payload returns the object along with its namespace and name
+func (e *ObjectEvent) Payload() map[string]any {
+	payload := map[string]any{
+		"resource":  e.Resource,
+		"change":    string(e.Change),
+		"namespace": e.Namespace(),
+		"name":      e.ObjectName(),
+		"object":    e.Object,
+	}
+	if e.OldObject != nil {
+		payload["old_object"] = e.OldObject
+	}
+	return payload
+}
+
+// Namespace returns metadata.namespace of the object
+func (e *ObjectEvent) Namespace() string {
+	return metadataField(e.Object, "namespace")
+}
+
+// ObjectName returns metadata.name of the object
+func (e *ObjectEvent) ObjectName() string {
+	return metadataField(e.Object, "name")
+}
+
+func metadataField(object map[string]any, field string) string {
+	metadata, _ := object["metadata"].(map[string]any)
+	value, _ := metadata[field].(string)
+	return value
+}
+
+// Source dispatches an ObjectEvent for each informer notification
+type Source struct {
+	bus      *goevent.GoEvent
+	resource string
+
+	// SkipInitialList ignores the adds an informer delivers while
+	// listing existing objects at startup
+	SkipInitialList bool
+
+	// OnError is called when an object cannot be converted
+	OnError func(err error)
+}
+
+// NewSource creates a source that names events after resource
+func NewSource(bus *goevent.GoEvent, resource string) *Source {
+	return &Source{bus: bus, resource: resource}
+}
+
+// OnAdd dispatches a "<resource>.added" event
+func (s *Source) OnAdd(obj any, isInInitialList bool) {
+	if isInInitialList && s.SkipInitialList {
+		return
+	}
+	s.dispatch(Added, nil, obj)
+}
+
+// OnUpdate dispatches a "<resource>.updated" event
+func (s *Source) OnUpdate(oldObj, newObj any) {
+	s.dispatch(Updated, oldObj, newObj)
+}
+
+// OnDelete dispatches a "<resource>.deleted" event. Tombstones for
+// deletions the informer missed are unwrapped to their last known state.
+func (s *Source) OnDelete(obj any) {
+	s.dispatch(Deleted, nil, unwrapTombstone(obj))
+}
+
+func (s *Source) dispatch(change ChangeType, oldObj, obj any) {
+	event := &ObjectEvent{Resource: s.resource, Change: change, Raw: obj}
+
+	var err error
+	if event.Object, err = toMap(obj); err == nil && oldObj != nil {
+		event.OldObject, err = toMap(oldObj)
+	}
+	if err != nil {
+		if s.OnError != nil {
+			s.OnError(fmt.Errorf("k8s: converting %s object: %w", s.resource, err))
+		}
+		return
+	}
+
+	s.bus.Dispatch(event)
+}
+
+// unwrapTombstone returns the Obj field of client-go's
+// cache.DeletedFinalStateUnknown, or obj itself for anything else
+func unwrapTombstone(obj any) any {
+	v := reflect.ValueOf(obj)
+	if v.Kind() == reflect.Pointer {
+		v = v.Elem()
+	}
+	if v.Kind() != reflect.Struct || v.Type().Name() != "DeletedFinalStateUnknown" {
+		return obj
+	}
+	if inner := v.FieldByName("Obj"); inner.IsValid() && inner.CanInterface() {
+		return inner.Interface()
+	}
+	return obj
+}
+
+func toMap(obj any) (map[string]any, error) {
+	data, err := json.Marshal(obj)
+	if err != nil {
+		return nil, err
+	}
+	var object map[string]any
+	return object, json.Unmarshal(data, &object)
+}
+
+// Kubernetes event types
+const (
+	EventTypeNormal  = "Normal"
+	EventTypeWarning = "Warning"
+)
+
+// Emission is a Kubernetes Event to record on an object
+type Emission struct {
+	Object  any // the object the event is about, e.g. a runtime.Object
+	Type    string
+	Reason  string
+	Message string
+}
+
+// Recorder records Kubernetes Events
+type Recorder interface {
+	Record(emission Emission)
+}
+
+// RecorderFunc adapts a function to a Recorder. To use a client-go
+// record.EventRecorder:
+//
+//	k8s.RecorderFunc(func(e k8s.Emission) {
+//		recorder.Event(e.Object.(runtime.Object), e.Type, e.Reason, e.Message)
+//	})
+type RecorderFunc func(emission Emission)
+
+// Record calls f
+func (f RecorderFunc) Record(emission Emission) {
+	f(emission)
+}
+
+// Sink is a listener that records bus events as Kubernetes Events
+type Sink struct {
+	eventName string
+	recorder  Recorder
+	mapper    func(event goevent.Event) (Emission, bool)
+}
+
+// NewSink records events named eventName. The mapper decides which
+// object each event is recorded on; returning false skips the event.
+func NewSink(eventName string, recorder Recorder, mapper func(event goevent.Event) (Emission, bool)) *Sink {
+	return &Sink{eventName: eventName, recorder: recorder, mapper: mapper}
+}
+
+// EventName returns the event the sink listens to
+func (s *Sink) EventName() string {
+	return s.eventName
+}
+
+// OnEvent records the mapped Kubernetes Event
+func (s *Sink) OnEvent(event goevent.Event) error {
+	emission, ok := s.mapper(event)
+	if !ok {
+		return nil
+	}
+	if emission.Object == nil {
+		return fmt.Errorf("k8s: no object to record %q on", event.Name())
+	}
+	if emission.Type == "" {
+		emission.Type = EventTypeNormal
+	}
+	s.recorder.Record(emission)
+	return nil
+}
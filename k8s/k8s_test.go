@@ -0,0 +1,118 @@
+package k8s
+
+import (
+	"sync"
+	"testing"
+
+	"github.com/openframebox/goevent"
+)
+
+type objectMeta struct {
+	Name      string `json:"name"`
+	Namespace string `json:"namespace"`
+}
+
+type pod struct {
+	Metadata objectMeta `json:"metadata"`
+	Phase    string     `json:"phase"`
+}
+
+// DeletedFinalStateUnknown mirrors client-go's tombstone type
+type DeletedFinalStateUnknown struct {
+	Key string
+	Obj any
+}
+
+type recorder struct {
+	mu     sync.Mutex
+	events []*ObjectEvent
+}
+
+func (r *recorder) record(event goevent.Event) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.events = append(r.events, event.(*ObjectEvent))
+}
+
+type podListener struct {
+	name string
+	rec  *recorder
+}
+
+func (l *podListener) EventName() string {
+	return l.name
+}
+
+func (l *podListener) OnEvent(event goevent.Event) error {
+	l.rec.record(event)
+	return nil
+}
+
+func TestSource_DispatchesChanges(t *testing.T) {
+	bus := goevent.New()
+	rec := &recorder{}
+	bus.RegisterListener(
+		&podListener{"pods.added", rec},
+		&podListener{"pods.updated", rec},
+		&podListener{"pods.deleted", rec},
+	)
+
+	source := NewSource(bus, "pods")
+	web := &pod{Metadata: objectMeta{Name: "web", Namespace: "prod"}, Phase: "Pending"}
+	running := &pod{Metadata: objectMeta{Name: "web", Namespace: "prod"}, Phase: "Running"}
+
+	source.OnAdd(web, false)
+	source.OnUpdate(web, running)
+	source.OnDelete(DeletedFinalStateUnknown{Key: "prod/web", Obj: running})
+
+	if len(rec.events) != 3 {
+		t.Fatalf("Expected 3 events, got %d", len(rec.events))
+	}
+	if rec.events[0].Name() != "pods.added" || rec.events[0].ObjectName() != "web" || rec.events[0].Namespace() != "prod" {
+		t.Errorf("Expected pods.added for prod/web, got %s for %s/%s",
+			rec.events[0].Name(), rec.events[0].Namespace(), rec.events[0].ObjectName())
+	}
+	if rec.events[1].OldObject["phase"] != "Pending" || rec.events[1].Object["phase"] != "Running" {
+		t.Errorf("Expected update from Pending to Running, got %v to %v",
+			rec.events[1].OldObject["phase"], rec.events[1].Object["phase"])
+	}
+	if rec.events[2].ObjectName() != "web" {
+		t.Errorf("Expected tombstone to be unwrapped, got %v", rec.events[2].Object)
+	}
+}
+
+func TestSource_SkipInitialList(t *testing.T) {
+	bus := goevent.New()
+	rec := &recorder{}
+	bus.RegisterListener(&podListener{"pods.added", rec})
+
+	source := NewSource(bus, "pods")
+	source.SkipInitialList = true
+	source.OnAdd(&pod{}, true)
+	source.OnAdd(&pod{}, false)
+
+	if len(rec.events) != 1 {
+		t.Errorf("Expected 1 event, got %d", len(rec.events))
+	}
+}
+
+func TestSink_RecordsEvents(t *testing.T) {
+	var recorded []Emission
+	target := &pod{Metadata: objectMeta{Name: "web"}}
+	sink := NewSink("deploy.failed", RecorderFunc(func(e Emission) {
+		recorded = append(recorded, e)
+	}), func(event goevent.Event) (Emission, bool) {
+		return Emission{Object: target, Reason: "DeployFailed", Message: "image pull failed"}, true
+	})
+
+	bus := goevent.New()
+	bus.RegisterListener(sink)
+	bus.Dispatch(&goevent.GenericEvent{EventName: "deploy.failed"}).Wait()
+
+	if len(recorded) != 1 {
+		t.Fatalf("Expected 1 recorded event, got %d", len(recorded))
+	}
+	if recorded[0].Object != target || recorded[0].Type != EventTypeNormal || recorded[0].Reason != "DeployFailed" {
+		t.Errorf("Unexpected emission: %+v", recorded[0])
+	}
+}
@@ -0,0 +1,130 @@
+package goevent
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"sync/atomic"
+)
+
+// ErrClosed is recorded on dispatches made after Close
+var ErrClosed = errors.New("goevent: bus closed")
+
+// ShutdownError is returned by Close when listeners are still running
+// after the context is done
+type ShutdownError struct {
+	Err       error    // the context error that ended the wait
+	Listeners []string // types of listeners that had not finished
+}
+
+func (e *ShutdownError) Error() string {
+	return fmt.Sprintf("goevent: shutdown: %v; unfinished listeners: %s", e.Err, strings.Join(e.Listeners, ", "))
+}
+
+func (e *ShutdownError) Unwrap() error {
+	return e.Err
+}
+
+// Close stops the scheduler and the bus from accepting new dispatches,
+// delivers pending digests and debounced events, and waits for in-flight async handlers until ctx is done.
+// If handlers are still running at that point, Close returns a
+// *ShutdownError naming the listeners that failed to finish.
+func (ge *GoEvent) Close(ctx context.Context) error {
+	ge.scheduler.Stop()
+	ge.closed.Store(true)
+
+	// Deferred events can no longer be released, so drop them
+	ge.degradation.mu.Lock()
+	deferred := ge.degradation.deferred
+	ge.degradation.deferred = nil
+	ge.degradation.stats.Dropped += uint64(len(deferred))
+	ge.degradation.mu.Unlock()
+	for _, d := range deferred {
+		d.handle.release()
+		ge.drop(d.handle)
+	}
+
+	// Events held by the gate are rejected as if dispatched after Close
+	ge.gate.mu.Lock()
+	gated := ge.gate.deferred
+	ge.gate.deferred = nil
+	ge.gate.mu.Unlock()
+	for _, d := range gated {
+		ge.rejectClosed(d.handle, d.event)
+		d.handle.release()
+	}
+
+	drained := make(chan struct{})
+	go func() {
+		ge.flushWindows()
+		ge.wg.Wait()
+		close(drained)
+	}()
+
+	select {
+	case <-drained:
+		if ge.queue != nil {
+			ge.queue.close()
+		}
+		return nil
+	case <-ctx.Done():
+		return &ShutdownError{Err: ctx.Err(), Listeners: ge.activeListeners()}
+	}
+}
+
+// flushWindows immediately delivers every open digest window and
+// debounced burst
+func (ge *GoEvent) flushWindows() {
+	ge.queuesMu.Lock()
+	var windows []listenerQueue
+	for _, rq := range ge.queues {
+		switch rq.queue.(type) {
+		case *digester, *debouncer:
+			windows = append(windows, rq.queue)
+		}
+	}
+	ge.queuesMu.Unlock()
+
+	for _, window := range windows {
+		window.flush()
+	}
+}
+
+// rejectClosed records ErrClosed on a dispatch made after Close
+func (ge *GoEvent) rejectClosed(handle *DispatchHandle, event Event) {
+	eventError := handle.newError(event.Name(), ErrClosed)
+	handle.recordError(eventError)
+	ge.recordError(eventError)
+	handle.markDone()
+}
+
+// activeCounter returns the counter of running invocations of a
+// listener type, creating it on first use. Listeners look it up when
+// they are subscribed, so calling them does not take activeMu.
+func (ge *GoEvent) activeCounter(listenerType string) *atomic.Int64 {
+	ge.activeMu.Lock()
+	defer ge.activeMu.Unlock()
+	counter, ok := ge.active[listenerType]
+	if !ok {
+		counter = new(atomic.Int64)
+		ge.active[listenerType] = counter
+	}
+	return counter
+}
+
+// activeListeners returns the sorted types of listeners currently running
+func (ge *GoEvent) activeListeners() []string {
+	ge.activeMu.Lock()
+	defer ge.activeMu.Unlock()
+
+	var listeners []string
+	for listenerType, counter := range ge.active {
+		if counter.Load() > 0 {
+			listeners = append(listeners, listenerType)
+		}
+	}
+	sort.Strings(listeners)
+	return listeners
+}
@@ -0,0 +1,89 @@
+package goevent
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestClose_WaitsForInFlightHandlers(t *testing.T) {
+	evt := New()
+	listener := &testAsyncListener{}
+	evt.RegisterListener(listener)
+
+	evt.Dispatch(&TestEvent{data: "in flight"})
+	if err := evt.Close(context.Background()); err != nil {
+		t.Fatalf("Close() failed: %v", err)
+	}
+
+	if !listener.called {
+		t.Error("Close() returned before the async listener finished")
+	}
+}
+
+func TestClose_RejectsNewDispatches(t *testing.T) {
+	evt := New()
+	listener := &testSyncListener{}
+	evt.RegisterListener(listener)
+
+	if err := evt.Close(context.Background()); err != nil {
+		t.Fatalf("Close() failed: %v", err)
+	}
+
+	handle := evt.Dispatch(&TestEvent{data: "too late"})
+	handle.Wait()
+
+	if listener.called {
+		t.Error("Listener was called after Close()")
+	}
+	errs := handle.GetErrors()
+	if len(errs) != 1 || !errors.Is(errs[0].Err, ErrClosed) {
+		t.Errorf("Expected ErrClosed, got %v", errs)
+	}
+}
+
+func TestClose_ReportsUnfinishedListeners(t *testing.T) {
+	evt := New()
+	blocking := &testBlockingListener{release: make(chan struct{})}
+	evt.RegisterListener(blocking)
+	evt.Dispatch(&TestEvent{data: "hang"})
+
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+
+	err := evt.Close(ctx)
+
+	var shutdownErr *ShutdownError
+	if !errors.As(err, &shutdownErr) {
+		t.Fatalf("Expected *ShutdownError, got %v", err)
+	}
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("Expected error to wrap context.DeadlineExceeded, got %v", err)
+	}
+	if len(shutdownErr.Listeners) != 1 || shutdownErr.Listeners[0] != "*goevent.testBlockingListener" {
+		t.Errorf("Expected the blocking listener to be reported, got %v", shutdownErr.Listeners)
+	}
+
+	close(blocking.release)
+	evt.Wait()
+}
+
+func TestClose_FlushesDigests(t *testing.T) {
+	evt := New()
+	listener := &testDigestListener{}
+	evt.RegisterListener(listener)
+	evt.Dispatch(&TestEvent{data: "pending"})
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	if err := evt.Close(ctx); err != nil {
+		t.Fatalf("Expected digest to be flushed before the deadline, got %v", err)
+	}
+
+	listener.mu.Lock()
+	defer listener.mu.Unlock()
+	if len(listener.digests) != 1 {
+		t.Errorf("Expected 1 digest, got %d", len(listener.digests))
+	}
+}
@@ -0,0 +1,97 @@
+package goevent
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"time"
+)
+
+// defaultSlowListenerThreshold is used when WithLogger is set without
+// WithSlowListenerThreshold
+const defaultSlowListenerThreshold = time.Second
+
+// WithLogger makes the bus log registrations and dispatches at debug
+// level, slow listeners and listener errors at warn level, and panics at
+// error level. Records carry "event" and "listener" attributes.
+func WithLogger(logger *slog.Logger) Option {
+	return func(ge *GoEvent) {
+		ge.logger = logger
+		if ge.slowListener == 0 {
+			ge.slowListener = defaultSlowListenerThreshold
+		}
+	}
+}
+
+// WithSlowListenerThreshold sets how long a listener call may take
+// before it is logged as slow. It has no effect without WithLogger.
+func WithSlowListenerThreshold(threshold time.Duration) Option {
+	return func(ge *GoEvent) {
+		ge.slowListener = threshold
+	}
+}
+
+// log emits a record if a logger is configured
+func (ge *GoEvent) log(level slog.Level, msg string, attrs ...slog.Attr) {
+	if ge.logger == nil {
+		return
+	}
+	ge.logger.LogAttrs(context.Background(), level, msg, attrs...)
+}
+
+// logSlow logs listener calls that took longer than the threshold.
+// Use it as: defer ge.logSlow(eventName, listenerType, dispatchID, time.Now())
+func (ge *GoEvent) logSlow(eventName, listenerType, dispatchID string, start time.Time) {
+	if ge.logger == nil {
+		return
+	}
+	elapsed := time.Since(start)
+	if elapsed <= ge.slowListener {
+		return
+	}
+
+	attrs := []slog.Attr{
+		slog.String("event", eventName),
+		slog.String("listener", listenerType),
+		slog.Duration("duration", elapsed),
+	}
+	if dispatchID != "" {
+		attrs = append(attrs, slog.String("dispatch_id", dispatchID))
+	}
+	ge.log(slog.LevelWarn, "slow listener", attrs...)
+}
+
+// logError logs a recorded error, at error level for panics
+func (ge *GoEvent) logError(err *EventError) {
+	if ge.logger == nil {
+		return
+	}
+
+	attrs := []slog.Attr{
+		slog.String("event", err.EventName),
+		slog.String("error", err.Err.Error()),
+	}
+	if err.ListenerType != "" {
+		attrs = append(attrs, slog.String("listener", err.ListenerType))
+	}
+	if err.DispatchID != "" {
+		attrs = append(attrs, slog.String("dispatch_id", err.DispatchID))
+	}
+	if err.EventID != "" && err.EventID != err.DispatchID {
+		attrs = append(attrs, slog.String("event_id", err.EventID))
+	}
+	if err.Attempts > 1 {
+		attrs = append(attrs, slog.Int("attempts", err.Attempts))
+	}
+	if err.Duration > 0 {
+		attrs = append(attrs, slog.Duration("duration", err.Duration))
+	}
+
+	var panicErr *PanicError
+	if errors.As(err.Err, &panicErr) {
+		attrs = append(attrs, slog.String("stack", string(panicErr.Stack)))
+		ge.log(slog.LevelError, "listener panicked", attrs...)
+		return
+	}
+	ge.log(slog.LevelWarn, "event error", attrs...)
+}
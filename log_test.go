@@ -0,0 +1,118 @@
+package goevent
+
+import (
+	"context"
+	"log/slog"
+	"sync"
+	"testing"
+	"time"
+)
+
+// testLogHandler collects log records for inspection
+type testLogHandler struct {
+	mu      sync.Mutex
+	records []slog.Record
+}
+
+func (h *testLogHandler) Enabled(context.Context, slog.Level) bool { return true }
+func (h *testLogHandler) WithAttrs([]slog.Attr) slog.Handler       { return h }
+func (h *testLogHandler) WithGroup(string) slog.Handler            { return h }
+
+func (h *testLogHandler) Handle(_ context.Context, record slog.Record) error {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.records = append(h.records, record)
+	return nil
+}
+
+// find returns the first record with the given message
+func (h *testLogHandler) find(msg string) (slog.Record, bool) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	for _, record := range h.records {
+		if record.Message == msg {
+			return record, true
+		}
+	}
+	return slog.Record{}, false
+}
+
+func recordAttr(record slog.Record, key string) string {
+	var value string
+	record.Attrs(func(attr slog.Attr) bool {
+		if attr.Key == key {
+			value = attr.Value.String()
+			return false
+		}
+		return true
+	})
+	return value
+}
+
+func TestLogger_RegistrationAndDispatch(t *testing.T) {
+	handler := &testLogHandler{}
+	evt := New(WithLogger(slog.New(handler)))
+	evt.RegisterListener(&testSyncListener{})
+	evt.Dispatch(&TestEvent{data: "test"})
+
+	record, ok := handler.find("listener registered")
+	if !ok {
+		t.Fatal("Expected a registration record")
+	}
+	if record.Level != slog.LevelDebug || recordAttr(record, "listener") != "*goevent.testSyncListener" {
+		t.Errorf("Unexpected registration record: %v %v", record.Level, recordAttr(record, "listener"))
+	}
+	if record, ok := handler.find("dispatching event"); !ok || recordAttr(record, "event") != "test.event" {
+		t.Error("Expected a dispatch record for 'test.event'")
+	}
+}
+
+func TestLogger_ErrorsAndPanics(t *testing.T) {
+	handler := &testLogHandler{}
+	evt := New(WithLogger(slog.New(handler)))
+	evt.RegisterListener(&testErrorListener{}, &testPanicListener{})
+	evt.Dispatch(&TestEvent{data: "test"})
+
+	if record, ok := handler.find("event error"); !ok || record.Level != slog.LevelWarn {
+		t.Error("Expected a warn record for the listener error")
+	} else if recordAttr(record, "error") != "test error" {
+		t.Errorf("Expected error 'test error', got '%s'", recordAttr(record, "error"))
+	}
+	if record, ok := handler.find("listener panicked"); !ok || record.Level != slog.LevelError {
+		t.Error("Expected an error record for the panic")
+	}
+}
+
+func TestLogger_SlowListener(t *testing.T) {
+	handler := &testLogHandler{}
+	evt := New(WithLogger(slog.New(handler)), WithSlowListenerThreshold(10*time.Millisecond))
+	listener := &testBlockingListener{release: make(chan struct{})}
+	evt.RegisterListener(listener)
+
+	handle := evt.Dispatch(&TestEvent{data: "test"})
+	time.Sleep(20 * time.Millisecond)
+	close(listener.release)
+	handle.Wait()
+
+	record, ok := handler.find("slow listener")
+	if !ok {
+		t.Fatal("Expected a slow listener record")
+	}
+	if recordAttr(record, "listener") != "*goevent.testBlockingListener" {
+		t.Errorf("Expected listener '*goevent.testBlockingListener', got '%s'", recordAttr(record, "listener"))
+	}
+}
+
+func TestLogger_DispatchID(t *testing.T) {
+	handler := &testLogHandler{}
+	evt := New(WithLogger(slog.New(handler)))
+	evt.RegisterListener(&testErrorListener{})
+	handle := evt.Dispatch(&TestEvent{data: "test"})
+
+	for _, msg := range []string{"dispatching event", "event error"} {
+		record, _ := handler.find(msg)
+		if got := recordAttr(record, "dispatch_id"); got != handle.DispatchID() {
+			t.Errorf("Expected %q record to carry dispatch ID '%s', got '%s'", msg, handle.DispatchID(), got)
+		}
+	}
+}
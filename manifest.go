@@ -0,0 +1,90 @@
+package goevent
+
+import (
+	"errors"
+	"fmt"
+)
+
+// Manifest declares a fleet of listeners so they can be configured and
+// reviewed in one place. Register it with RegisterFromManifest.
+type Manifest struct {
+	Listeners []ManifestEntry
+
+	// DisabledGroups lists groups whose listeners are not registered
+	DisabledGroups []string
+}
+
+// ManifestEntry declares a single listener
+type ManifestEntry struct {
+	// Name identifies the entry in errors. Defaults to its position.
+	Name string
+
+	// New constructs the listener
+	New func() (Listener, error)
+
+	// Options replaces the listener's own options when set
+	Options *ListenerOptions
+}
+
+// RegisterFromManifest constructs every listener in the manifest and
+// registers them in order. If any constructor fails, nothing is registered
+// and the returned error reports each failing entry.
+func (ge *GoEvent) RegisterFromManifest(m Manifest) error {
+	disabled := make(map[string]bool, len(m.DisabledGroups))
+	for _, group := range m.DisabledGroups {
+		disabled[group] = true
+	}
+
+	var listeners []Listener
+	var options []ListenerOptions
+	var errs []error
+	for i, entry := range m.Listeners {
+		name := entry.Name
+		if name == "" {
+			name = fmt.Sprintf("#%d", i)
+		}
+		if entry.New == nil {
+			errs = append(errs, fmt.Errorf("goevent: manifest entry %s: no constructor", name))
+			continue
+		}
+
+		listener, err := entry.New()
+		if err == nil && listener == nil {
+			err = errors.New("constructor returned nil")
+		}
+		if err != nil {
+			errs = append(errs, fmt.Errorf("goevent: manifest entry %s: %w", name, err))
+			continue
+		}
+
+		// Overriding options are passed on at subscription rather than
+		// by wrapping the listener, which would hide the optional
+		// interfaces it implements
+		opts := optionsOf(listener)
+		if entry.Options != nil {
+			opts = *entry.Options
+		}
+		if disabled[opts.Group] {
+			continue
+		}
+		listeners = append(listeners, listener)
+		options = append(options, opts)
+	}
+	if len(errs) > 0 {
+		return errors.Join(errs...)
+	}
+
+	ge.claimListeners(listeners, false)
+	for i, listener := range listeners {
+		ge.subscribeAll(listener, nil, options[i])
+	}
+	return nil
+}
+
+// optionsOf returns the options of a listener, or the zero value
+func optionsOf(listener Listener) ListenerOptions {
+	if withOpts, ok := listener.(ListenerWithOptions); ok {
+		return withOpts.Options()
+	}
+	return ListenerOptions{}
+}
@@ -0,0 +1,156 @@
+package goevent
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+)
+
+type testSlowContextListener struct{}
+
+func (l *testSlowContextListener) EventName() string {
+	return "test.event"
+}
+
+func (l *testSlowContextListener) OnEvent(event Event) error {
+	return l.OnEventContext(context.Background(), event)
+}
+
+func (l *testSlowContextListener) OnEventContext(ctx context.Context, event Event) error {
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-time.After(time.Second):
+		return nil
+	}
+}
+
+func TestRegisterFromManifest(t *testing.T) {
+	evt := New()
+	syncListener := &testCountingListener{}
+	asyncListener := &testCountingListener{}
+	reports := &testCountingListener{}
+
+	err := evt.RegisterFromManifest(Manifest{
+		Listeners: []ManifestEntry{
+			{Name: "sync", New: func() (Listener, error) { return syncListener, nil }},
+			{
+				Name:    "async",
+				New:     func() (Listener, error) { return asyncListener, nil },
+				Options: &ListenerOptions{Async: true, Group: "billing"},
+			},
+			{
+				Name:    "reports",
+				New:     func() (Listener, error) { return reports, nil },
+				Options: &ListenerOptions{Group: "reporting"},
+			},
+		},
+		DisabledGroups: []string{"reporting"},
+	})
+	if err != nil {
+		t.Fatalf("RegisterFromManifest() failed: %v", err)
+	}
+
+	infos := evt.Listeners()["test.event"]
+	if len(infos) != 2 {
+		t.Fatalf("Expected 2 listeners, got %d", len(infos))
+	}
+	if infos[1].Type != "*goevent.testCountingListener" || !infos[1].Async || infos[1].Options.Group != "billing" {
+		t.Errorf("Expected async billing listener, got %+v", infos[1])
+	}
+
+	evt.Dispatch(&TestEvent{}).Wait()
+	if syncListener.Count() != 1 || asyncListener.Count() != 1 || reports.Count() != 0 {
+		t.Errorf("Expected 1, 1 and 0 deliveries, got %d, %d and %d", syncListener.Count(), asyncListener.Count(), reports.Count())
+	}
+}
+
+func TestRegisterFromManifest_AllOrNothing(t *testing.T) {
+	evt := New()
+	errBroken := errors.New("missing config")
+
+	err := evt.RegisterFromManifest(Manifest{
+		Listeners: []ManifestEntry{
+			{New: func() (Listener, error) { return &testCountingListener{}, nil }},
+			{Name: "broken", New: func() (Listener, error) { return nil, errBroken }},
+			{Name: "empty"},
+		},
+	})
+	if !errors.Is(err, errBroken) {
+		t.Fatalf("Expected constructor error, got %v", err)
+	}
+	if !strings.Contains(err.Error(), "broken") || !strings.Contains(err.Error(), "empty") {
+		t.Errorf("Expected every failing entry to be named, got %v", err)
+	}
+	if len(evt.Events()) != 0 {
+		t.Errorf("Expected nothing to be registered, got %v", evt.Events())
+	}
+}
+
+func TestListenerOptions_Timeout(t *testing.T) {
+	evt := New()
+	err := evt.RegisterFromManifest(Manifest{
+		Listeners: []ManifestEntry{{
+			New:     func() (Listener, error) { return &testSlowContextListener{}, nil },
+			Options: &ListenerOptions{Timeout: 10 * time.Millisecond},
+		}},
+	})
+	if err != nil {
+		t.Fatalf("RegisterFromManifest() failed: %v", err)
+	}
+
+	start := time.Now()
+	handle := evt.Dispatch(&TestEvent{})
+	if elapsed := time.Since(start); elapsed > 500*time.Millisecond {
+		t.Errorf("Expected the timeout to end the call, took %v", elapsed)
+	}
+	errs := handle.GetErrors()
+	if len(errs) != 1 || !errors.Is(errs[0].Err, context.DeadlineExceeded) {
+		t.Errorf("Expected context.DeadlineExceeded, got %v", errs)
+	}
+}
+
+func TestRegisterFromManifest_OptionsKeepInterfaces(t *testing.T) {
+	evt := New()
+	projection := &testProjection{}
+	overridden := &ListenerOptions{Group: "overridden"}
+
+	err := evt.RegisterFromManifest(Manifest{
+		Listeners: []ManifestEntry{
+			{Name: "projection", New: func() (Listener, error) { return projection, nil }, Options: overridden},
+			{Name: "responder", New: func() (Listener, error) { return &testPriceResponder{}, nil }, Options: overridden},
+			{Name: "enricher", New: func() (Listener, error) { return &testEnricher{}, nil }, Options: overridden},
+		},
+	})
+	if err != nil {
+		t.Fatalf("RegisterFromManifest() failed: %v", err)
+	}
+
+	evt.Dispatch(&GenericEvent{EventName: "order.placed"})
+	if len(projection.received) != 1 || projection.received[0] != "order.placed" {
+		t.Errorf("Expected the events named by EventNames, got %v", projection.received)
+	}
+
+	resp, err := evt.Request(context.Background(), &GenericEvent{EventName: "price.requested"})
+	if err != nil {
+		t.Fatalf("Request() failed: %v", err)
+	}
+	if quote, ok := resp.Value.(testQuote); !ok || quote.Price != 42 || resp.Responder != "*goevent.testPriceResponder" {
+		t.Errorf("Expected a quote of 42 from the responder, got %+v", resp)
+	}
+
+	handle := evt.Dispatch(&TestEvent{data: "order"})
+	if _, ok := handle.Results()["*goevent.testEnricher"]; !ok {
+		t.Errorf("Expected a result from the enricher, got %v", handle.Results())
+	}
+
+	for _, infos := range evt.Listeners() {
+		for _, info := range infos {
+			if info.Options.Group != "overridden" {
+				t.Errorf("Expected the manifest's options, got %+v", info)
+			}
+		}
+	}
+}
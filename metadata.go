@@ -0,0 +1,111 @@
+package goevent
+
+import (
+	"context"
+	"time"
+)
+
+// Well-known metadata keys. WithMetadata sets the matching Metadata
+// fields from them; any other key becomes a header.
+const (
+	MetadataCorrelationID = "correlation_id"
+	MetadataCausationID   = "causation_id"
+	MetadataSource        = "source"
+	MetadataTenant        = "tenant"
+)
+
+// Metadata describes an event in transit. The well-known fields are
+// plain struct fields, so carrying them costs no allocations beyond
+// their strings; Headers holds anything else and stays nil unless used.
+type Metadata struct {
+	ID            string
+	Time          time.Time // when the event was dispatched
+	Deadline      time.Time // zero if there is none
+	CorrelationID string
+	CausationID   string // ID of the event whose handling caused this one
+	Source        string // service or component the event came from
+	Tenant        string
+	Priority      Priority
+	Headers       map[string]string
+}
+
+// Header returns a custom header, or "" if it is not set
+func (m *Metadata) Header(key string) string {
+	return m.Headers[key]
+}
+
+// SetHeader sets a custom header
+func (m *Metadata) SetHeader(key, value string) {
+	if m.Headers == nil {
+		m.Headers = make(map[string]string)
+	}
+	m.Headers[key] = value
+}
+
+// WithMetadata attaches metadata to the dispatch, for transport-level
+// concerns that do not belong in the payload. The correlation_id,
+// causation_id, source and tenant keys set the matching Metadata fields,
+// overriding values inherited from a parent dispatch; every other key is
+// a header.
+// Listeners read it from DispatchContext.Metadata or MetadataFromContext.
+func WithMetadata(md map[string]string) DispatchOption {
+	return func(c *dispatchConfig) {
+		if c.metadata == nil {
+			c.metadata = make(map[string]string, len(md))
+		}
+		for key, value := range md {
+			c.metadata[key] = value
+		}
+	}
+}
+
+// apply sets the fields and headers given by WithMetadata
+func (m *Metadata) apply(md map[string]string) {
+	for key, value := range md {
+		switch key {
+		case MetadataCorrelationID:
+			m.CorrelationID = value
+		case MetadataCausationID:
+			m.CausationID = value
+		case MetadataSource:
+			m.Source = value
+		case MetadataTenant:
+			m.Tenant = value
+		default:
+			m.SetHeader(key, value)
+		}
+	}
+}
+
+// Fields returns the well-known fields and headers of m in the form
+// WithMetadata takes, so a transport can dispatch a received event with
+// the metadata it was sent with
+func (m Metadata) Fields() map[string]string {
+	fields := make(map[string]string, len(m.Headers)+4)
+	for key, value := range m.Headers {
+		fields[key] = value
+	}
+	if m.CorrelationID != "" {
+		fields[MetadataCorrelationID] = m.CorrelationID
+	}
+	if m.CausationID != "" {
+		fields[MetadataCausationID] = m.CausationID
+	}
+	if m.Source != "" {
+		fields[MetadataSource] = m.Source
+	}
+	if m.Tenant != "" {
+		fields[MetadataTenant] = m.Tenant
+	}
+	return fields
+}
+
+// MetadataFromContext returns the metadata of the dispatch a listener is
+// currently handling
+func MetadataFromContext(ctx context.Context) (Metadata, bool) {
+	handle, ok := HandleFromContext(ctx)
+	if !ok {
+		return Metadata{}, false
+	}
+	return handle.Metadata(), true
+}
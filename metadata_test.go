@@ -0,0 +1,117 @@
+package goevent
+
+import (
+	"context"
+	"testing"
+)
+
+func TestWithMetadata(t *testing.T) {
+	evt := New()
+	var got DispatchContext
+	evt.RegisterListener(&testAwareFunc{name: "order.placed", fn: func(dc DispatchContext) {
+		got = dc
+	}})
+
+	handle := evt.Dispatch(&GenericEvent{EventName: "order.placed"}, WithMetadata(map[string]string{
+		MetadataCorrelationID: "req-1",
+		MetadataSource:        "checkout",
+		MetadataTenant:        "acme",
+		"region":              "eu",
+	}))
+
+	md := got.Metadata
+	if md.CorrelationID != "req-1" || md.Source != "checkout" || md.Tenant != "acme" {
+		t.Errorf("Expected the well-known fields to be set, got %+v", md)
+	}
+	if md.Header("region") != "eu" || len(md.Headers) != 1 {
+		t.Errorf("Expected only region as a header, got %v", md.Headers)
+	}
+	if md.ID != handle.DispatchID() {
+		t.Errorf("Expected metadata ID %q, got %q", handle.DispatchID(), md.ID)
+	}
+
+	// Listeners get their own copy of the headers
+	md.SetHeader("region", "us")
+	if fresh := handle.Metadata(); fresh.Header("region") != "eu" {
+		t.Error("Changing the listener's headers changed the dispatch")
+	}
+}
+
+func TestWithMetadata_OverridesInheritedCorrelation(t *testing.T) {
+	evt := New()
+	var child Metadata
+	evt.RegisterListener(&testAwareFunc{name: "order.placed", fn: func(dc DispatchContext) {
+		dc.Emit(&GenericEvent{EventName: "invoice.requested"}, WithMetadata(map[string]string{MetadataCorrelationID: "billing-run"}))
+	}})
+	evt.RegisterListener(&testAwareFunc{name: "invoice.requested", fn: func(dc DispatchContext) {
+		child = dc.Metadata
+	}})
+
+	evt.Dispatch(&GenericEvent{EventName: "order.placed"}, WithMetadata(map[string]string{MetadataTenant: "acme"}))
+
+	if child.CorrelationID != "billing-run" || child.Tenant != "acme" {
+		t.Errorf("Expected correlation billing-run and inherited tenant acme, got %+v", child)
+	}
+}
+
+// testMetadataListener records the metadata found in its context
+type testMetadataListener struct {
+	metadata Metadata
+	found    bool
+}
+
+func (l *testMetadataListener) EventName() string {
+	return "test.event"
+}
+
+func (l *testMetadataListener) OnEvent(event Event) error {
+	return nil
+}
+
+func (l *testMetadataListener) OnEventContext(ctx context.Context, event Event) error {
+	l.metadata, l.found = MetadataFromContext(ctx)
+	return nil
+}
+
+func TestMetadataFromContext(t *testing.T) {
+	if _, ok := MetadataFromContext(context.Background()); ok {
+		t.Error("Expected no metadata outside a dispatch")
+	}
+
+	evt := New()
+	listener := &testMetadataListener{}
+	evt.RegisterListener(listener)
+	evt.Dispatch(&TestEvent{}, WithMetadata(map[string]string{MetadataSource: "cron"}))
+
+	if !listener.found || listener.metadata.Source != "cron" {
+		t.Errorf("Expected source cron, got %+v", listener.metadata)
+	}
+}
+
+func TestCausationChain(t *testing.T) {
+	evt := New()
+	var child, grandchild Metadata
+	evt.RegisterListener(&testAwareFunc{name: "order.placed", fn: func(dc DispatchContext) {
+		dc.Emit(&GenericEvent{EventName: "invoice.requested"})
+	}})
+	evt.RegisterListener(&testAwareFunc{name: "invoice.requested", fn: func(dc DispatchContext) {
+		child = dc.Metadata
+		dc.Emit(&GenericEvent{EventName: "invoice.sent"})
+	}})
+	evt.RegisterListener(&testAwareFunc{name: "invoice.sent", fn: func(dc DispatchContext) {
+		grandchild = dc.Metadata
+	}})
+
+	root := evt.Dispatch(&GenericEvent{EventName: "order.placed"}).Metadata()
+
+	if root.CausationID != "" {
+		t.Errorf("Expected no causation on the root dispatch, got %q", root.CausationID)
+	}
+	if child.CausationID != root.ID || grandchild.CausationID != child.ID {
+		t.Errorf("Expected causation %s <- %s <- %s, got %q and %q",
+			root.ID, child.ID, grandchild.ID, child.CausationID, grandchild.CausationID)
+	}
+	if child.CorrelationID != root.ID || grandchild.CorrelationID != root.ID {
+		t.Errorf("Expected every event correlated to %s, got %q and %q", root.ID, child.CorrelationID, grandchild.CorrelationID)
+	}
+}
@@ -0,0 +1,77 @@
+package goevent
+
+import (
+	"context"
+	"runtime/debug"
+)
+
+// HandlerFunc invokes a listener for a single event
+type HandlerFunc func(ctx context.Context, event Event) error
+
+// Middleware wraps listener invocation. It is executed for every listener
+// call and may run code before and after calling next, alter the error,
+// or skip next entirely.
+type Middleware func(next HandlerFunc) HandlerFunc
+
+type listenerContextKey struct{}
+
+// Use appends middleware to the chain applied to every listener call.
+// Middleware registered first runs outermost.
+func (ge *GoEvent) Use(middleware ...Middleware) {
+	ge.middlewareMu.Lock()
+	defer ge.middlewareMu.Unlock()
+	ge.middleware = append(ge.middleware, middleware...)
+}
+
+// ListenerFromContext returns the listener being invoked.
+// It is available to middleware through the context passed to HandlerFunc.
+func ListenerFromContext(ctx context.Context) (Listener, bool) {
+	listener, ok := ctx.Value(listenerContextKey{}).(Listener)
+	return listener, ok
+}
+
+// invoke calls the listener through the middleware chain.
+// Panics raised by the listener or any middleware are recovered
+// and returned as a *PanicError.
+func (ge *GoEvent) invoke(ctx context.Context, listener Listener, event Event) (err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = &PanicError{Value: r, Stack: debug.Stack()}
+		}
+	}()
+
+	ge.middlewareMu.RLock()
+	middleware := ge.middleware
+	ge.middlewareMu.RUnlock()
+
+	handler := HandlerFunc(func(ctx context.Context, event Event) error {
+		if responder, ok := listener.(Responder); ok {
+			value, err := ge.callForResult(ctx, listener, func() (any, error) {
+				return responder.Respond(ctx, event)
+			})
+			if err == nil {
+				respond(ctx, listener, value)
+			}
+			return err
+		}
+		if resulting, ok := listener.(ResultListener); ok {
+			_, err := ge.callForResult(ctx, listener, func() (any, error) {
+				return resulting.OnEventResult(event)
+			})
+			return err
+		}
+		if aware, ok := listener.(AwareListener); ok {
+			return aware.OnEventAware(ge.dispatchContext(ctx), event)
+		}
+		if contextListener, ok := listener.(ContextListener); ok {
+			return contextListener.OnEventContext(ctx, event)
+		}
+		return listener.OnEvent(event)
+	})
+	for i := len(middleware) - 1; i >= 0; i-- {
+		handler = middleware[i](handler)
+	}
+
+	ctx = context.WithValue(ctx, listenerContextKey{}, listener)
+	return handler(ctx, event)
+}
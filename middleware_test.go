@@ -0,0 +1,86 @@
+package goevent
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+)
+
+func TestMiddleware_WrapsEveryListenerCall(t *testing.T) {
+	evt := New()
+
+	var mu sync.Mutex
+	var calls []string
+	record := func(name string) Middleware {
+		return func(next HandlerFunc) HandlerFunc {
+			return func(ctx context.Context, event Event) error {
+				mu.Lock()
+				calls = append(calls, name+":before")
+				mu.Unlock()
+				err := next(ctx, event)
+				mu.Lock()
+				calls = append(calls, name+":after")
+				mu.Unlock()
+				return err
+			}
+		}
+	}
+
+	evt.Use(record("outer"), record("inner"))
+	evt.RegisterListener(&testSyncListener{})
+	evt.Dispatch(&TestEvent{data: "middleware"})
+
+	expected := []string{"outer:before", "inner:before", "inner:after", "outer:after"}
+	if len(calls) != len(expected) {
+		t.Fatalf("Expected %d calls, got %d: %v", len(expected), len(calls), calls)
+	}
+	for i := range expected {
+		if calls[i] != expected[i] {
+			t.Errorf("Call %d: expected '%s', got '%s'", i, expected[i], calls[i])
+		}
+	}
+}
+
+func TestMiddleware_CanReplaceError(t *testing.T) {
+	evt := New()
+	errWrapped := errors.New("wrapped")
+
+	evt.Use(func(next HandlerFunc) HandlerFunc {
+		return func(ctx context.Context, event Event) error {
+			if err := next(ctx, event); err != nil {
+				return errWrapped
+			}
+			return nil
+		}
+	})
+	evt.RegisterListener(&testErrorListener{})
+	handle := evt.Dispatch(&TestEvent{data: "error"})
+
+	errs := handle.GetErrors()
+	if len(errs) != 1 {
+		t.Fatalf("Expected 1 error, got %d", len(errs))
+	}
+	if errs[0].Err != errWrapped {
+		t.Errorf("Expected middleware error, got '%v'", errs[0].Err)
+	}
+}
+
+func TestMiddleware_ListenerFromContext(t *testing.T) {
+	evt := New()
+	listener := &testAsyncListener{}
+
+	var seen Listener
+	evt.Use(func(next HandlerFunc) HandlerFunc {
+		return func(ctx context.Context, event Event) error {
+			seen, _ = ListenerFromContext(ctx)
+			return next(ctx, event)
+		}
+	})
+	evt.RegisterListener(listener)
+	evt.Dispatch(&TestEvent{data: "context"}).Wait()
+
+	if seen != listener {
+		t.Errorf("Expected middleware to see registered listener, got %v", seen)
+	}
+}
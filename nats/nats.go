@@ -0,0 +1,239 @@
+// Package nats bridges a goevent bus and NATS, so events flow between
+// services.
+//
+// Publish forwards dispatched events to subjects named after them, and
+// Subscribe dispatches events received on subjects into the local bus.
+// With JetStream, events are published to streams and SubscribeDurable
+// consumes them through a durable consumer, acknowledging each message
+// only once every local listener handled it without error.
+//
+//	bridge := nats.New(bus, conn, nats.WithSubjectPrefix("events."))
+//	bridge.Publish("user.created", "user.deleted")
+//	bridge.Subscribe("events.order.>")
+//
+// Events received from NATS are not published back, so a service can
+// publish and subscribe the same events without looping.
+package nats
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"reflect"
+	"sync"
+
+	"github.com/nats-io/nats.go"
+	"github.com/nats-io/nats.go/jetstream"
+
+	"github.com/openframebox/goevent"
+)
+
+// Option configures a Bridge
+type Option func(*Bridge)
+
+// WithCodec sets the codec envelopes are encoded with.
+// Defaults to goevent.JSONCodec.
+func WithCodec(codec goevent.Codec) Option {
+	return func(b *Bridge) {
+		b.codec = codec
+	}
+}
+
+// WithSubjectPrefix sets the prefix of the subject an event is published
+// to, which is otherwise its name
+func WithSubjectPrefix(prefix string) Option {
+	return func(b *Bridge) {
+		b.prefix = prefix
+	}
+}
+
+// WithJetStream publishes through JetStream, waiting for the stream to
+// store each event, and enables SubscribeDurable. The envelope ID is
+// sent as the message ID, so the stream drops duplicates.
+func WithJetStream(js jetstream.JetStream) Option {
+	return func(b *Bridge) {
+		b.js = js
+	}
+}
+
+// Bridge connects a bus to a NATS connection
+type Bridge struct {
+	bus    *goevent.GoEvent
+	conn   *nats.Conn
+	js     jetstream.JetStream
+	codec  goevent.Codec
+	prefix string
+
+	mu       sync.Mutex
+	subs     []*nats.Subscription
+	consumes []jetstream.ConsumeContext
+}
+
+// remoteKey holds the event of a dispatch received from NATS. Dispatches
+// its listeners make inherit the value but carry other events, so they
+// are still forwarded.
+type remoteKey struct{}
+
+// New creates a Bridge between bus and conn
+func New(bus *goevent.GoEvent, conn *nats.Conn, opts ...Option) *Bridge {
+	b := &Bridge{bus: bus, conn: conn, codec: goevent.JSONCodec{}}
+	for _, opt := range opts {
+		opt(b)
+	}
+	return b
+}
+
+// Subject returns the subject an event is published to
+func (b *Bridge) Subject(eventName string) string {
+	return b.prefix + eventName
+}
+
+// Publish forwards every dispatch of the named events to NATS. Forwarding
+// runs as a sync listener, so events are published in dispatch order and
+// a failed publish is recorded on the dispatch.
+func (b *Bridge) Publish(eventNames ...string) {
+	for _, name := range eventNames {
+		b.bus.RegisterListener(&forwarder{bridge: b, eventName: name})
+	}
+}
+
+// PublishEvent sends a single event to NATS. When ctx belongs to a
+// dispatch, its metadata travels with the event.
+func (b *Bridge) PublishEvent(ctx context.Context, event goevent.Event) error {
+	env := goevent.NewEnvelope(event)
+	if handle, ok := goevent.HandleFromContext(ctx); ok {
+		env.Metadata = handle.Metadata()
+	}
+	data, err := b.codec.Marshal(env)
+	if err != nil {
+		return err
+	}
+
+	subject := b.Subject(event.Name())
+	if b.js != nil {
+		_, err = b.js.Publish(ctx, subject, data, jetstream.WithMsgID(env.ID))
+	} else {
+		err = b.conn.Publish(subject, data)
+	}
+	if err != nil {
+		return fmt.Errorf("nats: publish %s: %w", subject, err)
+	}
+	return nil
+}
+
+// Subscribe dispatches events received on subject, which may contain
+// wildcards, into the bus. Messages that cannot be decoded are dropped.
+func (b *Bridge) Subscribe(subject string) (*nats.Subscription, error) {
+	sub, err := b.conn.Subscribe(subject, func(msg *nats.Msg) {
+		b.dispatch(msg.Data)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	b.mu.Lock()
+	b.subs = append(b.subs, sub)
+	b.mu.Unlock()
+	return sub, nil
+}
+
+// SubscribeDurable consumes a JetStream stream through the named durable
+// consumer, creating or updating it, and dispatches each event into the
+// bus. A message is acknowledged once the dispatch completes without
+// errors and redelivered otherwise; messages that cannot be decoded are
+// terminated. Without subjects, the consumer receives the whole stream.
+func (b *Bridge) SubscribeDurable(ctx context.Context, stream, durable string, subjects ...string) (jetstream.ConsumeContext, error) {
+	if b.js == nil {
+		return nil, errors.New("nats: SubscribeDurable requires WithJetStream")
+	}
+
+	consumer, err := b.js.CreateOrUpdateConsumer(ctx, stream, jetstream.ConsumerConfig{
+		Durable:        durable,
+		FilterSubjects: subjects,
+		AckPolicy:      jetstream.AckExplicitPolicy,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("nats: consumer %s: %w", durable, err)
+	}
+
+	consume, err := consumer.Consume(func(msg jetstream.Msg) {
+		handle := b.dispatch(msg.Data())
+		if handle == nil {
+			msg.Term()
+			return
+		}
+		handle.Wait()
+		if len(handle.GetErrors()) > 0 {
+			msg.Nak()
+			return
+		}
+		msg.Ack()
+	})
+	if err != nil {
+		return nil, fmt.Errorf("nats: consume %s: %w", durable, err)
+	}
+
+	b.mu.Lock()
+	b.consumes = append(b.consumes, consume)
+	b.mu.Unlock()
+	return consume, nil
+}
+
+// Close stops every subscription and consumer started by the bridge.
+// The connection is left open.
+func (b *Bridge) Close() error {
+	b.mu.Lock()
+	subs, consumes := b.subs, b.consumes
+	b.subs, b.consumes = nil, nil
+	b.mu.Unlock()
+
+	var errs []error
+	for _, sub := range subs {
+		if err := sub.Unsubscribe(); err != nil && !errors.Is(err, nats.ErrConnectionClosed) {
+			errs = append(errs, err)
+		}
+	}
+	for _, consume := range consumes {
+		consume.Stop()
+	}
+	return errors.Join(errs...)
+}
+
+// dispatch decodes an envelope and dispatches its event with the
+// metadata it was sent with, marked as received so it is not forwarded
+// back. It returns nil if the data cannot be decoded.
+func (b *Bridge) dispatch(data []byte) *goevent.DispatchHandle {
+	env, err := b.codec.Unmarshal(data)
+	if err != nil {
+		return nil
+	}
+	ctx := context.WithValue(context.Background(), remoteKey{}, env.Event)
+	return b.bus.DispatchContext(ctx, env.Event, goevent.WithMetadata(env.Metadata.Fields()))
+}
+
+// forwarder publishes the events of one name to NATS
+type forwarder struct {
+	bridge    *Bridge
+	eventName string
+}
+
+func (f *forwarder) EventName() string {
+	return f.eventName
+}
+
+func (f *forwarder) OnEvent(event goevent.Event) error {
+	return f.OnEventContext(context.Background(), event)
+}
+
+func (f *forwarder) OnEventContext(ctx context.Context, event goevent.Event) error {
+	if isRemote(ctx, event) {
+		return nil
+	}
+	return f.bridge.PublishEvent(ctx, event)
+}
+
+// isRemote reports whether event was received from NATS
+func isRemote(ctx context.Context, event goevent.Event) bool {
+	remote, ok := ctx.Value(remoteKey{}).(goevent.Event)
+	return ok && reflect.TypeOf(remote).Comparable() && remote == event
+}
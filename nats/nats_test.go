@@ -0,0 +1,266 @@
+package nats
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/nats-io/nats-server/v2/server"
+	"github.com/nats-io/nats.go"
+	"github.com/nats-io/nats.go/jetstream"
+
+	"github.com/openframebox/goevent"
+)
+
+func runServer(t *testing.T) *nats.Conn {
+	t.Helper()
+	srv, err := server.NewServer(&server.Options{
+		Host:      "127.0.0.1",
+		Port:      -1,
+		NoLog:     true,
+		NoSigs:    true,
+		JetStream: true,
+		StoreDir:  t.TempDir(),
+	})
+	if err != nil {
+		t.Fatalf("starting NATS server: %v", err)
+	}
+	go srv.Start()
+	if !srv.ReadyForConnections(5 * time.Second) {
+		t.Fatal("NATS server not ready")
+	}
+	t.Cleanup(srv.Shutdown)
+
+	conn, err := nats.Connect(srv.ClientURL())
+	if err != nil {
+		t.Fatalf("connecting to NATS: %v", err)
+	}
+	t.Cleanup(conn.Close)
+	return conn
+}
+
+type testRecorder struct {
+	eventName string
+	fail      int // number of calls to fail
+
+	mu       sync.Mutex
+	events   []goevent.Event
+	metadata []goevent.Metadata
+	calls    chan struct{}
+}
+
+func newTestRecorder(eventName string) *testRecorder {
+	return &testRecorder{eventName: eventName, calls: make(chan struct{}, 16)}
+}
+
+func (r *testRecorder) EventName() string {
+	return r.eventName
+}
+
+func (r *testRecorder) OnEvent(event goevent.Event) error {
+	r.mu.Lock()
+	defer func() {
+		r.mu.Unlock()
+		r.calls <- struct{}{}
+	}()
+	if r.fail > 0 {
+		r.fail--
+		return errors.New("not yet")
+	}
+	r.events = append(r.events, event)
+	return nil
+}
+
+func (r *testRecorder) OnEventContext(ctx context.Context, event goevent.Event) error {
+	if md, ok := goevent.MetadataFromContext(ctx); ok {
+		r.mu.Lock()
+		r.metadata = append(r.metadata, md)
+		r.mu.Unlock()
+	}
+	return r.OnEvent(event)
+}
+
+func (r *testRecorder) Metadata() []goevent.Metadata {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return append([]goevent.Metadata(nil), r.metadata...)
+}
+
+func (r *testRecorder) Events() []goevent.Event {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return append([]goevent.Event(nil), r.events...)
+}
+
+func (r *testRecorder) waitCalls(t *testing.T, n int) {
+	t.Helper()
+	for i := 0; i < n; i++ {
+		select {
+		case <-r.calls:
+		case <-time.After(5 * time.Second):
+			t.Fatalf("Expected %d calls, got %d", n, i)
+		}
+	}
+}
+
+func TestBridge_PublishSubscribe(t *testing.T) {
+	conn := runServer(t)
+
+	producer := goevent.New()
+	New(producer, conn, WithSubjectPrefix("events.")).Publish("user.created")
+
+	consumer := goevent.New()
+	recorder := newTestRecorder("user.created")
+	consumer.RegisterListener(recorder)
+	bridge := New(consumer, conn, WithSubjectPrefix("events."))
+	defer bridge.Close()
+	if _, err := bridge.Subscribe("events.user.>"); err != nil {
+		t.Fatalf("Subscribe() failed: %v", err)
+	}
+	conn.Flush()
+
+	handle := producer.Dispatch(&goevent.GenericEvent{EventName: "user.created", Data: map[string]any{"id": "u-1"}})
+	if errs := handle.GetErrors(); len(errs) != 0 {
+		t.Fatalf("Expected publish to succeed, got %v", errs)
+	}
+
+	recorder.waitCalls(t, 1)
+	events := recorder.Events()
+	if events[0].Payload()["id"] != "u-1" {
+		t.Errorf("Expected id u-1, got %v", events[0].Payload())
+	}
+}
+
+type testRelayListener struct {
+	bus *goevent.GoEvent
+}
+
+func (l *testRelayListener) EventName() string {
+	return "order.placed"
+}
+
+func (l *testRelayListener) OnEvent(event goevent.Event) error {
+	return nil
+}
+
+func (l *testRelayListener) OnEventContext(ctx context.Context, event goevent.Event) error {
+	l.bus.DispatchContext(ctx, &goevent.GenericEvent{EventName: "invoice.created"})
+	return nil
+}
+
+func (l *testRelayListener) Options() goevent.ListenerOptions {
+	return goevent.ListenerOptions{Async: true}
+}
+
+func TestBridge_NoEcho(t *testing.T) {
+	conn := runServer(t)
+
+	// The service publishes and subscribes the same events
+	bus := goevent.New()
+	bus.RegisterListener(&testRelayListener{bus: bus})
+	bridge := New(bus, conn)
+	defer bridge.Close()
+	bridge.Publish("order.placed", "invoice.created")
+	if _, err := bridge.Subscribe("order.placed"); err != nil {
+		t.Fatalf("Subscribe() failed: %v", err)
+	}
+
+	seen := make(chan string, 16)
+	watch, err := conn.Subscribe(">", func(msg *nats.Msg) { seen <- msg.Subject })
+	if err != nil {
+		t.Fatalf("Subscribe() failed: %v", err)
+	}
+	defer watch.Unsubscribe()
+	conn.Flush()
+
+	// Received from another service
+	if err := New(goevent.New(), conn).PublishEvent(context.Background(), &goevent.GenericEvent{EventName: "order.placed"}); err != nil {
+		t.Fatalf("PublishEvent() failed: %v", err)
+	}
+
+	var subjects []string
+	timeout := time.After(300 * time.Millisecond)
+	for done := false; !done; {
+		select {
+		case subject := <-seen:
+			subjects = append(subjects, subject)
+		case <-timeout:
+			done = true
+		}
+	}
+	if len(subjects) != 2 || subjects[0] != "order.placed" || subjects[1] != "invoice.created" {
+		t.Errorf("Expected the received event once and its follow-up forwarded, got %v", subjects)
+	}
+}
+
+func TestBridge_JetStreamDurable(t *testing.T) {
+	conn := runServer(t)
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	js, err := jetstream.New(conn)
+	if err != nil {
+		t.Fatalf("jetstream.New() failed: %v", err)
+	}
+	if _, err := js.CreateStream(ctx, jetstream.StreamConfig{Name: "EVENTS", Subjects: []string{"events.>"}}); err != nil {
+		t.Fatalf("CreateStream() failed: %v", err)
+	}
+
+	producer := goevent.New()
+	New(producer, conn, WithJetStream(js), WithSubjectPrefix("events.")).Publish("payment.received")
+	handle := producer.Dispatch(&goevent.GenericEvent{EventName: "payment.received", Data: map[string]any{"amount": 10}})
+	if errs := handle.GetErrors(); len(errs) != 0 {
+		t.Fatalf("Expected publish to succeed, got %v", errs)
+	}
+
+	// The consumer starts later and fails the first attempt
+	consumer := goevent.New()
+	recorder := newTestRecorder("payment.received")
+	recorder.fail = 1
+	consumer.RegisterListener(recorder)
+	bridge := New(consumer, conn, WithJetStream(js))
+	defer bridge.Close()
+	if _, err := bridge.SubscribeDurable(ctx, "EVENTS", "billing"); err != nil {
+		t.Fatalf("SubscribeDurable() failed: %v", err)
+	}
+
+	recorder.waitCalls(t, 2)
+	if events := recorder.Events(); len(events) != 1 || events[0].Payload()["amount"] != float64(10) {
+		t.Errorf("Expected the payment to be redelivered after the failure, got %v", events)
+	}
+}
+
+func TestBridge_SubscribeDurableRequiresJetStream(t *testing.T) {
+	bridge := New(goevent.New(), nil)
+	if _, err := bridge.SubscribeDurable(context.Background(), "EVENTS", "billing"); err == nil {
+		t.Error("Expected an error without JetStream")
+	}
+}
+
+func TestBridge_MetadataRoundTrip(t *testing.T) {
+	conn := runServer(t)
+
+	producer := goevent.New()
+	New(producer, conn).Publish("user.created")
+
+	consumer := goevent.New()
+	recorder := newTestRecorder("user.created")
+	consumer.RegisterListener(recorder)
+	bridge := New(consumer, conn)
+	defer bridge.Close()
+	if _, err := bridge.Subscribe("user.>"); err != nil {
+		t.Fatalf("Subscribe() failed: %v", err)
+	}
+	conn.Flush()
+
+	producer.Dispatch(&goevent.GenericEvent{EventName: "user.created"},
+		goevent.WithMetadata(map[string]string{goevent.MetadataCorrelationID: "c-1", goevent.MetadataTenant: "acme", "trace": "t-1"}))
+	recorder.waitCalls(t, 1)
+
+	md := recorder.Metadata()[0]
+	if md.CorrelationID != "c-1" || md.Tenant != "acme" || md.Header("trace") != "t-1" {
+		t.Errorf("Expected the sent metadata, got %+v", md)
+	}
+}
@@ -0,0 +1,48 @@
+package goevent
+
+import "errors"
+
+// ErrNoListeners is recorded on a dispatch that requires listeners when
+// no listener is registered for its event
+var ErrNoListeners = errors.New("goevent: no listeners for event")
+
+// WithStrictListeners makes every dispatch require listeners, as if
+// dispatched with RequireListeners. Use it where an event nobody
+// observes indicates a wiring bug, such as a typo in an event name.
+func WithStrictListeners() Option {
+	return func(ge *GoEvent) {
+		ge.requireListeners = true
+	}
+}
+
+// RequireListeners fails the dispatch with ErrNoListeners if the event
+// has no listener to deliver to. Paused subscriptions, listeners left
+// out by OnlyListeners or ExcludeListeners, and AnyListeners do not
+// count.
+func RequireListeners() DispatchOption {
+	return func(c *dispatchConfig) {
+		c.requireListeners = true
+	}
+}
+
+// checkListeners records ErrNoListeners on a dispatch that requires
+// listeners and has none. It reports whether the dispatch was rejected.
+// The subscribers it found are kept on the handle, so publish delivers
+// to the ones that were checked.
+func (ge *GoEvent) checkListeners(handle *DispatchHandle, event Event, cfg dispatchConfig) bool {
+	if !ge.requireListeners && !cfg.requireListeners {
+		return false
+	}
+
+	handle.subs, handle.subsAsync = ge.ownSubscribers(handle, event.Name())
+	if len(handle.subs) > 0 {
+		return false
+	}
+
+	handle.orphan.Store(true)
+	eventError := handle.newError(event.Name(), ErrNoListeners)
+	handle.recordError(eventError)
+	ge.recordError(eventError)
+	handle.markDone()
+	return true
+}
@@ -0,0 +1,96 @@
+package goevent
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestRequireListeners(t *testing.T) {
+	evt := New()
+
+	handle := evt.Dispatch(&TestEvent{})
+	if errs := handle.GetErrors(); len(errs) != 0 {
+		t.Errorf("Expected unobserved events to be a no-op by default, got %v", errs)
+	}
+
+	err := evt.DispatchE(&TestEvent{}, RequireListeners())
+	var eventErr *EventError
+	if !errors.As(err, &eventErr) || eventErr.Err != ErrNoListeners {
+		t.Errorf("Expected ErrNoListeners, got %v", err)
+	}
+
+	evt.RegisterListener(&testSyncListener{})
+	if err := evt.DispatchE(&TestEvent{}, RequireListeners()); err != nil {
+		t.Errorf("Expected no error once a listener is registered, got %v", err)
+	}
+}
+
+func TestWithStrictListeners(t *testing.T) {
+	evt := New(WithStrictListeners())
+
+	handle := evt.Dispatch(&testMutationEvent{})
+	handle.Wait()
+
+	errs := handle.GetErrors()
+	if len(errs) != 1 || errs[0].Err != ErrNoListeners || errs[0].EventName != "test.mutation" {
+		t.Errorf("Expected ErrNoListeners for test.mutation, got %v", errs)
+	}
+	if len(evt.GetErrors()) != 1 {
+		t.Errorf("Expected the error to be recorded globally, got %d", len(evt.GetErrors()))
+	}
+}
+
+func TestDispatchHandle_NoListeners(t *testing.T) {
+	evt := New()
+	if !evt.Dispatch(&TestEvent{}).NoListeners() {
+		t.Error("Expected an unobserved dispatch to report no listeners")
+	}
+	if !evt.Dispatch(&TestEvent{}, RequireListeners()).NoListeners() {
+		t.Error("Expected a rejected dispatch to report no listeners")
+	}
+
+	evt.RegisterListener(&testSyncListener{})
+	if evt.Dispatch(&TestEvent{}).NoListeners() {
+		t.Error("Expected an observed dispatch not to report no listeners")
+	}
+	if !evt.Dispatch(&TestEvent{}, WithExcludeListeners("*goevent.testSyncListener")).NoListeners() {
+		t.Error("Expected a dispatch skipping every listener to report no listeners")
+	}
+}
+
+func TestRequireListeners_SameSubscribersAsPublish(t *testing.T) {
+	evt := New()
+	anyListener := &testAnyListener{}
+	evt.RegisterAnyListener(anyListener)
+
+	handle := evt.Dispatch(&TestEvent{}, RequireListeners())
+	if errs := handle.GetErrors(); len(errs) != 1 || errs[0].Err != ErrNoListeners {
+		t.Errorf("Expected AnyListeners not to count, got %v", errs)
+	}
+	if !evt.Dispatch(&TestEvent{}).NoListeners() {
+		t.Error("Expected a dispatch reaching only AnyListeners to report no listeners")
+	}
+
+	sub, err := evt.Subscribe(&testSyncListener{})
+	if err != nil {
+		t.Fatalf("Subscribe() failed: %v", err)
+	}
+	sub.Pause()
+	if err := evt.DispatchE(&TestEvent{}, RequireListeners()); !errors.Is(err, ErrNoListeners) {
+		t.Errorf("Expected a paused subscription not to count, got %v", err)
+	}
+	sub.Resume()
+	if err := evt.DispatchE(&TestEvent{}, RequireListeners(), WithExcludeListeners("*goevent.testSyncListener")); !errors.Is(err, ErrNoListeners) {
+		t.Errorf("Expected an excluded listener not to count, got %v", err)
+	}
+	if err := evt.DispatchE(&TestEvent{}, RequireListeners()); err != nil {
+		t.Errorf("Expected no error with a listener to deliver to, got %v", err)
+	}
+}
+
+func TestEventError_WithoutListener(t *testing.T) {
+	err := &EventError{EventName: "test.event", Err: ErrNoListeners}
+	if got, want := err.Error(), "event 'test.event': "+ErrNoListeners.Error(); got != want {
+		t.Errorf("Expected %q, got %q", want, got)
+	}
+}
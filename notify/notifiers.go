@@ -0,0 +1,101 @@
+package notify
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/smtp"
+	"strings"
+)
+
+// Slack posts messages to a Slack incoming webhook
+type Slack struct {
+	WebhookURL string
+	Client     *http.Client // defaults to http.DefaultClient
+}
+
+// Notify posts the message text
+func (s *Slack) Notify(ctx context.Context, message Message) error {
+	return postJSON(ctx, s.Client, s.WebhookURL, nil, map[string]any{"text": message.Text})
+}
+
+// Webhook posts messages as JSON to an HTTP endpoint. The body has the
+// fields "event", "subject", "text" and "payload".
+type Webhook struct {
+	URL    string
+	Header http.Header
+	Client *http.Client // defaults to http.DefaultClient
+}
+
+// Notify posts the message with the event payload
+func (w *Webhook) Notify(ctx context.Context, message Message) error {
+	return postJSON(ctx, w.Client, w.URL, w.Header, map[string]any{
+		"event":   message.Event.Name(),
+		"subject": message.Subject,
+		"text":    message.Text,
+		"payload": message.Event.Payload(),
+	})
+}
+
+func postJSON(ctx context.Context, client *http.Client, url string, header http.Header, body any) error {
+	data, err := json.Marshal(body)
+	if err != nil {
+		return err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewReader(data))
+	if err != nil {
+		return err
+	}
+	for name, values := range header {
+		req.Header[name] = values
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	if client == nil {
+		client = http.DefaultClient
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return err
+	}
+	resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return fmt.Errorf("notify: POST %s: %s", url, resp.Status)
+	}
+	return nil
+}
+
+// Email sends messages through an SMTP server
+type Email struct {
+	Addr string // host:port of the SMTP server
+	Auth smtp.Auth
+	From string
+	To   []string
+
+	send func(addr string, auth smtp.Auth, from string, to []string, msg []byte) error
+}
+
+// Notify sends the message as a plain-text email
+func (e *Email) Notify(ctx context.Context, message Message) error {
+	var msg strings.Builder
+	fmt.Fprintf(&msg, "From: %s\r\n", e.From)
+	fmt.Fprintf(&msg, "To: %s\r\n", strings.Join(e.To, ", "))
+	fmt.Fprintf(&msg, "Subject: %s\r\n", headerValue(message.Subject))
+	msg.WriteString("Content-Type: text/plain; charset=utf-8\r\n\r\n")
+	msg.WriteString(strings.ReplaceAll(message.Text, "\n", "\r\n"))
+
+	send := e.send
+	if send == nil {
+		send = smtp.SendMail
+	}
+	return send(e.Addr, e.Auth, e.From, e.To, []byte(msg.String()))
+}
+
+// headerValue keeps rendered subjects on a single header line
+func headerValue(value string) string {
+	return strings.NewReplacer("\r", " ", "\n", " ").Replace(value)
+}
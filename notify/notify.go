@@ -0,0 +1,157 @@
+// Package notify provides ready-made listeners that send notifications
+// for matching events to Slack, webhooks or email.
+//
+// Messages are rendered with the render package, so templates see the
+// event name, payload and metadata and can use its functions:
+//
+//	listener, err := notify.New("order.failed",
+//		&notify.Slack{WebhookURL: os.Getenv("SLACK_WEBHOOK")},
+//		notify.WithText("Order {{.Payload.id}} failed: {{.Payload.reason}}"),
+//		notify.WithRateLimit(goevent.RateLimit{MaxPerSecond: 1, Policy: goevent.RateLimitDrop}),
+//	)
+//	bus.RegisterListener(listener)
+package notify
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/openframebox/goevent"
+	"github.com/openframebox/goevent/render"
+)
+
+const defaultText = "{{.Name}}: {{.Payload}}"
+
+// Message is a rendered notification
+type Message struct {
+	Subject string
+	Text    string
+	Event   goevent.Event
+}
+
+// Notifier delivers a rendered message
+type Notifier interface {
+	Notify(ctx context.Context, message Message) error
+}
+
+// Option configures a notification listener
+type Option func(*Listener) error
+
+// WithText sets the message template
+func WithText(text string) Option {
+	return func(l *Listener) (err error) {
+		l.text, err = render.Text(text)
+		return err
+	}
+}
+
+// WithSubject sets the subject template, used by email notifications
+func WithSubject(subject string) Option {
+	return func(l *Listener) (err error) {
+		l.subject, err = render.Text(subject)
+		return err
+	}
+}
+
+// WithFilter only notifies for events the filter accepts
+func WithFilter(filter func(event goevent.Event) bool) Option {
+	return func(l *Listener) error {
+		l.filter = filter
+		return nil
+	}
+}
+
+// WithRateLimit limits how often notifications are sent
+func WithRateLimit(limit goevent.RateLimit) Option {
+	return func(l *Listener) error {
+		l.options.RateLimit = limit
+		return nil
+	}
+}
+
+// WithRetry retries failed notifications
+func WithRetry(policy goevent.RetryPolicy) Option {
+	return func(l *Listener) error {
+		l.options.Retry = policy
+		return nil
+	}
+}
+
+// Sync sends notifications on the dispatching goroutine instead of
+// asynchronously
+func Sync() Option {
+	return func(l *Listener) error {
+		l.options.Async = false
+		return nil
+	}
+}
+
+// Listener renders matching events and passes them to a Notifier
+type Listener struct {
+	eventName string
+	notifier  Notifier
+	text      *render.Template
+	subject   *render.Template
+	filter    func(event goevent.Event) bool
+	options   goevent.ListenerOptions
+}
+
+// New creates an async listener that notifies for events named
+// eventName. It fails if a template does not parse.
+func New(eventName string, notifier Notifier, opts ...Option) (*Listener, error) {
+	l := &Listener{
+		eventName: eventName,
+		notifier:  notifier,
+		text:      render.MustText(defaultText),
+		subject:   render.MustText("{{.Name}}"),
+		options:   goevent.ListenerOptions{Async: true},
+	}
+	for _, opt := range opts {
+		if err := opt(l); err != nil {
+			return nil, fmt.Errorf("notify: %w", err)
+		}
+	}
+	return l, nil
+}
+
+// EventName returns the event the listener notifies for
+func (l *Listener) EventName() string {
+	return l.eventName
+}
+
+// Options returns the listener options
+func (l *Listener) Options() goevent.ListenerOptions {
+	return l.options
+}
+
+// OnEvent notifies without a context
+func (l *Listener) OnEvent(event goevent.Event) error {
+	return l.OnEventContext(context.Background(), event)
+}
+
+// OnEventContext renders the event and sends the notification
+func (l *Listener) OnEventContext(ctx context.Context, event goevent.Event) error {
+	if l.filter != nil && !l.filter(event) {
+		return nil
+	}
+
+	message, err := l.render(event)
+	if err != nil {
+		return err
+	}
+	return l.notifier.Notify(ctx, message)
+}
+
+func (l *Listener) render(event goevent.Event) (Message, error) {
+	data := render.DataFor(event)
+
+	subject, err := l.subject.RenderData(data)
+	if err != nil {
+		return Message{}, fmt.Errorf("notify: rendering subject: %w", err)
+	}
+	text, err := l.text.RenderData(data)
+	if err != nil {
+		return Message{}, fmt.Errorf("notify: rendering text: %w", err)
+	}
+	return Message{Subject: subject, Text: text, Event: event}, nil
+}
@@ -0,0 +1,116 @@
+package notify
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"net/smtp"
+	"strings"
+	"testing"
+
+	"github.com/openframebox/goevent"
+)
+
+func orderFailed(id int) goevent.Event {
+	return &goevent.GenericEvent{EventName: "order.failed", Data: map[string]any{"id": id, "reason": "card declined"}}
+}
+
+func TestSlack_PostsRenderedText(t *testing.T) {
+	var body map[string]any
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		json.NewDecoder(r.Body).Decode(&body)
+	}))
+	defer server.Close()
+
+	listener, err := New("order.failed", &Slack{WebhookURL: server.URL},
+		WithText("Order {{.Payload.id}} failed: {{.Payload.reason}}"))
+	if err != nil {
+		t.Fatalf("New() failed: %v", err)
+	}
+
+	bus := goevent.New()
+	bus.RegisterListener(listener)
+	handle := bus.Dispatch(orderFailed(42))
+	handle.Wait()
+
+	if errs := handle.GetErrors(); len(errs) > 0 {
+		t.Fatalf("Expected no errors, got %v", errs)
+	}
+	if body["text"] != "Order 42 failed: card declined" {
+		t.Errorf("Expected rendered text, got %v", body["text"])
+	}
+}
+
+func TestWebhook_ReportsHTTPErrors(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusBadGateway)
+	}))
+	defer server.Close()
+
+	listener, _ := New("order.failed", &Webhook{URL: server.URL})
+
+	bus := goevent.New()
+	bus.RegisterListener(listener)
+	handle := bus.Dispatch(orderFailed(1))
+	handle.Wait()
+
+	if len(handle.GetErrors()) != 1 {
+		t.Errorf("Expected 1 error, got %d", len(handle.GetErrors()))
+	}
+}
+
+func TestEmail_SendsSubjectAndText(t *testing.T) {
+	var sent string
+	email := &Email{Addr: "smtp.example.com:25", From: "bus@example.com", To: []string{"ops@example.com"}}
+	email.send = func(addr string, auth smtp.Auth, from string, to []string, msg []byte) error {
+		sent = string(msg)
+		return nil
+	}
+
+	listener, err := New("order.failed", email,
+		WithSubject("Order {{.Payload.id}} failed"),
+		WithText("Reason: {{.Payload.reason}}"),
+		Sync())
+	if err != nil {
+		t.Fatalf("New() failed: %v", err)
+	}
+
+	bus := goevent.New()
+	bus.RegisterListener(listener)
+	bus.Dispatch(orderFailed(7))
+
+	if !strings.Contains(sent, "Subject: Order 7 failed\r\n") {
+		t.Errorf("Expected subject header, got %q", sent)
+	}
+	if !strings.HasSuffix(sent, "Reason: card declined") {
+		t.Errorf("Expected rendered text, got %q", sent)
+	}
+}
+
+func TestListener_Filter(t *testing.T) {
+	var calls int
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls++
+	}))
+	defer server.Close()
+
+	listener, _ := New("order.failed", &Slack{WebhookURL: server.URL}, Sync(),
+		WithFilter(func(event goevent.Event) bool {
+			return event.Payload()["id"].(int) > 10
+		}))
+
+	bus := goevent.New()
+	bus.RegisterListener(listener)
+	bus.Dispatch(orderFailed(5))
+	bus.Dispatch(orderFailed(50))
+
+	if calls != 1 {
+		t.Errorf("Expected 1 notification, got %d", calls)
+	}
+}
+
+func TestNew_InvalidTemplate(t *testing.T) {
+	if _, err := New("order.failed", &Slack{}, WithText("{{.Payload")); err == nil {
+		t.Error("Expected an error for an invalid template")
+	}
+}
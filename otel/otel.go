@@ -0,0 +1,159 @@
+// Package otel adds OpenTelemetry tracing to a goevent bus.
+//
+// Dispatches made through Tracer.Dispatch run inside a "publish <event>"
+// span, and the middleware wraps every listener call in a
+// "process <event>" span. Listener spans are children of the dispatch
+// span, including those of async listeners, because the dispatch context
+// travels with the event to every listener.
+//
+// The trace context is also injected into the dispatch's metadata
+// headers, so it travels with the event through transports that carry
+// metadata. The middleware extracts it when a received event is
+// dispatched without a span of its own, which continues the trace on
+// the other side.
+//
+//	tracer := otel.New()
+//	bus.Use(tracer.Middleware())
+//	handle := tracer.Dispatch(ctx, bus, &UserCreated{})
+package otel
+
+import (
+	"context"
+	"fmt"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/trace"
+
+	"github.com/openframebox/goevent"
+)
+
+const instrumentationName = "github.com/openframebox/goevent/otel"
+
+// Attribute keys set on spans
+const (
+	EventNameKey  = attribute.Key("goevent.event")
+	ListenerKey   = attribute.Key("goevent.listener")
+	PriorityKey   = attribute.Key("goevent.priority")
+	ErrorCountKey = attribute.Key("goevent.errors")
+	ShedKey       = attribute.Key("goevent.shed")
+)
+
+// Option configures a Tracer
+type Option func(*Tracer)
+
+// WithTracerProvider sets the provider spans are created from.
+// Defaults to the global provider.
+func WithTracerProvider(provider trace.TracerProvider) Option {
+	return func(t *Tracer) {
+		t.provider = provider
+	}
+}
+
+// WithPropagator sets how trace context is written to and read from
+// metadata headers. Defaults to the global propagator.
+func WithPropagator(propagator propagation.TextMapPropagator) Option {
+	return func(t *Tracer) {
+		t.propagator = propagator
+	}
+}
+
+// Tracer creates spans for dispatches and listener calls
+type Tracer struct {
+	provider   trace.TracerProvider
+	propagator propagation.TextMapPropagator
+	tracer     trace.Tracer
+}
+
+// New creates a Tracer
+func New(opts ...Option) *Tracer {
+	t := &Tracer{}
+	for _, opt := range opts {
+		opt(t)
+	}
+	if t.provider == nil {
+		t.provider = otel.GetTracerProvider()
+	}
+	if t.propagator == nil {
+		t.propagator = otel.GetTextMapPropagator()
+	}
+	t.tracer = t.provider.Tracer(instrumentationName)
+	return t
+}
+
+// Dispatch dispatches event inside a span that ends when the dispatch
+// completes, including its async listeners. The span's trace context is
+// added to the dispatch's metadata headers. The span reads the handle
+// once it is done, so the handle must not be released. Errors recorded
+// on the span count as read for goevent.WithAbandonedHandleWarnings.
+func (t *Tracer) Dispatch(ctx context.Context, bus *goevent.GoEvent, event goevent.Event, opts ...goevent.DispatchOption) *goevent.DispatchHandle {
+	ctx, span := t.tracer.Start(ctx, "publish "+event.Name(),
+		trace.WithSpanKind(trace.SpanKindProducer),
+		trace.WithAttributes(EventNameKey.String(event.Name())),
+	)
+
+	carrier := propagation.MapCarrier{}
+	t.propagator.Inject(ctx, carrier)
+	if len(carrier) > 0 {
+		opts = append([]goevent.DispatchOption{goevent.WithMetadata(carrier)}, opts...)
+	}
+
+	handle := bus.DispatchContext(ctx, event, opts...)
+	span.SetAttributes(PriorityKey.Int(int(handle.Priority())))
+
+	go func() {
+		<-handle.Done()
+		errs := handle.GetErrors()
+		span.SetAttributes(ErrorCountKey.Int(len(errs)))
+		if handle.Shed() {
+			span.SetAttributes(ShedKey.Bool(true))
+		}
+		if len(errs) > 0 {
+			span.SetStatus(codes.Error, errs[0].Error())
+		}
+		span.End()
+	}()
+	return handle
+}
+
+// Middleware returns middleware that wraps every listener call in a span.
+// Retried calls get one span per attempt. When the dispatch has no span,
+// as for events received from a transport, the span continues the trace
+// found in the dispatch's metadata headers, if any.
+func (t *Tracer) Middleware() goevent.Middleware {
+	return func(next goevent.HandlerFunc) goevent.HandlerFunc {
+		return func(ctx context.Context, event goevent.Event) (err error) {
+			attrs := []attribute.KeyValue{EventNameKey.String(event.Name())}
+			if listener, ok := goevent.ListenerFromContext(ctx); ok {
+				attrs = append(attrs, ListenerKey.String(fmt.Sprintf("%T", listener)))
+			}
+
+			if !trace.SpanContextFromContext(ctx).IsValid() {
+				if md, ok := goevent.MetadataFromContext(ctx); ok && len(md.Headers) > 0 {
+					ctx = t.propagator.Extract(ctx, propagation.MapCarrier(md.Headers))
+				}
+			}
+
+			ctx, span := t.tracer.Start(ctx, "process "+event.Name(),
+				trace.WithSpanKind(trace.SpanKindConsumer),
+				trace.WithAttributes(attrs...),
+			)
+			defer func() {
+				if r := recover(); r != nil {
+					span.SetStatus(codes.Error, fmt.Sprintf("panic: %v", r))
+					span.End()
+					panic(r)
+				}
+				if err != nil {
+					span.RecordError(err)
+					span.SetStatus(codes.Error, err.Error())
+				}
+				span.End()
+			}()
+
+			return next(ctx, event)
+		}
+	}
+}
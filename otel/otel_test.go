@@ -0,0 +1,129 @@
+package otel
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/propagation"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+
+	"github.com/openframebox/goevent"
+)
+
+type listener struct {
+	async bool
+	err   error
+}
+
+func (l *listener) EventName() string {
+	return "user.created"
+}
+
+func (l *listener) OnEvent(event goevent.Event) error {
+	return l.err
+}
+
+func (l *listener) Options() goevent.ListenerOptions {
+	return goevent.ListenerOptions{Async: l.async}
+}
+
+func newTracer() (*Tracer, *tracetest.SpanRecorder) {
+	recorder := tracetest.NewSpanRecorder()
+	provider := sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder))
+	return New(WithTracerProvider(provider)), recorder
+}
+
+func waitForSpan(t *testing.T, recorder *tracetest.SpanRecorder, name string) sdktrace.ReadOnlySpan {
+	t.Helper()
+	deadline := time.Now().Add(time.Second)
+	for time.Now().Before(deadline) {
+		for _, span := range recorder.Ended() {
+			if span.Name() == name {
+				return span
+			}
+		}
+		time.Sleep(time.Millisecond)
+	}
+	t.Fatalf("Span %q never ended", name)
+	return nil
+}
+
+func TestTracer_ListenerSpansAreChildrenOfDispatch(t *testing.T) {
+	tracer, recorder := newTracer()
+	bus := goevent.New()
+	bus.Use(tracer.Middleware())
+	bus.RegisterListener(&listener{}, &listener{async: true})
+
+	<-tracer.Dispatch(context.Background(), bus, &goevent.GenericEvent{EventName: "user.created"}).Done()
+	bus.Wait()
+
+	// The dispatch span ends asynchronously after Done
+	publish := waitForSpan(t, recorder, "publish user.created")
+	spans := recorder.Ended()
+	processed := 0
+	for _, span := range spans {
+		if span.Name() != "process user.created" {
+			continue
+		}
+		processed++
+		if span.Parent().SpanID() != publish.SpanContext().SpanID() {
+			t.Errorf("Expected listener span to be a child of the dispatch span")
+		}
+		if span.SpanContext().TraceID() != publish.SpanContext().TraceID() {
+			t.Errorf("Expected listener span to share the dispatch trace")
+		}
+	}
+	if processed != 2 {
+		t.Errorf("Expected 2 listener spans, got %d", processed)
+	}
+}
+
+func TestTracer_RecordsListenerErrors(t *testing.T) {
+	tracer, recorder := newTracer()
+	bus := goevent.New()
+	bus.Use(tracer.Middleware())
+	bus.RegisterListener(&listener{err: errors.New("boom")})
+
+	bus.Dispatch(&goevent.GenericEvent{EventName: "user.created"}).Wait()
+
+	spans := recorder.Ended()
+	if len(spans) != 1 {
+		t.Fatalf("Expected 1 span, got %d", len(spans))
+	}
+	if spans[0].Status().Code != codes.Error || spans[0].Status().Description != "boom" {
+		t.Errorf("Expected error status 'boom', got %v", spans[0].Status())
+	}
+}
+
+func TestTracer_PropagatesThroughMetadata(t *testing.T) {
+	recorder := tracetest.NewSpanRecorder()
+	provider := sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder))
+	tracer := New(WithTracerProvider(provider), WithPropagator(propagation.TraceContext{}))
+
+	producer := goevent.New()
+	handle := tracer.Dispatch(context.Background(), producer, &goevent.GenericEvent{EventName: "user.created"})
+	handle.Wait()
+	md := handle.Metadata()
+	if md.Header("traceparent") == "" {
+		t.Fatalf("Expected the trace context in the metadata headers, got %v", md.Headers)
+	}
+
+	// Dispatch the event on another bus as a transport would
+	consumer := goevent.New()
+	consumer.Use(tracer.Middleware())
+	consumer.RegisterListener(&listener{})
+	consumer.Dispatch(&goevent.GenericEvent{EventName: "user.created"}, goevent.WithMetadata(md.Fields())).Wait()
+
+	publish := waitForSpan(t, recorder, "publish user.created")
+	process := waitForSpan(t, recorder, "process user.created")
+	if process.SpanContext().TraceID() != publish.SpanContext().TraceID() {
+		t.Error("Expected the received event's span to continue the dispatch trace")
+	}
+	if process.Parent().SpanID() != publish.SpanContext().SpanID() || !process.Parent().IsRemote() {
+		t.Error("Expected the received event's span to be a child of the remote dispatch span")
+	}
+}
@@ -0,0 +1,70 @@
+package goevent
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+type testPanicListener struct {
+	async bool
+	retry RetryPolicy
+}
+
+func (l *testPanicListener) EventName() string {
+	return "test.event"
+}
+
+func (l *testPanicListener) OnEvent(event Event) error {
+	panic("listener exploded")
+}
+
+func (l *testPanicListener) Options() ListenerOptions {
+	return ListenerOptions{Async: l.async, Retry: l.retry}
+}
+
+func TestPanicRecovery_Sync(t *testing.T) {
+	evt := New()
+	after := &testSyncListener{}
+	evt.RegisterListener(&testPanicListener{}, after)
+
+	handle := evt.Dispatch(&TestEvent{data: "panic"})
+
+	if !after.called {
+		t.Error("Listener registered after the panicking one was not called")
+	}
+
+	errs := handle.GetErrors()
+	if len(errs) != 1 {
+		t.Fatalf("Expected 1 error, got %d", len(errs))
+	}
+
+	panicErr, ok := errs[0].Err.(*PanicError)
+	if !ok {
+		t.Fatalf("Expected *PanicError, got %T", errs[0].Err)
+	}
+	if panicErr.Value != "listener exploded" {
+		t.Errorf("Expected panic value 'listener exploded', got '%v'", panicErr.Value)
+	}
+	if !strings.Contains(string(panicErr.Stack), "testPanicListener") {
+		t.Error("Expected stack trace to reference the panicking listener")
+	}
+}
+
+func TestPanicRecovery_AsyncMarksHandleDone(t *testing.T) {
+	evt := New()
+	evt.RegisterListener(&testPanicListener{async: true})
+
+	handle := evt.Dispatch(&TestEvent{data: "async panic"})
+
+	select {
+	case <-handle.Done():
+	case <-time.After(time.Second):
+		t.Fatal("Handle was not marked done after async panic")
+	}
+
+	if len(handle.GetErrors()) != 1 {
+		t.Errorf("Expected 1 error, got %d", len(handle.GetErrors()))
+	}
+	evt.Wait()
+}
@@ -0,0 +1,75 @@
+package goevent
+
+import "errors"
+
+// ErrPaused is wrapped by the error recorded on a dispatch rejected
+// because the bus or its event is paused, see WithRejectWhilePaused
+var ErrPaused = errors.New("goevent: paused")
+
+// WithRejectWhilePaused makes dispatches of paused events fail with an
+// EventError wrapping ErrRejected and ErrPaused, instead of being held
+// until Resume
+func WithRejectWhilePaused() Option {
+	return func(ge *GoEvent) {
+		ge.gate.mu.Lock()
+		defer ge.gate.mu.Unlock()
+		ge.gate.update(func(s *gateState) { s.rejectPaused = true })
+	}
+}
+
+// Pause holds every dispatch until Resume, for migrations, maintenance
+// windows, or tests that need to freeze side effects. Held dispatches
+// are counted by DeferredCount, and their handles stay open until they
+// are delivered.
+func (ge *GoEvent) Pause() {
+	ge.gate.mu.Lock()
+	defer ge.gate.mu.Unlock()
+	ge.gate.update(func(s *gateState) { s.paused = true })
+}
+
+// Resume lifts Pause and dispatches the held events that are not
+// otherwise paused or gated, in their original order. Events paused with
+// PauseEvents stay paused.
+func (ge *GoEvent) Resume() {
+	ge.gate.mu.Lock()
+	ge.gate.update(func(s *gateState) { s.paused = false })
+	ge.gate.mu.Unlock()
+
+	ge.ReleaseDeferred()
+}
+
+// PauseEvents holds dispatches of the named events like Pause, until
+// they are resumed with ResumeEvents
+func (ge *GoEvent) PauseEvents(eventNames ...string) {
+	ge.gate.mu.Lock()
+	defer ge.gate.mu.Unlock()
+	ge.gate.update(func(s *gateState) {
+		if s.pausedEvents == nil {
+			s.pausedEvents = make(map[string]bool)
+		}
+		for _, eventName := range eventNames {
+			s.pausedEvents[eventName] = true
+		}
+	})
+}
+
+// ResumeEvents resumes the named events and dispatches their held events
+// unless the whole bus is paused
+func (ge *GoEvent) ResumeEvents(eventNames ...string) {
+	ge.gate.mu.Lock()
+	ge.gate.update(func(s *gateState) {
+		for _, eventName := range eventNames {
+			delete(s.pausedEvents, eventName)
+		}
+	})
+	ge.gate.mu.Unlock()
+
+	ge.ReleaseDeferred()
+}
+
+// Paused reports whether dispatches of eventName are paused, by Pause or
+// PauseEvents
+func (ge *GoEvent) Paused(eventName string) bool {
+	state := ge.gate.state.Load()
+	return state != nil && (state.paused || state.pausedEvents[eventName])
+}
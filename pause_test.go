@@ -0,0 +1,76 @@
+package goevent
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestPause_HoldsUntilResume(t *testing.T) {
+	evt := New()
+	reads := &testCountingListener{}
+	evt.RegisterListener(reads)
+
+	evt.Pause()
+	if !evt.Paused("test.event") {
+		t.Error("Expected the bus to be paused")
+	}
+	handle := evt.Dispatch(&TestEvent{})
+	evt.Dispatch(&TestEvent{})
+	if reads.Count() != 0 || evt.DeferredCount() != 2 {
+		t.Fatalf("Expected 2 held dispatches, got %d delivered, %d held", reads.Count(), evt.DeferredCount())
+	}
+	select {
+	case <-handle.Done():
+		t.Error("Expected the held dispatch's handle to stay open")
+	default:
+	}
+
+	evt.Resume()
+	handle.Wait()
+	if reads.Count() != 2 || evt.DeferredCount() != 0 {
+		t.Errorf("Expected both dispatches after Resume, got %d", reads.Count())
+	}
+}
+
+func TestPauseEvents(t *testing.T) {
+	evt := New()
+	reads := &testCountingListener{}
+	mutations := &testMutationListener{}
+	evt.RegisterListener(reads, mutations)
+
+	evt.PauseEvents("test.mutation")
+	evt.Dispatch(&TestEvent{})
+	evt.Dispatch(&testMutationEvent{})
+	if reads.Count() != 1 || mutations.Count() != 0 {
+		t.Fatal("Expected only the paused event to be held")
+	}
+
+	// Resuming the bus leaves paused events paused
+	evt.Pause()
+	evt.Resume()
+	if mutations.Count() != 0 {
+		t.Fatal("Expected the event to stay paused after Resume")
+	}
+
+	evt.ResumeEvents("test.mutation")
+	if mutations.Count() != 1 || evt.Paused("test.mutation") {
+		t.Error("Expected the held event after ResumeEvents")
+	}
+}
+
+func TestPause_Reject(t *testing.T) {
+	evt := New(WithRejectWhilePaused())
+	reads := &testCountingListener{}
+	evt.RegisterListener(reads)
+
+	evt.Pause()
+	err := evt.Dispatch(&TestEvent{}).Err()
+	if !errors.Is(err, ErrPaused) || !errors.Is(err, ErrRejected) {
+		t.Errorf("Expected a rejection wrapping ErrPaused, got %v", err)
+	}
+
+	evt.Resume()
+	if reads.Count() != 0 || evt.DeferredCount() != 0 {
+		t.Error("Expected the rejected dispatch not to be delivered")
+	}
+}
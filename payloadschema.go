@@ -0,0 +1,99 @@
+package goevent
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sync"
+
+	"github.com/santhosh-tekuri/jsonschema/v5"
+)
+
+// ErrSchemaViolation wraps the validation error recorded on a dispatch
+// whose payload does not match the schema registered for its event
+var ErrSchemaViolation = errors.New("goevent: payload violates schema")
+
+type schemas struct {
+	mu     sync.RWMutex
+	byName map[string]*jsonschema.Schema
+	strict bool
+}
+
+// WithStrictSchemas rejects dispatches whose payload violates the schema
+// registered for their event, instead of only recording the violation
+func WithStrictSchemas() Option {
+	return func(ge *GoEvent) {
+		ge.schemas.strict = true
+	}
+}
+
+// RegisterSchema validates the payloads of events with the given name
+// against a JSON Schema document at dispatch time. A violation records
+// an EventError wrapping ErrSchemaViolation; the event is still delivered
+// unless the bus was created WithStrictSchemas. Registering a name again
+// replaces its schema.
+func (ge *GoEvent) RegisterSchema(eventName string, schemaJSON []byte) error {
+	url := "goevent:///" + eventName + ".json"
+	compiler := jsonschema.NewCompiler()
+	compiler.ExtractAnnotations = true // examples feed Synthesizer
+	if err := compiler.AddResource(url, bytes.NewReader(schemaJSON)); err != nil {
+		return fmt.Errorf("goevent: schema for %s: %w", eventName, err)
+	}
+	schema, err := compiler.Compile(url)
+	if err != nil {
+		return fmt.Errorf("goevent: schema for %s: %w", eventName, err)
+	}
+
+	ge.schemas.mu.Lock()
+	defer ge.schemas.mu.Unlock()
+	if ge.schemas.byName == nil {
+		ge.schemas.byName = make(map[string]*jsonschema.Schema)
+	}
+	ge.schemas.byName[eventName] = schema
+	return nil
+}
+
+// checkSchema validates the payload against the schema registered for
+// the event, if any. It reports whether the dispatch was rejected.
+func (ge *GoEvent) checkSchema(handle *DispatchHandle, event Event) bool {
+	ge.schemas.mu.RLock()
+	schema := ge.schemas.byName[event.Name()]
+	strict := ge.schemas.strict
+	ge.schemas.mu.RUnlock()
+	if schema == nil {
+		return false
+	}
+
+	err := validatePayload(schema, event.Payload())
+	if err == nil {
+		return false
+	}
+
+	eventError := handle.newError(event.Name(), fmt.Errorf("%w: %w", ErrSchemaViolation, err))
+	handle.recordError(eventError)
+	ge.recordError(eventError)
+	if strict {
+		handle.markDone()
+	}
+	return strict
+}
+
+// validatePayload validates the JSON form of payload, so typed values
+// such as structs and times are checked as consumers will see them
+func validatePayload(schema *jsonschema.Schema, payload map[string]any) error {
+	if payload == nil {
+		payload = map[string]any{}
+	}
+	data, err := json.Marshal(payload)
+	if err != nil {
+		return err
+	}
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.UseNumber()
+	var doc any
+	if err := decoder.Decode(&doc); err != nil {
+		return err
+	}
+	return schema.Validate(doc)
+}
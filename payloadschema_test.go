@@ -0,0 +1,70 @@
+package goevent
+
+import (
+	"errors"
+	"testing"
+)
+
+const testUserSchema = `{
+	"type": "object",
+	"required": ["email"],
+	"properties": {
+		"email": {"type": "string", "minLength": 3},
+		"age": {"type": "integer", "minimum": 0}
+	}
+}`
+
+func userEvent(payload map[string]any) Event {
+	return &GenericEvent{EventName: "test.event", Data: payload}
+}
+
+func TestRegisterSchema_RecordsViolations(t *testing.T) {
+	evt := New()
+	listener := &testCountingListener{}
+	evt.RegisterListener(listener)
+	if err := evt.RegisterSchema("test.event", []byte(testUserSchema)); err != nil {
+		t.Fatalf("RegisterSchema() failed: %v", err)
+	}
+
+	handle := evt.Dispatch(userEvent(map[string]any{"email": "a@example.com", "age": 30}))
+	if errs := handle.GetErrors(); len(errs) != 0 {
+		t.Errorf("Expected valid payload to pass, got %v", errs)
+	}
+
+	handle = evt.Dispatch(userEvent(map[string]any{"age": -1}))
+	errs := handle.GetErrors()
+	if len(errs) != 1 || !errors.Is(errs[0].Err, ErrSchemaViolation) {
+		t.Errorf("Expected a schema violation, got %v", errs)
+	}
+	if listener.Count() != 2 {
+		t.Errorf("Expected both events to be delivered, got %d", listener.Count())
+	}
+}
+
+func TestRegisterSchema_Strict(t *testing.T) {
+	evt := New(WithStrictSchemas())
+	listener := &testCountingListener{}
+	evt.RegisterListener(listener)
+	if err := evt.RegisterSchema("test.event", []byte(testUserSchema)); err != nil {
+		t.Fatalf("RegisterSchema() failed: %v", err)
+	}
+
+	err := evt.DispatchE(userEvent(map[string]any{"email": 42}))
+	var eventErr *EventError
+	if !errors.As(err, &eventErr) || !errors.Is(eventErr.Err, ErrSchemaViolation) {
+		t.Errorf("Expected a schema violation, got %v", err)
+	}
+	if listener.Count() != 0 {
+		t.Errorf("Expected the event to be rejected, got %d deliveries", listener.Count())
+	}
+}
+
+func TestRegisterSchema_Invalid(t *testing.T) {
+	evt := New()
+	if err := evt.RegisterSchema("test.event", []byte(`{"type": 5}`)); err == nil {
+		t.Error("Expected an error for an invalid schema")
+	}
+	if err := evt.RegisterSchema("test.event", []byte(`{`)); err == nil {
+		t.Error("Expected an error for malformed JSON")
+	}
+}
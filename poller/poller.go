@@ -0,0 +1,339 @@
+// Package poller turns an HTTP endpoint into a source of events.
+//
+// A Poller fetches the endpoint on an interval, follows pagination,
+// compares the returned items with those seen by the previous poll and
+// dispatches a *goevent.GenericEvent for every new item. The keys seen
+// are saved to a Checkpoint after the dispatches complete, so a restarted
+// poller picks up where it left off.
+//
+//	p := poller.New(bus, poller.Options{
+//		URL:        "https://api.example.com/orders",
+//		EventName:  "order.created",
+//		Interval:   30 * time.Second,
+//		Checkpoint: poller.NewFileCheckpoint("orders.json"),
+//	})
+//	go p.Run(ctx)
+package poller
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"math/rand"
+	"net/http"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"github.com/openframebox/goevent"
+)
+
+// Page is one page of results returned by the endpoint
+type Page struct {
+	Items []map[string]any
+	Next  string // URL of the next page, empty on the last page
+}
+
+// DecodeFunc parses a response into a page
+type DecodeFunc func(resp *http.Response) (Page, error)
+
+// DecodeJSON accepts either a JSON array of objects or an object with
+// "items" and an optional "next" URL
+func DecodeJSON(resp *http.Response) (Page, error) {
+	data, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return Page{}, err
+	}
+
+	var items []map[string]any
+	if err := json.Unmarshal(data, &items); err == nil {
+		return Page{Items: items}, nil
+	}
+
+	var page struct {
+		Items []map[string]any `json:"items"`
+		Next  string           `json:"next"`
+	}
+	if err := json.Unmarshal(data, &page); err != nil {
+		return Page{}, err
+	}
+	return Page{Items: page.Items, Next: page.Next}, nil
+}
+
+// Checkpoint persists the item keys seen by the last poll
+type Checkpoint interface {
+	Load(ctx context.Context) ([]string, error)
+	Save(ctx context.Context, keys []string) error
+}
+
+// Options configures a Poller
+type Options struct {
+	// URL of the first page
+	URL string
+
+	// EventName is the name of the events dispatched for new items
+	EventName string
+
+	// Interval between polls. Zero polls again as soon as the previous
+	// poll finishes, which suits long-polling endpoints.
+	Interval time.Duration
+
+	// Key identifies an item. Defaults to the item's "id" field.
+	Key func(item map[string]any) string
+
+	// Decode parses each response. Defaults to DecodeJSON.
+	Decode DecodeFunc
+
+	// Client sends the requests. Defaults to http.DefaultClient.
+	Client *http.Client
+
+	// Header is added to every request
+	Header http.Header
+
+	// Retry retries failing requests within a poll
+	Retry goevent.RetryPolicy
+
+	// Checkpoint stores the keys seen between polls and restarts.
+	// Defaults to an in-memory checkpoint.
+	Checkpoint Checkpoint
+
+	// SkipInitial records the items returned by the first poll without
+	// dispatching them when there is no checkpoint yet
+	SkipInitial bool
+
+	// OnError is called with errors from polls made by Run
+	OnError func(err error)
+}
+
+// Poller dispatches events for new items returned by an HTTP endpoint
+type Poller struct {
+	bus  *goevent.GoEvent
+	opts Options
+
+	mu     sync.Mutex
+	seen   map[string]bool
+	loaded bool
+}
+
+// New creates a poller that dispatches to bus
+func New(bus *goevent.GoEvent, opts Options) *Poller {
+	if opts.Key == nil {
+		opts.Key = func(item map[string]any) string {
+			return fmt.Sprint(item["id"])
+		}
+	}
+	if opts.Decode == nil {
+		opts.Decode = DecodeJSON
+	}
+	if opts.Client == nil {
+		opts.Client = http.DefaultClient
+	}
+	if opts.Checkpoint == nil {
+		opts.Checkpoint = &MemoryCheckpoint{}
+	}
+	return &Poller{bus: bus, opts: opts}
+}
+
+// Run polls until ctx is done. Errors from individual polls are passed
+// to Options.OnError and do not stop the poller.
+func (p *Poller) Run(ctx context.Context) error {
+	for {
+		if _, err := p.Poll(ctx); err != nil && ctx.Err() == nil && p.opts.OnError != nil {
+			p.opts.OnError(err)
+		}
+
+		timer := time.NewTimer(p.opts.Interval)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return ctx.Err()
+		case <-timer.C:
+		}
+	}
+}
+
+// Poll fetches every page once, dispatches events for new items and
+// waits for them before saving the checkpoint. It returns the number of
+// events dispatched.
+func (p *Poller) Poll(ctx context.Context) (int, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	initial := false
+	if !p.loaded {
+		keys, err := p.opts.Checkpoint.Load(ctx)
+		if err != nil {
+			return 0, fmt.Errorf("poller: loading checkpoint: %w", err)
+		}
+		p.seen = make(map[string]bool, len(keys))
+		for _, key := range keys {
+			p.seen[key] = true
+		}
+		p.loaded = true
+		initial = len(keys) == 0
+	}
+
+	items, err := p.fetchAll(ctx)
+	if err != nil {
+		return 0, err
+	}
+
+	keys := make([]string, 0, len(items))
+	var handles []*goevent.DispatchHandle
+	for _, item := range items {
+		key := p.opts.Key(item)
+		keys = append(keys, key)
+		if p.seen[key] || (initial && p.opts.SkipInitial) {
+			continue
+		}
+		event := &goevent.GenericEvent{EventName: p.opts.EventName, Data: item}
+		handles = append(handles, p.bus.DispatchContext(ctx, event))
+	}
+	for _, handle := range handles {
+		handle.Wait()
+	}
+
+	if err := p.opts.Checkpoint.Save(ctx, keys); err != nil {
+		return len(handles), fmt.Errorf("poller: saving checkpoint: %w", err)
+	}
+	p.seen = make(map[string]bool, len(keys))
+	for _, key := range keys {
+		p.seen[key] = true
+	}
+	return len(handles), nil
+}
+
+// fetchAll follows pagination from the first page to the last
+func (p *Poller) fetchAll(ctx context.Context) ([]map[string]any, error) {
+	var items []map[string]any
+	for url := p.opts.URL; url != ""; {
+		page, err := p.fetchWithRetry(ctx, url)
+		if err != nil {
+			return nil, err
+		}
+		items = append(items, page.Items...)
+		url = page.Next
+	}
+	return items, nil
+}
+
+func (p *Poller) fetchWithRetry(ctx context.Context, url string) (Page, error) {
+	policy := p.opts.Retry
+	for attempt := 1; ; attempt++ {
+		page, err := p.fetch(ctx, url)
+		if err == nil || attempt >= policy.MaxAttempts {
+			return page, err
+		}
+
+		timer := time.NewTimer(backoff(policy, attempt))
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return Page{}, errors.Join(err, ctx.Err())
+		case <-timer.C:
+		}
+	}
+}
+
+func (p *Poller) fetch(ctx context.Context, url string) (Page, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return Page{}, err
+	}
+	for name, values := range p.opts.Header {
+		req.Header[name] = values
+	}
+
+	resp, err := p.opts.Client.Do(req)
+	if err != nil {
+		return Page{}, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return Page{}, fmt.Errorf("poller: GET %s: %s", url, resp.Status)
+	}
+	return p.opts.Decode(resp)
+}
+
+// backoff mirrors the jittered delay goevent uses between listener retries
+func backoff(policy goevent.RetryPolicy, attempt int) time.Duration {
+	if policy.Backoff == nil {
+		return 0
+	}
+	delay := policy.Backoff(attempt)
+	if policy.Jitter > 0 {
+		delay += time.Duration(float64(delay) * policy.Jitter * (rand.Float64()*2 - 1))
+	}
+	return delay
+}
+
+// MemoryCheckpoint keeps the checkpoint in memory
+type MemoryCheckpoint struct {
+	mu   sync.Mutex
+	keys []string
+}
+
+// Load returns the saved keys
+func (c *MemoryCheckpoint) Load(ctx context.Context) ([]string, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return append([]string(nil), c.keys...), nil
+}
+
+// Save replaces the saved keys
+func (c *MemoryCheckpoint) Save(ctx context.Context, keys []string) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.keys = append([]string(nil), keys...)
+	return nil
+}
+
+// FileCheckpoint keeps the checkpoint in a JSON file
+type FileCheckpoint struct {
+	path string
+}
+
+// NewFileCheckpoint stores the checkpoint at path
+func NewFileCheckpoint(path string) *FileCheckpoint {
+	return &FileCheckpoint{path: path}
+}
+
+// Load returns the saved keys, or none if the file does not exist yet
+func (c *FileCheckpoint) Load(ctx context.Context) ([]string, error) {
+	data, err := os.ReadFile(c.path)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	var keys []string
+	return keys, json.Unmarshal(data, &keys)
+}
+
+// Save atomically replaces the file
+func (c *FileCheckpoint) Save(ctx context.Context, keys []string) error {
+	data, err := json.Marshal(keys)
+	if err != nil {
+		return err
+	}
+
+	tmp, err := os.CreateTemp(filepath.Dir(c.path), filepath.Base(c.path)+".*.tmp")
+	if err != nil {
+		return err
+	}
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		os.Remove(tmp.Name())
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		os.Remove(tmp.Name())
+		return err
+	}
+	return os.Rename(tmp.Name(), c.path)
+}
@@ -0,0 +1,164 @@
+package poller
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"sync"
+	"sync/atomic"
+	"testing"
+
+	"github.com/openframebox/goevent"
+)
+
+type recorder struct {
+	mu  sync.Mutex
+	ids []any
+}
+
+func (r *recorder) EventName() string {
+	return "order.created"
+}
+
+func (r *recorder) OnEvent(event goevent.Event) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.ids = append(r.ids, event.Payload()["id"])
+	return nil
+}
+
+func (r *recorder) count() int {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return len(r.ids)
+}
+
+// orders serves the current body under /orders
+type orders struct {
+	mu   sync.Mutex
+	body string
+}
+
+func (o *orders) set(body string) {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	o.body = body
+}
+
+func (o *orders) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	fmt.Fprint(w, o.body)
+}
+
+func TestPoll_DispatchesNewItems(t *testing.T) {
+	source := &orders{body: `[{"id":1},{"id":2}]`}
+	server := httptest.NewServer(source)
+	defer server.Close()
+
+	bus := goevent.New()
+	listener := &recorder{}
+	bus.RegisterListener(listener)
+	p := New(bus, Options{URL: server.URL, EventName: "order.created"})
+
+	if n, err := p.Poll(context.Background()); err != nil || n != 2 {
+		t.Fatalf("Expected 2 events from first poll, got %d (err %v)", n, err)
+	}
+
+	source.set(`[{"id":2},{"id":3}]`)
+	if n, err := p.Poll(context.Background()); err != nil || n != 1 {
+		t.Fatalf("Expected 1 event from second poll, got %d (err %v)", n, err)
+	}
+	if listener.count() != 3 {
+		t.Errorf("Expected 3 deliveries, got %d", listener.count())
+	}
+}
+
+func TestPoll_FollowsPagination(t *testing.T) {
+	mux := http.NewServeMux()
+	server := httptest.NewServer(mux)
+	defer server.Close()
+	mux.HandleFunc("/page1", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprintf(w, `{"items":[{"id":1}],"next":"%s/page2"}`, server.URL)
+	})
+	mux.HandleFunc("/page2", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `{"items":[{"id":2}]}`)
+	})
+
+	bus := goevent.New()
+	p := New(bus, Options{URL: server.URL + "/page1", EventName: "order.created"})
+
+	if n, err := p.Poll(context.Background()); err != nil || n != 2 {
+		t.Errorf("Expected 2 events across pages, got %d (err %v)", n, err)
+	}
+}
+
+func TestPoll_RetriesFailedRequests(t *testing.T) {
+	var calls atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if calls.Add(1) < 3 {
+			http.Error(w, "unavailable", http.StatusServiceUnavailable)
+			return
+		}
+		fmt.Fprint(w, `[{"id":1}]`)
+	}))
+	defer server.Close()
+
+	bus := goevent.New()
+	p := New(bus, Options{
+		URL:       server.URL,
+		EventName: "order.created",
+		Retry:     goevent.RetryPolicy{MaxAttempts: 3},
+	})
+
+	if n, err := p.Poll(context.Background()); err != nil || n != 1 {
+		t.Errorf("Expected 1 event after retries, got %d (err %v)", n, err)
+	}
+	if calls.Load() != 3 {
+		t.Errorf("Expected 3 requests, got %d", calls.Load())
+	}
+}
+
+func TestPoll_ResumesFromCheckpoint(t *testing.T) {
+	source := &orders{body: `[{"id":1},{"id":2}]`}
+	server := httptest.NewServer(source)
+	defer server.Close()
+
+	path := filepath.Join(t.TempDir(), "checkpoint.json")
+	bus := goevent.New()
+	New(bus, Options{URL: server.URL, EventName: "order.created", Checkpoint: NewFileCheckpoint(path)}).
+		Poll(context.Background())
+
+	// A new poller, as after a restart, only sees the new item
+	source.set(`[{"id":1},{"id":2},{"id":3}]`)
+	listener := &recorder{}
+	bus = goevent.New()
+	bus.RegisterListener(listener)
+	p := New(bus, Options{URL: server.URL, EventName: "order.created", Checkpoint: NewFileCheckpoint(path)})
+
+	if n, err := p.Poll(context.Background()); err != nil || n != 1 {
+		t.Fatalf("Expected 1 event after restart, got %d (err %v)", n, err)
+	}
+	if listener.ids[0] != float64(3) {
+		t.Errorf("Expected item 3, got %v", listener.ids[0])
+	}
+}
+
+func TestPoll_SkipInitial(t *testing.T) {
+	source := &orders{body: `[{"id":1}]`}
+	server := httptest.NewServer(source)
+	defer server.Close()
+
+	bus := goevent.New()
+	p := New(bus, Options{URL: server.URL, EventName: "order.created", SkipInitial: true})
+
+	if n, _ := p.Poll(context.Background()); n != 0 {
+		t.Errorf("Expected first poll to dispatch nothing, got %d", n)
+	}
+	source.set(`[{"id":1},{"id":2}]`)
+	if n, _ := p.Poll(context.Background()); n != 1 {
+		t.Errorf("Expected 1 event from second poll, got %d", n)
+	}
+}
@@ -0,0 +1,93 @@
+package goevent
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// progressInterval is how often WaitProgress reports
+const progressInterval = time.Second
+
+// Progress reports the async handlers a bus is still waiting for
+type Progress struct {
+	Remaining  int              // async handler calls still pending
+	ByEvent    map[string]int   // pending calls per event name
+	ByPriority map[Priority]int // pending calls per dispatch priority
+	Elapsed    time.Duration    // time since WaitProgress was called
+}
+
+// pendingHandlers counts the async handler calls that have not finished
+type pendingHandlers struct {
+	mu         sync.Mutex
+	byEvent    map[string]int
+	byPriority map[Priority]int
+}
+
+func (p *pendingHandlers) add(eventName string, priority Priority, n int) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if p.byEvent == nil {
+		p.byEvent = make(map[string]int)
+		p.byPriority = make(map[Priority]int)
+	}
+	p.byEvent[eventName] += n
+	p.byPriority[priority] += n
+	if p.byEvent[eventName] == 0 {
+		delete(p.byEvent, eventName)
+	}
+	if p.byPriority[priority] == 0 {
+		delete(p.byPriority, priority)
+	}
+}
+
+func (p *pendingHandlers) snapshot() Progress {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	progress := Progress{
+		ByEvent:    make(map[string]int, len(p.byEvent)),
+		ByPriority: make(map[Priority]int, len(p.byPriority)),
+	}
+	for name, n := range p.byEvent {
+		progress.ByEvent[name] = n
+		progress.Remaining += n
+	}
+	for priority, n := range p.byPriority {
+		progress.ByPriority[priority] = n
+	}
+	return progress
+}
+
+// WaitProgress blocks like Wait, calling fn with the handlers still
+// pending when it starts, every second while it waits, and once more
+// when everything completed. It returns ctx.Err() if ctx is done first.
+//
+// Pending digests and rate-limited deliveries are waited for but not
+// counted, as they are not running handlers yet.
+func (ge *GoEvent) WaitProgress(ctx context.Context, fn func(p Progress)) error {
+	done := make(chan struct{})
+	go func() {
+		ge.wg.Wait()
+		close(done)
+	}()
+
+	start := ge.clock.Now()
+	report := func() {
+		progress := ge.pending.snapshot()
+		progress.Elapsed = ge.clock.Now().Sub(start)
+		fn(progress)
+	}
+
+	report()
+	for {
+		select {
+		case <-done:
+			report()
+			return nil
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-ge.clock.After(progressInterval):
+			report()
+		}
+	}
+}
@@ -0,0 +1,66 @@
+package goevent
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestWaitProgress_Reports(t *testing.T) {
+	clock := newFakeClock(time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC))
+	evt := New(WithClock(clock))
+	listener := &testBlockingListener{release: make(chan struct{})}
+	evt.RegisterListener(listener)
+
+	evt.Dispatch(&TestEvent{})
+	evt.DispatchContext(context.Background(), &TestEvent{}, WithPriority(PriorityHigh))
+
+	reports := make(chan Progress, 8)
+	done := make(chan error)
+	go func() {
+		done <- evt.WaitProgress(context.Background(), func(p Progress) { reports <- p })
+	}()
+
+	first := <-reports
+	if first.Remaining != 2 || first.ByEvent["test.event"] != 2 {
+		t.Errorf("Expected 2 pending test.event handlers, got %+v", first)
+	}
+	if first.ByPriority[PriorityHigh] != 1 || first.ByPriority[PriorityNormal] != 1 {
+		t.Errorf("Expected one pending handler per priority, got %v", first.ByPriority)
+	}
+
+	clock.BlockUntil(t, 1)
+	clock.Advance(time.Second)
+	if p := <-reports; p.Remaining != 2 || p.Elapsed != time.Second {
+		t.Errorf("Expected a periodic report after 1s, got %+v", p)
+	}
+
+	close(listener.release)
+	if err := <-done; err != nil {
+		t.Fatalf("WaitProgress() failed: %v", err)
+	}
+	// Drain a periodic report that may have raced with completion
+	var last Progress
+	for len(reports) > 0 {
+		last = <-reports
+	}
+	if last.Remaining != 0 || len(last.ByEvent) != 0 {
+		t.Errorf("Expected a final report with nothing pending, got %+v", last)
+	}
+}
+
+func TestWaitProgress_Canceled(t *testing.T) {
+	evt := New()
+	listener := &testBlockingListener{release: make(chan struct{})}
+	evt.RegisterListener(listener)
+	defer close(listener.release)
+	evt.Dispatch(&TestEvent{})
+
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	err := evt.WaitProgress(ctx, func(Progress) {})
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("Expected context.DeadlineExceeded, got %v", err)
+	}
+}
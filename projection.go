@@ -0,0 +1,175 @@
+package goevent
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"sync"
+	"time"
+)
+
+// ErrNoStore is returned by operations that need a durable bus when the
+// bus has no Store
+var ErrNoStore = errors.New("goevent: bus has no store")
+
+// Projection builds a read model from the events of the store. It keeps
+// its own checkpoint, the sequence number of the last event it
+// processed, so it can persist the checkpoint together with the read
+// model.
+type Projection interface {
+	// ProjectionName names the projection in errors
+	ProjectionName() string
+	// EventNames returns the events the projection handles
+	EventNames() []string
+	// Project applies one stored event to the read model
+	Project(ctx context.Context, event StoredEvent) error
+	// Checkpoint returns the sequence number saved last, or zero
+	Checkpoint(ctx context.Context) (uint64, error)
+	// SaveCheckpoint records that every event up to seq was processed
+	SaveCheckpoint(ctx context.Context, seq uint64) error
+	// Reset clears the read model and its checkpoint before a rebuild
+	Reset(ctx context.Context) error
+}
+
+// ProjectionOptions configures a ProjectionRunner
+type ProjectionOptions struct {
+	// PageSize is how many events are read from the store at once, and
+	// how often the checkpoint is saved. Defaults to 256.
+	PageSize int
+
+	// PollInterval is how often Run checks the store for new events when
+	// no matching dispatch woke it. Defaults to one second.
+	PollInterval time.Duration
+}
+
+// ProjectionRunner feeds a Projection from the bus's store
+type ProjectionRunner struct {
+	ge         *GoEvent
+	projection Projection
+	opts       ProjectionOptions
+	wake       chan struct{}
+	mu         sync.Mutex // one catch-up at a time
+}
+
+// RegisterProjection returns a runner for projection. Dispatches of the
+// projection's events on the bus wake a running Run, so the read model
+// follows them closely.
+func (ge *GoEvent) RegisterProjection(projection Projection, opts ProjectionOptions) *ProjectionRunner {
+	if opts.PageSize <= 0 {
+		opts.PageSize = 256
+	}
+	if opts.PollInterval <= 0 {
+		opts.PollInterval = time.Second
+	}
+	r := &ProjectionRunner{ge: ge, projection: projection, opts: opts, wake: make(chan struct{}, 1)}
+	ge.RegisterListener(&projectionWaker{runner: r})
+	return r
+}
+
+// CatchUp projects the stored events after the projection's checkpoint
+// and returns how many it projected. Events after the last saved
+// checkpoint are projected again if the process stops, so Project should
+// be idempotent. On an error, the checkpoint stays before the failing
+// event.
+func (r *ProjectionRunner) CatchUp(ctx context.Context) (int, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	store := r.ge.store
+	if store == nil {
+		return 0, ErrNoStore
+	}
+	name := r.projection.ProjectionName()
+	checkpoint, err := r.projection.Checkpoint(ctx)
+	if err != nil {
+		return 0, fmt.Errorf("goevent: projection %s: reading checkpoint: %w", name, err)
+	}
+
+	eventNames := r.projection.EventNames()
+	projected := 0
+	for {
+		page, err := store.ReadFrom(ctx, checkpoint+1, r.opts.PageSize)
+		if err != nil {
+			return projected, err
+		}
+
+		next := checkpoint
+		for _, stored := range page {
+			if slices.Contains(eventNames, stored.Name) {
+				if err := r.projection.Project(ctx, stored); err != nil {
+					err = fmt.Errorf("goevent: projection %s: event %d: %w", name, stored.Seq, err)
+					return projected, errors.Join(err, r.save(ctx, checkpoint, next))
+				}
+				projected++
+			}
+			next = stored.Seq
+		}
+		if err := r.save(ctx, checkpoint, next); err != nil {
+			return projected, err
+		}
+		checkpoint = next
+
+		if len(page) < r.opts.PageSize {
+			return projected, nil
+		}
+	}
+}
+
+// save saves the checkpoint if it moved
+func (r *ProjectionRunner) save(ctx context.Context, from, to uint64) error {
+	if to == from {
+		return nil
+	}
+	if err := r.projection.SaveCheckpoint(ctx, to); err != nil {
+		return fmt.Errorf("goevent: projection %s: saving checkpoint: %w", r.projection.ProjectionName(), err)
+	}
+	return nil
+}
+
+// Rebuild resets the projection and projects the whole store again
+func (r *ProjectionRunner) Rebuild(ctx context.Context) (int, error) {
+	if err := r.projection.Reset(ctx); err != nil {
+		return 0, fmt.Errorf("goevent: projection %s: resetting: %w", r.projection.ProjectionName(), err)
+	}
+	return r.CatchUp(ctx)
+}
+
+// Run catches up, then keeps the projection current until ctx is done.
+// It returns ctx.Err(), or the first error of a catch-up.
+func (r *ProjectionRunner) Run(ctx context.Context) error {
+	for {
+		if _, err := r.CatchUp(ctx); err != nil {
+			return err
+		}
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-r.wake:
+		case <-r.ge.clock.After(r.opts.PollInterval):
+		}
+	}
+}
+
+// projectionWaker wakes a runner when one of its events is dispatched.
+// The store holds the event by then, as dispatches are persisted before
+// delivery.
+type projectionWaker struct {
+	runner *ProjectionRunner
+}
+
+func (w *projectionWaker) EventName() string {
+	return w.runner.projection.ProjectionName()
+}
+
+func (w *projectionWaker) EventNames() []string {
+	return w.runner.projection.EventNames()
+}
+
+func (w *projectionWaker) OnEvent(event Event) error {
+	select {
+	case w.runner.wake <- struct{}{}:
+	default:
+	}
+	return nil
+}
@@ -0,0 +1,148 @@
+package goevent
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+)
+
+// testOrderCount counts orders per status
+type testOrderCount struct {
+	failOn uint64
+
+	mu         sync.Mutex
+	counts     map[string]int
+	checkpoint uint64
+}
+
+func (p *testOrderCount) ProjectionName() string {
+	return "order_count"
+}
+
+func (p *testOrderCount) EventNames() []string {
+	return []string{"order.placed", "order.shipped"}
+}
+
+func (p *testOrderCount) Project(ctx context.Context, event StoredEvent) error {
+	if event.Seq == p.failOn {
+		return errors.New("projection failed")
+	}
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if p.counts == nil {
+		p.counts = make(map[string]int)
+	}
+	p.counts[event.Name]++
+	return nil
+}
+
+func (p *testOrderCount) Checkpoint(ctx context.Context) (uint64, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return p.checkpoint, nil
+}
+
+func (p *testOrderCount) SaveCheckpoint(ctx context.Context, seq uint64) error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.checkpoint = seq
+	return nil
+}
+
+func (p *testOrderCount) Reset(ctx context.Context) error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.counts, p.checkpoint = nil, 0
+	return nil
+}
+
+func (p *testOrderCount) count(status string) int {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return p.counts[status]
+}
+
+func dispatchOrders(evt *GoEvent, names ...string) {
+	for _, name := range names {
+		evt.Dispatch(&GenericEvent{EventName: name}).Wait()
+	}
+}
+
+func TestProjectionRunner_CatchUp(t *testing.T) {
+	ctx := context.Background()
+	evt := New(WithStore(NewMemoryStore()))
+	dispatchOrders(evt, "order.placed", "user.login", "order.placed", "order.shipped")
+
+	projection := &testOrderCount{}
+	runner := evt.RegisterProjection(projection, ProjectionOptions{PageSize: 2})
+	if n, err := runner.CatchUp(ctx); err != nil || n != 3 {
+		t.Fatalf("Expected 3 projected events, got %d, %v", n, err)
+	}
+	if projection.count("order.placed") != 2 || projection.count("order.shipped") != 1 || projection.checkpoint != 4 {
+		t.Errorf("Unexpected read model %v at checkpoint %d", projection.counts, projection.checkpoint)
+	}
+
+	// Only new events are projected from the checkpoint on
+	dispatchOrders(evt, "order.shipped", "user.login")
+	if n, err := runner.CatchUp(ctx); err != nil || n != 1 || projection.checkpoint != 6 {
+		t.Errorf("Expected 1 new event up to checkpoint 6, got %d at %d, %v", n, projection.checkpoint, err)
+	}
+
+	if n, err := runner.Rebuild(ctx); err != nil || n != 4 || projection.count("order.shipped") != 2 {
+		t.Errorf("Expected the rebuild to project 4 events, got %d, %v", n, err)
+	}
+}
+
+func TestProjectionRunner_StopsAtFailure(t *testing.T) {
+	evt := New(WithStore(NewMemoryStore()))
+	dispatchOrders(evt, "order.placed", "order.placed", "order.placed")
+
+	projection := &testOrderCount{failOn: 2}
+	runner := evt.RegisterProjection(projection, ProjectionOptions{})
+	if _, err := runner.CatchUp(context.Background()); err == nil {
+		t.Fatal("Expected the projection's error")
+	}
+	if projection.checkpoint != 1 {
+		t.Errorf("Expected the checkpoint before the failing event, got %d", projection.checkpoint)
+	}
+
+	projection.failOn = 0
+	if n, err := runner.CatchUp(context.Background()); err != nil || n != 2 {
+		t.Errorf("Expected the resumed catch-up to start at the failed event, got %d, %v", n, err)
+	}
+}
+
+func TestProjectionRunner_Run(t *testing.T) {
+	evt := New(WithStore(NewMemoryStore()))
+	projection := &testOrderCount{}
+	runner := evt.RegisterProjection(projection, ProjectionOptions{PollInterval: time.Hour})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error)
+	go func() {
+		done <- runner.Run(ctx)
+	}()
+
+	dispatchOrders(evt, "order.placed")
+	deadline := time.Now().Add(time.Second)
+	for projection.count("order.placed") != 1 && time.Now().Before(deadline) {
+		time.Sleep(5 * time.Millisecond)
+	}
+	if projection.count("order.placed") != 1 {
+		t.Error("Expected the dispatch to be projected without waiting for the poll interval")
+	}
+
+	cancel()
+	if err := <-done; !errors.Is(err, context.Canceled) {
+		t.Errorf("Expected context.Canceled, got %v", err)
+	}
+}
+
+func TestProjectionRunner_NoStore(t *testing.T) {
+	runner := New().RegisterProjection(&testOrderCount{}, ProjectionOptions{})
+	if _, err := runner.CatchUp(context.Background()); !errors.Is(err, ErrNoStore) {
+		t.Errorf("Expected ErrNoStore, got %v", err)
+	}
+}
@@ -0,0 +1,273 @@
+package protobuf
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"google.golang.org/protobuf/encoding/protowire"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/known/anypb"
+	"google.golang.org/protobuf/types/known/structpb"
+	"google.golang.org/protobuf/types/known/timestamppb"
+
+	"github.com/openframebox/goevent"
+)
+
+// Envelope field numbers. The wire format matches this message:
+//
+//	message Envelope {
+//	  string id = 1;
+//	  string name = 2;
+//	  google.protobuf.Timestamp time = 3;
+//	  map<string, string> headers = 4;
+//	  google.protobuf.Any payload = 5;
+//	  string correlation_id = 6;
+//	  string tenant = 7;
+//	  sint64 priority = 8;
+//	  google.protobuf.Timestamp deadline = 9;
+//	  string source = 10;
+//	  string causation_id = 11;
+//	}
+const (
+	fieldID            protowire.Number = 1
+	fieldName          protowire.Number = 2
+	fieldTime          protowire.Number = 3
+	fieldHeaders       protowire.Number = 4
+	fieldPayload       protowire.Number = 5
+	fieldCorrelationID protowire.Number = 6
+	fieldTenant        protowire.Number = 7
+	fieldPriority      protowire.Number = 8
+	fieldDeadline      protowire.Number = 9
+	fieldSource        protowire.Number = 10
+	fieldCausationID   protowire.Number = 11
+)
+
+// Codec encodes envelopes in the protobuf wire format. Messages of *Event
+// are packed into the payload as google.protobuf.Any; the payload maps
+// of other events are packed as google.protobuf.Struct.
+//
+// Decoded Any payloads become *Event if their message type is linked
+// into the program. Struct payloads are decoded with goevent.DecodeEvent.
+// In a goevent.WireCodec it goes by goevent.CodecProtobuf.
+type Codec struct{}
+
+// Marshal encodes env in the protobuf wire format
+func (Codec) Marshal(env goevent.Envelope) ([]byte, error) {
+	if env.Event == nil {
+		return nil, errors.New("protobuf: marshal: envelope has no event")
+	}
+	name := env.Name
+	if name == "" {
+		name = env.Event.Name()
+	}
+
+	payload, err := packPayload(env.Event)
+	if err != nil {
+		return nil, fmt.Errorf("protobuf: marshal %s: %w", name, err)
+	}
+	payloadBytes, err := proto.Marshal(payload)
+	if err != nil {
+		return nil, fmt.Errorf("protobuf: marshal %s: %w", name, err)
+	}
+
+	var b []byte
+	b = appendString(b, fieldID, env.ID)
+	b = appendString(b, fieldName, name)
+	if b, err = appendTime(b, fieldTime, env.Time); err != nil {
+		return nil, fmt.Errorf("protobuf: marshal %s: %w", name, err)
+	}
+	for key, value := range env.Headers {
+		var entry []byte
+		entry = appendString(entry, 1, key)
+		entry = appendString(entry, 2, value)
+		b = protowire.AppendTag(b, fieldHeaders, protowire.BytesType)
+		b = protowire.AppendBytes(b, entry)
+	}
+	b = protowire.AppendTag(b, fieldPayload, protowire.BytesType)
+	b = protowire.AppendBytes(b, payloadBytes)
+	b = appendString(b, fieldCorrelationID, env.CorrelationID)
+	b = appendString(b, fieldTenant, env.Tenant)
+	if env.Priority != 0 {
+		b = protowire.AppendTag(b, fieldPriority, protowire.VarintType)
+		b = protowire.AppendVarint(b, protowire.EncodeZigZag(int64(env.Priority)))
+	}
+	if b, err = appendTime(b, fieldDeadline, env.Deadline); err != nil {
+		return nil, fmt.Errorf("protobuf: marshal %s: %w", name, err)
+	}
+	b = appendString(b, fieldSource, env.Source)
+	b = appendString(b, fieldCausationID, env.CausationID)
+	return b, nil
+}
+
+// Unmarshal decodes an envelope in the protobuf wire format
+func (Codec) Unmarshal(data []byte) (goevent.Envelope, error) {
+	var env goevent.Envelope
+	var payload *anypb.Any
+
+	for len(data) > 0 {
+		num, typ, n := protowire.ConsumeTag(data)
+		if n < 0 {
+			return goevent.Envelope{}, fmt.Errorf("protobuf: unmarshal: %w", protowire.ParseError(n))
+		}
+		data = data[n:]
+
+		if num == fieldPriority && typ == protowire.VarintType {
+			v, n := protowire.ConsumeVarint(data)
+			if n < 0 {
+				return goevent.Envelope{}, fmt.Errorf("protobuf: unmarshal: %w", protowire.ParseError(n))
+			}
+			data = data[n:]
+			env.Priority = goevent.Priority(protowire.DecodeZigZag(v))
+			continue
+		}
+		if typ != protowire.BytesType || num < fieldID || num > fieldCausationID || num == fieldPriority {
+			n = protowire.ConsumeFieldValue(num, typ, data)
+			if n < 0 {
+				return goevent.Envelope{}, fmt.Errorf("protobuf: unmarshal: %w", protowire.ParseError(n))
+			}
+			data = data[n:]
+			continue
+		}
+
+		value, n := protowire.ConsumeBytes(data)
+		if n < 0 {
+			return goevent.Envelope{}, fmt.Errorf("protobuf: unmarshal: %w", protowire.ParseError(n))
+		}
+		data = data[n:]
+
+		var err error
+		switch num {
+		case fieldID:
+			env.ID = string(value)
+		case fieldName:
+			env.Name = string(value)
+		case fieldTime:
+			env.Time, err = parseTime(value)
+		case fieldDeadline:
+			env.Deadline, err = parseTime(value)
+		case fieldCorrelationID:
+			env.CorrelationID = string(value)
+		case fieldTenant:
+			env.Tenant = string(value)
+		case fieldSource:
+			env.Source = string(value)
+		case fieldCausationID:
+			env.CausationID = string(value)
+		case fieldHeaders:
+			var key, val string
+			if key, val, err = parseMapEntry(value); err == nil {
+				env.SetHeader(key, val)
+			}
+		case fieldPayload:
+			payload = &anypb.Any{}
+			err = proto.Unmarshal(value, payload)
+		}
+		if err != nil {
+			return goevent.Envelope{}, fmt.Errorf("protobuf: unmarshal: %w", err)
+		}
+	}
+
+	if env.Name == "" {
+		return goevent.Envelope{}, errors.New("protobuf: unmarshal: envelope has no event name")
+	}
+	event, err := unpackPayload(env.Name, payload)
+	if err != nil {
+		return goevent.Envelope{}, fmt.Errorf("protobuf: unmarshal %s: %w", env.Name, err)
+	}
+	env.Event = event
+	return env, nil
+}
+
+// packPayload wraps the payload of event in an Any
+func packPayload(event goevent.Event) (*anypb.Any, error) {
+	if pe, ok := event.(*Event); ok && pe.Message != nil {
+		return anypb.New(pe.Message)
+	}
+	payload, err := structpb.NewStruct(event.Payload())
+	if err != nil {
+		return nil, err
+	}
+	return anypb.New(payload)
+}
+
+// unpackPayload reconstructs the event carried by an Any
+func unpackPayload(name string, payload *anypb.Any) (goevent.Event, error) {
+	if payload == nil {
+		return goevent.DecodeEvent(name, nil)
+	}
+	if payload.MessageIs((*structpb.Struct)(nil)) {
+		var s structpb.Struct
+		if err := payload.UnmarshalTo(&s); err != nil {
+			return nil, err
+		}
+		return goevent.DecodeEvent(name, s.AsMap())
+	}
+
+	msg, err := payload.UnmarshalNew()
+	if err != nil {
+		return nil, err
+	}
+	return &Event{EventName: name, Message: msg}, nil
+}
+
+// parseMapEntry decodes a map<string, string> entry
+func parseMapEntry(data []byte) (key, value string, err error) {
+	for len(data) > 0 {
+		num, typ, n := protowire.ConsumeTag(data)
+		if n < 0 {
+			return "", "", protowire.ParseError(n)
+		}
+		data = data[n:]
+		if typ != protowire.BytesType {
+			if n = protowire.ConsumeFieldValue(num, typ, data); n < 0 {
+				return "", "", protowire.ParseError(n)
+			}
+			data = data[n:]
+			continue
+		}
+
+		v, n := protowire.ConsumeBytes(data)
+		if n < 0 {
+			return "", "", protowire.ParseError(n)
+		}
+		data = data[n:]
+		switch num {
+		case 1:
+			key = string(v)
+		case 2:
+			value = string(v)
+		}
+	}
+	return key, value, nil
+}
+
+// appendTime appends t as a google.protobuf.Timestamp, unless it is zero
+func appendTime(b []byte, num protowire.Number, t time.Time) ([]byte, error) {
+	if t.IsZero() {
+		return b, nil
+	}
+	data, err := proto.Marshal(timestamppb.New(t))
+	if err != nil {
+		return nil, err
+	}
+	b = protowire.AppendTag(b, num, protowire.BytesType)
+	return protowire.AppendBytes(b, data), nil
+}
+
+// parseTime decodes a google.protobuf.Timestamp
+func parseTime(data []byte) (time.Time, error) {
+	var ts timestamppb.Timestamp
+	if err := proto.Unmarshal(data, &ts); err != nil {
+		return time.Time{}, err
+	}
+	return ts.AsTime(), nil
+}
+
+func appendString(b []byte, num protowire.Number, s string) []byte {
+	if s == "" {
+		return b
+	}
+	b = protowire.AppendTag(b, num, protowire.BytesType)
+	return protowire.AppendString(b, s)
+}
@@ -0,0 +1,90 @@
+// Package protobuf lets events carry protocol buffer messages as their
+// payload and encodes envelopes in the protobuf wire format.
+//
+// Listeners receive the message itself through *Event, so it never has
+// to be converted into a map. Payload still offers the map form, as
+// protojson with proto field names, for stores and listeners that
+// expect one.
+//
+//	bus.Dispatch(&protobuf.Event{EventName: "user.created", Message: &pb.UserCreated{Id: 42}})
+//
+//	func (l *Welcome) OnEvent(event goevent.Event) error {
+//		msg := event.(*protobuf.Event).Message.(*pb.UserCreated)
+//		...
+//	}
+package protobuf
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/proto"
+
+	"github.com/openframebox/goevent"
+)
+
+var (
+	jsonMarshal   = protojson.MarshalOptions{UseProtoNames: true}
+	jsonUnmarshal = protojson.UnmarshalOptions{DiscardUnknown: true}
+)
+
+// Event is an event whose payload is a protobuf message
+type Event struct {
+	EventName string
+	Message   proto.Message
+}
+
+// Name returns the event name
+func (e *Event) Name() string {
+	return e.EventName
+}
+
+// Payload returns the message as a map, converted through protojson.
+// Messages whose JSON form is not an object, such as wrappers, are
+// returned under "value". It returns nil if the message cannot be converted.
+func (e *Event) Payload() map[string]any {
+	if e.Message == nil {
+		return nil
+	}
+	data, err := jsonMarshal.Marshal(e.Message)
+	if err != nil {
+		return nil
+	}
+
+	var value any
+	if err := json.Unmarshal(data, &value); err != nil {
+		return nil
+	}
+	if payload, ok := value.(map[string]any); ok {
+		return payload
+	}
+	return map[string]any{"value": value}
+}
+
+// UnmarshalPayload restores the message from its map form, so events
+// registered with Register can be read back from stores as *Event
+func (e *Event) UnmarshalPayload(payload map[string]any) error {
+	if e.Message == nil {
+		return fmt.Errorf("protobuf: %s: no message to unmarshal into", e.EventName)
+	}
+
+	var value any = payload
+	if inner, ok := payload["value"]; ok && len(payload) == 1 {
+		value = inner
+	}
+	data, err := json.Marshal(value)
+	if err != nil {
+		return err
+	}
+	return jsonUnmarshal.Unmarshal(data, e.Message)
+}
+
+// Register registers the events with the given name as *Event carrying
+// a message of the same type as msg, with goevent.RegisterEventType
+func Register(name string, msg proto.Message) {
+	messageType := msg.ProtoReflect().Type()
+	goevent.RegisterEventType(name, func() goevent.Event {
+		return &Event{EventName: name, Message: messageType.New().Interface()}
+	})
+}
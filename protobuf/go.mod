@@ -0,0 +1,10 @@
+module github.com/openframebox/goevent/protobuf
+
+go 1.21
+
+require (
+	github.com/openframebox/goevent v0.1.0
+	google.golang.org/protobuf v1.34.2
+)
+
+require github.com/santhosh-tekuri/jsonschema/v5 v5.3.1 // indirect
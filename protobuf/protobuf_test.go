@@ -0,0 +1,154 @@
+package protobuf
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/known/apipb"
+	"google.golang.org/protobuf/types/known/wrapperspb"
+
+	"github.com/openframebox/goevent"
+)
+
+func TestEvent_Payload(t *testing.T) {
+	event := &Event{EventName: "api.published", Message: &apipb.Api{Name: "billing", Version: "v2"}}
+
+	payload := event.Payload()
+	if payload["name"] != "billing" || payload["version"] != "v2" {
+		t.Errorf("Expected name and version in payload, got %v", payload)
+	}
+
+	restored := &Event{EventName: "api.published", Message: &apipb.Api{}}
+	if err := restored.UnmarshalPayload(payload); err != nil {
+		t.Fatalf("UnmarshalPayload() failed: %v", err)
+	}
+	if !proto.Equal(restored.Message, event.Message) {
+		t.Errorf("Expected %v, got %v", event.Message, restored.Message)
+	}
+}
+
+func TestEvent_ScalarPayload(t *testing.T) {
+	event := &Event{EventName: "greeting", Message: wrapperspb.String("hello")}
+
+	payload := event.Payload()
+	if payload["value"] != "hello" {
+		t.Errorf("Expected value hello, got %v", payload)
+	}
+
+	restored := &Event{EventName: "greeting", Message: &wrapperspb.StringValue{}}
+	if err := restored.UnmarshalPayload(payload); err != nil {
+		t.Fatalf("UnmarshalPayload() failed: %v", err)
+	}
+	if restored.Message.(*wrapperspb.StringValue).GetValue() != "hello" {
+		t.Errorf("Expected hello, got %v", restored.Message)
+	}
+}
+
+func TestCodec_ProtoMessage(t *testing.T) {
+	env := goevent.NewEnvelope(&Event{EventName: "api.published", Message: &apipb.Api{Name: "billing"}})
+	env.Tenant = "acme"
+	env.Source = "checkout"
+	env.CausationID = "evt-0"
+	env.Priority = goevent.PriorityLow
+	env.Deadline = env.Time.Add(time.Minute)
+	env.SetHeader("source", "registry")
+	env.SetHeader("region", "eu")
+
+	var codec Codec
+	data, err := codec.Marshal(env)
+	if err != nil {
+		t.Fatalf("Marshal() failed: %v", err)
+	}
+	decoded, err := codec.Unmarshal(data)
+	if err != nil {
+		t.Fatalf("Unmarshal() failed: %v", err)
+	}
+
+	if decoded.ID != env.ID || decoded.Name != "api.published" || !decoded.Time.Equal(env.Time) {
+		t.Errorf("Expected %s %s %v, got %s %s %v", env.ID, env.Name, env.Time, decoded.ID, decoded.Name, decoded.Time)
+	}
+	if decoded.Tenant != "acme" || decoded.Source != "checkout" || decoded.CausationID != "evt-0" || decoded.Priority != goevent.PriorityLow || !decoded.Deadline.Equal(env.Deadline) {
+		t.Errorf("Expected metadata to survive, got %+v", decoded.Metadata)
+	}
+	if len(decoded.Headers) != 2 || decoded.Header("region") != "eu" {
+		t.Errorf("Expected headers to survive, got %v", decoded.Headers)
+	}
+	event, ok := decoded.Event.(*Event)
+	if !ok {
+		t.Fatalf("Expected *Event, got %T", decoded.Event)
+	}
+	if !proto.Equal(event.Message, &apipb.Api{Name: "billing"}) {
+		t.Errorf("Expected the original message, got %v", event.Message)
+	}
+}
+
+func TestCodec_MapPayload(t *testing.T) {
+	env := goevent.NewEnvelope(&goevent.GenericEvent{EventName: "user.created", Data: map[string]any{"id": 42, "email": "a@example.com"}})
+
+	var codec Codec
+	data, err := codec.Marshal(env)
+	if err != nil {
+		t.Fatalf("Marshal() failed: %v", err)
+	}
+	decoded, err := codec.Unmarshal(data)
+	if err != nil {
+		t.Fatalf("Unmarshal() failed: %v", err)
+	}
+
+	payload := decoded.Event.Payload()
+	if payload["id"] != float64(42) || payload["email"] != "a@example.com" {
+		t.Errorf("Expected id and email in payload, got %v", payload)
+	}
+}
+
+func TestCodec_Invalid(t *testing.T) {
+	var codec Codec
+	if _, err := codec.Unmarshal([]byte{0xff}); err == nil {
+		t.Error("Expected an error for truncated input")
+	}
+	if _, err := codec.Unmarshal(nil); err == nil {
+		t.Error("Expected an error for an envelope without a name")
+	}
+}
+
+type testAPIListener struct {
+	received []*apipb.Api
+}
+
+func (l *testAPIListener) EventName() string {
+	return "api.stored"
+}
+
+func (l *testAPIListener) OnEvent(event goevent.Event) error {
+	l.received = append(l.received, event.(*Event).Message.(*apipb.Api))
+	return nil
+}
+
+func TestRegister_Redeliver(t *testing.T) {
+	Register("api.stored", &apipb.Api{})
+
+	store := goevent.NewMemoryStore()
+	if _, err := store.Append(context.Background(), &Event{EventName: "api.stored", Message: &apipb.Api{Name: "billing"}}); err != nil {
+		t.Fatalf("Append() failed: %v", err)
+	}
+
+	bus := goevent.New(goevent.WithStore(store))
+	listener := &testAPIListener{}
+	bus.RegisterListener(listener)
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+	handles, err := bus.Redeliver(ctx)
+	if err != nil {
+		t.Fatalf("Redeliver() failed: %v", err)
+	}
+	for _, handle := range handles {
+		handle.Wait()
+	}
+
+	if len(listener.received) != 1 || listener.received[0].GetName() != "billing" {
+		t.Errorf("Expected the stored message to be redelivered, got %v", listener.received)
+	}
+}
@@ -0,0 +1,328 @@
+// Package pubsub connects a bus to Google Cloud Pub/Sub.
+//
+// Each event name maps to a topic. Events received through a subscription
+// are dispatched into the bus and acknowledged once every local listener
+// handled them without error; failed events are nacked and redelivered.
+//
+//	bridge := pubsub.New(bus, client, pubsub.WithAutoCreate())
+//	bridge.Publish("invoice.created")
+//	bridge.Subscribe(ctx, "invoice.created", "billing-invoices")
+//	defer bridge.Close()
+//
+// Events received from Pub/Sub are not published back, so an instance can
+// publish and subscribe the same events without looping.
+package pubsub
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"sync"
+	"time"
+
+	"cloud.google.com/go/pubsub"
+
+	"github.com/openframebox/goevent"
+)
+
+// AttributeEvent is the message attribute holding the event name, for
+// use in subscription filters
+const AttributeEvent = "goevent.name"
+
+// Ack deadlines Pub/Sub accepts for a subscription
+const (
+	minAckDeadline = 10 * time.Second
+	maxAckDeadline = 600 * time.Second
+)
+
+// Option configures a Bridge
+type Option func(*Bridge)
+
+// WithCodec sets the codec envelopes are encoded with.
+// Defaults to goevent.JSONCodec.
+func WithCodec(codec goevent.Codec) Option {
+	return func(b *Bridge) {
+		b.codec = codec
+	}
+}
+
+// WithTopicPrefix sets the prefix of the topic an event is published to,
+// which is otherwise its name
+func WithTopicPrefix(prefix string) Option {
+	return func(b *Bridge) {
+		b.prefix = prefix
+	}
+}
+
+// WithAutoCreate creates topics and subscriptions that do not exist yet
+func WithAutoCreate() Option {
+	return func(b *Bridge) {
+		b.autoCreate = true
+	}
+}
+
+// WithOrderingKey publishes events with the ordering key fn returns for
+// their envelope, enabling message ordering on every topic. Events with
+// the same key are delivered in the order they were published to
+// subscriptions created with ordering enabled, which WithAutoCreate does.
+// An empty key publishes without ordering.
+//
+// The envelope carries the metadata of the dispatch being forwarded, so
+// a key can follow its tenant or correlation:
+//
+//	pubsub.WithOrderingKey(func(env goevent.Envelope) string { return env.CorrelationID })
+func WithOrderingKey(fn func(env goevent.Envelope) string) Option {
+	return func(b *Bridge) {
+		b.orderingKey = fn
+	}
+}
+
+// WithAckDeadline sets the ack deadline of every subscription, between
+// 10 seconds and 10 minutes. By default it follows the longest Timeout of
+// the local listeners for the event.
+func WithAckDeadline(deadline time.Duration) Option {
+	return func(b *Bridge) {
+		b.ackDeadline = deadline
+	}
+}
+
+// Bridge connects a bus to Pub/Sub
+type Bridge struct {
+	bus         *goevent.GoEvent
+	client      *pubsub.Client
+	codec       goevent.Codec
+	prefix      string
+	autoCreate  bool
+	orderingKey func(env goevent.Envelope) string
+	ackDeadline time.Duration
+
+	mu     sync.Mutex
+	topics map[string]*pubsub.Topic
+	cancel []context.CancelFunc
+	wg     sync.WaitGroup
+}
+
+// remoteKey holds the event of a dispatch received from Pub/Sub.
+// Dispatches its listeners make inherit the value but carry other
+// events, so they are still forwarded.
+type remoteKey struct{}
+
+// New creates a Bridge between bus and client
+func New(bus *goevent.GoEvent, client *pubsub.Client, opts ...Option) *Bridge {
+	b := &Bridge{
+		bus:    bus,
+		client: client,
+		codec:  goevent.JSONCodec{},
+		topics: make(map[string]*pubsub.Topic),
+	}
+	for _, opt := range opts {
+		opt(b)
+	}
+	return b
+}
+
+// TopicID returns the topic an event is published to
+func (b *Bridge) TopicID(eventName string) string {
+	return b.prefix + eventName
+}
+
+// Publish forwards every dispatch of the named events to Pub/Sub.
+// Forwarding runs as a sync listener that waits for the publish to be
+// confirmed, so a failed publish is recorded on the dispatch.
+func (b *Bridge) Publish(eventNames ...string) {
+	for _, name := range eventNames {
+		b.bus.RegisterListener(&forwarder{bridge: b, eventName: name})
+	}
+}
+
+// PublishEvent sends a single event to Pub/Sub and waits until it is
+// confirmed. When ctx belongs to a dispatch, its metadata travels with
+// the event.
+func (b *Bridge) PublishEvent(ctx context.Context, event goevent.Event) error {
+	env := goevent.NewEnvelope(event)
+	if handle, ok := goevent.HandleFromContext(ctx); ok {
+		env.Metadata = handle.Metadata()
+	}
+	data, err := b.codec.Marshal(env)
+	if err != nil {
+		return err
+	}
+
+	id := b.TopicID(event.Name())
+	topic, err := b.topic(ctx, id)
+	if err != nil {
+		return err
+	}
+
+	msg := &pubsub.Message{
+		Data:       data,
+		Attributes: map[string]string{AttributeEvent: event.Name()},
+	}
+	if b.orderingKey != nil {
+		msg.OrderingKey = b.orderingKey(env)
+	}
+	if _, err := topic.Publish(ctx, msg).Get(ctx); err != nil {
+		if msg.OrderingKey != "" {
+			// Publishing for a key pauses after an error until resumed
+			topic.ResumePublish(msg.OrderingKey)
+		}
+		return fmt.Errorf("pubsub: publish to %s: %w", id, err)
+	}
+	return nil
+}
+
+// topic returns the topic with the given ID, creating it if needed
+func (b *Bridge) topic(ctx context.Context, id string) (*pubsub.Topic, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if topic, ok := b.topics[id]; ok {
+		return topic, nil
+	}
+
+	topic := b.client.Topic(id)
+	if b.autoCreate {
+		exists, err := topic.Exists(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("pubsub: topic %s: %w", id, err)
+		}
+		if !exists {
+			if topic, err = b.client.CreateTopic(ctx, id); err != nil {
+				return nil, fmt.Errorf("pubsub: create topic %s: %w", id, err)
+			}
+		}
+	}
+	topic.EnableMessageOrdering = b.orderingKey != nil
+	b.topics[id] = topic
+	return topic, nil
+}
+
+// Subscribe dispatches the events of the named event received through
+// the subscription into the bus until ctx is done or Close is called.
+//
+// Messages are leased for at most the ack deadline: the longest Timeout
+// of the listeners registered for eventName, unless WithAckDeadline is
+// given. Register the listeners before subscribing. Without either, the
+// client keeps extending leases for as long as listeners run.
+func (b *Bridge) Subscribe(ctx context.Context, eventName, subscriptionID string) error {
+	deadline := b.deadlineFor(eventName)
+
+	sub := b.client.Subscription(subscriptionID)
+	if b.autoCreate {
+		exists, err := sub.Exists(ctx)
+		if err != nil {
+			return fmt.Errorf("pubsub: subscription %s: %w", subscriptionID, err)
+		}
+		if !exists {
+			topic, err := b.topic(ctx, b.TopicID(eventName))
+			if err != nil {
+				return err
+			}
+			sub, err = b.client.CreateSubscription(ctx, subscriptionID, pubsub.SubscriptionConfig{
+				Topic:                 topic,
+				AckDeadline:           deadline,
+				EnableMessageOrdering: b.orderingKey != nil,
+			})
+			if err != nil {
+				return fmt.Errorf("pubsub: create subscription %s: %w", subscriptionID, err)
+			}
+		}
+	}
+	if deadline > 0 {
+		sub.ReceiveSettings.MaxExtension = deadline
+	}
+
+	ctx, cancel := context.WithCancel(ctx)
+	b.mu.Lock()
+	b.cancel = append(b.cancel, cancel)
+	b.mu.Unlock()
+
+	b.wg.Add(1)
+	go func() {
+		defer b.wg.Done()
+		sub.Receive(ctx, func(_ context.Context, msg *pubsub.Message) {
+			if b.handle(msg.Data) {
+				msg.Ack()
+			} else {
+				msg.Nack()
+			}
+		})
+	}()
+	return nil
+}
+
+// deadlineFor returns the ack deadline of subscriptions to eventName, or
+// zero if neither WithAckDeadline nor a listener timeout sets one
+func (b *Bridge) deadlineFor(eventName string) time.Duration {
+	deadline := b.ackDeadline
+	if deadline == 0 {
+		for _, info := range b.bus.Listeners()[eventName] {
+			deadline = max(deadline, info.Options.Timeout)
+		}
+	}
+	if deadline == 0 {
+		return 0
+	}
+	return min(max(deadline, minAckDeadline), maxAckDeadline)
+}
+
+// Close stops every subscription, waits for events being dispatched and
+// flushes pending publishes. The client is left open.
+func (b *Bridge) Close() error {
+	b.mu.Lock()
+	cancel := b.cancel
+	b.cancel = nil
+	topics := b.topics
+	b.topics = make(map[string]*pubsub.Topic)
+	b.mu.Unlock()
+
+	for _, c := range cancel {
+		c()
+	}
+	b.wg.Wait()
+	for _, topic := range topics {
+		topic.Stop()
+	}
+	return nil
+}
+
+// handle dispatches a message with the metadata it was sent with and
+// reports whether it can be acknowledged.
+// Messages that cannot be decoded are acknowledged, as redelivering them
+// cannot fix them.
+func (b *Bridge) handle(data []byte) bool {
+	env, err := b.codec.Unmarshal(data)
+	if err != nil {
+		return true
+	}
+	ctx := context.WithValue(context.Background(), remoteKey{}, env.Event)
+	handle := b.bus.DispatchContext(ctx, env.Event, goevent.WithMetadata(env.Metadata.Fields()))
+	handle.Wait()
+	return len(handle.GetErrors()) == 0
+}
+
+// forwarder publishes the events of one name to Pub/Sub
+type forwarder struct {
+	bridge    *Bridge
+	eventName string
+}
+
+func (f *forwarder) EventName() string {
+	return f.eventName
+}
+
+func (f *forwarder) OnEvent(event goevent.Event) error {
+	return f.OnEventContext(context.Background(), event)
+}
+
+func (f *forwarder) OnEventContext(ctx context.Context, event goevent.Event) error {
+	if isRemote(ctx, event) {
+		return nil
+	}
+	return f.bridge.PublishEvent(ctx, event)
+}
+
+// isRemote reports whether event was received from Pub/Sub
+func isRemote(ctx context.Context, event goevent.Event) bool {
+	remote, ok := ctx.Value(remoteKey{}).(goevent.Event)
+	return ok && reflect.TypeOf(remote).Comparable() && remote == event
+}
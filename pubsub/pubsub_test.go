@@ -0,0 +1,235 @@
+package pubsub
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"cloud.google.com/go/pubsub"
+	"cloud.google.com/go/pubsub/pstest"
+	"google.golang.org/api/option"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials/insecure"
+
+	"github.com/openframebox/goevent"
+)
+
+func newClient(t *testing.T) (*pubsub.Client, *pstest.Server) {
+	t.Helper()
+	srv := pstest.NewServer()
+	t.Cleanup(func() { srv.Close() })
+
+	conn, err := grpc.Dial(srv.Addr, grpc.WithTransportCredentials(insecure.NewCredentials()))
+	if err != nil {
+		t.Fatalf("Dial() failed: %v", err)
+	}
+	client, err := pubsub.NewClient(context.Background(), "test-project", option.WithGRPCConn(conn))
+	if err != nil {
+		t.Fatalf("NewClient() failed: %v", err)
+	}
+	t.Cleanup(func() { client.Close() })
+	return client, srv
+}
+
+type testRecorder struct {
+	eventName string
+	fail      int // number of calls to fail
+	timeout   time.Duration
+
+	mu       sync.Mutex
+	events   []goevent.Event
+	metadata []goevent.Metadata
+	calls    chan struct{}
+}
+
+func newTestRecorder(eventName string) *testRecorder {
+	return &testRecorder{eventName: eventName, calls: make(chan struct{}, 16)}
+}
+
+func (r *testRecorder) EventName() string {
+	return r.eventName
+}
+
+func (r *testRecorder) Options() goevent.ListenerOptions {
+	return goevent.ListenerOptions{Timeout: r.timeout}
+}
+
+func (r *testRecorder) OnEvent(event goevent.Event) error {
+	r.mu.Lock()
+	defer func() {
+		r.mu.Unlock()
+		r.calls <- struct{}{}
+	}()
+	if r.fail > 0 {
+		r.fail--
+		return errors.New("not yet")
+	}
+	r.events = append(r.events, event)
+	return nil
+}
+
+func (r *testRecorder) OnEventContext(ctx context.Context, event goevent.Event) error {
+	if md, ok := goevent.MetadataFromContext(ctx); ok {
+		r.mu.Lock()
+		r.metadata = append(r.metadata, md)
+		r.mu.Unlock()
+	}
+	return r.OnEvent(event)
+}
+
+func (r *testRecorder) Metadata() []goevent.Metadata {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return append([]goevent.Metadata(nil), r.metadata...)
+}
+
+func (r *testRecorder) Events() []goevent.Event {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return append([]goevent.Event(nil), r.events...)
+}
+
+func (r *testRecorder) waitCalls(t *testing.T, n int) {
+	t.Helper()
+	for i := 0; i < n; i++ {
+		select {
+		case <-r.calls:
+		case <-time.After(10 * time.Second):
+			t.Fatalf("Expected %d calls, got %d", n, i)
+		}
+	}
+}
+
+func TestBridge_PublishAndSubscribe(t *testing.T) {
+	client, srv := newClient(t)
+	ctx := context.Background()
+
+	bus := goevent.New()
+	recorder := newTestRecorder("invoice.created")
+	recorder.fail = 1 // fails locally, succeeds when received
+	bus.RegisterListener(recorder)
+
+	bridge := New(bus, client, WithAutoCreate(), WithTopicPrefix("events-"))
+	bridge.Publish("invoice.created")
+	if err := bridge.Subscribe(ctx, "invoice.created", "billing"); err != nil {
+		t.Fatalf("Subscribe() failed: %v", err)
+	}
+	defer bridge.Close()
+
+	bus.Dispatch(&goevent.GenericEvent{EventName: "invoice.created", Data: map[string]any{"id": "inv-1"}})
+	recorder.waitCalls(t, 2)
+	bridge.Close()
+
+	events := recorder.Events()
+	if len(events) != 1 || events[0].Payload()["id"] != "inv-1" {
+		t.Fatalf("Expected inv-1 to be handled once, got %v", events)
+	}
+
+	messages := srv.Messages()
+	if len(messages) != 1 {
+		t.Fatalf("Expected received events not to be published back, got %d messages", len(messages))
+	}
+	if messages[0].Attributes[AttributeEvent] != "invoice.created" {
+		t.Errorf("Expected the event name attribute, got %v", messages[0].Attributes)
+	}
+	if messages[0].Acks != 1 {
+		t.Errorf("Expected the handled message to be acknowledged, got %d acks", messages[0].Acks)
+	}
+}
+
+func TestBridge_NackOnError(t *testing.T) {
+	client, srv := newClient(t)
+	ctx := context.Background()
+
+	producer := goevent.New()
+	publisher := New(producer, client, WithAutoCreate())
+	defer publisher.Close()
+
+	consumer := goevent.New()
+	recorder := newTestRecorder("invoice.created")
+	recorder.fail = 1
+	consumer.RegisterListener(recorder)
+	subscriber := New(consumer, client, WithAutoCreate())
+	if err := subscriber.Subscribe(ctx, "invoice.created", "billing"); err != nil {
+		t.Fatalf("Subscribe() failed: %v", err)
+	}
+
+	if err := publisher.PublishEvent(ctx, &goevent.GenericEvent{EventName: "invoice.created"}); err != nil {
+		t.Fatalf("PublishEvent() failed: %v", err)
+	}
+	recorder.waitCalls(t, 1)
+	subscriber.Close()
+
+	if messages := srv.Messages(); len(messages) != 1 || messages[0].Acks != 0 {
+		t.Errorf("Expected the failed message not to be acknowledged, got %+v", messages)
+	}
+}
+
+func TestBridge_OrderingKey(t *testing.T) {
+	client, srv := newClient(t)
+	ctx := context.Background()
+
+	byCorrelation := WithOrderingKey(func(env goevent.Envelope) string { return env.CorrelationID })
+
+	consumer := goevent.New()
+	recorder := newTestRecorder("order.updated")
+	recorder.timeout = 30 * time.Second
+	consumer.RegisterListener(recorder)
+	subscriber := New(consumer, client, WithAutoCreate(), byCorrelation)
+	if err := subscriber.Subscribe(ctx, "order.updated", "orders"); err != nil {
+		t.Fatalf("Subscribe() failed: %v", err)
+	}
+	defer subscriber.Close()
+
+	producer := goevent.New()
+	publisher := New(producer, client, WithAutoCreate(), byCorrelation)
+	publisher.Publish("order.updated")
+	defer publisher.Close()
+
+	handle := producer.Dispatch(&goevent.GenericEvent{EventName: "order.updated"})
+	if errs := handle.GetErrors(); len(errs) > 0 {
+		t.Fatalf("Expected the event to be published, got %v", errs[0])
+	}
+	recorder.waitCalls(t, 1)
+
+	messages := srv.Messages()
+	if len(messages) != 1 || messages[0].OrderingKey != handle.DispatchID() {
+		t.Errorf("Expected the correlation ID as ordering key, got %+v", messages)
+	}
+
+	cfg, err := client.Subscription("orders").Config(ctx)
+	if err != nil {
+		t.Fatalf("Config() failed: %v", err)
+	}
+	if !cfg.EnableMessageOrdering || cfg.AckDeadline != 30*time.Second {
+		t.Errorf("Expected ordering and the listener timeout as ack deadline, got %v %v", cfg.EnableMessageOrdering, cfg.AckDeadline)
+	}
+}
+
+func TestBridge_MetadataRoundTrip(t *testing.T) {
+	client, _ := newClient(t)
+	ctx := context.Background()
+
+	bus := goevent.New()
+	recorder := newTestRecorder("invoice.created")
+	bus.RegisterListener(recorder)
+
+	bridge := New(bus, client, WithAutoCreate())
+	bridge.Publish("invoice.created")
+	if err := bridge.Subscribe(ctx, "invoice.created", "billing"); err != nil {
+		t.Fatalf("Subscribe() failed: %v", err)
+	}
+	defer bridge.Close()
+
+	bus.Dispatch(&goevent.GenericEvent{EventName: "invoice.created"},
+		goevent.WithMetadata(map[string]string{goevent.MetadataCorrelationID: "c-1", goevent.MetadataTenant: "acme", "trace": "t-1"}))
+
+	// Once locally, once from the subscription
+	recorder.waitCalls(t, 2)
+	md := recorder.Metadata()[1]
+	if md.CorrelationID != "c-1" || md.Tenant != "acme" || md.Header("trace") != "t-1" {
+		t.Errorf("Expected the sent metadata, got %+v", md)
+	}
+}
@@ -0,0 +1,101 @@
+package goevent
+
+import "reflect"
+
+// listenerQueue holds events for a listener that have been dispatched
+// but not yet delivered
+type listenerQueue interface {
+	pendingEvents() []Event
+	purge() int
+	flush() int
+}
+
+type registeredQueue struct {
+	listener any // see listenerKey
+	queue    listenerQueue
+}
+
+// addQueue makes a listener's queue available for inspection
+func (ge *GoEvent) addQueue(listener Listener, queue listenerQueue) {
+	ge.queuesMu.Lock()
+	defer ge.queuesMu.Unlock()
+	ge.queues = append(ge.queues, registeredQueue{listener: listenerKey(listener), queue: queue})
+}
+
+// removeQueues forgets the queues registered for a listener and returns
+// them
+func (ge *GoEvent) removeQueues(listener Listener) []listenerQueue {
+	ge.queuesMu.Lock()
+	defer ge.queuesMu.Unlock()
+
+	key := listenerKey(listener)
+	var removed []listenerQueue
+	kept := ge.queues[:0]
+	for _, rq := range ge.queues {
+		if sameListener(rq.listener, key) {
+			removed = append(removed, rq.queue)
+		} else {
+			kept = append(kept, rq)
+		}
+	}
+	clear(ge.queues[len(kept):])
+	ge.queues = kept
+	return removed
+}
+
+// queuesFor returns the queues registered for a listener, given as
+// registered by the application
+func (ge *GoEvent) queuesFor(listener any) []listenerQueue {
+	ge.queuesMu.Lock()
+	defer ge.queuesMu.Unlock()
+
+	var queues []listenerQueue
+	for _, rq := range ge.queues {
+		if sameListener(rq.listener, listener) {
+			queues = append(queues, rq.queue)
+		}
+	}
+	return queues
+}
+
+// QueuedEvents returns the events waiting to be delivered to a listener,
+// either held back by its rate limit, collected for its next digest or
+// batch or waiting out its debounce period. listener is a Listener or a
+// BatchListener, as it was registered.
+func (ge *GoEvent) QueuedEvents(listener any) []Event {
+	var events []Event
+	for _, queue := range ge.queuesFor(listener) {
+		events = append(events, queue.pendingEvents()...)
+	}
+	return events
+}
+
+// PurgeQueue discards the events waiting to be delivered to a listener
+// and returns how many were discarded
+func (ge *GoEvent) PurgeQueue(listener any) int {
+	purged := 0
+	for _, queue := range ge.queuesFor(listener) {
+		purged += queue.purge()
+	}
+	return purged
+}
+
+// FlushQueue synchronously delivers the events waiting for a listener,
+// bypassing its rate limit, digest interval, batch size or debounce
+// period, and returns how many were delivered
+func (ge *GoEvent) FlushQueue(listener any) int {
+	flushed := 0
+	for _, queue := range ge.queuesFor(listener) {
+		flushed += queue.flush()
+	}
+	return flushed
+}
+
+// sameListener compares listeners without panicking on uncomparable types
+func sameListener(a, b any) bool {
+	typ := reflect.TypeOf(a)
+	if typ != reflect.TypeOf(b) || typ == nil || !typ.Comparable() {
+		return false
+	}
+	return a == b
+}
@@ -0,0 +1,97 @@
+package goevent
+
+import (
+	"testing"
+	"time"
+)
+
+func TestQueue_InspectAndPurgeRateLimited(t *testing.T) {
+	evt := New()
+	listener := &testLimitedListener{limit: RateLimit{MaxPerSecond: 0.001, Policy: RateLimitQueue}}
+	evt.RegisterListener(listener)
+
+	evt.Dispatch(&TestEvent{data: "delivered"})
+	held := evt.Dispatch(&TestEvent{data: "held 1"})
+	evt.Dispatch(&TestEvent{data: "held 2"})
+
+	queued := evt.QueuedEvents(listener)
+	if len(queued) != 2 {
+		t.Fatalf("Expected 2 queued events, got %d", len(queued))
+	}
+	if queued[0].(*TestEvent).data != "held 1" {
+		t.Errorf("Expected queue in dispatch order, got '%s' first", queued[0].(*TestEvent).data)
+	}
+
+	if purged := evt.PurgeQueue(listener); purged != 2 {
+		t.Errorf("Expected 2 purged events, got %d", purged)
+	}
+	select {
+	case <-held.Done():
+	case <-time.After(time.Second):
+		t.Fatal("Handle of purged event was not released")
+	}
+	if listener.Count() != 1 {
+		t.Errorf("Expected purged events not to be delivered, got %d deliveries", listener.Count())
+	}
+	if len(evt.QueuedEvents(listener)) != 0 {
+		t.Error("Expected empty queue after purge")
+	}
+}
+
+func TestQueue_FlushRateLimited(t *testing.T) {
+	evt := New()
+	listener := &testLimitedListener{limit: RateLimit{MaxPerSecond: 0.001, Policy: RateLimitQueue}}
+	evt.RegisterListener(listener)
+
+	evt.Dispatch(&TestEvent{})
+	evt.Dispatch(&TestEvent{})
+	evt.Dispatch(&TestEvent{})
+
+	if flushed := evt.FlushQueue(listener); flushed != 2 {
+		t.Errorf("Expected 2 flushed events, got %d", flushed)
+	}
+	if listener.Count() != 3 {
+		t.Errorf("Expected all events delivered after flush, got %d", listener.Count())
+	}
+	evt.Wait()
+}
+
+func TestQueue_FlushDigest(t *testing.T) {
+	evt := New()
+	listener := &testDigestListener{}
+	evt.RegisterListener(listener)
+
+	evt.Dispatch(&TestEvent{data: "one"})
+	evt.Dispatch(&TestEvent{data: "two"})
+
+	if queued := evt.QueuedEvents(listener); len(queued) != 2 {
+		t.Fatalf("Expected 2 events in the digest window, got %d", len(queued))
+	}
+	if flushed := evt.FlushQueue(listener); flushed != 2 {
+		t.Errorf("Expected 2 flushed events, got %d", flushed)
+	}
+
+	listener.mu.Lock()
+	digests := len(listener.digests)
+	listener.mu.Unlock()
+	if digests != 1 {
+		t.Errorf("Expected the digest to be delivered by flush, got %d digests", digests)
+	}
+
+	// The expired timer must not deliver the flushed window again
+	time.Sleep(40 * time.Millisecond)
+	evt.Wait()
+
+	listener.mu.Lock()
+	defer listener.mu.Unlock()
+	if len(listener.digests) != 1 {
+		t.Errorf("Expected exactly 1 digest, got %d", len(listener.digests))
+	}
+}
+
+func TestQueue_UnknownListener(t *testing.T) {
+	evt := New()
+	if n := evt.FlushQueue(&testSyncListener{}); n != 0 {
+		t.Errorf("Expected 0 for a listener without queue, got %d", n)
+	}
+}
@@ -0,0 +1,259 @@
+package goevent
+
+import (
+	"fmt"
+	"sync"
+	"time"
+)
+
+// RateLimitPolicy determines what happens to events over a rate limit
+type RateLimitPolicy int
+
+const (
+	// RateLimitQueue delays excess events until capacity is available
+	RateLimitQueue RateLimitPolicy = iota
+	// RateLimitDrop discards excess events
+	RateLimitDrop
+	// RateLimitCoalesce keeps only the most recent excess event and
+	// delivers it once capacity is available
+	RateLimitCoalesce
+)
+
+var rateLimitPolicyNames = map[RateLimitPolicy]string{
+	RateLimitQueue:    "queue",
+	RateLimitDrop:     "drop",
+	RateLimitCoalesce: "coalesce",
+}
+
+func (p RateLimitPolicy) String() string {
+	if name, ok := rateLimitPolicyNames[p]; ok {
+		return name
+	}
+	return fmt.Sprintf("RateLimitPolicy(%d)", int(p))
+}
+
+// MarshalText encodes the policy by name
+func (p RateLimitPolicy) MarshalText() ([]byte, error) {
+	if _, ok := rateLimitPolicyNames[p]; !ok {
+		return nil, fmt.Errorf("goevent: unknown rate limit policy %d", int(p))
+	}
+	return []byte(p.String()), nil
+}
+
+// UnmarshalText decodes a policy name
+func (p *RateLimitPolicy) UnmarshalText(text []byte) error {
+	for policy, name := range rateLimitPolicyNames {
+		if name == string(text) {
+			*p = policy
+			return nil
+		}
+	}
+	return fmt.Errorf("goevent: unknown rate limit policy %q", text)
+}
+
+// RateLimit limits how often events are delivered. A zero MaxPerSecond
+// disables the limit.
+type RateLimit struct {
+	MaxPerSecond float64         `json:"max_per_second"`
+	Burst        int             `json:"burst,omitempty"` // events allowed at once, at least 1
+	Policy       RateLimitPolicy `json:"policy"`
+}
+
+// SetRateLimit limits how often events with the given name are dispatched
+// to their listeners. A zero MaxPerSecond removes the limit.
+func (ge *GoEvent) SetRateLimit(eventName string, limit RateLimit) {
+	ge.rateLimitsMu.Lock()
+	defer ge.rateLimitsMu.Unlock()
+
+	if limit.MaxPerSecond <= 0 {
+		delete(ge.rateLimits, eventName)
+		return
+	}
+	ge.rateLimits[eventName] = newRateLimiter(ge.clock, limit)
+}
+
+// RateLimits returns the bus-level rate limits by event name
+func (ge *GoEvent) RateLimits() map[string]RateLimit {
+	ge.rateLimitsMu.RLock()
+	defer ge.rateLimitsMu.RUnlock()
+
+	limits := make(map[string]RateLimit, len(ge.rateLimits))
+	for eventName, limiter := range ge.rateLimits {
+		limits[eventName] = limiter.limit
+	}
+	return limits
+}
+
+// rateLimiterFor returns the bus-level limiter for an event, if any
+func (ge *GoEvent) rateLimiterFor(eventName string) *rateLimiter {
+	ge.rateLimitsMu.RLock()
+	defer ge.rateLimitsMu.RUnlock()
+	return ge.rateLimits[eventName]
+}
+
+// tokenBucket refills at rate tokens per second up to burst tokens
+type tokenBucket struct {
+	rate   float64
+	burst  float64
+	tokens float64
+	last   time.Time
+}
+
+func (b *tokenBucket) refill(now time.Time) {
+	if !b.last.IsZero() {
+		b.tokens = min(b.burst, b.tokens+now.Sub(b.last).Seconds()*b.rate)
+	}
+	b.last = now
+}
+
+// take consumes a token if one is available
+func (b *tokenBucket) take(now time.Time) bool {
+	b.refill(now)
+	if b.tokens >= 1 {
+		b.tokens--
+		return true
+	}
+	return false
+}
+
+// wait returns how long until the next token is available
+func (b *tokenBucket) wait(now time.Time) time.Duration {
+	b.refill(now)
+	if b.tokens >= 1 {
+		return 0
+	}
+	return time.Duration((1 - b.tokens) / b.rate * float64(time.Second))
+}
+
+// limitedCall is a delivery held back by a rate limiter.
+// Exactly one of run or drop is eventually called.
+type limitedCall struct {
+	event Event
+	run   func()
+	drop  func()
+}
+
+// rateLimiter admits deliveries according to a RateLimit
+type rateLimiter struct {
+	limit RateLimit
+	clock Clock
+
+	mu     sync.Mutex
+	bucket tokenBucket
+	queue  []limitedCall
+	timer  Timer
+}
+
+func newRateLimiter(clock Clock, limit RateLimit) *rateLimiter {
+	burst := float64(max(limit.Burst, 1))
+	return &rateLimiter{
+		limit: limit,
+		clock: clock,
+		bucket: tokenBucket{
+			rate:   limit.MaxPerSecond,
+			burst:  burst,
+			tokens: burst,
+		},
+	}
+}
+
+// submit runs the call immediately if capacity is available, otherwise
+// queues, coalesces, or drops it according to the policy
+func (l *rateLimiter) submit(call limitedCall) {
+	l.mu.Lock()
+
+	if len(l.queue) == 0 && l.bucket.take(l.clock.Now()) {
+		l.mu.Unlock()
+		call.run()
+		return
+	}
+
+	switch l.limit.Policy {
+	case RateLimitDrop:
+		l.mu.Unlock()
+		call.drop()
+		return
+	case RateLimitCoalesce:
+		if len(l.queue) > 0 {
+			replaced := l.queue[0]
+			l.queue[0] = call
+			l.mu.Unlock()
+			replaced.drop()
+			return
+		}
+	}
+
+	l.queue = append(l.queue, call)
+	l.schedule()
+	l.mu.Unlock()
+}
+
+// schedule arms the timer for the next token. The caller must hold mu.
+func (l *rateLimiter) schedule() {
+	if l.timer == nil {
+		l.timer = l.clock.AfterFunc(l.bucket.wait(l.clock.Now()), l.drain)
+	}
+}
+
+// drain runs queued calls for which capacity has become available
+func (l *rateLimiter) drain() {
+	l.mu.Lock()
+	l.timer = nil
+	var ready []limitedCall
+	for len(l.queue) > 0 && l.bucket.take(l.clock.Now()) {
+		ready = append(ready, l.queue[0])
+		l.queue = l.queue[1:]
+	}
+	if len(l.queue) > 0 {
+		l.schedule()
+	}
+	l.mu.Unlock()
+
+	for _, call := range ready {
+		call.run()
+	}
+}
+
+// pendingEvents returns the events waiting in the queue
+func (l *rateLimiter) pendingEvents() []Event {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	events := make([]Event, len(l.queue))
+	for i, call := range l.queue {
+		events[i] = call.event
+	}
+	return events
+}
+
+// takeQueue detaches all queued calls and stops the drain timer
+func (l *rateLimiter) takeQueue() []limitedCall {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	queue := l.queue
+	l.queue = nil
+	if l.timer != nil {
+		l.timer.Stop()
+		l.timer = nil
+	}
+	return queue
+}
+
+// purge drops all queued calls
+func (l *rateLimiter) purge() int {
+	queue := l.takeQueue()
+	for _, call := range queue {
+		call.drop()
+	}
+	return len(queue)
+}
+
+// flush runs all queued calls immediately, ignoring the limit
+func (l *rateLimiter) flush() int {
+	queue := l.takeQueue()
+	for _, call := range queue {
+		call.run()
+	}
+	return len(queue)
+}
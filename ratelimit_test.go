@@ -0,0 +1,141 @@
+package goevent
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+	"time"
+)
+
+type testLimitedListener struct {
+	testCountingListener
+	limit RateLimit
+}
+
+func (l *testLimitedListener) Options() ListenerOptions {
+	return ListenerOptions{RateLimit: l.limit}
+}
+
+func TestRateLimit_ListenerDropsExcessEvents(t *testing.T) {
+	evt := New()
+	listener := &testLimitedListener{limit: RateLimit{MaxPerSecond: 1, Burst: 2, Policy: RateLimitDrop}}
+	evt.RegisterListener(listener)
+
+	for i := 0; i < 5; i++ {
+		evt.Dispatch(&TestEvent{})
+	}
+	evt.Wait()
+
+	if listener.Count() != 2 {
+		t.Errorf("Expected 2 deliveries within burst, got %d", listener.Count())
+	}
+}
+
+func TestRateLimit_ListenerQueuesExcessEvents(t *testing.T) {
+	evt := New()
+	listener := &testLimitedListener{limit: RateLimit{MaxPerSecond: 100, Policy: RateLimitQueue}}
+	evt.RegisterListener(listener)
+
+	handles := make([]*DispatchHandle, 3)
+	for i := range handles {
+		handles[i] = evt.Dispatch(&TestEvent{})
+	}
+	if listener.Count() != 1 {
+		t.Errorf("Expected only the first event to be delivered immediately, got %d", listener.Count())
+	}
+
+	handles[2].Wait()
+	if listener.Count() != 3 {
+		t.Errorf("Expected queued events to be delivered, got %d", listener.Count())
+	}
+}
+
+func TestRateLimit_ListenerCoalescesExcessEvents(t *testing.T) {
+	evt := New()
+	listener := &testLimitedListener{limit: RateLimit{MaxPerSecond: 50, Policy: RateLimitCoalesce}}
+	evt.RegisterListener(listener)
+
+	for i := 0; i < 5; i++ {
+		evt.Dispatch(&TestEvent{})
+	}
+	evt.Wait()
+
+	if listener.Count() != 2 {
+		t.Errorf("Expected first and latest events to be delivered, got %d", listener.Count())
+	}
+}
+
+func TestRateLimit_BusLevel(t *testing.T) {
+	evt := New()
+	listener := &testCountingListener{}
+	evt.RegisterListener(listener)
+	evt.SetRateLimit("test.event", RateLimit{MaxPerSecond: 1, Policy: RateLimitDrop})
+
+	first := evt.Dispatch(&TestEvent{})
+	second := evt.DispatchContext(context.Background(), &TestEvent{})
+	second.Wait()
+
+	if first.Shed() || !second.Shed() {
+		t.Error("Expected only the second event to be dropped by the bus-level limit")
+	}
+	if listener.Count() != 1 {
+		t.Errorf("Expected 1 delivery, got %d", listener.Count())
+	}
+
+	evt.SetRateLimit("test.event", RateLimit{})
+	evt.Dispatch(&TestEvent{})
+	if listener.Count() != 2 {
+		t.Errorf("Expected delivery after removing the limit, got %d", listener.Count())
+	}
+}
+
+func TestRateLimit_StateRoundTrip(t *testing.T) {
+	source := New()
+	source.SetRateLimit("metrics.tick", RateLimit{MaxPerSecond: 10, Burst: 5, Policy: RateLimitCoalesce})
+
+	data, err := source.ExportState()
+	if err != nil {
+		t.Fatalf("ExportState() failed: %v", err)
+	}
+
+	var raw map[string]any
+	if err := json.Unmarshal(data, &raw); err != nil {
+		t.Fatalf("Exported state is not JSON: %v", err)
+	}
+	policy := raw["rate_limits"].(map[string]any)["metrics.tick"].(map[string]any)["policy"]
+	if policy != "coalesce" {
+		t.Errorf("Expected policy to be exported by name, got %v", policy)
+	}
+
+	target := New()
+	target.SetRateLimit("stale.event", RateLimit{MaxPerSecond: 1})
+	if err := target.ImportState(data); err != nil {
+		t.Fatalf("ImportState() failed: %v", err)
+	}
+
+	limits := target.RateLimits()
+	if len(limits) != 1 {
+		t.Fatalf("Expected imported limits to replace existing ones, got %v", limits)
+	}
+	if limits["metrics.tick"] != (RateLimit{MaxPerSecond: 10, Burst: 5, Policy: RateLimitCoalesce}) {
+		t.Errorf("Unexpected imported limit %+v", limits["metrics.tick"])
+	}
+}
+
+func TestTokenBucket_Refills(t *testing.T) {
+	now := time.Now()
+	bucket := tokenBucket{rate: 10, burst: 1, tokens: 1}
+
+	if !bucket.take(now) {
+		t.Fatal("Expected initial token")
+	}
+	if bucket.take(now) {
+		t.Fatal("Expected bucket to be empty")
+	}
+	if wait := bucket.wait(now); wait != 100*time.Millisecond {
+		t.Errorf("Expected 100ms wait, got %v", wait)
+	}
+	if !bucket.take(now.Add(100 * time.Millisecond)) {
+		t.Error("Expected token after refill")
+	}
+}
@@ -0,0 +1,343 @@
+// Package redis lets several instances of a service share one logical bus
+// through Redis.
+//
+// By default events travel over Pub/Sub: every subscribed instance
+// receives every event, and events published while an instance is down
+// are lost. WithStreams switches to Redis Streams with a consumer group:
+// each event is handled by one instance of the group, and it is only
+// acknowledged once every local listener handled it without error, so
+// failed and unfinished events are delivered again.
+//
+//	transport := redis.New(bus, client, redis.WithStreams("billing", hostname))
+//	transport.Publish("invoice.created")
+//	transport.Subscribe(ctx, "invoice.created")
+//	defer transport.Close()
+//
+// Events received from Redis are not published back, so an instance can
+// publish and subscribe the same events without looping.
+package redis
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"reflect"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+
+	"github.com/openframebox/goevent"
+)
+
+// envelopeField is the stream entry field holding the encoded envelope
+const envelopeField = "envelope"
+
+// Option configures a Transport
+type Option func(*Transport)
+
+// WithCodec sets the codec envelopes are encoded with.
+// Defaults to goevent.JSONCodec.
+func WithCodec(codec goevent.Codec) Option {
+	return func(t *Transport) {
+		t.codec = codec
+	}
+}
+
+// WithKeyPrefix sets the prefix of the channel or stream an event is
+// published to, which is otherwise its name
+func WithKeyPrefix(prefix string) Option {
+	return func(t *Transport) {
+		t.prefix = prefix
+	}
+}
+
+// WithStreams delivers events through Redis Streams, consumed by the
+// named group. consumer must be unique among the instances of the group.
+func WithStreams(group, consumer string) Option {
+	return func(t *Transport) {
+		t.group = group
+		t.consumer = consumer
+	}
+}
+
+// WithMaxLen caps each stream at about n entries. Zero keeps every entry.
+func WithMaxLen(n int64) Option {
+	return func(t *Transport) {
+		t.maxLen = n
+	}
+}
+
+// WithRetryInterval sets how often events that failed are retried in
+// streams mode. Defaults to 5 seconds.
+func WithRetryInterval(interval time.Duration) Option {
+	return func(t *Transport) {
+		t.retryInterval = interval
+	}
+}
+
+// Transport connects a bus to Redis
+type Transport struct {
+	bus           *goevent.GoEvent
+	client        redis.UniversalClient
+	codec         goevent.Codec
+	prefix        string
+	group         string // empty for Pub/Sub
+	consumer      string
+	maxLen        int64
+	retryInterval time.Duration
+	block         time.Duration
+
+	mu     sync.Mutex
+	cancel []context.CancelFunc
+	wg     sync.WaitGroup
+}
+
+// remoteKey holds the event of a dispatch received from Redis. Dispatches
+// its listeners make inherit the value but carry other events, so they
+// are still forwarded.
+type remoteKey struct{}
+
+// New creates a Transport between bus and client
+func New(bus *goevent.GoEvent, client redis.UniversalClient, opts ...Option) *Transport {
+	t := &Transport{
+		bus:           bus,
+		client:        client,
+		codec:         goevent.JSONCodec{},
+		retryInterval: 5 * time.Second,
+		block:         time.Second,
+	}
+	for _, opt := range opts {
+		opt(t)
+	}
+	return t
+}
+
+// Key returns the channel or stream an event is published to
+func (t *Transport) Key(eventName string) string {
+	return t.prefix + eventName
+}
+
+// Publish forwards every dispatch of the named events to Redis.
+// Forwarding runs as a sync listener, so events are published in
+// dispatch order and a failed publish is recorded on the dispatch.
+func (t *Transport) Publish(eventNames ...string) {
+	for _, name := range eventNames {
+		t.bus.RegisterListener(&forwarder{transport: t, eventName: name})
+	}
+}
+
+// PublishEvent sends a single event to Redis. When ctx belongs to a
+// dispatch, its metadata travels with the event.
+func (t *Transport) PublishEvent(ctx context.Context, event goevent.Event) error {
+	env := goevent.NewEnvelope(event)
+	if handle, ok := goevent.HandleFromContext(ctx); ok {
+		env.Metadata = handle.Metadata()
+	}
+	data, err := t.codec.Marshal(env)
+	if err != nil {
+		return err
+	}
+
+	key := t.Key(event.Name())
+	if t.group == "" {
+		err = t.client.Publish(ctx, key, data).Err()
+	} else {
+		err = t.client.XAdd(ctx, &redis.XAddArgs{
+			Stream: key,
+			MaxLen: t.maxLen,
+			Approx: t.maxLen > 0,
+			Values: map[string]any{envelopeField: data},
+		}).Err()
+	}
+	if err != nil {
+		return fmt.Errorf("redis: publish %s: %w", key, err)
+	}
+	return nil
+}
+
+// Subscribe dispatches events of the given names received from Redis
+// into the bus until Close is called
+func (t *Transport) Subscribe(ctx context.Context, eventNames ...string) error {
+	keys := make([]string, len(eventNames))
+	for i, name := range eventNames {
+		keys[i] = t.Key(name)
+	}
+
+	if t.group == "" {
+		return t.subscribe(ctx, keys)
+	}
+	return t.consume(ctx, keys)
+}
+
+// Close stops every subscription and waits for events being dispatched.
+// The client is left open.
+func (t *Transport) Close() error {
+	t.mu.Lock()
+	cancel := t.cancel
+	t.cancel = nil
+	t.mu.Unlock()
+
+	for _, c := range cancel {
+		c()
+	}
+	t.wg.Wait()
+	return nil
+}
+
+// run starts a loop that stops on Close
+func (t *Transport) run(loop func(ctx context.Context)) {
+	ctx, cancel := context.WithCancel(context.Background())
+	t.mu.Lock()
+	t.cancel = append(t.cancel, cancel)
+	t.mu.Unlock()
+
+	t.wg.Add(1)
+	go func() {
+		defer t.wg.Done()
+		loop(ctx)
+	}()
+}
+
+func (t *Transport) subscribe(ctx context.Context, channels []string) error {
+	pubsub := t.client.Subscribe(ctx, channels...)
+	if _, err := pubsub.Receive(ctx); err != nil {
+		pubsub.Close()
+		return fmt.Errorf("redis: subscribe: %w", err)
+	}
+
+	t.run(func(ctx context.Context) {
+		defer pubsub.Close()
+		messages := pubsub.Channel()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case msg, ok := <-messages:
+				if !ok {
+					return
+				}
+				t.dispatch([]byte(msg.Payload))
+			}
+		}
+	})
+	return nil
+}
+
+func (t *Transport) consume(ctx context.Context, streams []string) error {
+	for _, stream := range streams {
+		err := t.client.XGroupCreateMkStream(ctx, stream, t.group, "0").Err()
+		if err != nil && !strings.HasPrefix(err.Error(), "BUSYGROUP") {
+			return fmt.Errorf("redis: group %s on %s: %w", t.group, stream, err)
+		}
+	}
+
+	t.run(func(ctx context.Context) {
+		// Start with entries left pending by a previous run
+		retryAt := time.Time{}
+		for ctx.Err() == nil {
+			id := ">"
+			if !time.Now().Before(retryAt) {
+				id = "0"
+				retryAt = time.Now().Add(t.retryInterval)
+			}
+			t.readGroup(ctx, streams, id)
+		}
+	})
+	return nil
+}
+
+// readGroup reads new entries, or with id "0" the entries this consumer
+// has not acknowledged, and dispatches them
+func (t *Transport) readGroup(ctx context.Context, streams []string, id string) {
+	args := make([]string, 0, 2*len(streams))
+	args = append(args, streams...)
+	for range streams {
+		args = append(args, id)
+	}
+
+	block := t.block
+	if id != ">" {
+		block = -1 // pending entries are returned immediately
+	}
+	results, err := t.client.XReadGroup(ctx, &redis.XReadGroupArgs{
+		Group:    t.group,
+		Consumer: t.consumer,
+		Streams:  args,
+		Block:    block,
+	}).Result()
+	if err != nil {
+		if !errors.Is(err, redis.Nil) && ctx.Err() == nil {
+			// Back off before retrying, for example while Redis is down
+			select {
+			case <-ctx.Done():
+			case <-time.After(t.block):
+			}
+		}
+		return
+	}
+
+	for _, result := range results {
+		for _, msg := range result.Messages {
+			if t.handle(msg) {
+				t.client.XAck(ctx, result.Stream, t.group, msg.ID)
+			}
+		}
+	}
+}
+
+// handle dispatches a stream entry and reports whether it can be
+// acknowledged. Entries that cannot be decoded are acknowledged, as
+// retrying cannot fix them.
+func (t *Transport) handle(msg redis.XMessage) bool {
+	data, ok := msg.Values[envelopeField].(string)
+	if !ok {
+		return true
+	}
+	handle := t.dispatch([]byte(data))
+	if handle == nil {
+		return true
+	}
+	handle.Wait()
+	return len(handle.GetErrors()) == 0
+}
+
+// dispatch decodes an envelope and dispatches its event with the
+// metadata it was sent with, marked as received so it is not forwarded
+// back. It returns nil if the data cannot be decoded.
+func (t *Transport) dispatch(data []byte) *goevent.DispatchHandle {
+	env, err := t.codec.Unmarshal(data)
+	if err != nil {
+		return nil
+	}
+	ctx := context.WithValue(context.Background(), remoteKey{}, env.Event)
+	return t.bus.DispatchContext(ctx, env.Event, goevent.WithMetadata(env.Metadata.Fields()))
+}
+
+// forwarder publishes the events of one name to Redis
+type forwarder struct {
+	transport *Transport
+	eventName string
+}
+
+func (f *forwarder) EventName() string {
+	return f.eventName
+}
+
+func (f *forwarder) OnEvent(event goevent.Event) error {
+	return f.OnEventContext(context.Background(), event)
+}
+
+func (f *forwarder) OnEventContext(ctx context.Context, event goevent.Event) error {
+	if isRemote(ctx, event) {
+		return nil
+	}
+	return f.transport.PublishEvent(ctx, event)
+}
+
+// isRemote reports whether event was received from Redis
+func isRemote(ctx context.Context, event goevent.Event) bool {
+	remote, ok := ctx.Value(remoteKey{}).(goevent.Event)
+	return ok && reflect.TypeOf(remote).Comparable() && remote == event
+}
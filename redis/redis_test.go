@@ -0,0 +1,220 @@
+package redis
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/redis/go-redis/v9"
+
+	"github.com/openframebox/goevent"
+)
+
+func newClient(t *testing.T) redis.UniversalClient {
+	t.Helper()
+	srv := miniredis.RunT(t)
+	client := redis.NewClient(&redis.Options{Addr: srv.Addr()})
+	t.Cleanup(func() { client.Close() })
+	return client
+}
+
+type testRecorder struct {
+	eventName string
+	fail      int // number of calls to fail
+
+	mu       sync.Mutex
+	events   []goevent.Event
+	metadata []goevent.Metadata
+	calls    chan struct{}
+}
+
+func newTestRecorder(eventName string) *testRecorder {
+	return &testRecorder{eventName: eventName, calls: make(chan struct{}, 16)}
+}
+
+func (r *testRecorder) EventName() string {
+	return r.eventName
+}
+
+func (r *testRecorder) OnEvent(event goevent.Event) error {
+	r.mu.Lock()
+	defer func() {
+		r.mu.Unlock()
+		r.calls <- struct{}{}
+	}()
+	if r.fail > 0 {
+		r.fail--
+		return errors.New("not yet")
+	}
+	r.events = append(r.events, event)
+	return nil
+}
+
+func (r *testRecorder) OnEventContext(ctx context.Context, event goevent.Event) error {
+	if md, ok := goevent.MetadataFromContext(ctx); ok {
+		r.mu.Lock()
+		r.metadata = append(r.metadata, md)
+		r.mu.Unlock()
+	}
+	return r.OnEvent(event)
+}
+
+func (r *testRecorder) Metadata() []goevent.Metadata {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return append([]goevent.Metadata(nil), r.metadata...)
+}
+
+func (r *testRecorder) Events() []goevent.Event {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return append([]goevent.Event(nil), r.events...)
+}
+
+func (r *testRecorder) waitCalls(t *testing.T, n int) {
+	t.Helper()
+	for i := 0; i < n; i++ {
+		select {
+		case <-r.calls:
+		case <-time.After(5 * time.Second):
+			t.Fatalf("Expected %d calls, got %d", n, i)
+		}
+	}
+}
+
+func TestTransport_PubSub(t *testing.T) {
+	client := newClient(t)
+	ctx := context.Background()
+
+	producer := goevent.New()
+	New(producer, client, WithKeyPrefix("events:")).Publish("user.created")
+
+	// Every subscribed instance receives the event
+	var recorders []*testRecorder
+	for i := 0; i < 2; i++ {
+		bus := goevent.New()
+		recorder := newTestRecorder("user.created")
+		bus.RegisterListener(recorder)
+		recorders = append(recorders, recorder)
+
+		transport := New(bus, client, WithKeyPrefix("events:"))
+		defer transport.Close()
+		if err := transport.Subscribe(ctx, "user.created"); err != nil {
+			t.Fatalf("Subscribe() failed: %v", err)
+		}
+	}
+
+	handle := producer.Dispatch(&goevent.GenericEvent{EventName: "user.created", Data: map[string]any{"id": "u-1"}})
+	if errs := handle.GetErrors(); len(errs) != 0 {
+		t.Fatalf("Expected publish to succeed, got %v", errs)
+	}
+
+	for _, recorder := range recorders {
+		recorder.waitCalls(t, 1)
+		if events := recorder.Events(); events[0].Payload()["id"] != "u-1" {
+			t.Errorf("Expected id u-1, got %v", events[0].Payload())
+		}
+	}
+}
+
+func TestTransport_StreamsRetryFailed(t *testing.T) {
+	client := newClient(t)
+	ctx := context.Background()
+
+	producer := goevent.New()
+	New(producer, client, WithStreams("billing", "producer")).Publish("payment.received")
+	producer.Dispatch(&goevent.GenericEvent{EventName: "payment.received", Data: map[string]any{"amount": 10}})
+
+	// The consumer starts later and fails the first attempt
+	consumer := goevent.New()
+	recorder := newTestRecorder("payment.received")
+	recorder.fail = 1
+	consumer.RegisterListener(recorder)
+
+	transport := New(consumer, client, WithStreams("billing", "worker-1"), WithRetryInterval(50*time.Millisecond))
+	transport.block = 50 * time.Millisecond
+	defer transport.Close()
+	if err := transport.Subscribe(ctx, "payment.received"); err != nil {
+		t.Fatalf("Subscribe() failed: %v", err)
+	}
+
+	recorder.waitCalls(t, 2)
+	if events := recorder.Events(); len(events) != 1 || events[0].Payload()["amount"] != float64(10) {
+		t.Errorf("Expected the payment to be retried after the failure, got %v", events)
+	}
+
+	// Acknowledged entries are not delivered again
+	time.Sleep(150 * time.Millisecond)
+	pending, err := client.XPending(ctx, "payment.received", "billing").Result()
+	if err != nil {
+		t.Fatalf("XPending() failed: %v", err)
+	}
+	if pending.Count != 0 {
+		t.Errorf("Expected no pending entries, got %d", pending.Count)
+	}
+}
+
+func TestTransport_StreamsShareGroup(t *testing.T) {
+	client := newClient(t)
+	ctx := context.Background()
+
+	var recorders []*testRecorder
+	for _, name := range []string{"worker-1", "worker-2"} {
+		bus := goevent.New()
+		recorder := newTestRecorder("job.queued")
+		bus.RegisterListener(recorder)
+		recorders = append(recorders, recorder)
+
+		transport := New(bus, client, WithStreams("workers", name))
+		transport.block = 50 * time.Millisecond
+		defer transport.Close()
+		if err := transport.Subscribe(ctx, "job.queued"); err != nil {
+			t.Fatalf("Subscribe() failed: %v", err)
+		}
+	}
+
+	producer := New(goevent.New(), client, WithStreams("workers", "producer"))
+	for i := 0; i < 4; i++ {
+		if err := producer.PublishEvent(ctx, &goevent.GenericEvent{EventName: "job.queued"}); err != nil {
+			t.Fatalf("PublishEvent() failed: %v", err)
+		}
+	}
+
+	deadline := time.Now().Add(5 * time.Second)
+	for time.Now().Before(deadline) && len(recorders[0].Events())+len(recorders[1].Events()) < 4 {
+		time.Sleep(10 * time.Millisecond)
+	}
+	time.Sleep(100 * time.Millisecond)
+	if total := len(recorders[0].Events()) + len(recorders[1].Events()); total != 4 {
+		t.Errorf("Expected each job to be handled once by the group, got %d", total)
+	}
+}
+
+func TestTransport_MetadataRoundTrip(t *testing.T) {
+	client := newClient(t)
+	ctx := context.Background()
+
+	producer := goevent.New()
+	New(producer, client).Publish("user.created")
+
+	consumer := goevent.New()
+	recorder := newTestRecorder("user.created")
+	consumer.RegisterListener(recorder)
+	transport := New(consumer, client)
+	defer transport.Close()
+	if err := transport.Subscribe(ctx, "user.created"); err != nil {
+		t.Fatalf("Subscribe() failed: %v", err)
+	}
+
+	producer.Dispatch(&goevent.GenericEvent{EventName: "user.created"},
+		goevent.WithMetadata(map[string]string{goevent.MetadataCorrelationID: "c-1", goevent.MetadataTenant: "acme", "trace": "t-1"}))
+	recorder.waitCalls(t, 1)
+
+	md := recorder.Metadata()[0]
+	if md.CorrelationID != "c-1" || md.Tenant != "acme" || md.Header("trace") != "t-1" {
+		t.Errorf("Expected the sent metadata, got %+v", md)
+	}
+}
@@ -0,0 +1,117 @@
+package goevent
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+)
+
+// ErrInvalidListener is returned by RegisterListenerE for listeners that
+// cannot be registered
+var ErrInvalidListener = errors.New("goevent: invalid listener")
+
+// RegisterListenerE registers listeners like RegisterListener, after
+// checking them. It rejects nil listeners, listeners without an event
+// name, and listener instances that are already registered, returning
+// an error wrapping ErrInvalidListener that names the failing argument.
+// Either every listener is registered or none is.
+func (ge *GoEvent) RegisterListenerE(listeners ...Listener) error {
+	if err := ge.claimListeners(listeners, true); err != nil {
+		return err
+	}
+	for _, listener := range listeners {
+		ge.registerSingleListener(listener)
+	}
+	return nil
+}
+
+// MustRegisterListener is like RegisterListenerE but panics if a listener
+// is rejected, for registrations at startup
+func (ge *GoEvent) MustRegisterListener(listeners ...Listener) {
+	if err := ge.RegisterListenerE(listeners...); err != nil {
+		panic(err)
+	}
+}
+
+// claimListeners records listener instances as registered. If check is
+// set, nothing is recorded unless all listeners are valid.
+func (ge *GoEvent) claimListeners(listeners []Listener, check bool) error {
+	ge.registryMu.Lock()
+	defer ge.registryMu.Unlock()
+
+	if check {
+		seen := make(map[any]bool)
+		for i, listener := range listeners {
+			if err := ge.checkListener(listener, seen); err != nil {
+				return fmt.Errorf("%w: argument %d (%s): %v", ErrInvalidListener, i, describeListener(listener), err)
+			}
+		}
+	}
+
+	if ge.instances == nil {
+		ge.instances = make(map[any]bool)
+	}
+	for _, listener := range listeners {
+		if key := listenerKey(listener); isInstance(key) {
+			ge.instances[key] = true
+		}
+	}
+	return nil
+}
+
+// checkListener validates a listener. seen holds the instances earlier
+// in the same call. The caller must hold registryMu.
+func (ge *GoEvent) checkListener(listener Listener, seen map[any]bool) error {
+	if listener == nil {
+		return errors.New("nil listener")
+	}
+	key := listenerKey(listener)
+	if key == nil || isNilPointer(key) {
+		return errors.New("nil listener")
+	}
+	names := eventNamesOf(listener)
+	if len(names) == 0 {
+		return errors.New("no event names")
+	}
+	for _, name := range names {
+		if name == "" {
+			return errors.New("empty event name")
+		}
+	}
+	if isInstance(key) {
+		if ge.instances[key] || seen[key] {
+			return errors.New("already registered")
+		}
+		seen[key] = true
+	}
+	return nil
+}
+
+// releaseInstance forgets a registered listener instance, so it can be
+// registered again. The caller must hold registryMu.
+func (ge *GoEvent) releaseInstance(listener Listener) {
+	if key := listenerKey(listener); isInstance(key) {
+		delete(ge.instances, key)
+	}
+}
+
+// isInstance reports whether a listener has an identity of its own,
+// which holds for pointers. Listener values that compare equal are not
+// the same instance.
+func isInstance(listener any) bool {
+	return reflect.ValueOf(listener).Kind() == reflect.Pointer
+}
+
+func isNilPointer(listener any) bool {
+	v := reflect.ValueOf(listener)
+	return v.Kind() == reflect.Pointer && v.IsNil()
+}
+
+// describeListener names a listener in registration errors without
+// calling into it
+func describeListener(listener Listener) string {
+	if listener == nil || listenerKey(listener) == nil {
+		return "<nil>"
+	}
+	return reflect.TypeOf(listenerKey(listener)).String()
+}
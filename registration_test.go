@@ -0,0 +1,50 @@
+package goevent
+
+import (
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestRegisterListenerE(t *testing.T) {
+	evt := New()
+	listener := &testSyncListener{}
+	if err := evt.RegisterListenerE(listener); err != nil {
+		t.Fatalf("RegisterListenerE() failed: %v", err)
+	}
+
+	var nilListener *testSyncListener
+	tests := []struct {
+		name      string
+		listeners []Listener
+		want      string
+	}{
+		{"nil", []Listener{nil}, "argument 0 (<nil>): nil listener"},
+		{"nil pointer", []Listener{&testNamedListener{name: "a"}, nilListener}, "argument 1 (*goevent.testSyncListener): nil listener"},
+		{"empty name", []Listener{&testNamedListener{}}, "empty event name"},
+		{"already registered", []Listener{listener}, "already registered"},
+		{"duplicate argument", []Listener{&testNamedListener{name: "a"}, listener}, "argument 1"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := evt.RegisterListenerE(tt.listeners...)
+			if !errors.Is(err, ErrInvalidListener) || !strings.Contains(err.Error(), tt.want) {
+				t.Errorf("Expected ErrInvalidListener mentioning %q, got %v", tt.want, err)
+			}
+		})
+	}
+
+	// Rejected calls register none of their listeners
+	if got := evt.Events(); len(got) != 1 || got[0] != "test.event" {
+		t.Errorf("Expected only test.event to have listeners, got %v", got)
+	}
+}
+
+func TestMustRegisterListener(t *testing.T) {
+	defer func() {
+		if r := recover(); r == nil {
+			t.Error("Expected MustRegisterListener to panic on a nil listener")
+		}
+	}()
+	New().MustRegisterListener(nil)
+}
@@ -0,0 +1,157 @@
+package render
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"strings"
+	"text/template"
+	"time"
+	"unicode"
+)
+
+// Funcs returns the functions available to templates, modelled on the
+// most used sprig functions:
+//
+//	upper, lower, title, trim, trimPrefix, trimSuffix, replace, contains,
+//	hasPrefix, hasSuffix, truncate, join, split, quote, default, coalesce,
+//	empty, toString, toJSON, toPrettyJSON, now, date, ternary
+func Funcs() template.FuncMap {
+	return template.FuncMap{
+		"upper":      strings.ToUpper,
+		"lower":      strings.ToLower,
+		"title":      title,
+		"trim":       strings.TrimSpace,
+		"trimPrefix": func(prefix, s string) string { return strings.TrimPrefix(s, prefix) },
+		"trimSuffix": func(suffix, s string) string { return strings.TrimSuffix(s, suffix) },
+		"replace":    func(old, new, s string) string { return strings.ReplaceAll(s, old, new) },
+		"contains":   func(substr, s string) bool { return strings.Contains(s, substr) },
+		"hasPrefix":  func(prefix, s string) bool { return strings.HasPrefix(s, prefix) },
+		"hasSuffix":  func(suffix, s string) bool { return strings.HasSuffix(s, suffix) },
+		"truncate":   truncate,
+		"join":       join,
+		"split":      func(sep, s string) []string { return strings.Split(s, sep) },
+		"quote":      func(v any) string { return fmt.Sprintf("%q", toString(v)) },
+		"default":    defaultValue,
+		"coalesce":   coalesce,
+		"empty":      empty,
+		"toString":   toString,
+		"toJSON":     toJSON,
+		"toPrettyJSON": func(v any) (string, error) {
+			data, err := json.MarshalIndent(v, "", "  ")
+			return string(data), err
+		},
+		"now":     time.Now,
+		"date":    date,
+		"ternary": ternary,
+	}
+}
+
+// title upper-cases the first letter of each word
+func title(s string) string {
+	prev := ' '
+	return strings.Map(func(r rune) rune {
+		defer func() { prev = r }()
+		if unicode.IsSpace(prev) {
+			return unicode.ToTitle(r)
+		}
+		return r
+	}, s)
+}
+
+// truncate shortens s to at most n runes
+func truncate(n int, s string) string {
+	runes := []rune(s)
+	if len(runes) <= n {
+		return s
+	}
+	return string(runes[:n])
+}
+
+// join joins any slice using its values' string forms
+func join(sep string, list any) string {
+	v := reflect.ValueOf(list)
+	if v.Kind() != reflect.Slice && v.Kind() != reflect.Array {
+		return toString(list)
+	}
+	parts := make([]string, v.Len())
+	for i := range parts {
+		parts[i] = toString(v.Index(i).Interface())
+	}
+	return strings.Join(parts, sep)
+}
+
+// defaultValue returns given unless it is empty. It takes the default
+// first so it reads well in pipelines: {{.Payload.name | default "anonymous"}}
+func defaultValue(def any, given ...any) any {
+	if len(given) == 0 || empty(given[0]) {
+		return def
+	}
+	return given[0]
+}
+
+// coalesce returns the first non-empty value
+func coalesce(values ...any) any {
+	for _, v := range values {
+		if !empty(v) {
+			return v
+		}
+	}
+	return nil
+}
+
+// empty reports whether v is nil or its type's zero value
+func empty(v any) bool {
+	if v == nil {
+		return true
+	}
+	rv := reflect.ValueOf(v)
+	switch rv.Kind() {
+	case reflect.Slice, reflect.Map, reflect.Array, reflect.String:
+		return rv.Len() == 0
+	case reflect.Pointer, reflect.Interface:
+		return rv.IsNil()
+	default:
+		return rv.IsZero()
+	}
+}
+
+// ternary returns yes if cond is true and no otherwise:
+// {{.Payload.paid | ternary "paid" "due"}}
+func ternary(yes, no any, cond bool) any {
+	if cond {
+		return yes
+	}
+	return no
+}
+
+func toString(v any) string {
+	if v == nil {
+		return ""
+	}
+	return fmt.Sprint(v)
+}
+
+func toJSON(v any) (string, error) {
+	data, err := json.Marshal(v)
+	return string(data), err
+}
+
+// date formats a time.Time, or an RFC 3339 string as found in payloads
+// decoded from JSON, with a Go layout
+func date(layout string, v any) (string, error) {
+	switch t := v.(type) {
+	case time.Time:
+		return t.Format(layout), nil
+	case *time.Time:
+		return t.Format(layout), nil
+	case string:
+		parsed, err := time.Parse(time.RFC3339Nano, t)
+		if err != nil {
+			return "", err
+		}
+		return parsed.Format(layout), nil
+	default:
+		return "", fmt.Errorf("date: unsupported type %T", v)
+	}
+}
@@ -0,0 +1,85 @@
+// Package render renders text and HTML templates against events, so
+// notification, webhook and audit messages can be written declaratively.
+//
+// Templates see a Data value and can use the functions in Funcs:
+//
+//	tmpl := render.MustText(`{{.Name}}: order {{.Payload.id}} for {{.Payload.total | printf "%.2f"}}`)
+//	message, err := tmpl.Render(event)
+package render
+
+import (
+	htmltemplate "html/template"
+	"io"
+	"strings"
+	"text/template"
+
+	"github.com/openframebox/goevent"
+)
+
+// Data is the value templates are executed against
+type Data struct {
+	Name     string
+	Payload  map[string]any
+	Metadata map[string]any
+}
+
+// DataFor returns the template data for an event
+func DataFor(event goevent.Event) Data {
+	return Data{Name: event.Name(), Payload: event.Payload()}
+}
+
+// Template is a parsed text or HTML template
+type Template struct {
+	execute func(w io.Writer, data any) error
+}
+
+// Text parses a text/template with Funcs available
+func Text(text string) (*Template, error) {
+	tmpl, err := template.New("event").Funcs(Funcs()).Parse(text)
+	if err != nil {
+		return nil, err
+	}
+	return &Template{execute: tmpl.Execute}, nil
+}
+
+// HTML parses an html/template with Funcs available. Payload values are
+// escaped for the context they appear in.
+func HTML(text string) (*Template, error) {
+	tmpl, err := htmltemplate.New("event").Funcs(htmltemplate.FuncMap(Funcs())).Parse(text)
+	if err != nil {
+		return nil, err
+	}
+	return &Template{execute: tmpl.Execute}, nil
+}
+
+// MustText is like Text but panics if the template does not parse
+func MustText(text string) *Template {
+	tmpl, err := Text(text)
+	if err != nil {
+		panic(err)
+	}
+	return tmpl
+}
+
+// MustHTML is like HTML but panics if the template does not parse
+func MustHTML(text string) *Template {
+	tmpl, err := HTML(text)
+	if err != nil {
+		panic(err)
+	}
+	return tmpl
+}
+
+// Render executes the template against the event
+func (t *Template) Render(event goevent.Event) (string, error) {
+	return t.RenderData(DataFor(event))
+}
+
+// RenderData executes the template against prepared data
+func (t *Template) RenderData(data Data) (string, error) {
+	var out strings.Builder
+	if err := t.execute(&out, data); err != nil {
+		return "", err
+	}
+	return out.String(), nil
+}
@@ -0,0 +1,64 @@
+package render
+
+import (
+	"testing"
+	"time"
+
+	"github.com/openframebox/goevent"
+)
+
+func orderPlaced() goevent.Event {
+	return &goevent.GenericEvent{EventName: "order.placed", Data: map[string]any{
+		"id":       42,
+		"customer": "ada lovelace",
+		"items":    []string{"book", "pen"},
+		"placed":   time.Date(2024, 3, 1, 12, 0, 0, 0, time.UTC),
+		"note":     "",
+	}}
+}
+
+func TestText(t *testing.T) {
+	tests := []struct {
+		tmpl string
+		want string
+	}{
+		{`{{.Name}} #{{.Payload.id}}`, "order.placed #42"},
+		{`{{.Payload.customer | title}}`, "Ada Lovelace"},
+		{`{{.Payload.items | join ", "}}`, "book, pen"},
+		{`{{.Payload.note | default "none"}}`, "none"},
+		{`{{.Payload.missing | default "n/a"}}`, "n/a"},
+		{`{{.Payload.placed | date "2006-01-02"}}`, "2024-03-01"},
+		{`{{.Payload.items | toJSON}}`, `["book","pen"]`},
+		{`{{.Payload.customer | upper | truncate 3}}`, "ADA"},
+		{`{{coalesce .Payload.note .Payload.customer}}`, "ada lovelace"},
+	}
+
+	for _, tt := range tests {
+		got, err := MustText(tt.tmpl).Render(orderPlaced())
+		if err != nil {
+			t.Errorf("Render(%q) failed: %v", tt.tmpl, err)
+			continue
+		}
+		if got != tt.want {
+			t.Errorf("Render(%q): expected %q, got %q", tt.tmpl, tt.want, got)
+		}
+	}
+}
+
+func TestHTML_EscapesPayload(t *testing.T) {
+	event := &goevent.GenericEvent{EventName: "comment.added", Data: map[string]any{"body": "<script>x</script>"}}
+
+	got, err := MustHTML(`<p>{{.Payload.body}}</p>`).Render(event)
+	if err != nil {
+		t.Fatalf("Render() failed: %v", err)
+	}
+	if got != "<p>&lt;script&gt;x&lt;/script&gt;</p>" {
+		t.Errorf("Expected escaped body, got %q", got)
+	}
+}
+
+func TestText_ParseError(t *testing.T) {
+	if _, err := Text("{{.Payload"); err == nil {
+		t.Error("Expected a parse error")
+	}
+}
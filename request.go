@@ -0,0 +1,137 @@
+package goevent
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+)
+
+var (
+	// ErrNoResponder is returned by Request when no Responder is
+	// registered for the event, or when none responded
+	ErrNoResponder = errors.New("goevent: no responder for request")
+
+	// ErrMultipleResponders is returned by Request when more than one
+	// Responder is registered for the event
+	ErrMultipleResponders = errors.New("goevent: multiple responders for request")
+
+	// ErrRequestTimeout is returned by Request when the context ends or
+	// the request timeout elapses before the responder answered
+	ErrRequestTimeout = errors.New("goevent: request timed out")
+
+	// ErrUnexpectedResponse is returned by RequestAs when the response
+	// value does not have the requested type
+	ErrUnexpectedResponse = errors.New("goevent: unexpected response type")
+)
+
+// Responder is a listener that answers requests. If a listener implements
+// it, Respond is called instead of OnEventAware, OnEventContext and
+// OnEvent. An event sent with Request must have exactly one Responder;
+// other listeners of the event are called as for any dispatch.
+type Responder interface {
+	Listener
+	Respond(ctx context.Context, event Event) (any, error)
+}
+
+// Response is the answer to a Request
+type Response struct {
+	Value      any
+	Responder  string // type of the listener that responded
+	DispatchID string
+}
+
+// WithRequestTimeout bounds how long Request waits for a response when
+// its context has no deadline of its own
+func WithRequestTimeout(timeout time.Duration) Option {
+	return func(ge *GoEvent) {
+		ge.requestTimeout = timeout
+	}
+}
+
+// Request dispatches event and returns the value its Responder returned.
+// It returns ErrNoResponder or ErrMultipleResponders unless exactly one
+// Responder is registered for the event, the responder's error if it
+// failed, and ErrRequestTimeout if ctx ended first. Request does not
+// wait for the event's other listeners.
+func (ge *GoEvent) Request(ctx context.Context, event Event, opts ...DispatchOption) (Response, error) {
+	switch n := ge.responders(event.Name()); {
+	case n == 0:
+		return Response{}, fmt.Errorf("%w: %s", ErrNoResponder, event.Name())
+	case n > 1:
+		return Response{}, fmt.Errorf("%w: %s has %d", ErrMultipleResponders, event.Name(), n)
+	}
+
+	if _, ok := ctx.Deadline(); !ok && ge.requestTimeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = ge.withTimeout(ctx, ge.requestTimeout)
+		defer cancel()
+	}
+
+	handle := ge.DispatchContext(ctx, event, append(opts, asRequest())...)
+	select {
+	case resp := <-handle.reply:
+		return resp, nil
+	case <-handle.Done():
+	case <-ctx.Done():
+		return Response{}, fmt.Errorf("%w: %w", ErrRequestTimeout, ctx.Err())
+	}
+
+	// The responder may have answered just before the dispatch completed
+	select {
+	case resp := <-handle.reply:
+		return resp, nil
+	default:
+	}
+	if err := handle.Err(); err != nil {
+		return Response{}, err
+	}
+	return Response{}, fmt.Errorf("%w: %s was not answered", ErrNoResponder, event.Name())
+}
+
+// RequestAs sends a Request and returns the response value as a T
+func RequestAs[T any](ctx context.Context, ge *GoEvent, event Event, opts ...DispatchOption) (T, error) {
+	var zero T
+	resp, err := ge.Request(ctx, event, opts...)
+	if err != nil {
+		return zero, err
+	}
+	value, ok := resp.Value.(T)
+	if !ok {
+		return zero, fmt.Errorf("%w: got %T, want %T", ErrUnexpectedResponse, resp.Value, zero)
+	}
+	return value, nil
+}
+
+// asRequest makes the dispatch carry a reply channel
+func asRequest() DispatchOption {
+	return func(c *dispatchConfig) {
+		c.request = true
+	}
+}
+
+// responders returns the number of Responders registered for eventName
+func (ge *GoEvent) responders(eventName string) int {
+	ge.registryMu.RLock()
+	defer ge.registryMu.RUnlock()
+	n := 0
+	for _, info := range ge.registry[eventName] {
+		if info.Responder {
+			n++
+		}
+	}
+	return n
+}
+
+// respond hands the value a Responder returned to the waiting Request.
+// Values are dropped for dispatches that are not requests.
+func respond(ctx context.Context, listener Listener, value any) {
+	handle, ok := HandleFromContext(ctx)
+	if !ok || handle.reply == nil {
+		return
+	}
+	select {
+	case handle.reply <- Response{Value: value, Responder: listenerTypeOf(listener), DispatchID: handle.id}:
+	default:
+	}
+}
@@ -0,0 +1,130 @@
+package goevent
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+// testQuote is the response of testPriceResponder
+type testQuote struct {
+	Price int
+}
+
+// testPriceResponder answers price.requested with a quote
+type testPriceResponder struct {
+	async   bool
+	err     error
+	release chan struct{} // blocks until closed, if set
+}
+
+func (r *testPriceResponder) EventName() string {
+	return "price.requested"
+}
+
+func (r *testPriceResponder) OnEvent(event Event) error {
+	panic("OnEvent called instead of Respond")
+}
+
+func (r *testPriceResponder) Options() ListenerOptions {
+	return ListenerOptions{Async: r.async}
+}
+
+func (r *testPriceResponder) Respond(ctx context.Context, event Event) (any, error) {
+	if r.release != nil {
+		<-r.release
+	}
+	if r.err != nil {
+		return nil, r.err
+	}
+	return testQuote{Price: 42}, nil
+}
+
+func TestRequest(t *testing.T) {
+	for _, async := range []bool{false, true} {
+		evt := New()
+		evt.RegisterListener(&testPriceResponder{async: async}, &testNamedListener{name: "price.requested"})
+
+		resp, err := evt.Request(context.Background(), &GenericEvent{EventName: "price.requested"})
+		if err != nil {
+			t.Fatalf("Request() (async %v) failed: %v", async, err)
+		}
+		if quote, ok := resp.Value.(testQuote); !ok || quote.Price != 42 {
+			t.Errorf("Expected a quote of 42, got %v", resp.Value)
+		}
+		if resp.Responder != "*goevent.testPriceResponder" || resp.DispatchID == "" {
+			t.Errorf("Expected the responder and dispatch ID, got %+v", resp)
+		}
+	}
+}
+
+func TestRequestAs(t *testing.T) {
+	evt := New()
+	evt.RegisterListener(&testPriceResponder{})
+
+	quote, err := RequestAs[testQuote](context.Background(), evt, &GenericEvent{EventName: "price.requested"})
+	if err != nil || quote.Price != 42 {
+		t.Fatalf("Expected a quote of 42, got %v, %v", quote, err)
+	}
+
+	if _, err := RequestAs[string](context.Background(), evt, &GenericEvent{EventName: "price.requested"}); !errors.Is(err, ErrUnexpectedResponse) {
+		t.Errorf("Expected ErrUnexpectedResponse, got %v", err)
+	}
+}
+
+func TestRequest_Responders(t *testing.T) {
+	evt := New()
+	evt.RegisterListener(&testNamedListener{name: "price.requested"})
+	if _, err := evt.Request(context.Background(), &GenericEvent{EventName: "price.requested"}); !errors.Is(err, ErrNoResponder) {
+		t.Errorf("Expected ErrNoResponder, got %v", err)
+	}
+
+	evt.RegisterListener(&testPriceResponder{}, &testPriceResponder{})
+	if _, err := evt.Request(context.Background(), &GenericEvent{EventName: "price.requested"}); !errors.Is(err, ErrMultipleResponders) {
+		t.Errorf("Expected ErrMultipleResponders, got %v", err)
+	}
+}
+
+func TestRequest_ResponderError(t *testing.T) {
+	errUnavailable := errors.New("pricing unavailable")
+	evt := New()
+	evt.RegisterListener(&testPriceResponder{err: errUnavailable})
+
+	_, err := evt.Request(context.Background(), &GenericEvent{EventName: "price.requested"})
+	if !errors.Is(err, errUnavailable) {
+		t.Errorf("Expected the responder's error, got %v", err)
+	}
+}
+
+func TestRequest_Timeout(t *testing.T) {
+	clock := newFakeClock(time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC))
+	evt := New(WithClock(clock), WithRequestTimeout(time.Second))
+	responder := &testPriceResponder{async: true, release: make(chan struct{})}
+	evt.RegisterListener(responder)
+	defer close(responder.release)
+
+	done := make(chan error)
+	go func() {
+		_, err := evt.Request(context.Background(), &GenericEvent{EventName: "price.requested"})
+		done <- err
+	}()
+
+	clock.BlockUntil(t, 1)
+	clock.Advance(time.Second)
+	if err := <-done; !errors.Is(err, ErrRequestTimeout) || !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("Expected ErrRequestTimeout, got %v", err)
+	}
+}
+
+func TestRequest_DispatchIgnoresResponse(t *testing.T) {
+	evt := New()
+	evt.RegisterListener(&testPriceResponder{})
+
+	if err := evt.Dispatch(&GenericEvent{EventName: "price.requested"}).Err(); err != nil {
+		t.Errorf("Expected a plain dispatch to a responder to succeed, got %v", err)
+	}
+	if infos := evt.Listeners()["price.requested"]; len(infos) != 1 || !infos[0].Responder {
+		t.Errorf("Expected the responder to be listed as one, got %+v", infos)
+	}
+}
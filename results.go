@@ -0,0 +1,61 @@
+package goevent
+
+import (
+	"context"
+	"time"
+)
+
+// ResultListener is a listener that returns a value, for pipelines such
+// as validation or enrichment that need the outputs of their listeners.
+// If a listener implements it, OnEventResult is called instead of
+// OnEventAware, OnEventContext and OnEvent.
+type ResultListener interface {
+	Listener
+	OnEventResult(event Event) (any, error)
+}
+
+// ListenerResult is the value a listener returned for a dispatch
+type ListenerResult struct {
+	Value    any
+	Duration time.Duration // time the successful call took
+}
+
+// Results returns the values returned by ResultListeners and Responders
+// of this dispatch, keyed by listener type. Only listeners that succeeded
+// are included, and when several listeners of one type returned a value,
+// the last one wins. Wait for the dispatch first to include async
+// listeners.
+func (dh *DispatchHandle) Results() map[string]ListenerResult {
+	dh.resultsMu.Lock()
+	defer dh.resultsMu.Unlock()
+
+	results := make(map[string]ListenerResult, len(dh.results))
+	for listenerType, result := range dh.results {
+		results[listenerType] = result
+	}
+	return results
+}
+
+// callForResult calls a listener through fn and, if it succeeds, records
+// the value it returned on the dispatch being handled in ctx
+func (ge *GoEvent) callForResult(ctx context.Context, listener Listener, fn func() (any, error)) (any, error) {
+	start := ge.clock.Now()
+	value, err := fn()
+	if err != nil {
+		return nil, err
+	}
+	if handle, ok := HandleFromContext(ctx); ok {
+		handle.recordResult(listener, ListenerResult{Value: value, Duration: ge.clock.Now().Sub(start)})
+	}
+	return value, nil
+}
+
+// recordResult stores the value a listener returned
+func (dh *DispatchHandle) recordResult(listener Listener, result ListenerResult) {
+	dh.resultsMu.Lock()
+	defer dh.resultsMu.Unlock()
+	if dh.results == nil {
+		dh.results = make(map[string]ListenerResult)
+	}
+	dh.results[listenerTypeOf(listener)] = result
+}
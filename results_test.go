@@ -0,0 +1,89 @@
+package goevent
+
+import (
+	"errors"
+	"testing"
+)
+
+// testEnricher returns an enriched copy of the event payload
+type testEnricher struct {
+	async bool
+	err   error
+}
+
+func (l *testEnricher) EventName() string {
+	return "test.event"
+}
+
+func (l *testEnricher) OnEvent(event Event) error {
+	panic("OnEvent called instead of OnEventResult")
+}
+
+func (l *testEnricher) Options() ListenerOptions {
+	return ListenerOptions{Async: l.async}
+}
+
+func (l *testEnricher) OnEventResult(event Event) (any, error) {
+	if l.err != nil {
+		return nil, l.err
+	}
+	return map[string]any{"data": event.Payload()["data"], "enriched": true}, nil
+}
+
+// testValidator reports whether the event carries data
+type testValidator struct{}
+
+func (l *testValidator) EventName() string {
+	return "test.event"
+}
+
+func (l *testValidator) OnEvent(event Event) error {
+	return nil
+}
+
+func (l *testValidator) OnEventResult(event Event) (any, error) {
+	return event.Payload()["data"] != "", nil
+}
+
+func TestResults(t *testing.T) {
+	evt := New()
+	evt.RegisterListener(&testEnricher{async: true}, &testValidator{}, &testSyncListener{})
+
+	handle := evt.Dispatch(&TestEvent{data: "order"})
+	handle.Wait()
+
+	results := handle.Results()
+	if len(results) != 2 {
+		t.Fatalf("Expected results from the 2 result listeners, got %v", results)
+	}
+	enriched, ok := results["*goevent.testEnricher"].Value.(map[string]any)
+	if !ok || enriched["enriched"] != true || enriched["data"] != "order" {
+		t.Errorf("Expected the enriched payload, got %v", results["*goevent.testEnricher"])
+	}
+	if valid := results["*goevent.testValidator"]; valid.Value != true || valid.Duration < 0 {
+		t.Errorf("Expected the validator to pass, got %+v", valid)
+	}
+}
+
+func TestResults_FailedListenerOmitted(t *testing.T) {
+	evt := New()
+	evt.RegisterListener(&testEnricher{err: errors.New("lookup failed")}, &testValidator{})
+
+	handle := evt.Dispatch(&TestEvent{data: "order"})
+	if _, ok := handle.Results()["*goevent.testEnricher"]; ok {
+		t.Error("Expected no result from the failed listener")
+	}
+	if len(handle.GetErrors()) != 1 || len(handle.Results()) != 1 {
+		t.Errorf("Expected 1 error and 1 result, got %v and %v", handle.GetErrors(), handle.Results())
+	}
+}
+
+func TestResults_IncludeResponders(t *testing.T) {
+	evt := New()
+	evt.RegisterListener(&testPriceResponder{})
+
+	handle := evt.Dispatch(&GenericEvent{EventName: "price.requested"})
+	if quote, ok := handle.Results()["*goevent.testPriceResponder"].Value.(testQuote); !ok || quote.Price != 42 {
+		t.Errorf("Expected the responder's quote as a result, got %v", handle.Results())
+	}
+}
@@ -0,0 +1,99 @@
+package goevent
+
+import (
+	"context"
+	"math/rand"
+	"time"
+)
+
+// BackoffStrategy returns the delay before a retry.
+// The first retry is attempt 1.
+type BackoffStrategy func(attempt int) time.Duration
+
+// ConstantBackoff waits the same delay before every retry
+func ConstantBackoff(delay time.Duration) BackoffStrategy {
+	return func(int) time.Duration {
+		return delay
+	}
+}
+
+// ExponentialBackoff doubles the delay before each retry, starting at
+// initial and never exceeding max
+func ExponentialBackoff(initial, max time.Duration) BackoffStrategy {
+	return func(attempt int) time.Duration {
+		delay := initial
+		for i := 1; i < attempt && delay < max; i++ {
+			delay *= 2
+		}
+		if delay > max {
+			delay = max
+		}
+		return delay
+	}
+}
+
+// RetryPolicy configures automatic retries of a failing listener
+type RetryPolicy struct {
+	// MaxAttempts is the total number of attempts including the first.
+	// Values of 1 or less disable retries.
+	MaxAttempts int
+
+	// Backoff determines the delay between attempts. Nil retries immediately.
+	Backoff BackoffStrategy
+
+	// Jitter randomizes each delay by up to this fraction (0.0 to 1.0)
+	Jitter float64
+}
+
+// delay returns the jittered delay before the given retry attempt
+func (p RetryPolicy) delay(attempt int) time.Duration {
+	if p.Backoff == nil {
+		return 0
+	}
+	delay := p.Backoff(attempt)
+	if p.Jitter > 0 {
+		delay += time.Duration(float64(delay) * p.Jitter * (rand.Float64()*2 - 1))
+	}
+	return delay
+}
+
+// invokeWithRetry calls the listener until it succeeds, the policy is
+// exhausted, or ctx is done. Panics are never retried. A timeout greater
+// than zero bounds each attempt.
+// It returns the number of attempts made along with the last error.
+func (ge *GoEvent) invokeWithRetry(ctx context.Context, listener Listener, event Event, policy RetryPolicy, timeout time.Duration) (int, error) {
+	attempt := 1
+	for {
+		err := ge.invokeWithTimeout(ctx, listener, event, timeout)
+		if err == nil || attempt >= policy.MaxAttempts {
+			return attempt, err
+		}
+		if _, ok := err.(*PanicError); ok {
+			return attempt, err
+		}
+
+		select {
+		case <-ctx.Done():
+			return attempt, err
+		case <-ge.clock.After(policy.delay(attempt)):
+		}
+		attempt++
+	}
+}
+
+// invokeWithTimeout calls the listener with a context that expires after
+// timeout. A listener still running at that point is not interrupted, but
+// unless it returns an error of its own the call fails with
+// context.DeadlineExceeded.
+func (ge *GoEvent) invokeWithTimeout(ctx context.Context, listener Listener, event Event, timeout time.Duration) error {
+	if timeout <= 0 {
+		return ge.invoke(ctx, listener, event)
+	}
+
+	ctx, cancel := ge.withTimeout(ctx, timeout)
+	defer cancel()
+	if err := ge.invoke(ctx, listener, event); err != nil {
+		return err
+	}
+	return ctx.Err()
+}
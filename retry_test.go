@@ -0,0 +1,113 @@
+package goevent
+
+import (
+	"errors"
+	"sync"
+	"testing"
+	"time"
+)
+
+type testFlakyListener struct {
+	mu       sync.Mutex
+	failures int
+	calls    int
+	policy   RetryPolicy
+}
+
+func (l *testFlakyListener) EventName() string {
+	return "test.event"
+}
+
+func (l *testFlakyListener) OnEvent(event Event) error {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.calls++
+	if l.calls <= l.failures {
+		return errors.New("transient failure")
+	}
+	return nil
+}
+
+func (l *testFlakyListener) Options() ListenerOptions {
+	return ListenerOptions{Retry: l.policy}
+}
+
+func TestRetry_SucceedsAfterTransientFailures(t *testing.T) {
+	evt := New()
+	listener := &testFlakyListener{
+		failures: 2,
+		policy:   RetryPolicy{MaxAttempts: 3, Backoff: ConstantBackoff(time.Millisecond)},
+	}
+	evt.RegisterListener(listener)
+
+	handle := evt.Dispatch(&TestEvent{data: "retry"})
+
+	if errs := handle.GetErrors(); len(errs) != 0 {
+		t.Errorf("Expected no errors, got %v", errs)
+	}
+	if listener.calls != 3 {
+		t.Errorf("Expected 3 calls, got %d", listener.calls)
+	}
+}
+
+func TestRetry_RecordsAttemptsWhenExhausted(t *testing.T) {
+	evt := New()
+	listener := &testFlakyListener{
+		failures: 5,
+		policy:   RetryPolicy{MaxAttempts: 3},
+	}
+	evt.RegisterListener(listener)
+
+	handle := evt.Dispatch(&TestEvent{data: "retry"})
+
+	errs := handle.GetErrors()
+	if len(errs) != 1 {
+		t.Fatalf("Expected 1 error, got %d", len(errs))
+	}
+	if errs[0].Attempts != 3 {
+		t.Errorf("Expected 3 attempts, got %d", errs[0].Attempts)
+	}
+	if listener.calls != 3 {
+		t.Errorf("Expected 3 calls, got %d", listener.calls)
+	}
+}
+
+func TestRetry_DoesNotRetryPanics(t *testing.T) {
+	evt := New()
+	calls := 0
+	evt.Use(func(next HandlerFunc) HandlerFunc {
+		calls++
+		return next
+	})
+	evt.RegisterListener(&testPanicListener{retry: RetryPolicy{MaxAttempts: 3}})
+
+	handle := evt.Dispatch(&TestEvent{data: "panic"})
+
+	if calls != 1 {
+		t.Errorf("Expected 1 attempt, got %d", calls)
+	}
+	if len(handle.GetErrors()) != 1 {
+		t.Errorf("Expected 1 error, got %d", len(handle.GetErrors()))
+	}
+}
+
+func TestExponentialBackoff(t *testing.T) {
+	backoff := ExponentialBackoff(10*time.Millisecond, 50*time.Millisecond)
+
+	tests := []struct {
+		attempt  int
+		expected time.Duration
+	}{
+		{1, 10 * time.Millisecond},
+		{2, 20 * time.Millisecond},
+		{3, 40 * time.Millisecond},
+		{4, 50 * time.Millisecond},
+		{10, 50 * time.Millisecond},
+	}
+
+	for _, tt := range tests {
+		if got := backoff(tt.attempt); got != tt.expected {
+			t.Errorf("Attempt %d: expected %v, got %v", tt.attempt, tt.expected, got)
+		}
+	}
+}
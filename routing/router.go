@@ -0,0 +1,310 @@
+// Package routing directs events to listener groups or alternate event
+// names from one table of content-based rules, instead of spreading
+// routing decisions across listeners.
+//
+// A Rule matches event names with a pattern and payloads with a script
+// expression. Rules are tried in order and the first match wins:
+//
+//	[
+//	  {"match": "order.*", "when": "payload.total > 1000", "group": "review"},
+//	  {"match": "order.*", "when": "payload.region == 'eu'", "emit": "order.eu"},
+//	  {"match": "order.*", "group": "fulfilment"}
+//	]
+//
+//	router := routing.New(bus)
+//	router.Group("review", &FraudCheck{}, &ManagerApproval{})
+//	router.Group("fulfilment", &Warehouse{})
+//	if err := router.LoadRules(data); err != nil {
+//		return err
+//	}
+//	router.Listen("order.placed", "order.updated")
+//
+// A router adds its table to the bus state, so goevent.GoEvent.ExportState
+// captures rules added at runtime and ImportState restores them. Give
+// each router on a bus its own name with WithName.
+//
+// Patterns use path.Match syntax, so "order.*" matches "order.placed".
+// See script.Compile for the expression language; expressions see two
+// variables: name, the event name, and payload.
+package routing
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"path"
+	"sync"
+
+	"github.com/openframebox/goevent"
+	"github.com/openframebox/goevent/script"
+)
+
+// ErrUnknownGroup is returned when a rule routes to a group that has no
+// listeners
+var ErrUnknownGroup = errors.New("routing: unknown listener group")
+
+// Rule routes the events it matches to a listener group, an alternate
+// event name, or both
+type Rule struct {
+	Match string `json:"match"`           // event name pattern
+	When  string `json:"when,omitempty"`  // condition; empty matches every event
+	Group string `json:"group,omitempty"` // listener group to deliver the event to
+	Emit  string `json:"emit,omitempty"`  // name to dispatch the payload under
+}
+
+type compiledRule struct {
+	Rule
+	when *script.Expr
+}
+
+// Option configures a Router
+type Option func(*Router)
+
+// WithAsync makes the router's listeners async, so groups run and routed
+// events are dispatched outside of the original dispatch's listener calls
+func WithAsync() Option {
+	return func(r *Router) {
+		r.async = true
+	}
+}
+
+// WithName sets the name the router's table is kept under in the bus
+// state. It defaults to "routing".
+func WithName(name string) Option {
+	return func(r *Router) {
+		r.name = name
+	}
+}
+
+// Router delivers events to the target of the first rule they match.
+// Events that match no rule are not routed.
+type Router struct {
+	bus   *goevent.GoEvent
+	async bool
+	name  string
+
+	mu     sync.RWMutex
+	rules  []compiledRule
+	groups map[string][]goevent.Listener
+}
+
+// New creates a router that dispatches routed events on bus and
+// registers its table in the bus state
+func New(bus *goevent.GoEvent, opts ...Option) *Router {
+	r := &Router{bus: bus, name: "routing", groups: make(map[string][]goevent.Listener)}
+	for _, opt := range opts {
+		opt(r)
+	}
+	bus.RegisterRules(r.name, r)
+	return r
+}
+
+// Group adds listeners to the named group. Group listeners are not
+// registered on the bus: the router calls them, whatever their
+// EventName, for the events routed to the group.
+func (r *Router) Group(name string, listeners ...goevent.Listener) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.groups[name] = append(r.groups[name], listeners...)
+}
+
+// AddRule compiles rule and appends it to the routing table
+func (r *Router) AddRule(rule Rule) error {
+	compiled, err := compile(rule)
+	if err != nil {
+		return err
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.rules = append(r.rules, compiled)
+	return nil
+}
+
+// compile checks rule and compiles its condition
+func compile(rule Rule) (compiledRule, error) {
+	if rule.Match == "" {
+		return compiledRule{}, fmt.Errorf("routing: rule needs a match pattern")
+	}
+	if rule.Group == "" && rule.Emit == "" {
+		return compiledRule{}, fmt.Errorf("routing: rule %q needs a group or emit", rule.Match)
+	}
+	if _, err := path.Match(rule.Match, ""); err != nil {
+		return compiledRule{}, fmt.Errorf("routing: rule %q: %w", rule.Match, err)
+	}
+
+	compiled := compiledRule{Rule: rule}
+	if rule.When != "" {
+		when, err := script.Compile(rule.When)
+		if err != nil {
+			return compiledRule{}, fmt.Errorf("routing: rule %q: %w", rule.Match, err)
+		}
+		compiled.when = when
+	}
+	return compiled, nil
+}
+
+// compileAll decodes and compiles a JSON array of rules
+func compileAll(data []byte) ([]compiledRule, error) {
+	var rules []Rule
+	if err := json.Unmarshal(data, &rules); err != nil {
+		return nil, fmt.Errorf("routing: decoding rules: %w", err)
+	}
+
+	compiled := make([]compiledRule, len(rules))
+	for i, rule := range rules {
+		var err error
+		if compiled[i], err = compile(rule); err != nil {
+			return nil, fmt.Errorf("routing: rule %d: %w", i, err)
+		}
+	}
+	return compiled, nil
+}
+
+// LoadRules appends a JSON array of rules to the routing table. No rule
+// is added if any of them is invalid.
+func (r *Router) LoadRules(data []byte) error {
+	compiled, err := compileAll(data)
+	if err != nil {
+		return err
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.rules = append(r.rules, compiled...)
+	return nil
+}
+
+// ExportRules returns the routing table as a JSON array of rules, for
+// goevent.GoEvent.ExportState
+func (r *Router) ExportRules() (json.RawMessage, error) {
+	return json.Marshal(r.Rules())
+}
+
+// ImportRules replaces the routing table with a JSON array of rules, for
+// goevent.GoEvent.ImportState. The table is unchanged if any rule is
+// invalid.
+func (r *Router) ImportRules(data json.RawMessage) error {
+	compiled, err := compileAll(data)
+	if err != nil {
+		return err
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.rules = compiled
+	return nil
+}
+
+// Rules returns the routing table in the order rules are tried
+func (r *Router) Rules() []Rule {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	rules := make([]Rule, len(r.rules))
+	for i, rule := range r.rules {
+		rules[i] = rule.Rule
+	}
+	return rules
+}
+
+// Listen registers a listener on the bus for each of eventNames that
+// routes the event. Only listened events are routed, whatever the rules
+// match.
+func (r *Router) Listen(eventNames ...string) {
+	for _, name := range eventNames {
+		r.bus.RegisterListener(&listener{router: r, eventName: name})
+	}
+}
+
+// Resolve returns the first rule event matches
+func (r *Router) Resolve(event goevent.Event) (Rule, bool, error) {
+	rule, ok, err := r.resolve(event)
+	return rule.Rule, ok, err
+}
+
+func (r *Router) resolve(event goevent.Event) (compiledRule, bool, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	var env map[string]any
+	for _, rule := range r.rules {
+		if ok, _ := path.Match(rule.Match, event.Name()); !ok {
+			continue
+		}
+		if rule.when != nil {
+			if env == nil {
+				env = map[string]any{"name": event.Name(), "payload": event.Payload()}
+			}
+			match, err := rule.when.Bool(env)
+			if err != nil {
+				return compiledRule{}, false, fmt.Errorf("routing: rule %q: %w", rule.Match, err)
+			}
+			if !match {
+				continue
+			}
+		}
+		return rule, true, nil
+	}
+	return compiledRule{}, false, nil
+}
+
+// Route delivers event to the target of the first rule it matches. Group
+// listeners are called in the order they were added, and their errors
+// are joined. The routed event is dispatched with ctx, so it inherits
+// the original dispatch's priority, deadline and metadata.
+func (r *Router) Route(ctx context.Context, event goevent.Event) error {
+	rule, ok, err := r.resolve(event)
+	if err != nil || !ok {
+		return err
+	}
+
+	var errs []error
+	if rule.Group != "" {
+		r.mu.RLock()
+		listeners, found := r.groups[rule.Group]
+		r.mu.RUnlock()
+		if !found {
+			return fmt.Errorf("%w: %s", ErrUnknownGroup, rule.Group)
+		}
+		for _, l := range listeners {
+			if err := call(ctx, l, event); err != nil {
+				errs = append(errs, fmt.Errorf("routing: group %s: %T: %w", rule.Group, l, err))
+			}
+		}
+	}
+	if rule.Emit != "" {
+		r.bus.DispatchContext(ctx, &goevent.GenericEvent{EventName: rule.Emit, Data: event.Payload()})
+	}
+	return errors.Join(errs...)
+}
+
+// call hands event to a group listener
+func call(ctx context.Context, l goevent.Listener, event goevent.Event) error {
+	if cl, ok := l.(goevent.ContextListener); ok {
+		return cl.OnEventContext(ctx, event)
+	}
+	return l.OnEvent(event)
+}
+
+// listener routes the events of one name
+type listener struct {
+	router    *Router
+	eventName string
+}
+
+func (l *listener) EventName() string {
+	return l.eventName
+}
+
+func (l *listener) Options() goevent.ListenerOptions {
+	return goevent.ListenerOptions{Async: l.router.async}
+}
+
+func (l *listener) OnEvent(event goevent.Event) error {
+	return l.router.Route(context.Background(), event)
+}
+
+func (l *listener) OnEventContext(ctx context.Context, event goevent.Event) error {
+	return l.router.Route(ctx, event)
+}
@@ -0,0 +1,147 @@
+package routing
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+
+	"github.com/openframebox/goevent"
+)
+
+type recorder struct {
+	name string
+
+	mu     sync.Mutex
+	events []string
+}
+
+func (r *recorder) EventName() string {
+	return r.name
+}
+
+func (r *recorder) OnEvent(event goevent.Event) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.events = append(r.events, event.Name())
+	return nil
+}
+
+func (r *recorder) count() int {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return len(r.events)
+}
+
+const rules = `[
+  {"match": "order.*", "when": "payload.total > 1000", "group": "review"},
+  {"match": "order.*", "when": "payload.region == 'eu'", "emit": "order.eu"},
+  {"match": "order.*", "group": "fulfilment"}
+]`
+
+func order(total float64, region string) *goevent.GenericEvent {
+	return &goevent.GenericEvent{EventName: "order.placed", Data: map[string]any{"total": total, "region": region}}
+}
+
+func TestRouter(t *testing.T) {
+	bus := goevent.New()
+	review, fulfilment, eu := &recorder{}, &recorder{}, &recorder{name: "order.eu"}
+	bus.RegisterListener(eu)
+
+	router := New(bus)
+	router.Group("review", review)
+	router.Group("fulfilment", fulfilment)
+	if err := router.LoadRules([]byte(rules)); err != nil {
+		t.Fatalf("LoadRules() failed: %v", err)
+	}
+	router.Listen("order.placed")
+
+	for _, event := range []goevent.Event{order(5000, "eu"), order(50, "eu"), order(50, "us")} {
+		if err := bus.Dispatch(event).WaitTree(context.Background()); err != nil {
+			t.Fatalf("WaitTree() failed: %v", err)
+		}
+	}
+
+	if review.count() != 1 || eu.count() != 1 || fulfilment.count() != 1 {
+		t.Errorf("Expected one event per target, got review %d, eu %d, fulfilment %d", review.count(), eu.count(), fulfilment.count())
+	}
+	if review.events[0] != "order.placed" {
+		t.Errorf("Expected the group to get the original event, got %v", review.events)
+	}
+}
+
+func TestRouter_NoMatch(t *testing.T) {
+	router := New(goevent.New())
+	if err := router.AddRule(Rule{Match: "user.*", Group: "users"}); err != nil {
+		t.Fatalf("AddRule() failed: %v", err)
+	}
+
+	if _, ok, err := router.Resolve(order(1, "eu")); ok || err != nil {
+		t.Errorf("Expected no route, got %v, %v", ok, err)
+	}
+	if err := router.Route(context.Background(), order(1, "eu")); err != nil {
+		t.Errorf("Expected an unrouted event to be ignored, got %v", err)
+	}
+}
+
+func TestRouter_UnknownGroup(t *testing.T) {
+	bus := goevent.New()
+	router := New(bus)
+	if err := router.AddRule(Rule{Match: "order.placed", Group: "missing"}); err != nil {
+		t.Fatalf("AddRule() failed: %v", err)
+	}
+	router.Listen("order.placed")
+
+	if err := bus.Dispatch(order(1, "eu")).Err(); !errors.Is(err, ErrUnknownGroup) {
+		t.Errorf("Expected ErrUnknownGroup, got %v", err)
+	}
+}
+
+func TestRouter_InvalidRules(t *testing.T) {
+	router := New(goevent.New())
+	for _, rule := range []Rule{
+		{Group: "g"},
+		{Match: "order.*"},
+		{Match: "[", Group: "g"},
+		{Match: "order.*", When: "payload.total >", Group: "g"},
+	} {
+		if err := router.AddRule(rule); err == nil {
+			t.Errorf("Expected %+v to be rejected", rule)
+		}
+	}
+
+	if err := router.LoadRules([]byte(`[{"match": "a", "group": "g"}, {"match": "b"}]`)); err == nil {
+		t.Error("Expected LoadRules() to reject an invalid rule")
+	}
+	if rules := router.Rules(); len(rules) != 0 {
+		t.Errorf("Expected no rules after failed loads, got %v", rules)
+	}
+}
+
+func TestRouter_StateRoundTrip(t *testing.T) {
+	source := New(goevent.New())
+	if err := source.LoadRules([]byte(rules)); err != nil {
+		t.Fatalf("LoadRules() failed: %v", err)
+	}
+	data, err := source.bus.ExportState()
+	if err != nil {
+		t.Fatalf("ExportState() failed: %v", err)
+	}
+
+	bus := goevent.New()
+	target := New(bus)
+	target.AddRule(Rule{Match: "user.*", Group: "users"})
+	if err := bus.ImportState(data); err != nil {
+		t.Fatalf("ImportState() failed: %v", err)
+	}
+	if got := target.Rules(); len(got) != 3 || got[0].When != "payload.total > 1000" || got[2].Group != "fulfilment" {
+		t.Errorf("Expected the exported table to replace the rules, got %+v", got)
+	}
+
+	if err := bus.ImportState([]byte(`{"version": 1, "rules": {"routing": [{"match": "order.*"}]}}`)); err == nil {
+		t.Fatal("Expected ImportState() to reject an invalid rule")
+	}
+	if got := target.Rules(); len(got) != 3 {
+		t.Errorf("Expected a rejected table to leave the rules unchanged, got %+v", got)
+	}
+}
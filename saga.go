@@ -0,0 +1,230 @@
+package goevent
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sync"
+)
+
+// ErrSagaStateNotFound is returned by a SagaStore when no state is stored
+// for a saga instance
+var ErrSagaStateNotFound = errors.New("goevent: saga state not found")
+
+// Saga coordinates a long-running flow, such as order → payment →
+// shipment, across the events of one correlation ID. Each instance keeps
+// a state of type S that is loaded before and saved after every event it
+// handles.
+type Saga[S any] interface {
+	// SagaName names the saga; it keys its stored state
+	SagaName() string
+	// EventNames returns the events the saga reacts to
+	EventNames() []string
+	// Handle reacts to an event, updating sc.State and dispatching
+	// follow-up or compensating events through sc
+	Handle(sc *SagaContext[S], event Event) error
+}
+
+// SagaCorrelator is implemented by sagas that derive the correlation ID
+// of an event themselves, for flows that span several dispatch trees. By
+// default the correlation ID of the dispatch's metadata is used.
+type SagaCorrelator interface {
+	CorrelationID(ctx context.Context, event Event) string
+}
+
+// SagaContext is passed to Saga.Handle
+type SagaContext[S any] struct {
+	context.Context
+
+	// CorrelationID identifies the saga instance
+	CorrelationID string
+
+	// State is the instance's state, the zero value when IsNew
+	State S
+
+	// IsNew reports whether no state was stored for the instance yet
+	IsNew bool
+
+	completed bool
+	queued    []sagaDispatch
+}
+
+type sagaDispatch struct {
+	event Event
+	opts  []DispatchOption
+}
+
+// Dispatch queues a follow-up or compensating event. Queued events are
+// dispatched as children of the event being handled, carrying the saga's
+// correlation ID, once Handle returned and the state was saved; they are
+// dropped if Handle fails.
+func (sc *SagaContext[S]) Dispatch(event Event, opts ...DispatchOption) {
+	sc.queued = append(sc.queued, sagaDispatch{event: event, opts: opts})
+}
+
+// Complete ends the saga instance. Its state is deleted once Handle
+// returned without error.
+func (sc *SagaContext[S]) Complete() {
+	sc.completed = true
+}
+
+// SagaStore persists the state of saga instances
+type SagaStore interface {
+	// Load returns the state of a saga instance, or ErrSagaStateNotFound
+	Load(ctx context.Context, saga, correlationID string) ([]byte, error)
+	// Save stores the state of a saga instance
+	Save(ctx context.Context, saga, correlationID string, state []byte) error
+	// Delete removes the state of a saga instance
+	Delete(ctx context.Context, saga, correlationID string) error
+}
+
+// WithSagaStore replaces the default in-memory saga state storage
+func WithSagaStore(store SagaStore) Option {
+	return func(ge *GoEvent) {
+		ge.sagaStore = store
+	}
+}
+
+// RegisterSaga registers a saga for the events it names. Its state is
+// stored as JSON in the bus's SagaStore. Events of one instance are
+// handled one at a time; if Handle fails, the state is left as it was
+// and the error is recorded like any listener error. Options are read
+// from Options() if the saga provides it.
+func RegisterSaga[S any](ge *GoEvent, saga Saga[S]) {
+	ge.RegisterListener(&sagaListener[S]{ge: ge, saga: saga})
+}
+
+// sagaListener loads, handles and saves saga instances
+type sagaListener[S any] struct {
+	ge    *GoEvent
+	saga  Saga[S]
+	locks [dispatcherShards]sync.Mutex // serializes instances by correlation ID
+}
+
+func (l *sagaListener[S]) EventName() string {
+	return l.saga.SagaName()
+}
+
+func (l *sagaListener[S]) EventNames() []string {
+	return l.saga.EventNames()
+}
+
+func (l *sagaListener[S]) Options() ListenerOptions {
+	if withOpts, ok := l.saga.(interface{ Options() ListenerOptions }); ok {
+		return withOpts.Options()
+	}
+	return ListenerOptions{}
+}
+
+func (l *sagaListener[S]) listenerType() string {
+	return fmt.Sprintf("%T", l.saga)
+}
+
+func (l *sagaListener[S]) OnEvent(event Event) error {
+	return l.OnEventContext(context.Background(), event)
+}
+
+func (l *sagaListener[S]) OnEventContext(ctx context.Context, event Event) error {
+	correlationID := l.correlationID(ctx, event)
+	if correlationID == "" {
+		return fmt.Errorf("goevent: saga %s: no correlation ID for %s", l.saga.SagaName(), event.Name())
+	}
+
+	// Queued events are dispatched outside of the instance lock, so the
+	// saga may react to them, or to their consequences, synchronously
+	sc := &SagaContext[S]{Context: ctx, CorrelationID: correlationID}
+	if err := l.handle(sc, event); err != nil {
+		return err
+	}
+	md := WithMetadata(map[string]string{MetadataCorrelationID: correlationID})
+	for _, queued := range sc.queued {
+		l.ge.DispatchContext(ctx, queued.event, append([]DispatchOption{md}, queued.opts...)...)
+	}
+	return nil
+}
+
+// handle runs Handle for one instance between loading and saving its state
+func (l *sagaListener[S]) handle(sc *SagaContext[S], event Event) error {
+	ctx, correlationID := sc.Context, sc.CorrelationID
+	lock := &l.locks[fnv32(correlationID)%dispatcherShards]
+	lock.Lock()
+	defer lock.Unlock()
+
+	name := l.saga.SagaName()
+	data, err := l.ge.sagaStore.Load(ctx, name, correlationID)
+	switch {
+	case errors.Is(err, ErrSagaStateNotFound):
+		sc.IsNew = true
+	case err != nil:
+		return fmt.Errorf("goevent: saga %s: loading %s: %w", name, correlationID, err)
+	default:
+		if err := json.Unmarshal(data, &sc.State); err != nil {
+			return fmt.Errorf("goevent: saga %s: decoding %s: %w", name, correlationID, err)
+		}
+	}
+
+	if err := l.saga.Handle(sc, event); err != nil {
+		return err
+	}
+
+	if sc.completed {
+		if sc.IsNew {
+			return nil
+		}
+		return l.ge.sagaStore.Delete(ctx, name, correlationID)
+	}
+	data, err = json.Marshal(sc.State)
+	if err != nil {
+		return fmt.Errorf("goevent: saga %s: encoding %s: %w", name, correlationID, err)
+	}
+	return l.ge.sagaStore.Save(ctx, name, correlationID, data)
+}
+
+// correlationID returns the saga instance an event belongs to
+func (l *sagaListener[S]) correlationID(ctx context.Context, event Event) string {
+	if correlator, ok := l.saga.(SagaCorrelator); ok {
+		return correlator.CorrelationID(ctx, event)
+	}
+	md, _ := MetadataFromContext(ctx)
+	return md.CorrelationID
+}
+
+// MemorySagaStore is a SagaStore kept in memory, useful for tests and
+// development
+type MemorySagaStore struct {
+	mu     sync.Mutex
+	states map[string][]byte
+}
+
+// NewMemorySagaStore creates an empty in-memory saga store
+func NewMemorySagaStore() *MemorySagaStore {
+	return &MemorySagaStore{states: make(map[string][]byte)}
+}
+
+// Load returns the state of a saga instance, or ErrSagaStateNotFound
+func (s *MemorySagaStore) Load(ctx context.Context, saga, correlationID string) ([]byte, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	state, ok := s.states[saga+"/"+correlationID]
+	if !ok {
+		return nil, ErrSagaStateNotFound
+	}
+	return state, nil
+}
+
+// Save stores the state of a saga instance
+func (s *MemorySagaStore) Save(ctx context.Context, saga, correlationID string, state []byte) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.states[saga+"/"+correlationID] = append([]byte(nil), state...)
+	return nil
+}
+
+// Delete removes the state of a saga instance
+func (s *MemorySagaStore) Delete(ctx context.Context, saga, correlationID string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	delete(s.states, saga+"/"+correlationID)
+	return nil
+}
@@ -0,0 +1,154 @@
+package goevent
+
+import (
+	"context"
+	"errors"
+	"testing"
+)
+
+// testOrderState is the state of testOrderSaga
+type testOrderState struct {
+	Total float64
+	Steps []string
+}
+
+// testOrderSaga takes an order through payment, cancelling it when the
+// payment fails
+type testOrderSaga struct {
+	failOn string
+}
+
+func (s *testOrderSaga) SagaName() string {
+	return "order"
+}
+
+func (s *testOrderSaga) EventNames() []string {
+	return []string{"order.placed", "payment.succeeded", "payment.failed"}
+}
+
+func (s *testOrderSaga) Handle(sc *SagaContext[testOrderState], event Event) error {
+	if event.Name() == s.failOn {
+		return errors.New("saga failed")
+	}
+	sc.State.Steps = append(sc.State.Steps, event.Name())
+
+	switch event.Name() {
+	case "order.placed":
+		sc.State.Total, _ = event.Payload()["total"].(float64)
+		sc.Dispatch(&GenericEvent{EventName: "payment.requested", Data: map[string]any{"amount": sc.State.Total}})
+	case "payment.failed":
+		sc.Dispatch(&GenericEvent{EventName: "order.cancelled"})
+		sc.Complete()
+	case "payment.succeeded":
+		sc.Dispatch(&GenericEvent{EventName: "shipment.requested"})
+	}
+	return nil
+}
+
+// testPaymentListener answers payment requests synchronously
+type testPaymentListener struct {
+	bus *GoEvent
+}
+
+func (l *testPaymentListener) EventName() string {
+	return "payment.requested"
+}
+
+func (l *testPaymentListener) OnEvent(event Event) error {
+	return nil
+}
+
+func (l *testPaymentListener) OnEventContext(ctx context.Context, event Event) error {
+	result := "payment.succeeded"
+	if event.Payload()["amount"].(float64) > 100 {
+		result = "payment.failed"
+	}
+	l.bus.DispatchContext(ctx, &GenericEvent{EventName: result})
+	return nil
+}
+
+func TestSaga(t *testing.T) {
+	store := NewMemorySagaStore()
+	evt := New(WithSagaStore(store))
+	RegisterSaga[testOrderState](evt, &testOrderSaga{})
+	evt.RegisterListener(&testPaymentListener{bus: evt})
+	cancelled := &testNameRecorder{name: "order.cancelled"}
+	shipped := &testNameRecorder{name: "shipment.requested"}
+	evt.RegisterListener(cancelled, shipped)
+
+	paid := dispatchWithin(t, evt, &GenericEvent{EventName: "order.placed", Data: map[string]any{"total": 50.0}})
+	failed := dispatchWithin(t, evt, &GenericEvent{EventName: "order.placed", Data: map[string]any{"total": 500.0}})
+	for _, handle := range []*DispatchHandle{paid, failed} {
+		if err := handle.WaitTree(context.Background()); err != nil {
+			t.Fatalf("WaitTree() failed: %v", err)
+		}
+	}
+
+	if len(shipped.received) != 1 || len(cancelled.received) != 1 {
+		t.Errorf("Expected one shipment and one cancellation, got %d and %d", len(shipped.received), len(cancelled.received))
+	}
+
+	data, err := store.Load(context.Background(), "order", paid.DispatchID())
+	if err != nil {
+		t.Fatalf("Expected the paid order's state, got %v", err)
+	}
+	if string(data) != `{"Total":50,"Steps":["order.placed","payment.succeeded"]}` {
+		t.Errorf("Unexpected state %s", data)
+	}
+	if _, err := store.Load(context.Background(), "order", failed.DispatchID()); !errors.Is(err, ErrSagaStateNotFound) {
+		t.Errorf("Expected the completed saga's state to be deleted, got %v", err)
+	}
+}
+
+func TestSaga_HandleErrorKeepsState(t *testing.T) {
+	store := NewMemorySagaStore()
+	evt := New(WithSagaStore(store))
+	RegisterSaga[testOrderState](evt, &testOrderSaga{failOn: "payment.succeeded"})
+	evt.RegisterListener(&testPaymentListener{bus: evt})
+
+	handle := evt.Dispatch(&GenericEvent{EventName: "order.placed", Data: map[string]any{"total": 50.0}})
+	if err := handle.WaitTree(context.Background()); err != nil {
+		t.Fatalf("WaitTree() failed: %v", err)
+	}
+
+	data, err := store.Load(context.Background(), "order", handle.DispatchID())
+	if err != nil || string(data) != `{"Total":50,"Steps":["order.placed"]}` {
+		t.Errorf("Expected the state before the failure, got %s, %v", data, err)
+	}
+	if errs := evt.GetErrors(); len(errs) != 1 || errs[0].ListenerType != "*goevent.testOrderSaga" {
+		t.Errorf("Expected the error recorded for the saga, got %v", errs)
+	}
+}
+
+// testCorrelatedSaga counts events in a single instance
+type testCorrelatedSaga struct{}
+
+func (s *testCorrelatedSaga) SagaName() string {
+	return "counter"
+}
+
+func (s *testCorrelatedSaga) EventNames() []string {
+	return []string{"test.event"}
+}
+
+func (s *testCorrelatedSaga) CorrelationID(ctx context.Context, event Event) string {
+	return "fixed"
+}
+
+func (s *testCorrelatedSaga) Handle(sc *SagaContext[int], event Event) error {
+	sc.State++
+	return nil
+}
+
+func TestSaga_Correlator(t *testing.T) {
+	store := NewMemorySagaStore()
+	evt := New(WithSagaStore(store))
+	RegisterSaga[int](evt, &testCorrelatedSaga{})
+
+	evt.Dispatch(&TestEvent{})
+	evt.Dispatch(&TestEvent{})
+
+	if data, err := store.Load(context.Background(), "counter", "fixed"); err != nil || string(data) != "2" {
+		t.Errorf("Expected both dispatches counted by one instance, got %s, %v", data, err)
+	}
+}
@@ -0,0 +1,265 @@
+package goevent
+
+import (
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+)
+
+// Scheduler dispatches generated events on cron schedules. Every bus has
+// one, reachable through GoEvent.Scheduler; schedules only fire between
+// Start and Stop.
+type Scheduler struct {
+	ge *GoEvent
+
+	mu   sync.Mutex
+	jobs []*ScheduledJob
+	stop chan struct{} // nil while stopped
+	wake chan struct{}
+	done chan struct{}
+}
+
+// ScheduledJob is a schedule registered with Schedule
+type ScheduledJob struct {
+	spec      string
+	schedule  *cronSchedule
+	factory   func(at time.Time) Event
+	scheduler *Scheduler
+	location  *time.Location // nil for the clock's location
+	calendar  Calendar
+	next      time.Time // guarded by scheduler.mu
+}
+
+// ScheduleOption configures a schedule
+type ScheduleOption func(*ScheduledJob)
+
+// InLocation evaluates the schedule on the wall clock of loc, so
+// "0 9 * * *" fires at 09:00 local time across DST changes. It takes
+// precedence over a CRON_TZ prefix in the spec.
+func InLocation(loc *time.Location) ScheduleOption {
+	return func(job *ScheduledJob) {
+		job.location = loc
+	}
+}
+
+// WithCalendar skips runs on the days the calendar excludes
+func WithCalendar(calendar Calendar) ScheduleOption {
+	return func(job *ScheduledJob) {
+		job.calendar = calendar
+	}
+}
+
+// Schedule registers factory to run on the cron expression spec. See
+// Scheduler.Schedule.
+func (ge *GoEvent) Schedule(spec string, factory func(at time.Time) Event, opts ...ScheduleOption) (*ScheduledJob, error) {
+	return ge.scheduler.Schedule(spec, factory, opts...)
+}
+
+// Scheduler returns the bus scheduler
+func (ge *GoEvent) Scheduler() *Scheduler {
+	return ge.scheduler
+}
+
+// Schedule registers factory to run on the cron expression spec, dispatching
+// the event it returns. A nil event skips that run. The factory receives the
+// scheduled time of the run.
+//
+// spec has five fields: minute, hour, day of month, month and day of week,
+// each accepting *, lists, ranges and steps, with month and day names such
+// as "jan" and "mon". The descriptors @hourly, @daily, @weekly, @monthly
+// and @yearly are also accepted.
+//
+// Times are evaluated on the wall clock of the clock's location, unless
+// the spec starts with a time zone such as "CRON_TZ=Europe/Berlin " or
+// InLocation is given. Times skipped when clocks spring forward fire at
+// the transition, and times repeated when they fall back fire once.
+//
+// Runs missed while the scheduler is stopped or busy are not caught up;
+// a job fires once and moves on to its next time.
+func (s *Scheduler) Schedule(spec string, factory func(at time.Time) Event, opts ...ScheduleOption) (*ScheduledJob, error) {
+	loc, expr, err := cutCronTZ(spec)
+	if err != nil {
+		return nil, err
+	}
+	schedule, err := parseCron(expr)
+	if err != nil {
+		return nil, err
+	}
+	if factory == nil {
+		return nil, errors.New("goevent: schedule: nil factory")
+	}
+
+	job := &ScheduledJob{spec: spec, schedule: schedule, factory: factory, scheduler: s, location: loc}
+	for _, opt := range opts {
+		opt(job)
+	}
+	job.next = job.nextAfter(s.ge.clock.Now())
+	if job.next.IsZero() {
+		return nil, fmt.Errorf("goevent: cron %q never fires", spec)
+	}
+
+	s.mu.Lock()
+	s.jobs = append(s.jobs, job)
+	s.mu.Unlock()
+	s.notify()
+	return job, nil
+}
+
+// Jobs returns the registered schedules
+func (s *Scheduler) Jobs() []*ScheduledJob {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return append([]*ScheduledJob(nil), s.jobs...)
+}
+
+// Start begins firing schedules. Starting a running scheduler is a no-op.
+func (s *Scheduler) Start() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.stop != nil {
+		return
+	}
+
+	// Runs that passed while stopped are skipped
+	now := s.ge.clock.Now()
+	for _, job := range s.jobs {
+		job.next = job.nextAfter(now)
+	}
+
+	s.stop = make(chan struct{})
+	s.wake = make(chan struct{}, 1)
+	s.done = make(chan struct{})
+	go s.run(s.stop, s.wake, s.done)
+}
+
+// Stop stops firing schedules and waits for a dispatch in progress to
+// return. Registered schedules are kept for the next Start.
+func (s *Scheduler) Stop() {
+	s.mu.Lock()
+	stop, done := s.stop, s.done
+	s.stop, s.wake, s.done = nil, nil, nil
+	s.mu.Unlock()
+
+	if stop == nil {
+		return
+	}
+	close(stop)
+	<-done
+}
+
+// Running reports whether the scheduler is started
+func (s *Scheduler) Running() bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.stop != nil
+}
+
+// notify wakes the run loop to pick up a changed job list
+func (s *Scheduler) notify() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.wake == nil {
+		return
+	}
+	select {
+	case s.wake <- struct{}{}:
+	default:
+	}
+}
+
+func (s *Scheduler) run(stop, wake, done chan struct{}) {
+	defer close(done)
+	clock := s.ge.clock
+
+	type run struct {
+		job *ScheduledJob
+		at  time.Time
+	}
+	for {
+		var due []run
+		var earliest time.Time
+
+		s.mu.Lock()
+		now := clock.Now()
+		for _, job := range s.jobs {
+			if job.next.IsZero() {
+				continue // no matching time left
+			}
+			if !job.next.After(now) {
+				due = append(due, run{job: job, at: job.next})
+				job.next = job.nextAfter(now)
+			}
+			if earliest.IsZero() || job.next.Before(earliest) {
+				earliest = job.next
+			}
+		}
+		s.mu.Unlock()
+
+		for _, r := range due {
+			select {
+			case <-stop:
+				return
+			default:
+			}
+			if event := r.job.factory(r.at); event != nil {
+				s.ge.Dispatch(event)
+			}
+		}
+
+		var timer <-chan time.Time
+		if !earliest.IsZero() {
+			timer = clock.After(earliest.Sub(clock.Now()))
+		}
+		select {
+		case <-stop:
+			return
+		case <-wake:
+		case <-timer:
+		}
+	}
+}
+
+// Spec returns the cron expression of the job
+func (j *ScheduledJob) Spec() string {
+	return j.spec
+}
+
+// Next returns the next time the job fires
+func (j *ScheduledJob) Next() time.Time {
+	j.scheduler.mu.Lock()
+	defer j.scheduler.mu.Unlock()
+	return j.next
+}
+
+// Cancel removes the job from the scheduler
+func (j *ScheduledJob) Cancel() {
+	s := j.scheduler
+	s.mu.Lock()
+	for i, job := range s.jobs {
+		if job == j {
+			s.jobs = append(s.jobs[:i], s.jobs[i+1:]...)
+			break
+		}
+	}
+	s.mu.Unlock()
+	s.notify()
+}
+
+// maxExcludedRuns bounds the search for a run the calendar allows
+const maxExcludedRuns = 100000
+
+// nextAfter returns the next run after t that the calendar allows, or
+// the zero time if there is none
+func (j *ScheduledJob) nextAfter(t time.Time) time.Time {
+	if j.location != nil {
+		t = t.In(j.location)
+	}
+	for i := 0; i < maxExcludedRuns; i++ {
+		t = j.schedule.next(t)
+		if t.IsZero() || j.calendar == nil || !j.calendar.Excludes(t) {
+			return t
+		}
+	}
+	return time.Time{}
+}
@@ -0,0 +1,261 @@
+package goevent
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+)
+
+// fakeClock only moves when advanced
+type fakeClock struct {
+	mu      sync.Mutex
+	now     time.Time
+	waiters []*fakeWaiter
+}
+
+type fakeWaiter struct {
+	at time.Time
+	ch chan time.Time
+	fn func()
+}
+
+func newFakeClock(now time.Time) *fakeClock {
+	return &fakeClock{now: now}
+}
+
+func (c *fakeClock) Now() time.Time {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.now
+}
+
+func (c *fakeClock) After(d time.Duration) <-chan time.Time {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	ch := make(chan time.Time, 1)
+	if d <= 0 {
+		ch <- c.now
+		return ch
+	}
+	c.waiters = append(c.waiters, &fakeWaiter{at: c.now.Add(d), ch: ch})
+	return ch
+}
+
+func (c *fakeClock) AfterFunc(d time.Duration, f func()) Timer {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	w := &fakeWaiter{at: c.now.Add(d), fn: f}
+	c.waiters = append(c.waiters, w)
+	return &fakeTimer{clock: c, waiter: w}
+}
+
+// fakeTimer stops a pending AfterFunc call
+type fakeTimer struct {
+	clock  *fakeClock
+	waiter *fakeWaiter
+}
+
+func (t *fakeTimer) Stop() bool {
+	t.clock.mu.Lock()
+	defer t.clock.mu.Unlock()
+	for i, w := range t.clock.waiters {
+		if w == t.waiter {
+			t.clock.waiters = append(t.clock.waiters[:i], t.clock.waiters[i+1:]...)
+			return true
+		}
+	}
+	return false
+}
+
+// Advance moves the clock forward and fires every timer that came due.
+// AfterFunc calls run before Advance returns.
+func (c *fakeClock) Advance(d time.Duration) {
+	c.mu.Lock()
+	c.now = c.now.Add(d)
+	var pending []*fakeWaiter
+	var calls []func()
+	for _, w := range c.waiters {
+		switch {
+		case w.at.After(c.now):
+			pending = append(pending, w)
+		case w.fn != nil:
+			calls = append(calls, w.fn)
+		default:
+			w.ch <- c.now
+		}
+	}
+	c.waiters = pending
+	c.mu.Unlock()
+
+	for _, call := range calls {
+		call()
+	}
+}
+
+// BlockUntil waits until n timers are pending
+func (c *fakeClock) BlockUntil(t *testing.T, n int) {
+	t.Helper()
+	deadline := time.Now().Add(time.Second)
+	for time.Now().Before(deadline) {
+		c.mu.Lock()
+		waiting := len(c.waiters)
+		c.mu.Unlock()
+		if waiting >= n {
+			return
+		}
+		time.Sleep(time.Millisecond)
+	}
+	t.Fatalf("Expected %d pending timers", n)
+}
+
+func TestScheduler_FiresOnSchedule(t *testing.T) {
+	clock := newFakeClock(time.Date(2024, time.January, 1, 9, 59, 0, 0, time.UTC))
+	evt := New(WithClock(clock))
+	listener := &testCountingListener{}
+	evt.RegisterListener(listener)
+
+	var mu sync.Mutex
+	var fired []time.Time
+	job, err := evt.Schedule("0 * * * *", func(at time.Time) Event {
+		mu.Lock()
+		defer mu.Unlock()
+		fired = append(fired, at)
+		return &TestEvent{}
+	})
+	if err != nil {
+		t.Fatalf("Schedule() failed: %v", err)
+	}
+	if want := time.Date(2024, time.January, 1, 10, 0, 0, 0, time.UTC); !job.Next().Equal(want) {
+		t.Errorf("Expected next run at %v, got %v", want, job.Next())
+	}
+
+	evt.Scheduler().Start()
+	defer evt.Scheduler().Stop()
+
+	clock.BlockUntil(t, 1)
+	clock.Advance(time.Minute)
+	clock.BlockUntil(t, 1)
+	clock.Advance(time.Hour)
+	clock.BlockUntil(t, 1)
+
+	if listener.Count() != 2 {
+		t.Fatalf("Expected 2 dispatches, got %d", listener.Count())
+	}
+	mu.Lock()
+	defer mu.Unlock()
+	if !fired[0].Equal(time.Date(2024, time.January, 1, 10, 0, 0, 0, time.UTC)) ||
+		!fired[1].Equal(time.Date(2024, time.January, 1, 11, 0, 0, 0, time.UTC)) {
+		t.Errorf("Expected runs at 10:00 and 11:00, got %v", fired)
+	}
+}
+
+func TestScheduler_StopAndCancel(t *testing.T) {
+	clock := newFakeClock(time.Date(2024, time.January, 1, 0, 0, 0, 0, time.UTC))
+	evt := New(WithClock(clock))
+	listener := &testCountingListener{}
+	evt.RegisterListener(listener)
+
+	job, err := evt.Schedule("* * * * *", func(time.Time) Event { return &TestEvent{} })
+	if err != nil {
+		t.Fatalf("Schedule() failed: %v", err)
+	}
+
+	// Nothing fires while stopped
+	clock.Advance(time.Hour)
+	if listener.Count() != 0 {
+		t.Fatalf("Expected no dispatches before Start, got %d", listener.Count())
+	}
+
+	evt.Scheduler().Start()
+	clock.BlockUntil(t, 1)
+	job.Cancel()
+	clock.Advance(time.Hour)
+	evt.Scheduler().Stop()
+
+	if listener.Count() != 0 {
+		t.Errorf("Expected cancelled job not to fire, got %d", listener.Count())
+	}
+	if evt.Scheduler().Running() {
+		t.Error("Expected scheduler to be stopped")
+	}
+}
+
+func TestScheduler_CloseStops(t *testing.T) {
+	evt := New()
+	evt.Scheduler().Start()
+	if err := evt.Close(context.Background()); err != nil {
+		t.Fatalf("Close() failed: %v", err)
+	}
+	if evt.Scheduler().Running() {
+		t.Error("Expected Close to stop the scheduler")
+	}
+}
+
+func TestScheduler_Invalid(t *testing.T) {
+	evt := New()
+	if _, err := evt.Schedule("0 0 30 feb *", func(time.Time) Event { return nil }); err == nil {
+		t.Error("Expected an error for a schedule that never fires")
+	}
+	if _, err := evt.Schedule("bogus", func(time.Time) Event { return nil }); err == nil {
+		t.Error("Expected an error for an invalid spec")
+	}
+}
+
+func TestScheduler_Location(t *testing.T) {
+	berlin, err := time.LoadLocation("Europe/Berlin")
+	if err != nil {
+		t.Skipf("time zone data unavailable: %v", err)
+	}
+	clock := newFakeClock(time.Date(2024, time.January, 1, 0, 0, 0, 0, time.UTC))
+	evt := New(WithClock(clock))
+	factory := func(time.Time) Event { return nil }
+	want := time.Date(2024, time.January, 1, 8, 0, 0, 0, time.UTC)
+
+	byPrefix, err := evt.Schedule("CRON_TZ=Europe/Berlin 0 9 * * *", factory)
+	if err != nil {
+		t.Fatalf("Schedule() failed: %v", err)
+	}
+	byOption, err := evt.Schedule("0 9 * * *", factory, InLocation(berlin))
+	if err != nil {
+		t.Fatalf("Schedule() failed: %v", err)
+	}
+
+	if !byPrefix.Next().Equal(want) || !byOption.Next().Equal(want) {
+		t.Errorf("Expected 09:00 Berlin time (%v), got %v and %v", want, byPrefix.Next(), byOption.Next())
+	}
+	if _, err := evt.Schedule("CRON_TZ=Nowhere/City 0 9 * * *", factory); err == nil {
+		t.Error("Expected an error for an unknown time zone")
+	}
+}
+
+func TestScheduler_Calendar(t *testing.T) {
+	// Tuesday 2024-12-24, with the next two days excluded
+	clock := newFakeClock(time.Date(2024, time.December, 24, 12, 0, 0, 0, time.UTC))
+	evt := New(WithClock(clock))
+	holidays, err := ExcludeDates("2024-12-25", "2024-12-26")
+	if err != nil {
+		t.Fatalf("ExcludeDates() failed: %v", err)
+	}
+
+	job, err := evt.Schedule("0 9 * * *", func(time.Time) Event { return nil }, WithCalendar(holidays))
+	if err != nil {
+		t.Fatalf("Schedule() failed: %v", err)
+	}
+	if want := time.Date(2024, time.December, 27, 9, 0, 0, 0, time.UTC); !job.Next().Equal(want) {
+		t.Errorf("Expected holidays to be skipped, next run %v, got %v", want, job.Next())
+	}
+
+	weekdays, err := evt.Schedule("0 9 28 12 *", func(time.Time) Event { return nil }, WithCalendar(ExcludeWeekends))
+	if err != nil {
+		t.Fatalf("Schedule() failed: %v", err)
+	}
+	// December 28 falls on a Saturday in 2024 and a Sunday in 2025
+	if want := time.Date(2026, time.December, 28, 9, 0, 0, 0, time.UTC); !weekdays.Next().Equal(want) {
+		t.Errorf("Expected %v, got %v", want, weekdays.Next())
+	}
+
+	if _, err := ExcludeDates("12/25/2024"); err == nil {
+		t.Error("Expected an error for a malformed date")
+	}
+}
@@ -0,0 +1,276 @@
+// Package schema generates JSON Schema and TypeScript definitions for
+// event payloads, so non-Go consumers share a single source of truth
+// with the Go events that produce them.
+//
+// Definitions are derived from prototype events: the dynamic type of
+// each value returned by Payload() determines the type of its key.
+// Prototypes should therefore populate every payload key, even with a
+// zero value.
+//
+//	data, err := schema.JSONSchema(&UserCreated{}, &UserDeleted{})
+//	ts := schema.TypeScript(&UserCreated{}, &UserDeleted{})
+package schema
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/openframebox/goevent"
+)
+
+var timeType = reflect.TypeOf(time.Time{})
+
+// JSONSchema returns a JSON Schema document with one definition per
+// event, keyed by event name
+func JSONSchema(events ...goevent.Event) ([]byte, error) {
+	defs := make(map[string]any, len(events))
+	for _, event := range events {
+		defs[event.Name()] = objectSchema(event.Payload())
+	}
+
+	return json.MarshalIndent(map[string]any{
+		"$schema": "https://json-schema.org/draft/2020-12/schema",
+		"$defs":   defs,
+	}, "", "  ")
+}
+
+// TypeScript returns TypeScript declarations for the payload of each
+// event, plus an EventPayloads interface mapping event names to them
+func TypeScript(events ...goevent.Event) string {
+	sorted := make([]goevent.Event, len(events))
+	copy(sorted, events)
+	sort.Slice(sorted, func(i, j int) bool {
+		return sorted[i].Name() < sorted[j].Name()
+	})
+
+	var b strings.Builder
+	b.WriteString("// Code generated by goevent/schema. DO NOT EDIT.\n")
+
+	for _, event := range sorted {
+		fmt.Fprintf(&b, "\nexport interface %s %s\n", interfaceName(event.Name()), objectTS(event.Payload(), ""))
+	}
+
+	b.WriteString("\nexport interface EventPayloads {\n")
+	for _, event := range sorted {
+		fmt.Fprintf(&b, "  %q: %s;\n", event.Name(), interfaceName(event.Name()))
+	}
+	b.WriteString("}\n")
+
+	b.WriteString("\nexport type EventName = keyof EventPayloads;\n")
+	return b.String()
+}
+
+// interfaceName converts an event name such as "user.created" into
+// a TypeScript identifier such as "UserCreatedPayload"
+func interfaceName(eventName string) string {
+	var b strings.Builder
+	upper := true
+	for _, r := range eventName {
+		switch {
+		case r >= 'a' && r <= 'z' || r >= 'A' && r <= 'Z' || r >= '0' && r <= '9':
+			if upper && r >= 'a' && r <= 'z' {
+				r -= 'a' - 'A'
+			}
+			b.WriteRune(r)
+			upper = false
+		default:
+			upper = true
+		}
+	}
+	b.WriteString("Payload")
+	return b.String()
+}
+
+func sortedKeys(payload map[string]any) []string {
+	keys := make([]string, 0, len(payload))
+	for key := range payload {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// objectSchema describes a payload map, using the dynamic type of each value
+func objectSchema(payload map[string]any) map[string]any {
+	properties := make(map[string]any, len(payload))
+	required := sortedKeys(payload)
+	for key, value := range payload {
+		properties[key] = valueSchema(value)
+	}
+	return map[string]any{
+		"type":       "object",
+		"properties": properties,
+		"required":   required,
+	}
+}
+
+func valueSchema(value any) map[string]any {
+	if nested, ok := value.(map[string]any); ok {
+		return objectSchema(nested)
+	}
+	if value == nil {
+		return map[string]any{}
+	}
+	return typeSchema(reflect.TypeOf(value))
+}
+
+func typeSchema(t reflect.Type) map[string]any {
+	if t == timeType {
+		return map[string]any{"type": "string", "format": "date-time"}
+	}
+
+	switch t.Kind() {
+	case reflect.Pointer:
+		return typeSchema(t.Elem())
+	case reflect.String:
+		return map[string]any{"type": "string"}
+	case reflect.Bool:
+		return map[string]any{"type": "boolean"}
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return map[string]any{"type": "integer"}
+	case reflect.Float32, reflect.Float64:
+		return map[string]any{"type": "number"}
+	case reflect.Slice, reflect.Array:
+		if t.Elem().Kind() == reflect.Uint8 {
+			return map[string]any{"type": "string", "contentEncoding": "base64"}
+		}
+		return map[string]any{"type": "array", "items": typeSchema(t.Elem())}
+	case reflect.Map:
+		return map[string]any{"type": "object", "additionalProperties": typeSchema(t.Elem())}
+	case reflect.Struct:
+		properties := map[string]any{}
+		var required []string
+		for _, field := range jsonFields(t) {
+			properties[field.name] = typeSchema(field.typ)
+			if !field.optional {
+				required = append(required, field.name)
+			}
+		}
+		schema := map[string]any{"type": "object", "properties": properties}
+		if len(required) > 0 {
+			schema["required"] = required
+		}
+		return schema
+	}
+	return map[string]any{}
+}
+
+// objectTS renders a payload map as a TypeScript object type
+func objectTS(payload map[string]any, indent string) string {
+	var b strings.Builder
+	b.WriteString("{\n")
+	for _, key := range sortedKeys(payload) {
+		fmt.Fprintf(&b, "%s  %s: %s;\n", indent, tsKey(key), valueTS(payload[key], indent+"  "))
+	}
+	b.WriteString(indent + "}")
+	return b.String()
+}
+
+func valueTS(value any, indent string) string {
+	if nested, ok := value.(map[string]any); ok {
+		return objectTS(nested, indent)
+	}
+	if value == nil {
+		return "unknown"
+	}
+	return typeTS(reflect.TypeOf(value), indent)
+}
+
+func typeTS(t reflect.Type, indent string) string {
+	if t == timeType {
+		return "string"
+	}
+
+	switch t.Kind() {
+	case reflect.Pointer:
+		return typeTS(t.Elem(), indent) + " | null"
+	case reflect.String:
+		return "string"
+	case reflect.Bool:
+		return "boolean"
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64,
+		reflect.Float32, reflect.Float64:
+		return "number"
+	case reflect.Slice, reflect.Array:
+		if t.Elem().Kind() == reflect.Uint8 {
+			return "string"
+		}
+		elem := typeTS(t.Elem(), indent)
+		if strings.Contains(elem, " ") {
+			elem = "(" + elem + ")"
+		}
+		return elem + "[]"
+	case reflect.Map:
+		return "Record<string, " + typeTS(t.Elem(), indent) + ">"
+	case reflect.Struct:
+		var b strings.Builder
+		b.WriteString("{\n")
+		for _, field := range jsonFields(t) {
+			optional := ""
+			if field.optional {
+				optional = "?"
+			}
+			fmt.Fprintf(&b, "%s  %s%s: %s;\n", indent, tsKey(field.name), optional, typeTS(field.typ, indent+"  "))
+		}
+		b.WriteString(indent + "}")
+		return b.String()
+	}
+	return "unknown"
+}
+
+// tsKey quotes property names that are not valid identifiers
+func tsKey(key string) string {
+	for i, r := range key {
+		valid := r == '_' || r == '$' || r >= 'a' && r <= 'z' || r >= 'A' && r <= 'Z' || i > 0 && r >= '0' && r <= '9'
+		if !valid {
+			return fmt.Sprintf("%q", key)
+		}
+	}
+	if key == "" {
+		return `""`
+	}
+	return key
+}
+
+type jsonField struct {
+	name     string
+	typ      reflect.Type
+	optional bool
+}
+
+// jsonFields returns the exported fields of a struct as encoding/json sees them
+func jsonFields(t reflect.Type) []jsonField {
+	var fields []jsonField
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+		if !f.IsExported() {
+			continue
+		}
+
+		name := f.Name
+		optional := false
+		if tag, ok := f.Tag.Lookup("json"); ok {
+			parts := strings.Split(tag, ",")
+			if parts[0] == "-" {
+				continue
+			}
+			if parts[0] != "" {
+				name = parts[0]
+			}
+			for _, opt := range parts[1:] {
+				if opt == "omitempty" {
+					optional = true
+				}
+			}
+		}
+
+		fields = append(fields, jsonField{name: name, typ: f.Type, optional: optional})
+	}
+	return fields
+}
@@ -0,0 +1,114 @@
+package schema
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+	"time"
+)
+
+type address struct {
+	City string `json:"city"`
+	Zip  string `json:"zip,omitempty"`
+}
+
+type userCreated struct {
+	ID      int
+	Email   string
+	Tags    []string
+	Address address
+	At      time.Time
+}
+
+func (e *userCreated) Name() string {
+	return "user.created"
+}
+
+func (e *userCreated) Payload() map[string]any {
+	return map[string]any{
+		"id":      e.ID,
+		"email":   e.Email,
+		"tags":    e.Tags,
+		"address": e.Address,
+		"at":      e.At,
+		"meta":    map[string]any{"verified": false},
+	}
+}
+
+func TestJSONSchema(t *testing.T) {
+	data, err := JSONSchema(&userCreated{})
+	if err != nil {
+		t.Fatalf("JSONSchema() failed: %v", err)
+	}
+
+	var doc struct {
+		Defs map[string]struct {
+			Type       string                    `json:"type"`
+			Properties map[string]map[string]any `json:"properties"`
+			Required   []string                  `json:"required"`
+		} `json:"$defs"`
+	}
+	if err := json.Unmarshal(data, &doc); err != nil {
+		t.Fatalf("Invalid JSON: %v", err)
+	}
+
+	def, ok := doc.Defs["user.created"]
+	if !ok {
+		t.Fatal("Expected a definition for 'user.created'")
+	}
+
+	tests := []struct {
+		property string
+		expected string
+	}{
+		{"id", "integer"},
+		{"email", "string"},
+		{"tags", "array"},
+		{"address", "object"},
+		{"at", "string"},
+		{"meta", "object"},
+	}
+	for _, tt := range tests {
+		if got := def.Properties[tt.property]["type"]; got != tt.expected {
+			t.Errorf("Property '%s': expected type '%s', got '%v'", tt.property, tt.expected, got)
+		}
+	}
+
+	if len(def.Required) != 6 {
+		t.Errorf("Expected all 6 payload keys to be required, got %v", def.Required)
+	}
+}
+
+func TestTypeScript(t *testing.T) {
+	ts := TypeScript(&userCreated{})
+
+	expected := []string{
+		"export interface UserCreatedPayload {",
+		"  id: number;",
+		"  tags: string[];",
+		"  at: string;",
+		"    city: string;",
+		"    zip?: string;",
+		"    verified: boolean;",
+		`  "user.created": UserCreatedPayload;`,
+		"export type EventName = keyof EventPayloads;",
+	}
+	for _, line := range expected {
+		if !strings.Contains(ts, line) {
+			t.Errorf("Expected output to contain %q, got:\n%s", line, ts)
+		}
+	}
+}
+
+func TestInterfaceName(t *testing.T) {
+	tests := map[string]string{
+		"user.created":       "UserCreatedPayload",
+		"order-line_updated": "OrderLineUpdatedPayload",
+		"v2.Thing":           "V2ThingPayload",
+	}
+	for input, expected := range tests {
+		if got := interfaceName(input); got != expected {
+			t.Errorf("interfaceName(%q): expected %q, got %q", input, expected, got)
+		}
+	}
+}
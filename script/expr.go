@@ -0,0 +1,618 @@
+package script
+
+import (
+	"fmt"
+	"math"
+	"reflect"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+// Expr is a compiled expression
+type Expr struct {
+	source string
+	eval   evalFunc
+}
+
+type evalFunc func(env map[string]any) (any, error)
+
+// Compile parses an expression. The language supports:
+//
+//	literals     42, 1.5, "text", 'text', true, false, null
+//	variables    name, payload, payload.customer.email
+//	operators    || && ! == != < <= > >= + - * / %
+//	functions    len(x), lower(s), upper(s), contains(s, sub), startsWith(s, prefix)
+//
+// Missing fields evaluate to null. Numbers are float64.
+func Compile(source string) (*Expr, error) {
+	p := &parser{source: source}
+	if err := p.lex(); err != nil {
+		return nil, err
+	}
+	eval, err := p.parseOr()
+	if err != nil {
+		return nil, err
+	}
+	if !p.done() {
+		return nil, p.errorf("unexpected %q", p.peek().text)
+	}
+	return &Expr{source: source, eval: eval}, nil
+}
+
+// MustCompile is like Compile but panics on error
+func MustCompile(source string) *Expr {
+	expr, err := Compile(source)
+	if err != nil {
+		panic(err)
+	}
+	return expr
+}
+
+// String returns the expression source
+func (e *Expr) String() string {
+	return e.source
+}
+
+// Eval evaluates the expression against variables
+func (e *Expr) Eval(env map[string]any) (any, error) {
+	return e.eval(env)
+}
+
+// Bool evaluates the expression and reports whether the result is truthy:
+// anything but false, null, 0 and ""
+func (e *Expr) Bool(env map[string]any) (bool, error) {
+	value, err := e.eval(env)
+	if err != nil {
+		return false, err
+	}
+	return truthy(value), nil
+}
+
+type tokenKind int
+
+const (
+	tokNumber tokenKind = iota
+	tokString
+	tokIdent
+	tokOp
+)
+
+type token struct {
+	kind tokenKind
+	text string
+	num  float64
+	pos  int
+}
+
+type parser struct {
+	source string
+	tokens []token
+	pos    int
+}
+
+func (p *parser) errorf(format string, args ...any) error {
+	pos := len(p.source)
+	if p.pos < len(p.tokens) {
+		pos = p.tokens[p.pos].pos
+	}
+	return fmt.Errorf("script: %s at offset %d in %q", fmt.Sprintf(format, args...), pos, p.source)
+}
+
+var operators = []string{"||", "&&", "==", "!=", "<=", ">=", "<", ">", "!", "+", "-", "*", "/", "%", "(", ")", ",", "."}
+
+func (p *parser) lex() error {
+	src := p.source
+	for i := 0; i < len(src); {
+		c := rune(src[i])
+		switch {
+		case unicode.IsSpace(c):
+			i++
+		case c >= '0' && c <= '9':
+			start := i
+			for i < len(src) && (src[i] >= '0' && src[i] <= '9' || src[i] == '.') {
+				i++
+			}
+			num, err := strconv.ParseFloat(src[start:i], 64)
+			if err != nil {
+				return fmt.Errorf("script: invalid number %q in %q", src[start:i], src)
+			}
+			p.tokens = append(p.tokens, token{kind: tokNumber, text: src[start:i], num: num, pos: start})
+		case c == '"' || c == '\'':
+			start := i
+			i++
+			for i < len(src) && rune(src[i]) != c {
+				if src[i] == '\\' {
+					i++
+				}
+				i++
+			}
+			if i >= len(src) {
+				return fmt.Errorf("script: unterminated string at offset %d in %q", start, src)
+			}
+			i++
+			raw := src[start:i]
+			if c == '\'' {
+				body := strings.ReplaceAll(raw[1:len(raw)-1], `\'`, `'`)
+				raw = `"` + strings.ReplaceAll(body, `"`, `\"`) + `"`
+			}
+			text, err := strconv.Unquote(raw)
+			if err != nil {
+				return fmt.Errorf("script: invalid string %s in %q", src[start:i], src)
+			}
+			p.tokens = append(p.tokens, token{kind: tokString, text: text, pos: start})
+		case isIdentByte(src[i]) && !(c >= '0' && c <= '9'):
+			start := i
+			for i < len(src) && isIdentByte(src[i]) {
+				i++
+			}
+			p.tokens = append(p.tokens, token{kind: tokIdent, text: src[start:i], pos: start})
+		default:
+			matched := false
+			for _, op := range operators {
+				if strings.HasPrefix(src[i:], op) {
+					p.tokens = append(p.tokens, token{kind: tokOp, text: op, pos: i})
+					i += len(op)
+					matched = true
+					break
+				}
+			}
+			if !matched {
+				return fmt.Errorf("script: unexpected %q at offset %d in %q", c, i, src)
+			}
+		}
+	}
+	return nil
+}
+
+// isIdentByte reports whether b may appear in an identifier
+func isIdentByte(b byte) bool {
+	return b == '_' || b >= 'a' && b <= 'z' || b >= 'A' && b <= 'Z' || b >= '0' && b <= '9'
+}
+
+func (p *parser) done() bool {
+	return p.pos >= len(p.tokens)
+}
+
+func (p *parser) peek() token {
+	if p.done() {
+		return token{}
+	}
+	return p.tokens[p.pos]
+}
+
+// accept consumes the next token if it is one of the given operators
+func (p *parser) accept(ops ...string) (string, bool) {
+	next := p.peek()
+	if p.done() || next.kind != tokOp {
+		return "", false
+	}
+	for _, op := range ops {
+		if next.text == op {
+			p.pos++
+			return op, true
+		}
+	}
+	return "", false
+}
+
+func (p *parser) expect(op string) error {
+	if _, ok := p.accept(op); !ok {
+		if p.done() {
+			return p.errorf("expected %q", op)
+		}
+		return p.errorf("expected %q, got %q", op, p.peek().text)
+	}
+	return nil
+}
+
+func (p *parser) parseOr() (evalFunc, error) {
+	left, err := p.parseAnd()
+	if err != nil {
+		return nil, err
+	}
+	for {
+		if _, ok := p.accept("||"); !ok {
+			return left, nil
+		}
+		right, err := p.parseAnd()
+		if err != nil {
+			return nil, err
+		}
+		l := left
+		left = func(env map[string]any) (any, error) {
+			a, err := l(env)
+			if err != nil || truthy(a) {
+				return truthy(a), err
+			}
+			b, err := right(env)
+			return truthy(b), err
+		}
+	}
+}
+
+func (p *parser) parseAnd() (evalFunc, error) {
+	left, err := p.parseEquality()
+	if err != nil {
+		return nil, err
+	}
+	for {
+		if _, ok := p.accept("&&"); !ok {
+			return left, nil
+		}
+		right, err := p.parseEquality()
+		if err != nil {
+			return nil, err
+		}
+		l := left
+		left = func(env map[string]any) (any, error) {
+			a, err := l(env)
+			if err != nil || !truthy(a) {
+				return false, err
+			}
+			b, err := right(env)
+			return truthy(b), err
+		}
+	}
+}
+
+func (p *parser) parseEquality() (evalFunc, error) {
+	return p.parseBinary(p.parseComparison, []string{"==", "!="}, func(op string, a, b any) (any, error) {
+		equal := reflect.DeepEqual(normalize(a), normalize(b))
+		return equal == (op == "=="), nil
+	})
+}
+
+func (p *parser) parseComparison() (evalFunc, error) {
+	return p.parseBinary(p.parseAdditive, []string{"<=", ">=", "<", ">"}, compare)
+}
+
+func (p *parser) parseAdditive() (evalFunc, error) {
+	return p.parseBinary(p.parseMultiplicative, []string{"+", "-"}, arithmetic)
+}
+
+func (p *parser) parseMultiplicative() (evalFunc, error) {
+	return p.parseBinary(p.parseUnary, []string{"*", "/", "%"}, arithmetic)
+}
+
+// parseBinary parses a left-associative chain of operators at one
+// precedence level
+func (p *parser) parseBinary(next func() (evalFunc, error), ops []string, apply func(op string, a, b any) (any, error)) (evalFunc, error) {
+	left, err := next()
+	if err != nil {
+		return nil, err
+	}
+	for {
+		op, ok := p.accept(ops...)
+		if !ok {
+			return left, nil
+		}
+		right, err := next()
+		if err != nil {
+			return nil, err
+		}
+		l := left
+		left = func(env map[string]any) (any, error) {
+			a, err := l(env)
+			if err != nil {
+				return nil, err
+			}
+			b, err := right(env)
+			if err != nil {
+				return nil, err
+			}
+			return apply(op, a, b)
+		}
+	}
+}
+
+func (p *parser) parseUnary() (evalFunc, error) {
+	op, ok := p.accept("!", "-")
+	if !ok {
+		return p.parsePrimary()
+	}
+	operand, err := p.parseUnary()
+	if err != nil {
+		return nil, err
+	}
+	if op == "!" {
+		return func(env map[string]any) (any, error) {
+			v, err := operand(env)
+			return !truthy(v), err
+		}, nil
+	}
+	return func(env map[string]any) (any, error) {
+		v, err := operand(env)
+		if err != nil {
+			return nil, err
+		}
+		return arithmetic("-", 0.0, v)
+	}, nil
+}
+
+func (p *parser) parsePrimary() (evalFunc, error) {
+	if p.done() {
+		return nil, p.errorf("unexpected end of expression")
+	}
+
+	tok := p.peek()
+	switch {
+	case tok.kind == tokNumber:
+		p.pos++
+		return constant(tok.num), nil
+	case tok.kind == tokString:
+		p.pos++
+		return constant(tok.text), nil
+	case tok.kind == tokOp && tok.text == "(":
+		p.pos++
+		inner, err := p.parseOr()
+		if err != nil {
+			return nil, err
+		}
+		return inner, p.expect(")")
+	case tok.kind == tokIdent:
+		p.pos++
+		return p.parseIdent(tok.text)
+	default:
+		return nil, p.errorf("unexpected %q", tok.text)
+	}
+}
+
+func (p *parser) parseIdent(name string) (evalFunc, error) {
+	switch name {
+	case "true":
+		return constant(true), nil
+	case "false":
+		return constant(false), nil
+	case "null":
+		return constant(nil), nil
+	}
+
+	if _, ok := p.accept("("); ok {
+		return p.parseCall(name)
+	}
+
+	path := []string{name}
+	for {
+		if _, ok := p.accept("."); !ok {
+			break
+		}
+		field := p.peek()
+		if p.done() || field.kind != tokIdent {
+			return nil, p.errorf("expected field name after '.'")
+		}
+		p.pos++
+		path = append(path, field.text)
+	}
+
+	return func(env map[string]any) (any, error) {
+		var value any = env
+		for _, field := range path {
+			value = lookup(value, field)
+		}
+		return normalize(value), nil
+	}, nil
+}
+
+func (p *parser) parseCall(name string) (evalFunc, error) {
+	fn, ok := functions[name]
+	if !ok {
+		return nil, p.errorf("unknown function %q", name)
+	}
+
+	var args []evalFunc
+	if _, ok := p.accept(")"); !ok {
+		for {
+			arg, err := p.parseOr()
+			if err != nil {
+				return nil, err
+			}
+			args = append(args, arg)
+			if _, ok := p.accept(","); !ok {
+				break
+			}
+		}
+		if err := p.expect(")"); err != nil {
+			return nil, err
+		}
+	}
+	if len(args) != fn.arity {
+		return nil, p.errorf("%s expects %d arguments, got %d", name, fn.arity, len(args))
+	}
+
+	return func(env map[string]any) (any, error) {
+		values := make([]any, len(args))
+		for i, arg := range args {
+			v, err := arg(env)
+			if err != nil {
+				return nil, err
+			}
+			values[i] = v
+		}
+		return fn.call(values)
+	}, nil
+}
+
+func constant(v any) evalFunc {
+	return func(map[string]any) (any, error) {
+		return v, nil
+	}
+}
+
+type function struct {
+	arity int
+	call  func(args []any) (any, error)
+}
+
+var functions = map[string]function{
+	"len": {1, func(args []any) (any, error) {
+		v := reflect.ValueOf(args[0])
+		switch v.Kind() {
+		case reflect.String, reflect.Slice, reflect.Array, reflect.Map:
+			return float64(v.Len()), nil
+		case reflect.Invalid:
+			return 0.0, nil
+		}
+		return nil, fmt.Errorf("script: len of %T", args[0])
+	}},
+	"lower": {1, func(args []any) (any, error) {
+		return strings.ToLower(toString(args[0])), nil
+	}},
+	"upper": {1, func(args []any) (any, error) {
+		return strings.ToUpper(toString(args[0])), nil
+	}},
+	"contains": {2, func(args []any) (any, error) {
+		if list, ok := args[0].([]any); ok {
+			for _, item := range list {
+				if reflect.DeepEqual(normalize(item), normalize(args[1])) {
+					return true, nil
+				}
+			}
+			return false, nil
+		}
+		return strings.Contains(toString(args[0]), toString(args[1])), nil
+	}},
+	"startsWith": {2, func(args []any) (any, error) {
+		return strings.HasPrefix(toString(args[0]), toString(args[1])), nil
+	}},
+}
+
+// lookup returns a field of a map or struct, or nil if it has none
+func lookup(value any, field string) any {
+	switch v := value.(type) {
+	case map[string]any:
+		return v[field]
+	case nil:
+		return nil
+	}
+
+	rv := reflect.Indirect(reflect.ValueOf(value))
+	switch rv.Kind() {
+	case reflect.Map:
+		if rv.Type().Key().Kind() == reflect.String {
+			if item := rv.MapIndex(reflect.ValueOf(field).Convert(rv.Type().Key())); item.IsValid() {
+				return item.Interface()
+			}
+		}
+	case reflect.Struct:
+		if f := rv.FieldByName(field); f.IsValid() && f.CanInterface() {
+			return f.Interface()
+		}
+	}
+	return nil
+}
+
+// normalize converts every numeric type to float64 so values compare
+// the same regardless of how the payload was built
+func normalize(value any) any {
+	rv := reflect.ValueOf(value)
+	switch rv.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return float64(rv.Int())
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		return float64(rv.Uint())
+	case reflect.Float32, reflect.Float64:
+		return rv.Float()
+	}
+	return value
+}
+
+func truthy(value any) bool {
+	switch v := normalize(value).(type) {
+	case nil:
+		return false
+	case bool:
+		return v
+	case float64:
+		return v != 0
+	case string:
+		return v != ""
+	}
+	return true
+}
+
+func toString(value any) string {
+	switch v := normalize(value).(type) {
+	case nil:
+		return ""
+	case string:
+		return v
+	case float64:
+		return strconv.FormatFloat(v, 'f', -1, 64)
+	}
+	return fmt.Sprint(value)
+}
+
+func compare(op string, a, b any) (any, error) {
+	a, b = normalize(a), normalize(b)
+
+	var cmp int
+	switch x := a.(type) {
+	case float64:
+		y, ok := b.(float64)
+		if !ok {
+			return nil, fmt.Errorf("script: cannot compare number with %T", b)
+		}
+		switch {
+		case x < y:
+			cmp = -1
+		case x > y:
+			cmp = 1
+		}
+	case string:
+		y, ok := b.(string)
+		if !ok {
+			return nil, fmt.Errorf("script: cannot compare string with %T", b)
+		}
+		cmp = strings.Compare(x, y)
+	default:
+		return nil, fmt.Errorf("script: cannot compare %T", a)
+	}
+
+	switch op {
+	case "<":
+		return cmp < 0, nil
+	case "<=":
+		return cmp <= 0, nil
+	case ">":
+		return cmp > 0, nil
+	default:
+		return cmp >= 0, nil
+	}
+}
+
+func arithmetic(op string, a, b any) (any, error) {
+	a, b = normalize(a), normalize(b)
+
+	if op == "+" {
+		_, aString := a.(string)
+		_, bString := b.(string)
+		if aString || bString {
+			return toString(a) + toString(b), nil
+		}
+	}
+
+	x, ok1 := a.(float64)
+	y, ok2 := b.(float64)
+	if !ok1 || !ok2 {
+		return nil, fmt.Errorf("script: cannot apply %s to %T and %T", op, a, b)
+	}
+	switch op {
+	case "+":
+		return x + y, nil
+	case "-":
+		return x - y, nil
+	case "*":
+		return x * y, nil
+	case "/":
+		if y == 0 {
+			return nil, fmt.Errorf("script: division by zero")
+		}
+		return x / y, nil
+	default:
+		if y == 0 {
+			return nil, fmt.Errorf("script: division by zero")
+		}
+		return math.Mod(x, y), nil
+	}
+}
@@ -0,0 +1,78 @@
+package script
+
+import (
+	"testing"
+)
+
+func TestEval(t *testing.T) {
+	env := map[string]any{
+		"name": "order.placed",
+		"payload": map[string]any{
+			"id":       7,
+			"total":    1250.5,
+			"currency": "EUR",
+			"customer": map[string]any{"email": "Ada@Example.com"},
+			"tags":     []any{"vip", "eu"},
+		},
+	}
+
+	tests := []struct {
+		expr string
+		want any
+	}{
+		{`payload.total > 1000`, true},
+		{`payload.total > 1000 && payload.currency == "USD"`, false},
+		{`payload.currency == 'EUR' || false`, true},
+		{`!(payload.id == 7)`, false},
+		{`payload.id * 2 + 1`, 15.0},
+		{`-payload.id % 4`, -3.0},
+		{`'order ' + payload.id`, "order 7"},
+		{`lower(payload.customer.email)`, "ada@example.com"},
+		{`contains(payload.tags, "vip")`, true},
+		{`startsWith(name, "order.")`, true},
+		{`len(payload.tags)`, 2.0},
+		{`payload.missing.field == null`, true},
+		{`payload.customer.email != null && len(payload.customer.email) > 3`, true},
+	}
+
+	for _, tt := range tests {
+		expr, err := Compile(tt.expr)
+		if err != nil {
+			t.Errorf("Compile(%q) failed: %v", tt.expr, err)
+			continue
+		}
+		got, err := expr.Eval(env)
+		if err != nil {
+			t.Errorf("Eval(%q) failed: %v", tt.expr, err)
+			continue
+		}
+		if got != tt.want {
+			t.Errorf("Eval(%q): expected %v, got %v", tt.expr, tt.want, got)
+		}
+	}
+}
+
+func TestCompile_Errors(t *testing.T) {
+	for _, expr := range []string{
+		`payload.total >`,
+		`(payload.total`,
+		`unknown(1)`,
+		`len(1, 2)`,
+		`"unterminated`,
+		`payload.`,
+		`1 # 2`,
+	} {
+		if _, err := Compile(expr); err == nil {
+			t.Errorf("Compile(%q): expected an error", expr)
+		}
+	}
+}
+
+func TestEval_Errors(t *testing.T) {
+	env := map[string]any{"payload": map[string]any{"name": "ada"}}
+	for _, expr := range []string{`payload.name > 1`, `1 / 0`, `payload.name * 2`} {
+		if _, err := MustCompile(expr).Eval(env); err == nil {
+			t.Errorf("Eval(%q): expected an error", expr)
+		}
+	}
+}
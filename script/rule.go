@@ -0,0 +1,141 @@
+// Package script lets operators define reactions to events without
+// recompiling the host application.
+//
+// A Rule matches events with an expression and reacts by dispatching a
+// new event, optionally with a payload computed from the original one.
+// That covers routing (re-emit under another name), transformation
+// (compute new fields) and alerting (emit an event a notify listener
+// subscribes to):
+//
+//	[
+//	  {
+//	    "event": "order.placed",
+//	    "when":  "payload.total > 1000 && payload.currency == 'EUR'",
+//	    "emit":  "alert.large_order",
+//	    "set":   {"order": "payload.id", "message": "'Large order from ' + payload.customer"}
+//	  }
+//	]
+//
+//	listeners, err := script.LoadRules(bus, data)
+//	for _, l := range listeners {
+//		bus.RegisterListener(l)
+//	}
+//
+// See Compile for the expression language. Expressions see two
+// variables: name, the event name, and payload.
+package script
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"github.com/openframebox/goevent"
+)
+
+// Rule is an operator-defined reaction to an event
+type Rule struct {
+	Event string            `json:"event"`          // name of the event to react to
+	When  string            `json:"when,omitempty"` // condition; empty matches every event
+	Emit  string            `json:"emit"`           // name of the event to dispatch
+	Set   map[string]string `json:"set,omitempty"`  // emitted payload; empty copies the original payload
+}
+
+// Listener runs a compiled rule. It is async, so emitted events are
+// dispatched outside of the original dispatch's listener calls.
+type Listener struct {
+	bus  *goevent.GoEvent
+	rule Rule
+	when *Expr
+	set  map[string]*Expr
+}
+
+// NewListener compiles a rule into a listener that dispatches on bus
+func NewListener(bus *goevent.GoEvent, rule Rule) (*Listener, error) {
+	if rule.Event == "" || rule.Emit == "" {
+		return nil, fmt.Errorf("script: rule needs both event and emit")
+	}
+
+	l := &Listener{bus: bus, rule: rule, set: make(map[string]*Expr, len(rule.Set))}
+	if rule.When != "" {
+		when, err := Compile(rule.When)
+		if err != nil {
+			return nil, err
+		}
+		l.when = when
+	}
+	for field, source := range rule.Set {
+		expr, err := Compile(source)
+		if err != nil {
+			return nil, fmt.Errorf("script: field %q: %w", field, err)
+		}
+		l.set[field] = expr
+	}
+	return l, nil
+}
+
+// LoadRules compiles a JSON array of rules
+func LoadRules(bus *goevent.GoEvent, data []byte) ([]*Listener, error) {
+	var rules []Rule
+	if err := json.Unmarshal(data, &rules); err != nil {
+		return nil, fmt.Errorf("script: decoding rules: %w", err)
+	}
+
+	listeners := make([]*Listener, 0, len(rules))
+	for i, rule := range rules {
+		listener, err := NewListener(bus, rule)
+		if err != nil {
+			return nil, fmt.Errorf("script: rule %d: %w", i, err)
+		}
+		listeners = append(listeners, listener)
+	}
+	return listeners, nil
+}
+
+// Rule returns the rule the listener was compiled from
+func (l *Listener) Rule() Rule {
+	return l.rule
+}
+
+// EventName returns the event the rule reacts to
+func (l *Listener) EventName() string {
+	return l.rule.Event
+}
+
+// Options makes rule listeners async
+func (l *Listener) Options() goevent.ListenerOptions {
+	return goevent.ListenerOptions{Async: true}
+}
+
+// OnEvent runs the rule without a parent dispatch
+func (l *Listener) OnEvent(event goevent.Event) error {
+	return l.OnEventContext(context.Background(), event)
+}
+
+// OnEventContext runs the rule. The emitted event is dispatched with ctx,
+// so it inherits the original dispatch's priority and deadline.
+func (l *Listener) OnEventContext(ctx context.Context, event goevent.Event) error {
+	env := map[string]any{"name": event.Name(), "payload": event.Payload()}
+
+	if l.when != nil {
+		match, err := l.when.Bool(env)
+		if err != nil || !match {
+			return err
+		}
+	}
+
+	payload := event.Payload()
+	if len(l.set) > 0 {
+		payload = make(map[string]any, len(l.set))
+		for field, expr := range l.set {
+			value, err := expr.Eval(env)
+			if err != nil {
+				return fmt.Errorf("script: field %q: %w", field, err)
+			}
+			payload[field] = value
+		}
+	}
+
+	l.bus.DispatchContext(ctx, &goevent.GenericEvent{EventName: l.rule.Emit, Data: payload})
+	return nil
+}
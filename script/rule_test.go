@@ -0,0 +1,87 @@
+package script
+
+import (
+	"context"
+	"sync"
+	"testing"
+
+	"github.com/openframebox/goevent"
+)
+
+type alertRecorder struct {
+	mu       sync.Mutex
+	payloads []map[string]any
+}
+
+func (r *alertRecorder) EventName() string {
+	return "alert.large_order"
+}
+
+func (r *alertRecorder) OnEvent(event goevent.Event) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.payloads = append(r.payloads, event.Payload())
+	return nil
+}
+
+const rules = `[
+  {
+    "event": "order.placed",
+    "when":  "payload.total > 1000",
+    "emit":  "alert.large_order",
+    "set":   {"order": "payload.id", "message": "'Large order from ' + payload.customer"}
+  }
+]`
+
+func TestLoadRules(t *testing.T) {
+	bus := goevent.New()
+	listeners, err := LoadRules(bus, []byte(rules))
+	if err != nil {
+		t.Fatalf("LoadRules() failed: %v", err)
+	}
+	for _, listener := range listeners {
+		bus.RegisterListener(listener)
+	}
+	alerts := &alertRecorder{}
+	bus.RegisterListener(alerts)
+
+	for _, total := range []float64{50, 5000} {
+		handle := bus.Dispatch(&goevent.GenericEvent{EventName: "order.placed", Data: map[string]any{
+			"id": 1, "total": total, "customer": "Ada",
+		}})
+		if err := handle.WaitTree(context.Background()); err != nil {
+			t.Fatalf("WaitTree() failed: %v", err)
+		}
+	}
+
+	if len(alerts.payloads) != 1 {
+		t.Fatalf("Expected 1 alert, got %d", len(alerts.payloads))
+	}
+	if alerts.payloads[0]["message"] != "Large order from Ada" || alerts.payloads[0]["order"] != 1.0 {
+		t.Errorf("Unexpected alert payload: %v", alerts.payloads[0])
+	}
+}
+
+func TestNewListener_CopiesPayloadWithoutSet(t *testing.T) {
+	bus := goevent.New()
+	listener, err := NewListener(bus, Rule{Event: "order.placed", Emit: "alert.large_order"})
+	if err != nil {
+		t.Fatalf("NewListener() failed: %v", err)
+	}
+	alerts := &alertRecorder{}
+	bus.RegisterListener(listener, alerts)
+
+	bus.Dispatch(&goevent.GenericEvent{EventName: "order.placed", Data: map[string]any{"id": 9}}).
+		WaitTree(context.Background())
+
+	if len(alerts.payloads) != 1 || alerts.payloads[0]["id"] != 9 {
+		t.Errorf("Expected the original payload to be routed, got %v", alerts.payloads)
+	}
+}
+
+func TestLoadRules_InvalidExpression(t *testing.T) {
+	_, err := LoadRules(goevent.New(), []byte(`[{"event": "a", "emit": "b", "when": "payload.x >"}]`))
+	if err == nil {
+		t.Error("Expected an error for an invalid expression")
+	}
+}
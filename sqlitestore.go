@@ -0,0 +1,295 @@
+package goevent
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+)
+
+// SQLiteStore is an AggregateStore and SnapshotStore backed by a SQLite
+// database. It works with any database/sql SQLite driver; the application
+// imports and registers one (for example modernc.org/sqlite or
+// github.com/mattn/go-sqlite3) and passes the opened *sql.DB.
+//
+// SQLite allows one writer at a time and fails a conflicting statement
+// with SQLITE_BUSY, so the store serializes its writes. Set a busy
+// timeout on the database if other processes write to it too.
+type SQLiteStore struct {
+	mu sync.RWMutex
+	db *sql.DB
+}
+
+// NewSQLiteStore creates the events and snapshots tables if needed and
+// returns the store.
+// Tables created by earlier versions gain the aggregate columns.
+func NewSQLiteStore(ctx context.Context, db *sql.DB) (*SQLiteStore, error) {
+	_, err := db.ExecContext(ctx, `CREATE TABLE IF NOT EXISTS goevent_events (
+		seq          INTEGER PRIMARY KEY AUTOINCREMENT,
+		name         TEXT    NOT NULL,
+		payload      TEXT    NOT NULL,
+		created_at   TEXT    NOT NULL,
+		acked        INTEGER NOT NULL DEFAULT 0,
+		aggregate_id TEXT,
+		version      INTEGER
+	)`)
+	if err != nil {
+		return nil, fmt.Errorf("goevent: creating events table: %w", err)
+	}
+	if err := addAggregateColumns(ctx, db); err != nil {
+		return nil, fmt.Errorf("goevent: migrating events table: %w", err)
+	}
+	_, err = db.ExecContext(ctx, `CREATE UNIQUE INDEX IF NOT EXISTS goevent_events_aggregate
+		ON goevent_events (aggregate_id, version)`)
+	if err != nil {
+		return nil, fmt.Errorf("goevent: creating aggregate index: %w", err)
+	}
+	_, err = db.ExecContext(ctx, `CREATE TABLE IF NOT EXISTS goevent_snapshots (
+		aggregate_id TEXT    NOT NULL,
+		version      INTEGER NOT NULL,
+		state        BLOB,
+		created_at   TEXT    NOT NULL,
+		PRIMARY KEY (aggregate_id, version)
+	)`)
+	if err != nil {
+		return nil, fmt.Errorf("goevent: creating snapshots table: %w", err)
+	}
+	return &SQLiteStore{db: db}, nil
+}
+
+// addAggregateColumns adds the aggregate columns to an events table
+// created before aggregates were supported
+func addAggregateColumns(ctx context.Context, db *sql.DB) error {
+	rows, err := db.QueryContext(ctx, `SELECT name FROM pragma_table_info('goevent_events')`)
+	if err != nil {
+		return err
+	}
+	columns := make(map[string]bool)
+	for rows.Next() {
+		var name string
+		if err := rows.Scan(&name); err != nil {
+			rows.Close()
+			return err
+		}
+		columns[name] = true
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return err
+	}
+
+	for _, column := range []string{"aggregate_id TEXT", "version INTEGER"} {
+		if columns[strings.Fields(column)[0]] {
+			continue
+		}
+		if _, err := db.ExecContext(ctx, `ALTER TABLE goevent_events ADD COLUMN `+column); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Append persists an event and assigns it the next sequence number
+func (s *SQLiteStore) Append(ctx context.Context, event Event) (StoredEvent, error) {
+	stored := StoredEvent{
+		Name:    event.Name(),
+		Payload: event.Payload(),
+		Time:    time.Now().UTC(),
+	}
+
+	payload, err := json.Marshal(stored.Payload)
+	if err != nil {
+		return StoredEvent{}, err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	result, err := s.db.ExecContext(ctx,
+		`INSERT INTO goevent_events (name, payload, created_at) VALUES (?, ?, ?)`,
+		stored.Name, string(payload), stored.Time.Format(time.RFC3339Nano))
+	if err != nil {
+		return StoredEvent{}, err
+	}
+
+	id, err := result.LastInsertId()
+	if err != nil {
+		return StoredEvent{}, err
+	}
+	stored.Seq = uint64(id)
+	return stored, nil
+}
+
+// AppendAggregate persists the events raised by an aggregate in one
+// transaction, after checking the aggregate is at expectedVersion
+func (s *SQLiteStore) AppendAggregate(ctx context.Context, aggregateID string, expectedVersion int, events []Event) ([]StoredEvent, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	var current int
+	err = tx.QueryRowContext(ctx,
+		`SELECT COALESCE(MAX(version), 0) FROM goevent_events WHERE aggregate_id = ?`,
+		aggregateID).Scan(&current)
+	if err != nil {
+		return nil, err
+	}
+	if current != expectedVersion {
+		return nil, versionConflict(aggregateID, current, expectedVersion)
+	}
+
+	now := time.Now().UTC()
+	stored := make([]StoredEvent, len(events))
+	for i, event := range events {
+		stored[i] = StoredEvent{
+			Name:        event.Name(),
+			Payload:     event.Payload(),
+			Time:        now,
+			AggregateID: aggregateID,
+			Version:     expectedVersion + i + 1,
+		}
+		payload, err := json.Marshal(stored[i].Payload)
+		if err != nil {
+			return nil, err
+		}
+		result, err := tx.ExecContext(ctx,
+			`INSERT INTO goevent_events (name, payload, created_at, aggregate_id, version) VALUES (?, ?, ?, ?, ?)`,
+			stored[i].Name, string(payload), now.Format(time.RFC3339Nano), aggregateID, stored[i].Version)
+		if err != nil {
+			return nil, err
+		}
+		id, err := result.LastInsertId()
+		if err != nil {
+			return nil, err
+		}
+		stored[i].Seq = uint64(id)
+	}
+	if err := tx.Commit(); err != nil {
+		return nil, err
+	}
+	return stored, nil
+}
+
+// LoadAggregate returns the events of an aggregate above fromVersion
+func (s *SQLiteStore) LoadAggregate(ctx context.Context, aggregateID string, fromVersion int) ([]StoredEvent, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	rows, err := s.db.QueryContext(ctx,
+		`SELECT `+eventColumns+` FROM goevent_events WHERE aggregate_id = ? AND version > ? ORDER BY version`,
+		aggregateID, fromVersion)
+	if err != nil {
+		return nil, err
+	}
+	return scanEvents(rows)
+}
+
+// SaveSnapshot stores a snapshot of an aggregate
+func (s *SQLiteStore) SaveSnapshot(ctx context.Context, snapshot Snapshot) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	_, err := s.db.ExecContext(ctx,
+		`INSERT OR REPLACE INTO goevent_snapshots (aggregate_id, version, state, created_at) VALUES (?, ?, ?, ?)`,
+		snapshot.AggregateID, snapshot.Version, snapshot.State, snapshot.Time.UTC().Format(time.RFC3339Nano))
+	return err
+}
+
+// LoadLatestSnapshot returns the latest snapshot of an aggregate
+func (s *SQLiteStore) LoadLatestSnapshot(ctx context.Context, aggregateID string) (Snapshot, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	snapshot := Snapshot{AggregateID: aggregateID}
+	var createdAt string
+	err := s.db.QueryRowContext(ctx,
+		`SELECT version, state, created_at FROM goevent_snapshots WHERE aggregate_id = ? ORDER BY version DESC LIMIT 1`,
+		aggregateID).Scan(&snapshot.Version, &snapshot.State, &createdAt)
+	if errors.Is(err, sql.ErrNoRows) {
+		return Snapshot{}, ErrSnapshotNotFound
+	}
+	if err != nil {
+		return Snapshot{}, err
+	}
+	if snapshot.Time, err = time.Parse(time.RFC3339Nano, createdAt); err != nil {
+		return Snapshot{}, fmt.Errorf("goevent: decoding time of snapshot of %s: %w", aggregateID, err)
+	}
+	return snapshot, nil
+}
+
+// ReadFrom returns up to limit events starting at seq
+func (s *SQLiteStore) ReadFrom(ctx context.Context, seq uint64, limit int) ([]StoredEvent, error) {
+	if limit <= 0 {
+		limit = -1 // SQLite: no limit
+	}
+
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	rows, err := s.db.QueryContext(ctx,
+		`SELECT `+eventColumns+` FROM goevent_events WHERE seq >= ? ORDER BY seq LIMIT ?`,
+		int64(seq), limit)
+	if err != nil {
+		return nil, err
+	}
+	return scanEvents(rows)
+}
+
+// eventColumns are the columns scanEvents reads, in order
+const eventColumns = `seq, name, payload, created_at, acked, aggregate_id, version`
+
+// scanEvents reads and closes rows of eventColumns
+func scanEvents(rows *sql.Rows) ([]StoredEvent, error) {
+	defer rows.Close()
+
+	var events []StoredEvent
+	for rows.Next() {
+		var (
+			stored      StoredEvent
+			id          int64
+			payload     string
+			createdAt   string
+			aggregateID sql.NullString
+			version     sql.NullInt64
+		)
+		if err := rows.Scan(&id, &stored.Name, &payload, &createdAt, &stored.Acked, &aggregateID, &version); err != nil {
+			return nil, err
+		}
+		if err := json.Unmarshal([]byte(payload), &stored.Payload); err != nil {
+			return nil, fmt.Errorf("goevent: decoding payload of event %d: %w", id, err)
+		}
+		var err error
+		if stored.Time, err = time.Parse(time.RFC3339Nano, createdAt); err != nil {
+			return nil, fmt.Errorf("goevent: decoding time of event %d: %w", id, err)
+		}
+		stored.Seq = uint64(id)
+		stored.AggregateID = aggregateID.String
+		stored.Version = int(version.Int64)
+		events = append(events, stored)
+	}
+	return events, rows.Err()
+}
+
+// Ack marks an event as fully handled
+func (s *SQLiteStore) Ack(ctx context.Context, seq uint64) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	result, err := s.db.ExecContext(ctx, `UPDATE goevent_events SET acked = 1 WHERE seq = ?`, int64(seq))
+	if err != nil {
+		return err
+	}
+	if n, err := result.RowsAffected(); err == nil && n == 0 {
+		return fmt.Errorf("goevent: unknown sequence number %d", seq)
+	}
+	return nil
+}
@@ -0,0 +1,221 @@
+package sqlitetest
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"path/filepath"
+	"testing"
+
+	"github.com/openframebox/goevent"
+)
+
+// order is an event-sourced aggregate
+type order struct {
+	goevent.AggregateRoot
+	Status  string
+	Items   int
+	applied int
+}
+
+func (o *order) Snapshot() ([]byte, error) {
+	return json.Marshal(o)
+}
+
+func (o *order) RestoreSnapshot(state []byte) error {
+	return json.Unmarshal(state, o)
+}
+
+func (o *order) Place() {
+	o.raise(&goevent.GenericEvent{EventName: "order.placed"})
+}
+
+func (o *order) AddItem() {
+	o.raise(&goevent.GenericEvent{EventName: "order.item_added"})
+}
+
+func (o *order) raise(event goevent.Event) {
+	o.Apply(event)
+	o.Raise(event)
+}
+
+func (o *order) Apply(event goevent.Event) error {
+	o.applied++
+	switch event.Name() {
+	case "order.placed":
+		o.Status = "placed"
+	case "order.item_added":
+		o.Items++
+	default:
+		return errors.New("unknown event")
+	}
+	return nil
+}
+
+func TestSQLiteStore_SaveAndLoadAggregate(t *testing.T) {
+	ctx := context.Background()
+	path := filepath.Join(t.TempDir(), "events.db")
+	store, db := openStore(t, path)
+	evt := goevent.New(goevent.WithStore(store))
+
+	placed := &order{}
+	placed.SetAggregateID("order-1")
+	placed.Place()
+	placed.AddItem()
+	if _, err := evt.SaveAggregate(ctx, placed); err != nil {
+		t.Fatalf("SaveAggregate() failed: %v", err)
+	}
+	// Events of other aggregates and plain events share the table
+	evt.Dispatch(userCreated("ada@example.com")).Wait()
+	other := &order{}
+	other.SetAggregateID("order-2")
+	other.Place()
+	if _, err := evt.SaveAggregate(ctx, other); err != nil {
+		t.Fatalf("SaveAggregate() failed: %v", err)
+	}
+	placed.AddItem()
+	if _, err := evt.SaveAggregate(ctx, placed); err != nil {
+		t.Fatalf("SaveAggregate() failed: %v", err)
+	}
+	unacked(t, store, 0)
+	db.Close()
+
+	store, _ = openStore(t, path)
+	evt = goevent.New(goevent.WithStore(store))
+	loaded := &order{}
+	loaded.SetAggregateID("order-1")
+	if err := evt.LoadAggregate(ctx, loaded); err != nil {
+		t.Fatalf("LoadAggregate() failed: %v", err)
+	}
+	if loaded.Status != "placed" || loaded.Items != 2 || loaded.Version() != 3 {
+		t.Errorf("Expected the replayed order at version 3, got %+v", loaded)
+	}
+
+	events, _ := store.ReadFrom(ctx, 0, 0)
+	if len(events) != 5 || events[2].AggregateID != "" || events[4].AggregateID != "order-1" || events[4].Version != 3 {
+		t.Errorf("Expected ReadFrom to return every event with its aggregate, got %+v", events)
+	}
+}
+
+func TestSQLiteStore_AggregateVersionConflict(t *testing.T) {
+	ctx := context.Background()
+	store, _ := openStore(t, filepath.Join(t.TempDir(), "events.db"))
+	evt := goevent.New(goevent.WithStore(store))
+
+	first, second := &order{}, &order{}
+	first.SetAggregateID("order-1")
+	second.SetAggregateID("order-1")
+	first.AddItem()
+	second.AddItem()
+
+	if _, err := evt.SaveAggregate(ctx, first); err != nil {
+		t.Fatalf("SaveAggregate() failed: %v", err)
+	}
+	if _, err := evt.SaveAggregate(ctx, second); !errors.Is(err, goevent.ErrVersionConflict) {
+		t.Fatalf("Expected ErrVersionConflict, got %v", err)
+	}
+	if events, _ := store.ReadFrom(ctx, 0, 0); len(events) != 1 {
+		t.Errorf("Expected the rejected changes not to be stored, got %d events", len(events))
+	}
+
+	if err := evt.LoadAggregate(ctx, second); err != nil {
+		t.Fatalf("LoadAggregate() failed: %v", err)
+	}
+	second.AddItem()
+	if _, err := evt.SaveAggregate(ctx, second); err != nil {
+		t.Fatalf("SaveAggregate() after reloading failed: %v", err)
+	}
+	if second.Version() != 2 {
+		t.Errorf("Expected version 2, got %d", second.Version())
+	}
+}
+
+func TestSQLiteStore_Snapshots(t *testing.T) {
+	ctx := context.Background()
+	path := filepath.Join(t.TempDir(), "events.db")
+	store, db := openStore(t, path)
+	evt := goevent.New(goevent.WithStore(store))
+
+	if _, err := store.LoadLatestSnapshot(ctx, "order-1"); !errors.Is(err, goevent.ErrSnapshotNotFound) {
+		t.Fatalf("Expected ErrSnapshotNotFound, got %v", err)
+	}
+
+	placed := &order{}
+	placed.SetAggregateID("order-1")
+	placed.Place()
+	placed.AddItem()
+	placed.AddItem()
+	if _, err := evt.SaveAggregate(ctx, placed); err != nil {
+		t.Fatalf("SaveAggregate() failed: %v", err)
+	}
+	if err := evt.SnapshotAggregate(ctx, placed); err != nil {
+		t.Fatalf("SnapshotAggregate() failed: %v", err)
+	}
+	placed.AddItem()
+	if _, err := evt.SaveAggregate(ctx, placed); err != nil {
+		t.Fatalf("SaveAggregate() failed: %v", err)
+	}
+	unacked(t, store, 0)
+	db.Close()
+
+	store, _ = openStore(t, path)
+	evt = goevent.New(goevent.WithStore(store))
+
+	snapshot, err := store.LoadLatestSnapshot(ctx, "order-1")
+	if err != nil {
+		t.Fatalf("LoadLatestSnapshot() failed: %v", err)
+	}
+	if snapshot.Version != 3 || len(snapshot.State) == 0 || snapshot.Time.IsZero() {
+		t.Errorf("Expected a snapshot at version 3, got %+v", snapshot)
+	}
+
+	loaded := &order{}
+	loaded.SetAggregateID("order-1")
+	if err := evt.LoadAggregate(ctx, loaded); err != nil {
+		t.Fatalf("LoadAggregate() failed: %v", err)
+	}
+	if loaded.Status != "placed" || loaded.Items != 3 || loaded.Version() != 4 {
+		t.Errorf("Expected the order at version 4, got %+v", loaded)
+	}
+	if loaded.applied != 1 {
+		t.Errorf("Expected only the event after the snapshot to be replayed, got %d", loaded.applied)
+	}
+}
+
+func TestSQLiteStore_MigratesEventsTable(t *testing.T) {
+	ctx := context.Background()
+	path := filepath.Join(t.TempDir(), "events.db")
+	db, err := sql.Open("sqlite", path)
+	if err != nil {
+		t.Fatalf("sql.Open() failed: %v", err)
+	}
+	// The table as created before aggregates were supported
+	_, err = db.Exec(`CREATE TABLE goevent_events (
+		seq        INTEGER PRIMARY KEY AUTOINCREMENT,
+		name       TEXT    NOT NULL,
+		payload    TEXT    NOT NULL,
+		created_at TEXT    NOT NULL,
+		acked      INTEGER NOT NULL DEFAULT 0
+	)`)
+	if err != nil {
+		t.Fatalf("creating table failed: %v", err)
+	}
+	_, err = db.Exec(`INSERT INTO goevent_events (name, payload, created_at) VALUES ('user.created', '{}', '2024-01-01T00:00:00Z')`)
+	if err != nil {
+		t.Fatalf("inserting event failed: %v", err)
+	}
+	db.Close()
+
+	store, _ := openStore(t, path)
+	stored, err := store.AppendAggregate(ctx, "order-1", 0, []goevent.Event{&goevent.GenericEvent{EventName: "order.placed"}})
+	if err != nil {
+		t.Fatalf("AppendAggregate() failed: %v", err)
+	}
+	if stored[0].Seq != 2 || stored[0].Version != 1 {
+		t.Errorf("Expected event 2 at version 1, got %+v", stored[0])
+	}
+	if events, _ := store.ReadFrom(ctx, 0, 0); len(events) != 2 || events[0].Name != "user.created" {
+		t.Errorf("Expected the existing event to be kept, got %+v", events)
+	}
+}
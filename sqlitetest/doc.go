@@ -0,0 +1,4 @@
+// Package sqlitetest tests goevent.SQLiteStore against a real SQLite
+// driver. It is a separate module so that the core module does not depend
+// on one; it has no API of its own.
+package sqlitetest
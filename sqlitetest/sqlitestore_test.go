@@ -0,0 +1,175 @@
+package sqlitetest
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"path/filepath"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	_ "modernc.org/sqlite"
+
+	"github.com/openframebox/goevent"
+)
+
+type listener struct {
+	err   error
+	calls atomic.Int32
+}
+
+func (l *listener) EventName() string {
+	return "user.created"
+}
+
+func (l *listener) OnEvent(event goevent.Event) error {
+	l.calls.Add(1)
+	return l.err
+}
+
+func userCreated(email string) goevent.Event {
+	return &goevent.GenericEvent{EventName: "user.created", Data: map[string]any{"email": email}}
+}
+
+// openStore opens the SQLite database at path as a store, closing it
+// when the test ends
+func openStore(t *testing.T, path string) (*goevent.SQLiteStore, *sql.DB) {
+	t.Helper()
+	db, err := sql.Open("sqlite", path)
+	if err != nil {
+		t.Fatalf("sql.Open() failed: %v", err)
+	}
+	t.Cleanup(func() { db.Close() })
+
+	store, err := goevent.NewSQLiteStore(context.Background(), db)
+	if err != nil {
+		t.Fatalf("NewSQLiteStore() failed: %v", err)
+	}
+	return store, db
+}
+
+// unacked polls the store until the expected number of events remain
+// unacknowledged, since acks are written after the handle completes
+func unacked(t *testing.T, store goevent.Store, want int) []goevent.StoredEvent {
+	t.Helper()
+	deadline := time.Now().Add(time.Second)
+	for {
+		events, err := store.ReadFrom(context.Background(), 0, 0)
+		if err != nil {
+			t.Fatalf("ReadFrom() failed: %v", err)
+		}
+		var pending []goevent.StoredEvent
+		for _, stored := range events {
+			if !stored.Acked {
+				pending = append(pending, stored)
+			}
+		}
+		if len(pending) == want || time.Now().After(deadline) {
+			return pending
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+}
+
+func TestSQLiteStore_AcksSuccessfulDispatches(t *testing.T) {
+	store, _ := openStore(t, filepath.Join(t.TempDir(), "events.db"))
+	evt := goevent.New(goevent.WithStore(store))
+	evt.RegisterListener(&listener{})
+
+	evt.Dispatch(userCreated("ada@example.com")).Wait()
+
+	if pending := unacked(t, store, 0); len(pending) != 0 {
+		t.Errorf("Expected 0 unacked events, got %d", len(pending))
+	}
+	events, _ := store.ReadFrom(context.Background(), 0, 0)
+	if len(events) != 1 || events[0].Name != "user.created" || events[0].Payload["email"] != "ada@example.com" {
+		t.Errorf("Expected stored 'user.created' with email 'ada@example.com', got %+v", events)
+	}
+}
+
+func TestSQLiteStore_FailedDispatchesStayUnacked(t *testing.T) {
+	store, _ := openStore(t, filepath.Join(t.TempDir(), "events.db"))
+	evt := goevent.New(goevent.WithStore(store))
+	evt.RegisterListener(&listener{err: errors.New("failed")})
+
+	evt.Dispatch(userCreated("ada@example.com")).Wait()
+
+	if pending := unacked(t, store, 1); len(pending) != 1 {
+		t.Errorf("Expected 1 unacked event, got %d", len(pending))
+	}
+}
+
+func TestSQLiteStore_RedeliverAfterRestart(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "events.db")
+	store, db := openStore(t, path)
+
+	evt := goevent.New(goevent.WithStore(store))
+	evt.RegisterListener(&listener{err: errors.New("failed")})
+	evt.Dispatch(userCreated("first@example.com")).Wait()
+	evt.Dispatch(userCreated("second@example.com")).Wait()
+	unacked(t, store, 2)
+	db.Close()
+
+	// Restart with a healthy listener
+	store, _ = openStore(t, path)
+	healthy := &listener{}
+	evt = goevent.New(goevent.WithStore(store))
+	evt.RegisterListener(healthy)
+
+	handles, err := evt.Redeliver(context.Background())
+	if err != nil {
+		t.Fatalf("Redeliver() failed: %v", err)
+	}
+	if len(handles) != 2 {
+		t.Fatalf("Expected 2 redelivered events, got %d", len(handles))
+	}
+	for _, handle := range handles {
+		handle.Wait()
+	}
+
+	if calls := healthy.calls.Load(); calls != 2 {
+		t.Errorf("Expected listener to be called 2 times, got %d", calls)
+	}
+	if pending := unacked(t, store, 0); len(pending) != 0 {
+		t.Errorf("Expected 0 unacked events, got %d", len(pending))
+	}
+
+	events, _ := store.ReadFrom(context.Background(), 0, 0)
+	if len(events) != 2 {
+		t.Errorf("Expected redelivery not to append new events, got %d stored", len(events))
+	}
+}
+
+func TestSQLiteStore_SequenceContinuesAfterReopen(t *testing.T) {
+	ctx := context.Background()
+	path := filepath.Join(t.TempDir(), "events.db")
+	store, db := openStore(t, path)
+
+	first, _ := store.Append(ctx, userCreated("first@example.com"))
+	store.Append(ctx, userCreated("second@example.com"))
+	if err := store.Ack(ctx, first.Seq); err != nil {
+		t.Fatalf("Ack() failed: %v", err)
+	}
+	db.Close()
+
+	store, _ = openStore(t, path)
+	third, err := store.Append(ctx, userCreated("third@example.com"))
+	if err != nil {
+		t.Fatalf("Append() failed: %v", err)
+	}
+	if third.Seq != 3 {
+		t.Errorf("Expected sequence numbers to continue at 3, got %d", third.Seq)
+	}
+
+	events, _ := store.ReadFrom(ctx, 0, 0)
+	if len(events) != 3 || !events[0].Acked || events[1].Acked {
+		t.Fatalf("Expected 3 events with only the first acked, got %+v", events)
+	}
+	if page, _ := store.ReadFrom(ctx, 2, 1); len(page) != 1 || page[0].Seq != 2 || page[0].Payload["email"] != "second@example.com" {
+		t.Errorf("Expected ReadFrom(2, 1) to return event 2, got %+v", page)
+	}
+	if err := store.Ack(ctx, 42); err == nil {
+		t.Error("Expected Ack() of an unknown sequence number to fail")
+	}
+}
@@ -0,0 +1,184 @@
+package goevent
+
+import (
+	"encoding/json"
+	"fmt"
+	"slices"
+	"sort"
+)
+
+// stateVersion is the version of the State document format
+const stateVersion = 1
+
+// State is the runtime-mutable configuration of a bus. It captures
+// operational changes made while the bus is running so they can be
+// reapplied after a restart. Paused subscriptions are identified by
+// their ID, so only subscriptions made with SubscriptionID are matched
+// again after a restart. Rules holds the rule sets added with
+// RegisterRules, by name.
+type State struct {
+	Version             int                        `json:"version"`
+	Degraded            bool                       `json:"degraded"`
+	RateLimits          map[string]RateLimit       `json:"rate_limits,omitempty"`
+	Paused              bool                       `json:"paused,omitempty"`
+	PausedEvents        []string                   `json:"paused_events,omitempty"`
+	PausedSubscriptions []string                   `json:"paused_subscriptions,omitempty"`
+	DisabledListeners   []string                   `json:"disabled_listeners,omitempty"`
+	Rules               map[string]json.RawMessage `json:"rules,omitempty"`
+}
+
+// RuleSet is runtime-mutable configuration kept outside the bus that
+// belongs in its state, such as the table of a routing.Router
+type RuleSet interface {
+	ExportRules() (json.RawMessage, error)
+	// ImportRules replaces the rules, leaving them unchanged on error
+	ImportRules(data json.RawMessage) error
+}
+
+// RegisterRules adds rules to the state under name. A later call with
+// the same name replaces it.
+func (ge *GoEvent) RegisterRules(name string, rules RuleSet) {
+	ge.rulesMu.Lock()
+	defer ge.rulesMu.Unlock()
+	if ge.rules == nil {
+		ge.rules = make(map[string]RuleSet)
+	}
+	ge.rules[name] = rules
+}
+
+// ExportState returns the runtime-mutable configuration as a JSON document
+func (ge *GoEvent) ExportState() ([]byte, error) {
+	ge.degradation.mu.Lock()
+	state := State{
+		Version:  stateVersion,
+		Degraded: ge.degradation.manual,
+	}
+	ge.degradation.mu.Unlock()
+	state.RateLimits = ge.RateLimits()
+
+	if gate := ge.gate.state.Load(); gate != nil {
+		state.Paused = gate.paused
+		for eventName := range gate.pausedEvents {
+			state.PausedEvents = append(state.PausedEvents, eventName)
+		}
+		sort.Strings(state.PausedEvents)
+	}
+
+	ge.registryMu.RLock()
+	for id, sub := range ge.subscriptions {
+		if sub.Paused() {
+			state.PausedSubscriptions = append(state.PausedSubscriptions, id)
+		}
+	}
+	ge.registryMu.RUnlock()
+	sort.Strings(state.PausedSubscriptions)
+	state.DisabledListeners = ge.DisabledListeners()
+
+	ge.rulesMu.Lock()
+	defer ge.rulesMu.Unlock()
+	for name, rules := range ge.rules {
+		data, err := rules.ExportRules()
+		if err != nil {
+			return nil, fmt.Errorf("goevent: exporting rules %q: %w", name, err)
+		}
+		if state.Rules == nil {
+			state.Rules = make(map[string]json.RawMessage, len(ge.rules))
+		}
+		state.Rules[name] = data
+	}
+
+	return json.MarshalIndent(state, "", "  ")
+}
+
+// ImportState applies a JSON document produced by ExportState. Rule sets
+// are imported first, so nothing is applied if one of them is rejected;
+// rule sets the document has no entry for, or that are not registered,
+// are left alone.
+func (ge *GoEvent) ImportState(data []byte) error {
+	var state State
+	if err := json.Unmarshal(data, &state); err != nil {
+		return fmt.Errorf("goevent: decoding state: %w", err)
+	}
+	if state.Version != stateVersion {
+		return fmt.Errorf("goevent: unsupported state version %d", state.Version)
+	}
+	if err := ge.importRules(state.Rules); err != nil {
+		return err
+	}
+
+	ge.SetDegraded(state.Degraded)
+
+	for eventName := range ge.RateLimits() {
+		if _, ok := state.RateLimits[eventName]; !ok {
+			ge.SetRateLimit(eventName, RateLimit{})
+		}
+	}
+	for eventName, limit := range state.RateLimits {
+		ge.SetRateLimit(eventName, limit)
+	}
+
+	var enabled []string
+	for _, listenerType := range ge.DisabledListeners() {
+		if !slices.Contains(state.DisabledListeners, listenerType) {
+			enabled = append(enabled, listenerType)
+		}
+	}
+	ge.DisableListeners(state.DisabledListeners...)
+	ge.EnableListeners(enabled...)
+
+	ge.importPaused(state)
+	return nil
+}
+
+// importRules imports the registered rule sets that rules has an entry for
+func (ge *GoEvent) importRules(rules map[string]json.RawMessage) error {
+	ge.rulesMu.Lock()
+	defer ge.rulesMu.Unlock()
+	for name, data := range rules {
+		set, ok := ge.rules[name]
+		if !ok {
+			continue
+		}
+		if err := set.ImportRules(data); err != nil {
+			return fmt.Errorf("goevent: importing rules %q: %w", name, err)
+		}
+	}
+	return nil
+}
+
+// importPaused pauses and resumes the bus, events and subscriptions to
+// match state. Pauses are applied before resumes, so held events are
+// only released once.
+func (ge *GoEvent) importPaused(state State) {
+	var resumed []string
+	if current := ge.gate.state.Load(); current != nil {
+		for eventName := range current.pausedEvents {
+			if !slices.Contains(state.PausedEvents, eventName) {
+				resumed = append(resumed, eventName)
+			}
+		}
+	}
+	if len(state.PausedEvents) > 0 {
+		ge.PauseEvents(state.PausedEvents...)
+	}
+	if state.Paused {
+		ge.Pause()
+	}
+
+	ge.registryMu.RLock()
+	for id, sub := range ge.subscriptions {
+		if slices.Contains(state.PausedSubscriptions, id) {
+			sub.Pause()
+		} else {
+			sub.Resume()
+		}
+	}
+	ge.registryMu.RUnlock()
+
+	if len(resumed) > 0 {
+		ge.ResumeEvents(resumed...)
+	}
+	if !state.Paused {
+		ge.Resume()
+	}
+}
@@ -0,0 +1,161 @@
+package goevent
+
+import (
+	"encoding/json"
+	"errors"
+	"testing"
+)
+
+func TestState_ExportImportRoundTrip(t *testing.T) {
+	source := New()
+	source.SetDegraded(true)
+	source.Pause()
+	source.PauseEvents("order.placed", "order.shipped")
+	paused, err := source.Subscribe(&testSyncListener{}, SubscriptionID("paused"))
+	if err != nil {
+		t.Fatalf("Subscribe() failed: %v", err)
+	}
+	paused.Pause()
+	if _, err := source.Subscribe(&testSyncListener{}, SubscriptionID("running")); err != nil {
+		t.Fatalf("Subscribe() failed: %v", err)
+	}
+
+	data, err := source.ExportState()
+	if err != nil {
+		t.Fatalf("ExportState() failed: %v", err)
+	}
+
+	target := New()
+	target.PauseEvents("order.cancelled")
+	targetPaused, _ := target.Subscribe(&testSyncListener{}, SubscriptionID("paused"))
+	targetRunning, _ := target.Subscribe(&testSyncListener{}, SubscriptionID("running"))
+	targetRunning.Pause()
+	if err := target.ImportState(data); err != nil {
+		t.Fatalf("ImportState() failed: %v", err)
+	}
+	if !target.Degraded() {
+		t.Error("Expected degraded mode to be restored")
+	}
+
+	target.Resume()
+	if !target.Paused("order.placed") || !target.Paused("order.shipped") || target.Paused("order.cancelled") {
+		t.Error("Expected exactly the exported events to be paused")
+	}
+	if !targetPaused.Paused() || targetRunning.Paused() {
+		t.Error("Expected exactly the exported subscriptions to be paused")
+	}
+
+	if err := target.ImportState(data); err != nil {
+		t.Fatalf("ImportState() failed: %v", err)
+	}
+	if !target.Paused("test.event") {
+		t.Error("Expected the bus to be paused again")
+	}
+}
+
+func TestState_ImportRejectsInvalidDocuments(t *testing.T) {
+	evt := New()
+
+	tests := []struct {
+		name string
+		data string
+	}{
+		{"malformed", `{"version":`},
+		{"unknown version", `{"version": 99, "degraded": true}`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := evt.ImportState([]byte(tt.data)); err == nil {
+				t.Error("Expected ImportState() to fail")
+			}
+			if evt.Degraded() {
+				t.Error("Invalid document must not change the bus")
+			}
+		})
+	}
+}
+
+func TestState_RestoresDisabledListeners(t *testing.T) {
+	source := New()
+	source.DisableListeners("*goevent.testSyncListener")
+	data, err := source.ExportState()
+	if err != nil {
+		t.Fatalf("ExportState() failed: %v", err)
+	}
+
+	target := New()
+	listener := &testSyncListener{}
+	target.RegisterListener(listener)
+	target.DisableListeners("*goevent.testAsyncListener")
+	if err := target.ImportState(data); err != nil {
+		t.Fatalf("ImportState() failed: %v", err)
+	}
+
+	if got := target.DisabledListeners(); len(got) != 1 || got[0] != "*goevent.testSyncListener" {
+		t.Errorf("Expected exactly the exported listener to be disabled, got %v", got)
+	}
+	target.Dispatch(&TestEvent{}).Wait()
+	if listener.called {
+		t.Error("Expected the disabled listener not to be called")
+	}
+
+	target.EnableListeners("*goevent.testSyncListener")
+	target.Dispatch(&TestEvent{}).Wait()
+	if !listener.called {
+		t.Error("Expected the enabled listener to be called")
+	}
+}
+
+// testRuleSet keeps its rules as the raw document
+type testRuleSet struct {
+	data json.RawMessage
+	err  error
+}
+
+func (r *testRuleSet) ExportRules() (json.RawMessage, error) {
+	return r.data, nil
+}
+
+func (r *testRuleSet) ImportRules(data json.RawMessage) error {
+	if r.err != nil {
+		return r.err
+	}
+	r.data = data
+	return nil
+}
+
+func TestState_RestoresRules(t *testing.T) {
+	source := New()
+	source.RegisterRules("routing", &testRuleSet{data: json.RawMessage(`["exported"]`)})
+	data, err := source.ExportState()
+	if err != nil {
+		t.Fatalf("ExportState() failed: %v", err)
+	}
+
+	target := New()
+	rules, other := &testRuleSet{data: json.RawMessage(`[]`)}, &testRuleSet{data: json.RawMessage(`["kept"]`)}
+	target.RegisterRules("routing", rules)
+	target.RegisterRules("other", other)
+	if err := target.ImportState(data); err != nil {
+		t.Fatalf("ImportState() failed: %v", err)
+	}
+	var restored []string
+	if err := json.Unmarshal(rules.data, &restored); err != nil || len(restored) != 1 || restored[0] != "exported" {
+		t.Errorf("Expected the exported rules to be restored, got %s", rules.data)
+	}
+	if string(other.data) != `["kept"]` {
+		t.Errorf("Expected rules missing from the document to be kept, got %s", other.data)
+	}
+
+	rejecting := New()
+	rejecting.RegisterRules("routing", &testRuleSet{err: errors.New("invalid rule")})
+	source.SetDegraded(true)
+	data, _ = source.ExportState()
+	if err := rejecting.ImportState(data); err == nil {
+		t.Fatal("Expected ImportState() to fail when a rule set is rejected")
+	}
+	if rejecting.Degraded() {
+		t.Error("Rejected rules must not change the bus")
+	}
+}
@@ -0,0 +1,237 @@
+package goevent
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+)
+
+// StoredEvent is an event persisted by a Store
+type StoredEvent struct {
+	Seq     uint64
+	Name    string
+	Payload map[string]any
+	Time    time.Time
+	Acked   bool
+
+	// AggregateID and Version are set for events appended with
+	// AggregateStore.AppendAggregate
+	AggregateID string
+	Version     int
+}
+
+// Event reconstructs the stored event as its registered type, or as a
+// *GenericEvent if none is registered or the payload does not fit it.
+// See RegisterEventType.
+func (se StoredEvent) Event() Event {
+	return decodeOrGeneric(se.Name, se.Payload)
+}
+
+// Store persists dispatched events for at-least-once delivery
+type Store interface {
+	// Append persists an event and assigns it the next sequence number
+	Append(ctx context.Context, event Event) (StoredEvent, error)
+	// ReadFrom returns up to limit events with a sequence number of at
+	// least seq, in order. A limit of zero or less returns all of them.
+	ReadFrom(ctx context.Context, seq uint64, limit int) ([]StoredEvent, error)
+	// Ack marks an event as fully handled
+	Ack(ctx context.Context, seq uint64) error
+}
+
+// WithStore makes the bus durable: every dispatch is appended to the store
+// before delivery and acknowledged once all of its listeners succeeded.
+// After a restart, Redeliver dispatches events that were never acknowledged.
+func WithStore(store Store) Option {
+	return func(ge *GoEvent) {
+		ge.store = store
+	}
+}
+
+// alreadyStored marks a dispatch of an event the store already holds,
+// such as a redelivery
+func alreadyStored(seq uint64) DispatchOption {
+	return func(c *dispatchConfig) {
+		c.storedSeq = seq
+	}
+}
+
+// Redeliver dispatches every stored event that was never acknowledged,
+// oldest first. Register listeners before calling it.
+func (ge *GoEvent) Redeliver(ctx context.Context) ([]*DispatchHandle, error) {
+	if ge.store == nil {
+		return nil, nil
+	}
+
+	const pageSize = 256
+	var handles []*DispatchHandle
+	next := uint64(0)
+	for {
+		page, err := ge.store.ReadFrom(ctx, next, pageSize)
+		if err != nil {
+			return handles, err
+		}
+		for _, stored := range page {
+			if !stored.Acked {
+				handles = append(handles, ge.DispatchContext(ctx, stored.Event(), alreadyStored(stored.Seq)))
+			}
+			next = stored.Seq + 1
+		}
+		if len(page) < pageSize {
+			return handles, nil
+		}
+	}
+}
+
+// persist appends a dispatch to the store and acknowledges it once all
+// listeners succeeded. It reports whether delivery may proceed.
+func (ge *GoEvent) persist(handle *DispatchHandle, event Event, cfg dispatchConfig) bool {
+	if ge.store == nil {
+		return true
+	}
+
+	seq := cfg.storedSeq
+	if seq == 0 {
+		stored, err := ge.store.Append(handle.ctx, event)
+		if err != nil {
+			eventError := handle.newError(event.Name(), fmt.Errorf("goevent: persisting event: %w", err))
+			handle.recordError(eventError)
+			ge.recordError(eventError)
+			handle.markDone()
+			return false
+		}
+		seq = stored.Seq
+	}
+
+	handle.pinned.Store(true)
+	go func() {
+		<-handle.Done()
+		if len(handle.errorsSnapshot()) > 0 {
+			return
+		}
+		if err := ge.store.Ack(context.Background(), seq); err != nil {
+			ge.recordError(handle.newError(event.Name(), fmt.Errorf("goevent: acknowledging event: %w", err)))
+		}
+	}()
+	return true
+}
+
+// MemoryStore is a Store kept in memory, useful for tests and development
+type MemoryStore struct {
+	mu        sync.Mutex
+	events    []StoredEvent
+	versions  aggregateVersions
+	snapshots map[string]Snapshot // latest per aggregate
+}
+
+// NewMemoryStore creates an empty in-memory store
+func NewMemoryStore() *MemoryStore {
+	return &MemoryStore{}
+}
+
+// Append persists an event and assigns it the next sequence number
+func (s *MemoryStore) Append(ctx context.Context, event Event) (StoredEvent, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	stored := StoredEvent{
+		Seq:     uint64(len(s.events)) + 1,
+		Name:    event.Name(),
+		Payload: event.Payload(),
+		Time:    time.Now(),
+	}
+	s.events = append(s.events, stored)
+	return stored, nil
+}
+
+// AppendAggregate persists the events raised by an aggregate as one unit
+func (s *MemoryStore) AppendAggregate(ctx context.Context, aggregateID string, expectedVersion int, events []Event) ([]StoredEvent, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if err := s.versions.check(aggregateID, expectedVersion); err != nil {
+		return nil, err
+	}
+	if s.versions == nil {
+		s.versions = make(aggregateVersions)
+	}
+
+	now := time.Now()
+	stored := make([]StoredEvent, len(events))
+	for i, event := range events {
+		stored[i] = StoredEvent{
+			Seq:         uint64(len(s.events)) + 1,
+			Name:        event.Name(),
+			Payload:     event.Payload(),
+			Time:        now,
+			AggregateID: aggregateID,
+			Version:     expectedVersion + i + 1,
+		}
+		s.events = append(s.events, stored[i])
+	}
+	s.versions[aggregateID] = expectedVersion + len(events)
+	return stored, nil
+}
+
+// LoadAggregate returns the events of an aggregate above fromVersion
+func (s *MemoryStore) LoadAggregate(ctx context.Context, aggregateID string, fromVersion int) ([]StoredEvent, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return aggregateEvents(s.events, aggregateID, fromVersion), nil
+}
+
+// SaveSnapshot stores a snapshot of an aggregate
+func (s *MemoryStore) SaveSnapshot(ctx context.Context, snapshot Snapshot) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.snapshots == nil {
+		s.snapshots = make(map[string]Snapshot)
+	}
+	keepLatest(s.snapshots, snapshot)
+	return nil
+}
+
+// LoadLatestSnapshot returns the latest snapshot of an aggregate
+func (s *MemoryStore) LoadLatestSnapshot(ctx context.Context, aggregateID string) (Snapshot, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	snapshot, ok := s.snapshots[aggregateID]
+	if !ok {
+		return Snapshot{}, ErrSnapshotNotFound
+	}
+	return snapshot, nil
+}
+
+// ReadFrom returns up to limit events starting at seq
+func (s *MemoryStore) ReadFrom(ctx context.Context, seq uint64, limit int) ([]StoredEvent, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return readFrom(s.events, seq, limit), nil
+}
+
+// Ack marks an event as fully handled
+func (s *MemoryStore) Ack(ctx context.Context, seq uint64) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if seq == 0 || seq > uint64(len(s.events)) {
+		return fmt.Errorf("goevent: unknown sequence number %d", seq)
+	}
+	s.events[seq-1].Acked = true
+	return nil
+}
+
+// readFrom pages through events sorted by sequence number
+func readFrom(events []StoredEvent, seq uint64, limit int) []StoredEvent {
+	var page []StoredEvent
+	for _, stored := range events {
+		if stored.Seq < seq {
+			continue
+		}
+		if limit > 0 && len(page) == limit {
+			break
+		}
+		page = append(page, stored)
+	}
+	return page
+}
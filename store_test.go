@@ -0,0 +1,239 @@
+package goevent
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+// unacked polls the store until the expected number of events remain
+// unacknowledged, since acks are written after the handle completes
+func unacked(t *testing.T, store Store, want int) []StoredEvent {
+	t.Helper()
+	deadline := time.Now().Add(time.Second)
+	for {
+		events, err := store.ReadFrom(context.Background(), 0, 0)
+		if err != nil {
+			t.Fatalf("ReadFrom() failed: %v", err)
+		}
+		var pending []StoredEvent
+		for _, stored := range events {
+			if !stored.Acked {
+				pending = append(pending, stored)
+			}
+		}
+		if len(pending) == want || time.Now().After(deadline) {
+			return pending
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+}
+
+func TestStore_AcksSuccessfulDispatches(t *testing.T) {
+	store := NewMemoryStore()
+	evt := New(WithStore(store))
+	evt.RegisterListener(&testSyncListener{})
+
+	evt.Dispatch(&TestEvent{data: "ok"}).Wait()
+
+	if pending := unacked(t, store, 0); len(pending) != 0 {
+		t.Errorf("Expected 0 unacked events, got %d", len(pending))
+	}
+	events, _ := store.ReadFrom(context.Background(), 0, 0)
+	if len(events) != 1 || events[0].Name != "test.event" || events[0].Payload["data"] != "ok" {
+		t.Errorf("Expected stored 'test.event' with data 'ok', got %+v", events)
+	}
+}
+
+func TestStore_FailedDispatchesStayUnacked(t *testing.T) {
+	store := NewMemoryStore()
+	evt := New(WithStore(store))
+	evt.RegisterListener(&testErrorListener{})
+
+	evt.Dispatch(&TestEvent{data: "fail"}).Wait()
+
+	if pending := unacked(t, store, 1); len(pending) != 1 {
+		t.Errorf("Expected 1 unacked event, got %d", len(pending))
+	}
+}
+
+func TestStore_RedeliverAfterRestart(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "events.wal")
+	store, err := OpenFileStore(path)
+	if err != nil {
+		t.Fatalf("OpenFileStore() failed: %v", err)
+	}
+
+	evt := New(WithStore(store))
+	evt.RegisterListener(&testErrorListener{})
+	evt.Dispatch(&TestEvent{data: "first"}).Wait()
+	evt.Dispatch(&TestEvent{data: "second"}).Wait()
+	unacked(t, store, 2)
+	store.Close()
+
+	// Restart with a healthy listener
+	store, err = OpenFileStore(path)
+	if err != nil {
+		t.Fatalf("OpenFileStore() failed: %v", err)
+	}
+	defer store.Close()
+
+	listener := &testCountingListener{}
+	evt = New(WithStore(store))
+	evt.RegisterListener(listener)
+
+	handles, err := evt.Redeliver(context.Background())
+	if err != nil {
+		t.Fatalf("Redeliver() failed: %v", err)
+	}
+	if len(handles) != 2 {
+		t.Fatalf("Expected 2 redelivered events, got %d", len(handles))
+	}
+	for _, handle := range handles {
+		handle.Wait()
+	}
+
+	if listener.Count() != 2 {
+		t.Errorf("Expected listener to be called 2 times, got %d", listener.Count())
+	}
+	if pending := unacked(t, store, 0); len(pending) != 0 {
+		t.Errorf("Expected 0 unacked events, got %d", len(pending))
+	}
+
+	events, _ := store.ReadFrom(context.Background(), 0, 0)
+	if len(events) != 2 {
+		t.Errorf("Expected redelivery not to append new events, got %d stored", len(events))
+	}
+}
+
+func TestFileStore_Compact(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "events.wal")
+	store, err := OpenFileStore(path)
+	if err != nil {
+		t.Fatalf("OpenFileStore() failed: %v", err)
+	}
+
+	ctx := context.Background()
+	first, _ := store.Append(ctx, &TestEvent{data: "first"})
+	store.Append(ctx, &TestEvent{data: "second"})
+	if err := store.Ack(ctx, first.Seq); err != nil {
+		t.Fatalf("Ack() failed: %v", err)
+	}
+	if err := store.Compact(); err != nil {
+		t.Fatalf("Compact() failed: %v", err)
+	}
+	third, _ := store.Append(ctx, &TestEvent{data: "third"})
+	store.Close()
+
+	if third.Seq != 3 {
+		t.Errorf("Expected sequence numbers to continue at 3, got %d", third.Seq)
+	}
+
+	store, err = OpenFileStore(path)
+	if err != nil {
+		t.Fatalf("OpenFileStore() failed: %v", err)
+	}
+	defer store.Close()
+
+	events, _ := store.ReadFrom(ctx, 0, 0)
+	if len(events) != 2 {
+		t.Fatalf("Expected 2 events after compaction, got %d", len(events))
+	}
+	if events[0].Seq != 2 || events[0].Payload["data"] != "second" {
+		t.Errorf("Expected event 2 with data 'second', got %+v", events[0])
+	}
+	if page, _ := store.ReadFrom(ctx, 3, 1); len(page) != 1 || page[0].Seq != 3 {
+		t.Errorf("Expected ReadFrom(3, 1) to return event 3, got %+v", page)
+	}
+}
+
+func TestFileStore_IgnoresTornRecord(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "events.wal")
+	store, _ := OpenFileStore(path)
+	store.Append(context.Background(), &TestEvent{data: "first"})
+	store.Close()
+
+	// Simulate a crash in the middle of writing a record
+	file, _ := os.OpenFile(path, os.O_WRONLY|os.O_APPEND, 0o644)
+	file.WriteString(`{"op":"append","seq":2,"na`)
+	file.Close()
+
+	store, err := OpenFileStore(path)
+	if err != nil {
+		t.Fatalf("OpenFileStore() failed: %v", err)
+	}
+	second, err := store.Append(context.Background(), &TestEvent{data: "second"})
+	if err != nil {
+		t.Fatalf("Append() failed: %v", err)
+	}
+	store.Close()
+
+	store, err = OpenFileStore(path)
+	if err != nil {
+		t.Fatalf("OpenFileStore() after recovery failed: %v", err)
+	}
+	defer store.Close()
+
+	events, _ := store.ReadFrom(context.Background(), 0, 0)
+	if len(events) != 2 || second.Seq != 2 {
+		t.Errorf("Expected 2 events ending at seq 2, got %d events (last seq %d)", len(events), second.Seq)
+	}
+}
+
+func TestStore_ReleasedHandleIsAcked(t *testing.T) {
+	store := NewMemoryStore()
+	evt := New(WithStore(store))
+	evt.RegisterListener(&testSyncListener{})
+
+	evt.Dispatch(&TestEvent{data: "released"}).Release()
+	evt.Dispatch(&TestEvent{data: "reuses the pool"}).Release()
+
+	if pending := unacked(t, store, 0); len(pending) != 0 {
+		t.Errorf("Expected released dispatches to be acked, got %d unacked", len(pending))
+	}
+}
+
+func TestStore_BatchListenerAcksAfterDelivery(t *testing.T) {
+	store := NewMemoryStore()
+	evt := New(WithStore(store))
+	listener := &testBatchListener{opts: ListenerOptions{BatchSize: 3}}
+	evt.RegisterBatchListener(listener)
+
+	first := evt.Dispatch(&TestEvent{data: "a"})
+	evt.Dispatch(&TestEvent{data: "b"})
+	select {
+	case <-first.Done():
+		t.Fatal("Expected the dispatch to stay open until its batch is delivered")
+	default:
+	}
+	// Acking now would lose the events if the process died before the batch
+	if pending := unacked(t, store, 2); len(pending) != 2 {
+		t.Fatalf("Expected 2 unacked events while the batch fills, got %d", len(pending))
+	}
+
+	evt.Dispatch(&TestEvent{data: "c"})
+	first.Wait()
+	if pending := unacked(t, store, 0); len(pending) != 0 {
+		t.Errorf("Expected the delivered batch to be acked, got %d unacked", len(pending))
+	}
+
+	// A failed batch is not acknowledged, so it can be redelivered
+	listener.mu.Lock()
+	listener.err = errors.New("warehouse down")
+	listener.mu.Unlock()
+	var handles []*DispatchHandle
+	for i := 0; i < 3; i++ {
+		handles = append(handles, evt.Dispatch(&TestEvent{data: "d"}))
+	}
+	for _, handle := range handles {
+		if err := handle.Err(); err == nil {
+			t.Error("Expected the batch failure on every dispatch of the batch")
+		}
+	}
+	if pending := unacked(t, store, 3); len(pending) != 3 {
+		t.Errorf("Expected the failed batch to stay unacked, got %d", len(pending))
+	}
+}
@@ -0,0 +1,149 @@
+package goevent
+
+import (
+	"errors"
+	"fmt"
+	"sync/atomic"
+)
+
+// ErrDuplicateSubscription is returned by Subscribe when a subscription
+// with the requested ID exists already
+var ErrDuplicateSubscription = errors.New("goevent: duplicate subscription ID")
+
+// Subscription is a listener registered with Subscribe. It manages the
+// listener at runtime without keeping a reference to the listener itself.
+type Subscription struct {
+	ge         *GoEvent
+	id         string
+	listener   Listener
+	eventNames []string
+	paused     atomic.Bool
+	removed    atomic.Bool
+}
+
+// SubscribeOption configures Subscribe
+type SubscribeOption func(*subscribeConfig)
+
+type subscribeConfig struct {
+	id string
+}
+
+// SubscriptionID sets the ID of a subscription instead of a generated one
+func SubscriptionID(id string) SubscribeOption {
+	return func(c *subscribeConfig) {
+		c.id = id
+	}
+}
+
+// Subscribe registers a listener like RegisterListenerE and returns its
+// subscription. The listener is listed with its subscription's ID by
+// Listeners.
+func (ge *GoEvent) Subscribe(listener Listener, opts ...SubscribeOption) (*Subscription, error) {
+	cfg := subscribeConfig{}
+	for _, opt := range opts {
+		opt(&cfg)
+	}
+	if cfg.id == "" {
+		cfg.id = newID()
+	}
+
+	if err := ge.claimListeners([]Listener{listener}, true); err != nil {
+		return nil, err
+	}
+	sub := &Subscription{ge: ge, id: cfg.id, listener: listener, eventNames: eventNamesOf(listener)}
+
+	ge.registryMu.Lock()
+	if _, ok := ge.subscriptions[cfg.id]; ok {
+		ge.releaseInstance(listener)
+		ge.registryMu.Unlock()
+		return nil, fmt.Errorf("%w: %s", ErrDuplicateSubscription, cfg.id)
+	}
+	if ge.subscriptions == nil {
+		ge.subscriptions = make(map[string]*Subscription)
+	}
+	ge.subscriptions[cfg.id] = sub
+	ge.registryMu.Unlock()
+
+	ge.subscribeAll(listener, sub, optionsOf(listener))
+	return sub, nil
+}
+
+// Subscription returns the subscription with the given ID
+func (ge *GoEvent) Subscription(id string) (*Subscription, bool) {
+	ge.registryMu.RLock()
+	defer ge.registryMu.RUnlock()
+	sub, ok := ge.subscriptions[id]
+	return sub, ok
+}
+
+// ID returns the subscription's ID
+func (s *Subscription) ID() string {
+	if s == nil {
+		return ""
+	}
+	return s.id
+}
+
+// Pause stops delivering events to the listener until Resume. Events
+// dispatched while it is paused are not delivered later.
+func (s *Subscription) Pause() {
+	s.paused.Store(true)
+}
+
+// Resume delivers events to a paused listener again
+func (s *Subscription) Resume() {
+	s.paused.Store(false)
+}
+
+// Paused reports whether the subscription is paused
+func (s *Subscription) Paused() bool {
+	return s.paused.Load()
+}
+
+// isPaused reports whether events should skip the subscription's
+// listener. It is false for listeners without a subscription.
+func (s *Subscription) isPaused() bool {
+	return s != nil && s.paused.Load()
+}
+
+// Unsubscribe removes the listener from the bus. Calls already under way
+// finish, and events its digest, debouncer or rate limit holds are
+// delivered before Unsubscribe returns. The listener may be registered
+// again afterwards. Unsubscribing more than once has no effect.
+func (s *Subscription) Unsubscribe() {
+	if s.removed.Swap(true) {
+		return
+	}
+	ge := s.ge
+	for _, eventName := range s.eventNames {
+		ge.dispatcher.unsubscribe(eventName, s)
+	}
+	for _, queue := range ge.removeQueues(s.listener) {
+		queue.flush()
+	}
+
+	ge.registryMu.Lock()
+	delete(ge.subscriptions, s.id)
+	ge.releaseInstance(s.listener)
+	var emptied []string
+	for _, eventName := range s.eventNames {
+		var kept []ListenerInfo
+		for _, info := range ge.registry[eventName] {
+			if info.SubscriptionID != s.id {
+				kept = append(kept, info)
+			}
+		}
+		if len(kept) == 0 {
+			delete(ge.registry, eventName)
+			emptied = append(emptied, eventName)
+		} else {
+			ge.registry[eventName] = kept
+		}
+	}
+	hooks := ge.onLast
+	ge.registryMu.Unlock()
+
+	for _, eventName := range emptied {
+		notifyDemand(hooks, eventName)
+	}
+}
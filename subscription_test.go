@@ -0,0 +1,126 @@
+package goevent
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestSubscription_Unsubscribe(t *testing.T) {
+	evt := New()
+	var emptied []string
+	evt.OnLastUnsubscriber(func(eventName string) {
+		emptied = append(emptied, eventName)
+	})
+	other := &testSyncListener{}
+	evt.RegisterListener(other)
+	recorder := &testNameRecorder{name: "test.event"}
+	sub, err := evt.Subscribe(recorder)
+	if err != nil {
+		t.Fatalf("Subscribe() failed: %v", err)
+	}
+	if got, ok := evt.Subscription(sub.ID()); !ok || got != sub {
+		t.Error("Expected the subscription to be found by its ID")
+	}
+
+	evt.Dispatch(&TestEvent{})
+	sub.Unsubscribe()
+	sub.Unsubscribe()
+	evt.Dispatch(&TestEvent{})
+
+	if len(recorder.received) != 1 {
+		t.Errorf("Expected 1 event before unsubscribing, got %d", len(recorder.received))
+	}
+	if infos := evt.Listeners()["test.event"]; len(infos) != 1 || infos[0].SubscriptionID != "" {
+		t.Errorf("Expected only the other listener to be left, got %+v", infos)
+	}
+	if len(emptied) != 0 {
+		t.Errorf("Expected no last-unsubscriber notification, got %v", emptied)
+	}
+	if _, ok := evt.Subscription(sub.ID()); ok {
+		t.Error("Expected the subscription to be forgotten")
+	}
+
+	// The listener can be registered again
+	if _, err := evt.Subscribe(recorder); err != nil {
+		t.Errorf("Expected the unsubscribed listener to be accepted again, got %v", err)
+	}
+}
+
+func TestSubscription_LastUnsubscriber(t *testing.T) {
+	evt := New()
+	var emptied []string
+	evt.OnLastUnsubscriber(func(eventName string) {
+		emptied = append(emptied, eventName)
+	})
+	sub, _ := evt.Subscribe(&testNamedListener{name: "order.placed"})
+	sub.Unsubscribe()
+
+	if len(emptied) != 1 || emptied[0] != "order.placed" || len(evt.Events()) != 0 {
+		t.Errorf("Expected order.placed to lose its last listener, got %v", emptied)
+	}
+}
+
+func TestSubscription_Pause(t *testing.T) {
+	evt := New()
+	recorder := &testNameRecorder{name: "test.event"}
+	async := &testAsyncListener{}
+	syncSub, _ := evt.Subscribe(recorder)
+	asyncSub, _ := evt.Subscribe(async)
+
+	syncSub.Pause()
+	asyncSub.Pause()
+	if !syncSub.Paused() {
+		t.Error("Expected the subscription to be paused")
+	}
+	evt.Dispatch(&TestEvent{}).Wait()
+	if len(recorder.received) != 0 || async.called {
+		t.Fatal("Expected paused listeners not to be called")
+	}
+
+	syncSub.Resume()
+	asyncSub.Resume()
+	evt.Dispatch(&TestEvent{}).Wait()
+	if len(recorder.received) != 1 || !async.called {
+		t.Error("Expected resumed listeners to be called")
+	}
+}
+
+func TestSubscribe_ID(t *testing.T) {
+	evt := New()
+	sub, err := evt.Subscribe(&testNamedListener{name: "a"}, SubscriptionID("mailer"))
+	if err != nil || sub.ID() != "mailer" {
+		t.Fatalf("Expected the subscription mailer, got %v", err)
+	}
+	if _, err := evt.Subscribe(&testNamedListener{name: "b"}, SubscriptionID("mailer")); !errors.Is(err, ErrDuplicateSubscription) {
+		t.Errorf("Expected ErrDuplicateSubscription, got %v", err)
+	}
+	if _, err := evt.Subscribe(nil); !errors.Is(err, ErrInvalidListener) {
+		t.Errorf("Expected ErrInvalidListener, got %v", err)
+	}
+}
+
+func TestSubscription_UnsubscribeReleasesQueues(t *testing.T) {
+	evt := New()
+	listener := &testDigestListener{interval: time.Hour}
+	sub, err := evt.Subscribe(listener)
+	if err != nil {
+		t.Fatalf("Subscribe() failed: %v", err)
+	}
+	evt.Dispatch(&TestEvent{})
+	sub.Unsubscribe()
+	if listener.count() != 1 {
+		t.Fatalf("Expected the held event to be delivered on Unsubscribe, got %d digests", listener.count())
+	}
+
+	if _, err := evt.Subscribe(listener); err != nil {
+		t.Fatalf("Subscribe() failed: %v", err)
+	}
+	evt.Dispatch(&TestEvent{})
+	if queued := evt.QueuedEvents(listener); len(queued) != 1 {
+		t.Errorf("Expected only the new subscription's event to be queued, got %d", len(queued))
+	}
+	if flushed := evt.FlushQueue(listener); flushed != 1 || listener.count() != 2 {
+		t.Errorf("Expected 1 event flushed into a second digest, got %d and %d digests", flushed, listener.count())
+	}
+}
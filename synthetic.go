@@ -0,0 +1,364 @@
+package goevent
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"math/big"
+	"math/rand"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/santhosh-tekuri/jsonschema/v5"
+)
+
+// maxSyntheticDepth bounds the nesting of generated payloads, so
+// recursive schemas terminate
+const maxSyntheticDepth = 8
+
+// SyntheticOptions configures a Synthesizer
+type SyntheticOptions struct {
+	// Mix maps event names to their relative share of the generated
+	// events. Nil generates every event with a registered schema equally
+	// often.
+	Mix map[string]float64
+
+	// Seed makes the generated sequence reproducible. Zero seeds from
+	// the current time.
+	Seed int64
+
+	// Rate limits Run to this many events per second. Zero dispatches as
+	// fast as the bus accepts them.
+	Rate float64
+
+	// Count stops Run after this many events. Zero runs until its
+	// context is done.
+	Count int
+}
+
+// Synthesizer produces events with random payloads that satisfy the
+// schemas registered with RegisterSchema, for load tests and demos.
+//
+// Payloads respect types, enums, constants, numeric bounds, string and
+// array lengths, and common formats such as date-time, email and uuid.
+// Schema examples are used when present. Patterns are not, so schemas
+// relying on them may produce invalid values.
+type Synthesizer struct {
+	ge       *GoEvent
+	interval time.Duration
+	count    int
+
+	mu      sync.Mutex
+	rng     *rand.Rand
+	names   []string
+	weights []float64 // cumulative
+	schemas []*jsonschema.Schema
+}
+
+// Synthesizer creates a Synthesizer for the registered schemas. It fails
+// if the mix names an event without a schema or no event can be
+// generated.
+func (ge *GoEvent) Synthesizer(opts SyntheticOptions) (*Synthesizer, error) {
+	seed := opts.Seed
+	if seed == 0 {
+		seed = time.Now().UnixNano()
+	}
+	s := &Synthesizer{ge: ge, count: opts.Count, rng: rand.New(rand.NewSource(seed))}
+	if opts.Rate > 0 {
+		s.interval = time.Duration(float64(time.Second) / opts.Rate)
+	}
+
+	ge.schemas.mu.RLock()
+	defer ge.schemas.mu.RUnlock()
+
+	mix := opts.Mix
+	if mix == nil {
+		mix = make(map[string]float64, len(ge.schemas.byName))
+		for name := range ge.schemas.byName {
+			mix[name] = 1
+		}
+	}
+	names := make([]string, 0, len(mix))
+	for name := range mix {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	var total float64
+	for _, name := range names {
+		schema := ge.schemas.byName[name]
+		if schema == nil {
+			return nil, fmt.Errorf("goevent: no schema registered for %s", name)
+		}
+		if mix[name] <= 0 {
+			continue
+		}
+		total += mix[name]
+		s.names = append(s.names, name)
+		s.weights = append(s.weights, total)
+		s.schemas = append(s.schemas, schema)
+	}
+	if len(s.names) == 0 {
+		return nil, errors.New("goevent: no events to synthesize")
+	}
+	return s, nil
+}
+
+// Next returns a new synthetic event, chosen according to the mix
+func (s *Synthesizer) Next() Event {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	pick := s.rng.Float64() * s.weights[len(s.weights)-1]
+	i := sort.SearchFloat64s(s.weights, pick)
+	if i == len(s.weights) {
+		i--
+	}
+
+	payload, _ := s.value(s.schemas[i], 0).(map[string]any)
+	if payload == nil {
+		payload = map[string]any{}
+	}
+	return &GenericEvent{EventName: s.names[i], Data: payload}
+}
+
+// Run dispatches synthetic events on the bus at the configured rate. It
+// returns the number dispatched once Count is reached or ctx is done.
+func (s *Synthesizer) Run(ctx context.Context) (int, error) {
+	dispatched := 0
+	for s.count == 0 || dispatched < s.count {
+		if dispatched > 0 && s.interval > 0 {
+			select {
+			case <-ctx.Done():
+			case <-s.ge.clock.After(s.interval):
+			}
+		}
+		if err := ctx.Err(); err != nil {
+			return dispatched, err
+		}
+		s.ge.DispatchContext(ctx, s.Next())
+		dispatched++
+	}
+	return dispatched, nil
+}
+
+// value generates a value satisfying schema. The caller must hold mu.
+func (s *Synthesizer) value(schema *jsonschema.Schema, depth int) any {
+	if schema == nil || depth > maxSyntheticDepth {
+		return nil
+	}
+	if schema.Ref != nil {
+		return s.value(schema.Ref, depth+1)
+	}
+	if len(schema.Constant) > 0 {
+		return jsonValue(schema.Constant[0])
+	}
+	if len(schema.Enum) > 0 {
+		return jsonValue(schema.Enum[s.rng.Intn(len(schema.Enum))])
+	}
+	if len(schema.Examples) > 0 {
+		return jsonValue(schema.Examples[s.rng.Intn(len(schema.Examples))])
+	}
+	if len(schema.OneOf) > 0 {
+		return s.value(schema.OneOf[s.rng.Intn(len(schema.OneOf))], depth+1)
+	}
+	if len(schema.AnyOf) > 0 {
+		return s.value(schema.AnyOf[s.rng.Intn(len(schema.AnyOf))], depth+1)
+	}
+	if len(schema.AllOf) > 0 {
+		// The schema's own properties are merged with those of its subschemas
+		own := &jsonschema.Schema{Properties: schema.Properties, Required: schema.Required}
+		subs := append(schema.AllOf[:len(schema.AllOf):len(schema.AllOf)], own)
+
+		merged := map[string]any{}
+		for _, sub := range subs {
+			part, _ := s.value(sub, depth+1).(map[string]any)
+			for key, v := range part {
+				merged[key] = v
+			}
+		}
+		return merged
+	}
+	return s.typed(schema, depth)
+}
+
+// typed generates a value of the schema's type
+func (s *Synthesizer) typed(schema *jsonschema.Schema, depth int) any {
+	typ := ""
+	for _, t := range schema.Types {
+		if t != "null" {
+			typ = t
+			break
+		}
+	}
+	if typ == "" {
+		switch {
+		case len(schema.Properties) > 0:
+			typ = "object"
+		case schema.Items != nil || schema.Items2020 != nil:
+			typ = "array"
+		case len(schema.Types) > 0:
+			return nil
+		default:
+			typ = "string"
+		}
+	}
+
+	switch typ {
+	case "object":
+		return s.object(schema, depth)
+	case "array":
+		return s.array(schema, depth)
+	case "integer":
+		lo, hi := s.bounds(schema, 0, 1000)
+		lo, hi = math.Ceil(lo), math.Floor(hi)
+		if hi < lo {
+			return int64(lo)
+		}
+		return int64(lo) + s.rng.Int63n(int64(hi-lo)+1)
+	case "number":
+		lo, hi := s.bounds(schema, 0, 1000)
+		return math.Round((lo+s.rng.Float64()*(hi-lo))*100) / 100
+	case "boolean":
+		return s.rng.Intn(2) == 1
+	default:
+		return s.str(schema)
+	}
+}
+
+func (s *Synthesizer) object(schema *jsonschema.Schema, depth int) map[string]any {
+	required := make(map[string]bool, len(schema.Required))
+	for _, name := range schema.Required {
+		required[name] = true
+	}
+
+	keys := make([]string, 0, len(schema.Properties))
+	for key := range schema.Properties {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	object := make(map[string]any, len(keys))
+	for _, key := range keys {
+		// Optional properties are left out half of the time
+		if !required[key] && s.rng.Intn(2) == 0 {
+			continue
+		}
+		object[key] = s.value(schema.Properties[key], depth+1)
+	}
+	return object
+}
+
+func (s *Synthesizer) array(schema *jsonschema.Schema, depth int) []any {
+	items := schema.Items2020
+	if items == nil {
+		items, _ = schema.Items.(*jsonschema.Schema)
+	}
+
+	lo, hi := max(schema.MinItems, 0), schema.MaxItems
+	if hi < 0 {
+		hi = lo + 3
+	}
+	n := lo + s.rng.Intn(max(hi-lo, 0)+1)
+
+	array := make([]any, 0, n)
+	for len(array) < n {
+		array = append(array, s.value(items, depth+1))
+	}
+	return array
+}
+
+// bounds returns the numeric range allowed by schema, defaulting to
+// [lo, hi] around the given bound when only one side is set
+func (s *Synthesizer) bounds(schema *jsonschema.Schema, lo, hi float64) (float64, float64) {
+	span := hi - lo
+	minSet, maxSet := false, false
+	if v, ok := ratFloat(schema.Minimum); ok {
+		lo, minSet = v, true
+	}
+	if v, ok := ratFloat(schema.ExclusiveMinimum); ok {
+		lo, minSet = v+1, true
+	}
+	if v, ok := ratFloat(schema.Maximum); ok {
+		hi, maxSet = v, true
+	}
+	if v, ok := ratFloat(schema.ExclusiveMaximum); ok {
+		hi, maxSet = v-1, true
+	}
+	switch {
+	case minSet && !maxSet:
+		hi = lo + span
+	case maxSet && !minSet:
+		lo = hi - span
+	}
+	return lo, hi
+}
+
+var syntheticWords = []string{
+	"alpha", "bravo", "charlie", "delta", "echo", "foxtrot", "golf", "hotel",
+	"india", "juliet", "kilo", "lima", "mike", "november", "oscar", "papa",
+}
+
+func (s *Synthesizer) str(schema *jsonschema.Schema) string {
+	switch schema.Format {
+	case "date-time":
+		return s.ge.clock.Now().Add(-time.Duration(s.rng.Int63n(int64(30 * 24 * time.Hour)))).UTC().Format(time.RFC3339)
+	case "date":
+		return s.ge.clock.Now().AddDate(0, 0, -s.rng.Intn(365)).Format(time.DateOnly)
+	case "email":
+		return s.word() + "." + s.word() + "@example.com"
+	case "uuid":
+		b := make([]byte, 16)
+		s.rng.Read(b)
+		b[6] = b[6]&0x0f | 0x40
+		b[8] = b[8]&0x3f | 0x80
+		return fmt.Sprintf("%x-%x-%x-%x-%x", b[0:4], b[4:6], b[6:8], b[8:10], b[10:16])
+	case "uri", "url":
+		return "https://example.com/" + s.word()
+	case "hostname":
+		return s.word() + ".example.com"
+	case "ipv4":
+		return fmt.Sprintf("10.%d.%d.%d", s.rng.Intn(256), s.rng.Intn(256), 1+s.rng.Intn(254))
+	}
+
+	lo, hi := max(schema.MinLength, 0), schema.MaxLength
+	var b strings.Builder
+	b.WriteString(s.word())
+	for b.Len() < lo {
+		b.WriteString("-" + s.word())
+	}
+	text := b.String()
+	if hi >= 0 && len(text) > hi {
+		text = text[:hi]
+	}
+	return text
+}
+
+func (s *Synthesizer) word() string {
+	return syntheticWords[s.rng.Intn(len(syntheticWords))]
+}
+
+// jsonValue converts the json.Number values of compiled schemas
+func jsonValue(v any) any {
+	number, ok := v.(json.Number)
+	if !ok {
+		return v
+	}
+	if i, err := number.Int64(); err == nil {
+		return i
+	}
+	f, _ := number.Float64()
+	return f
+}
+
+func ratFloat(r *big.Rat) (float64, bool) {
+	if r == nil {
+		return 0, false
+	}
+	f, _ := r.Float64()
+	return f, true
+}
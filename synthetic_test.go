@@ -0,0 +1,108 @@
+package goevent
+
+import (
+	"context"
+	"regexp"
+	"testing"
+)
+
+const testOrderSchema = `{
+	"type": "object",
+	"required": ["id", "status", "total", "items", "customer"],
+	"properties": {
+		"id": {"type": "string", "format": "uuid"},
+		"status": {"enum": ["pending", "paid", "shipped"]},
+		"total": {"type": "number", "minimum": 1, "maximum": 500},
+		"quantity": {"type": "integer", "minimum": 1, "maximum": 5},
+		"items": {"type": "array", "minItems": 1, "maxItems": 3, "items": {"type": "string", "maxLength": 4}},
+		"customer": {
+			"type": "object",
+			"required": ["email"],
+			"properties": {"email": {"type": "string", "format": "email"}}
+		}
+	}
+}`
+
+func TestSynthesizer_SatisfiesSchema(t *testing.T) {
+	evt := New(WithStrictSchemas())
+	if err := evt.RegisterSchema("order.created", []byte(testOrderSchema)); err != nil {
+		t.Fatalf("RegisterSchema() failed: %v", err)
+	}
+
+	synth, err := evt.Synthesizer(SyntheticOptions{Seed: 1})
+	if err != nil {
+		t.Fatalf("Synthesizer() failed: %v", err)
+	}
+	uuid := regexp.MustCompile(`^[0-9a-f]{8}-[0-9a-f]{4}-4[0-9a-f]{3}-[89ab][0-9a-f]{3}-[0-9a-f]{12}$`)
+	for i := 0; i < 100; i++ {
+		event := synth.Next()
+		handle := evt.Dispatch(event)
+		if errs := handle.GetErrors(); len(errs) > 0 {
+			t.Fatalf("Expected a valid payload, got %v for %v", errs[0], event.Payload())
+		}
+		if id, _ := event.Payload()["id"].(string); !uuid.MatchString(id) {
+			t.Errorf("Expected a uuid, got %q", id)
+		}
+	}
+}
+
+func TestSynthesizer_Mix(t *testing.T) {
+	evt := New()
+	evt.RegisterSchema("order.created", []byte(testOrderSchema))
+	evt.RegisterSchema("user.login", []byte(`{"type": "object", "properties": {"user": {"const": "demo"}}, "required": ["user"]}`))
+
+	synth, err := evt.Synthesizer(SyntheticOptions{Seed: 7, Mix: map[string]float64{"order.created": 1, "user.login": 3}})
+	if err != nil {
+		t.Fatalf("Synthesizer() failed: %v", err)
+	}
+	counts := map[string]int{}
+	for i := 0; i < 4000; i++ {
+		event := synth.Next()
+		counts[event.Name()]++
+		if event.Name() == "user.login" && event.Payload()["user"] != "demo" {
+			t.Fatalf("Expected the constant user, got %v", event.Payload())
+		}
+	}
+	if counts["user.login"] < 2800 || counts["user.login"] > 3200 {
+		t.Errorf("Expected about 3000 user.login events, got %v", counts)
+	}
+
+	if _, err := evt.Synthesizer(SyntheticOptions{Mix: map[string]float64{"unknown": 1}}); err == nil {
+		t.Error("Expected an error for an event without a schema")
+	}
+	if _, err := New().Synthesizer(SyntheticOptions{}); err == nil {
+		t.Error("Expected an error without schemas")
+	}
+}
+
+func TestSynthesizer_RunCount(t *testing.T) {
+	evt := New()
+	evt.RegisterSchema("test.event", []byte(`{"type": "object"}`))
+	listener := &testCountingListener{}
+	evt.RegisterListener(listener)
+
+	synth, _ := evt.Synthesizer(SyntheticOptions{Count: 25})
+	n, err := synth.Run(context.Background())
+	if err != nil || n != 25 {
+		t.Fatalf("Expected 25 events, got %d (%v)", n, err)
+	}
+	if got := listener.Count(); got != 25 {
+		t.Errorf("Expected the listener to see 25 events, got %d", got)
+	}
+}
+
+func BenchmarkSyntheticDispatch(b *testing.B) {
+	evt := New()
+	evt.RegisterSchema("test.event", []byte(testOrderSchema))
+	evt.RegisterListener(&testSyncListener{})
+	synth, _ := evt.Synthesizer(SyntheticOptions{Seed: 1})
+	events := make([]Event, 1024)
+	for i := range events {
+		events[i] = synth.Next()
+	}
+
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		evt.Dispatch(events[i%len(events)])
+	}
+}
@@ -0,0 +1,39 @@
+package goevent
+
+import (
+	"math/rand"
+	"sync"
+	"time"
+)
+
+// throttle lets through at most one event per interval, dropping the
+// rest until the interval has passed
+type throttle struct {
+	interval time.Duration
+	clock    Clock
+
+	mu   sync.Mutex
+	last time.Time
+}
+
+// allow reports whether an event may pass now
+func (t *throttle) allow() bool {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	now := t.clock.Now()
+	if !t.last.IsZero() && now.Sub(t.last) < t.interval {
+		return false
+	}
+	t.last = now
+	return true
+}
+
+// sampled reports whether an event is selected at the given rate.
+// Rates outside (0, 1) select every event.
+func sampled(rate float64) bool {
+	if rate <= 0 || rate >= 1 {
+		return true
+	}
+	return rand.Float64() < rate
+}
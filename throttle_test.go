@@ -0,0 +1,65 @@
+package goevent
+
+import (
+	"testing"
+	"time"
+)
+
+// testSampledListener counts calls with configurable throttling and sampling
+type testSampledListener struct {
+	testCountingListener
+	throttle time.Duration
+	rate     float64
+}
+
+func (l *testSampledListener) Options() ListenerOptions {
+	return ListenerOptions{Throttle: l.throttle, SampleRate: l.rate}
+}
+
+func TestThrottle(t *testing.T) {
+	evt := New()
+	listener := &testSampledListener{throttle: 50 * time.Millisecond}
+	evt.RegisterListener(listener)
+
+	for i := 0; i < 5; i++ {
+		evt.Dispatch(&TestEvent{data: "burst"})
+	}
+	if listener.Count() != 1 {
+		t.Fatalf("Expected 1 call within the interval, got %d", listener.Count())
+	}
+
+	time.Sleep(60 * time.Millisecond)
+	evt.Dispatch(&TestEvent{data: "later"})
+	if listener.Count() != 2 {
+		t.Errorf("Expected a second call after the interval, got %d", listener.Count())
+	}
+}
+
+func TestSampleRate(t *testing.T) {
+	evt := New()
+	listener := &testSampledListener{rate: 0.25}
+	evt.RegisterListener(listener)
+
+	const dispatches = 2000
+	for i := 0; i < dispatches; i++ {
+		evt.Dispatch(&TestEvent{data: "sample"})
+	}
+
+	// Expect roughly 500; the bounds are loose enough to never flake
+	if n := listener.Count(); n < 350 || n > 650 {
+		t.Errorf("Expected about 25%% of %d events, got %d", dispatches, n)
+	}
+}
+
+func TestSampleRate_ZeroHandlesAll(t *testing.T) {
+	evt := New()
+	listener := &testSampledListener{}
+	evt.RegisterListener(listener)
+
+	for i := 0; i < 10; i++ {
+		evt.Dispatch(&TestEvent{data: "all"})
+	}
+	if listener.Count() != 10 {
+		t.Errorf("Expected 10 calls, got %d", listener.Count())
+	}
+}
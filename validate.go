@@ -0,0 +1,37 @@
+package goevent
+
+import (
+	"errors"
+	"fmt"
+)
+
+// ErrInvalidEvent wraps the error recorded on a dispatch whose event
+// failed validation
+var ErrInvalidEvent = errors.New("goevent: invalid event")
+
+// ValidatingEvent is an event that checks itself before dispatch.
+// If Validate returns an error, no listener is called and the dispatch
+// records an EventError wrapping ErrInvalidEvent and that error.
+type ValidatingEvent interface {
+	Event
+	Validate() error
+}
+
+// validate checks an event that implements ValidatingEvent. It reports
+// whether the dispatch was rejected.
+func (ge *GoEvent) validate(handle *DispatchHandle, event Event) bool {
+	validating, ok := event.(ValidatingEvent)
+	if !ok {
+		return false
+	}
+	err := validating.Validate()
+	if err == nil {
+		return false
+	}
+
+	eventError := handle.newError(event.Name(), fmt.Errorf("%w: %w", ErrInvalidEvent, err))
+	handle.recordError(eventError)
+	ge.recordError(eventError)
+	handle.markDone()
+	return true
+}
@@ -0,0 +1,61 @@
+package goevent
+
+import (
+	"errors"
+	"testing"
+)
+
+type testValidatingEvent struct {
+	email string
+}
+
+func (e *testValidatingEvent) Name() string {
+	return "test.event"
+}
+
+func (e *testValidatingEvent) Payload() map[string]any {
+	return map[string]any{"email": e.email}
+}
+
+func (e *testValidatingEvent) Validate() error {
+	if e.email == "" {
+		return errors.New("email is required")
+	}
+	return nil
+}
+
+func TestValidate_RejectsInvalidEvent(t *testing.T) {
+	evt := New()
+	listener := &testCountingListener{}
+	evt.RegisterListener(listener)
+
+	handle := evt.Dispatch(&testValidatingEvent{})
+	handle.Wait()
+
+	if listener.Count() != 0 {
+		t.Error("Invalid event was delivered")
+	}
+	errs := handle.GetErrors()
+	if len(errs) != 1 || !errors.Is(errs[0].Err, ErrInvalidEvent) || errs[0].Err.Error() != "goevent: invalid event: email is required" {
+		t.Errorf("Expected ErrInvalidEvent wrapping the validation error, got %v", errs)
+	}
+	if len(evt.GetErrors()) != 1 {
+		t.Errorf("Expected the error to be recorded globally, got %d", len(evt.GetErrors()))
+	}
+
+	if err := evt.DispatchE(&testValidatingEvent{}); err == nil {
+		t.Error("Expected DispatchE to return the validation error")
+	}
+}
+
+func TestValidate_DeliversValidEvent(t *testing.T) {
+	evt := New()
+	listener := &testCountingListener{}
+	evt.RegisterListener(listener)
+
+	handle := evt.Dispatch(&testValidatingEvent{email: "a@example.com"})
+
+	if listener.Count() != 1 || len(handle.GetErrors()) != 0 {
+		t.Errorf("Expected valid event to be delivered without errors, got %d deliveries and %v", listener.Count(), handle.GetErrors())
+	}
+}
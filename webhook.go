@@ -0,0 +1,167 @@
+package goevent
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+// Headers set on webhook requests
+const (
+	WebhookEventHeader     = "X-Goevent-Event"
+	WebhookDeliveryHeader  = "X-Goevent-Delivery" // envelope ID, the same for every retry
+	WebhookTimestampHeader = "X-Goevent-Timestamp"
+	WebhookSignatureHeader = "X-Goevent-Signature"
+)
+
+// WebhookListener posts event envelopes, encoded with JSONCodec, to one
+// or more URLs. Each URL is retried on its own, so an endpoint that failed
+// does not cause the others to receive the event twice.
+//
+// When Secret is set, requests carry an HMAC-SHA256 signature of the
+// timestamp and body in the X-Goevent-Signature header; see
+// WebhookSignature.
+type WebhookListener struct {
+	Event   string
+	URLs    []string
+	Secret  []byte
+	Header  http.Header
+	Client  *http.Client // defaults to http.DefaultClient
+	Retry   RetryPolicy  // applied per URL to network errors, 429 and 5xx responses
+	Timeout time.Duration
+	Async   bool
+}
+
+// EventName returns the event the webhook is posted for
+func (w *WebhookListener) EventName() string {
+	return w.Event
+}
+
+// Options makes the listener async if Async is set
+func (w *WebhookListener) Options() ListenerOptions {
+	return ListenerOptions{Async: w.Async}
+}
+
+// OnEvent posts the event to every URL
+func (w *WebhookListener) OnEvent(event Event) error {
+	return w.OnEventContext(context.Background(), event)
+}
+
+// OnEventContext posts the event to every URL. The envelope carries the
+// metadata of the dispatch ctx belongs to.
+func (w *WebhookListener) OnEventContext(ctx context.Context, event Event) error {
+	env := NewEnvelope(event)
+	if handle, ok := HandleFromContext(ctx); ok {
+		env.Metadata = handle.Metadata()
+	}
+	body, err := JSONCodec{}.Marshal(env)
+	if err != nil {
+		return err
+	}
+
+	var errs []error
+	for _, url := range w.URLs {
+		if err := w.post(ctx, url, env, body); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// post delivers body to url, retrying transient failures
+func (w *WebhookListener) post(ctx context.Context, url string, env Envelope, body []byte) error {
+	attempt := 1
+	for {
+		err := w.send(ctx, url, env, body)
+		var statusErr *webhookStatusError
+		permanent := errors.As(err, &statusErr) && !statusErr.transient()
+		if err == nil || permanent || attempt >= w.Retry.MaxAttempts {
+			return err
+		}
+
+		timer := time.NewTimer(w.Retry.delay(attempt))
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return err
+		case <-timer.C:
+		}
+		attempt++
+	}
+}
+
+// send makes a single request
+func (w *WebhookListener) send(ctx context.Context, url string, env Envelope, body []byte) error {
+	if w.Timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, w.Timeout)
+		defer cancel()
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	for name, values := range w.Header {
+		req.Header[name] = values
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set(WebhookEventHeader, env.Name)
+	req.Header.Set(WebhookDeliveryHeader, env.ID)
+	if len(w.Secret) > 0 {
+		timestamp := strconv.FormatInt(time.Now().Unix(), 10)
+		req.Header.Set(WebhookTimestampHeader, timestamp)
+		req.Header.Set(WebhookSignatureHeader, WebhookSignature(w.Secret, timestamp, body))
+	}
+
+	client := w.Client
+	if client == nil {
+		client = http.DefaultClient
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return fmt.Errorf("goevent: webhook %s: %w", url, err)
+	}
+	defer resp.Body.Close()
+	io.Copy(io.Discard, resp.Body)
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return &webhookStatusError{url: url, status: resp.StatusCode}
+	}
+	return nil
+}
+
+// WebhookSignature returns the X-Goevent-Signature value for a request:
+// "sha256=" followed by the hex HMAC-SHA256 of the timestamp header, a
+// dot and the body. Receivers should compare it with hmac.Equal and
+// reject old timestamps to prevent replays.
+func WebhookSignature(secret []byte, timestamp string, body []byte) string {
+	mac := hmac.New(sha256.New, secret)
+	mac.Write([]byte(timestamp))
+	mac.Write([]byte("."))
+	mac.Write(body)
+	return "sha256=" + hex.EncodeToString(mac.Sum(nil))
+}
+
+// webhookStatusError reports a response outside the 2xx range
+type webhookStatusError struct {
+	url    string
+	status int
+}
+
+func (e *webhookStatusError) Error() string {
+	return fmt.Sprintf("goevent: webhook %s: status %d", e.url, e.status)
+}
+
+// transient reports whether the request may succeed if retried
+func (e *webhookStatusError) transient() bool {
+	return e.status == http.StatusTooManyRequests || e.status >= 500
+}
@@ -0,0 +1,123 @@
+package goevent
+
+import (
+	"crypto/hmac"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+// testWebhookServer answers with the queued status codes, then 200
+type testWebhookServer struct {
+	*httptest.Server
+
+	mu       sync.Mutex
+	statuses []int
+	requests []*http.Request
+	bodies   [][]byte
+}
+
+func newTestWebhookServer(t *testing.T, statuses ...int) *testWebhookServer {
+	s := &testWebhookServer{statuses: statuses}
+	s.Server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		s.mu.Lock()
+		defer s.mu.Unlock()
+		s.requests = append(s.requests, r)
+		s.bodies = append(s.bodies, body)
+		if len(s.statuses) > 0 {
+			w.WriteHeader(s.statuses[0])
+			s.statuses = s.statuses[1:]
+		}
+	}))
+	t.Cleanup(s.Close)
+	return s
+}
+
+func (s *testWebhookServer) count() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return len(s.requests)
+}
+
+func TestWebhookListener_Signed(t *testing.T) {
+	srv := newTestWebhookServer(t)
+	secret := []byte("s3cret")
+
+	evt := New()
+	evt.RegisterListener(&WebhookListener{Event: "test.event", URLs: []string{srv.URL}, Secret: secret})
+	handle := evt.Dispatch(&GenericEvent{EventName: "test.event", Data: map[string]any{"id": "42"}})
+	if errs := handle.GetErrors(); len(errs) > 0 {
+		t.Fatalf("Expected no errors, got %v", errs[0])
+	}
+
+	if srv.count() != 1 {
+		t.Fatalf("Expected 1 request, got %d", srv.count())
+	}
+	req, body := srv.requests[0], srv.bodies[0]
+	if req.Header.Get(WebhookEventHeader) != "test.event" || req.Header.Get(WebhookDeliveryHeader) != handle.DispatchID() {
+		t.Errorf("Expected event and delivery headers, got %v", req.Header)
+	}
+	want := WebhookSignature(secret, req.Header.Get(WebhookTimestampHeader), body)
+	if !hmac.Equal([]byte(req.Header.Get(WebhookSignatureHeader)), []byte(want)) {
+		t.Errorf("Expected signature %s, got %s", want, req.Header.Get(WebhookSignatureHeader))
+	}
+
+	env, err := JSONCodec{}.Unmarshal(body)
+	if err != nil {
+		t.Fatalf("Expected a JSON envelope, got %v", err)
+	}
+	if env.Event.Payload()["id"] != "42" || env.CorrelationID != handle.DispatchID() {
+		t.Errorf("Expected the event and its metadata, got %+v", env)
+	}
+}
+
+func TestWebhookListener_Retry(t *testing.T) {
+	flaky := newTestWebhookServer(t, http.StatusServiceUnavailable, http.StatusInternalServerError)
+	rejecting := newTestWebhookServer(t, http.StatusBadRequest)
+	healthy := newTestWebhookServer(t)
+
+	evt := New()
+	evt.RegisterListener(&WebhookListener{
+		Event: "test.event",
+		URLs:  []string{flaky.URL, rejecting.URL, healthy.URL},
+		Retry: RetryPolicy{MaxAttempts: 3, Backoff: ConstantBackoff(time.Millisecond)},
+	})
+	handle := evt.Dispatch(&GenericEvent{EventName: "test.event"})
+
+	if flaky.count() != 3 {
+		t.Errorf("Expected transient failures to be retried, got %d requests", flaky.count())
+	}
+	if rejecting.count() != 1 {
+		t.Errorf("Expected client errors not to be retried, got %d requests", rejecting.count())
+	}
+	if healthy.count() != 1 {
+		t.Errorf("Expected 1 request to the healthy endpoint, got %d", healthy.count())
+	}
+
+	errs := handle.GetErrors()
+	if len(errs) != 1 || !strings.Contains(errs[0].Error(), "status 400") {
+		t.Errorf("Expected only the rejected request to fail, got %v", errs)
+	}
+}
+
+func TestWebhookListener_Timeout(t *testing.T) {
+	release := make(chan struct{})
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		<-release
+	}))
+	defer srv.Close()
+	defer close(release)
+
+	evt := New()
+	evt.RegisterListener(&WebhookListener{Event: "test.event", URLs: []string{srv.URL}, Timeout: 20 * time.Millisecond})
+	handle := evt.Dispatch(&GenericEvent{EventName: "test.event"})
+
+	if errs := handle.GetErrors(); len(errs) != 1 || !strings.Contains(errs[0].Error(), "deadline exceeded") {
+		t.Errorf("Expected the request to time out, got %v", errs)
+	}
+}
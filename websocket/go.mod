@@ -0,0 +1,10 @@
+module github.com/openframebox/goevent/websocket
+
+go 1.21
+
+require (
+	github.com/coder/websocket v1.8.12
+	github.com/openframebox/goevent v0.1.0
+)
+
+require github.com/santhosh-tekuri/jsonschema/v5 v5.3.1 // indirect
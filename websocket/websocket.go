@@ -0,0 +1,384 @@
+// Package websocket streams bus events to browser clients over
+// WebSocket, for live dashboards driven by domain events.
+//
+// A Feed streams the events it was told to expose. Clients choose among
+// them with name patterns, given in the query string and changed later
+// with control messages:
+//
+//	feed := websocket.New(bus)
+//	feed.Expose("order.created", "order.shipped", "user.login")
+//	http.Handle("/events", feed)
+//	defer feed.Close()
+//
+//	// ws://host/events?events=order.*
+//	// client → server: {"subscribe": ["user.*"]}, {"unsubscribe": ["order.*"]}
+//	// server → client: goevent.JSONCodec envelopes, and {"dropped": n}
+//	//                  when events were dropped for a slow client
+//
+// Patterns use path.Match syntax, so "order.*" matches "order.created".
+//
+// Every connection has its own buffer. When a client cannot keep up,
+// the oldest buffered events are dropped and the client is told how many
+// it missed, or with WithOverflow(Disconnect) the connection is closed.
+package websocket
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"path"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/coder/websocket"
+
+	"github.com/openframebox/goevent"
+)
+
+// writeTimeout bounds how long a single message may take to send
+const writeTimeout = 10 * time.Second
+
+// Overflow decides what happens when a client's buffer is full
+type Overflow int
+
+const (
+	// DropOldest drops the oldest buffered event and reports the number
+	// of dropped events to the client
+	DropOldest Overflow = iota
+	// Disconnect closes the connection with StatusTryAgainLater
+	Disconnect
+)
+
+// Option configures a Feed
+type Option func(*Feed)
+
+// WithBufferSize sets how many events are buffered per connection.
+// Defaults to 256.
+func WithBufferSize(n int) Option {
+	return func(f *Feed) {
+		f.bufferSize = n
+	}
+}
+
+// WithOverflow sets what happens when a connection's buffer is full.
+// Defaults to DropOldest.
+func WithOverflow(policy Overflow) Option {
+	return func(f *Feed) {
+		f.overflow = policy
+	}
+}
+
+// WithAcceptOptions sets the options connections are accepted with,
+// such as the allowed origins
+func WithAcceptOptions(opts *websocket.AcceptOptions) Option {
+	return func(f *Feed) {
+		f.acceptOptions = opts
+	}
+}
+
+// Feed is an http.Handler streaming events to WebSocket clients
+type Feed struct {
+	bus           *goevent.GoEvent
+	bufferSize    int
+	overflow      Overflow
+	acceptOptions *websocket.AcceptOptions
+
+	mu      sync.Mutex
+	exposed map[string]bool
+	conns   map[*conn]struct{}
+	closed  chan struct{}
+	wg      sync.WaitGroup
+}
+
+// New creates a Feed for bus
+func New(bus *goevent.GoEvent, opts ...Option) *Feed {
+	f := &Feed{
+		bus:        bus,
+		bufferSize: 256,
+		exposed:    make(map[string]bool),
+		conns:      make(map[*conn]struct{}),
+		closed:     make(chan struct{}),
+	}
+	for _, opt := range opts {
+		opt(f)
+	}
+	return f
+}
+
+// Expose makes the events with the given names available to clients
+func (f *Feed) Expose(names ...string) {
+	var added []string
+	f.mu.Lock()
+	for _, name := range names {
+		if !f.exposed[name] {
+			f.exposed[name] = true
+			added = append(added, name)
+		}
+	}
+	f.mu.Unlock()
+
+	for _, name := range added {
+		f.bus.RegisterListener(&forwarder{feed: f, eventName: name})
+	}
+}
+
+// Close disconnects every client and waits for their handlers to return
+func (f *Feed) Close() {
+	f.mu.Lock()
+	select {
+	case <-f.closed:
+	default:
+		close(f.closed)
+	}
+	f.mu.Unlock()
+	f.wg.Wait()
+}
+
+// ServeHTTP upgrades the request and streams the events matching the
+// "events" query parameter, a comma-separated list of patterns
+func (f *Feed) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	var patterns []string
+	for _, param := range r.URL.Query()["events"] {
+		for _, pattern := range strings.Split(param, ",") {
+			if pattern = strings.TrimSpace(pattern); pattern != "" {
+				patterns = append(patterns, pattern)
+			}
+		}
+	}
+	if err := validPatterns(patterns); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	f.mu.Lock()
+	select {
+	case <-f.closed:
+		f.mu.Unlock()
+		http.Error(w, "feed closed", http.StatusServiceUnavailable)
+		return
+	default:
+	}
+	f.wg.Add(1)
+	f.mu.Unlock()
+	defer f.wg.Done()
+
+	ws, err := websocket.Accept(w, r, f.acceptOptions)
+	if err != nil {
+		return
+	}
+	c := newConn(f.bufferSize, f.overflow)
+	c.subscribe(patterns)
+
+	f.mu.Lock()
+	f.conns[c] = struct{}{}
+	f.mu.Unlock()
+	defer func() {
+		f.mu.Lock()
+		delete(f.conns, c)
+		f.mu.Unlock()
+	}()
+
+	ctx, cancel := context.WithCancel(r.Context())
+	defer cancel()
+	go f.readControl(ctx, cancel, ws, c)
+
+	status, reason := f.write(ctx, ws, c)
+	ws.Close(status, reason)
+}
+
+// controlMessage is a message sent by a client
+type controlMessage struct {
+	Subscribe   []string `json:"subscribe"`
+	Unsubscribe []string `json:"unsubscribe"`
+}
+
+// readControl applies the client's control messages until the
+// connection fails, then cancels the writer
+func (f *Feed) readControl(ctx context.Context, cancel context.CancelFunc, ws *websocket.Conn, c *conn) {
+	defer cancel()
+	for {
+		_, data, err := ws.Read(ctx)
+		if err != nil {
+			return
+		}
+		var msg controlMessage
+		if err := json.Unmarshal(data, &msg); err != nil {
+			ws.Close(websocket.StatusUnsupportedData, "invalid control message")
+			return
+		}
+		if err := validPatterns(msg.Subscribe); err != nil {
+			ws.Close(websocket.StatusPolicyViolation, err.Error())
+			return
+		}
+		c.subscribe(msg.Subscribe)
+		c.unsubscribe(msg.Unsubscribe)
+	}
+}
+
+// write sends buffered events until the connection ends, returning the
+// close status to send
+func (f *Feed) write(ctx context.Context, ws *websocket.Conn, c *conn) (websocket.StatusCode, string) {
+	for {
+		select {
+		case <-ctx.Done():
+			return websocket.StatusNormalClosure, ""
+		case <-f.closed:
+			return websocket.StatusGoingAway, "server shutting down"
+		case <-c.ready:
+		}
+
+		messages, dropped, overflowed := c.drain()
+		if overflowed {
+			return websocket.StatusTryAgainLater, "client too slow"
+		}
+		if dropped > 0 {
+			notice, _ := json.Marshal(map[string]int{"dropped": dropped})
+			messages = append([][]byte{notice}, messages...)
+		}
+		for _, msg := range messages {
+			writeCtx, cancel := context.WithTimeout(ctx, writeTimeout)
+			err := ws.Write(writeCtx, websocket.MessageText, msg)
+			cancel()
+			if err != nil {
+				return websocket.StatusInternalError, "write failed"
+			}
+		}
+	}
+}
+
+// deliver buffers an event for every connection subscribed to it
+func (f *Feed) deliver(ctx context.Context, event goevent.Event) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	var msg []byte
+	for c := range f.conns {
+		if !c.matches(event.Name()) {
+			continue
+		}
+		if msg == nil {
+			env := goevent.NewEnvelope(event)
+			if handle, ok := goevent.HandleFromContext(ctx); ok {
+				env.Metadata = handle.Metadata()
+			}
+			var err error
+			if msg, err = (goevent.JSONCodec{}).Marshal(env); err != nil {
+				return err
+			}
+		}
+		c.push(msg)
+	}
+	return nil
+}
+
+func validPatterns(patterns []string) error {
+	for _, pattern := range patterns {
+		if _, err := path.Match(pattern, ""); err != nil {
+			return fmt.Errorf("websocket: invalid pattern %q", pattern)
+		}
+	}
+	return nil
+}
+
+// conn is the subscription and buffer of one client
+type conn struct {
+	size     int
+	overflow Overflow
+	ready    chan struct{}
+
+	mu         sync.Mutex
+	patterns   map[string]bool
+	queue      [][]byte
+	dropped    int
+	overflowed bool
+}
+
+func newConn(size int, overflow Overflow) *conn {
+	return &conn{
+		size:     size,
+		overflow: overflow,
+		ready:    make(chan struct{}, 1),
+		patterns: make(map[string]bool),
+	}
+}
+
+func (c *conn) subscribe(patterns []string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	for _, pattern := range patterns {
+		c.patterns[pattern] = true
+	}
+}
+
+func (c *conn) unsubscribe(patterns []string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	for _, pattern := range patterns {
+		delete(c.patterns, pattern)
+	}
+}
+
+func (c *conn) matches(name string) bool {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	for pattern := range c.patterns {
+		if ok, _ := path.Match(pattern, name); ok {
+			return true
+		}
+	}
+	return false
+}
+
+// push buffers msg, applying the overflow policy if the buffer is full
+func (c *conn) push(msg []byte) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if len(c.queue) >= c.size {
+		if c.overflow == Disconnect {
+			c.overflowed = true
+		} else {
+			c.queue = c.queue[1:]
+			c.dropped++
+		}
+	}
+	if !c.overflowed {
+		c.queue = append(c.queue, msg)
+	}
+
+	select {
+	case c.ready <- struct{}{}:
+	default:
+	}
+}
+
+// drain takes the buffered messages and the number dropped since the
+// last drain
+func (c *conn) drain() ([][]byte, int, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	messages, dropped := c.queue, c.dropped
+	c.queue, c.dropped = nil, 0
+	return messages, dropped, c.overflowed
+}
+
+// forwarder buffers the events of one name for the feed's clients
+type forwarder struct {
+	feed      *Feed
+	eventName string
+}
+
+func (f *forwarder) EventName() string {
+	return f.eventName
+}
+
+func (f *forwarder) OnEvent(event goevent.Event) error {
+	return f.OnEventContext(context.Background(), event)
+}
+
+func (f *forwarder) OnEventContext(ctx context.Context, event goevent.Event) error {
+	return f.feed.deliver(ctx, event)
+}
@@ -0,0 +1,196 @@
+package websocket
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/coder/websocket"
+
+	"github.com/openframebox/goevent"
+)
+
+func startFeed(t *testing.T, opts ...Option) (*goevent.GoEvent, *Feed, *httptest.Server) {
+	bus := goevent.New()
+	feed := New(bus, opts...)
+	server := httptest.NewServer(feed)
+	t.Cleanup(func() {
+		feed.Close()
+		server.Close()
+	})
+	return bus, feed, server
+}
+
+// onlyConn returns the single connection of the feed
+func onlyConn(feed *Feed) *conn {
+	feed.mu.Lock()
+	defer feed.mu.Unlock()
+	for c := range feed.conns {
+		return c
+	}
+	return nil
+}
+
+func dial(t *testing.T, server *httptest.Server, query string) (*websocket.Conn, context.Context) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	t.Cleanup(cancel)
+	ws, _, err := websocket.Dial(ctx, "ws"+strings.TrimPrefix(server.URL, "http")+query, nil)
+	if err != nil {
+		t.Fatalf("Dial() failed: %v", err)
+	}
+	t.Cleanup(func() { ws.CloseNow() })
+	return ws, ctx
+}
+
+func waitForConns(t *testing.T, feed *Feed, n int) {
+	for {
+		feed.mu.Lock()
+		count := len(feed.conns)
+		feed.mu.Unlock()
+		if count == n {
+			return
+		}
+		time.Sleep(time.Millisecond)
+	}
+}
+
+func read(t *testing.T, ctx context.Context, ws *websocket.Conn) map[string]any {
+	_, data, err := ws.Read(ctx)
+	if err != nil {
+		t.Fatalf("Read() failed: %v", err)
+	}
+	var msg map[string]any
+	if err := json.Unmarshal(data, &msg); err != nil {
+		t.Fatalf("Expected JSON, got %q", data)
+	}
+	return msg
+}
+
+func TestFeed_StreamsMatchingEvents(t *testing.T) {
+	bus, feed, server := startFeed(t)
+	feed.Expose("order.created", "user.login")
+
+	ws, ctx := dial(t, server, "?events=order.*")
+	waitForConns(t, feed, 1)
+
+	bus.Dispatch(&goevent.GenericEvent{EventName: "user.login"})
+	handle := bus.Dispatch(&goevent.GenericEvent{EventName: "order.created", Data: map[string]any{"id": "42"}})
+
+	msg := read(t, ctx, ws)
+	if msg["name"] != "order.created" || msg["id"] != handle.DispatchID() {
+		t.Errorf("Expected the order.created envelope, got %v", msg)
+	}
+	if payload, _ := msg["payload"].(map[string]any); payload["id"] != "42" {
+		t.Errorf("Expected payload id 42, got %v", msg["payload"])
+	}
+}
+
+func TestFeed_ControlMessages(t *testing.T) {
+	bus, feed, server := startFeed(t)
+	feed.Expose("order.created", "user.login")
+
+	ws, ctx := dial(t, server, "?events=order.*")
+	waitForConns(t, feed, 1)
+	ws.Write(ctx, websocket.MessageText, []byte(`{"subscribe": ["user.*"], "unsubscribe": ["order.*"]}`))
+
+	// Wait for the control message to be applied
+	c := onlyConn(feed)
+	for !c.matches("user.login") {
+		time.Sleep(time.Millisecond)
+	}
+
+	bus.Dispatch(&goevent.GenericEvent{EventName: "order.created"})
+	bus.Dispatch(&goevent.GenericEvent{EventName: "user.login"})
+	if msg := read(t, ctx, ws); msg["name"] != "user.login" {
+		t.Errorf("Expected only user.login after resubscribing, got %v", msg)
+	}
+}
+
+func TestConn_Overflow(t *testing.T) {
+	c := newConn(2, DropOldest)
+	for _, msg := range []string{"1", "2", "3", "4", "5"} {
+		c.push([]byte(msg))
+	}
+	messages, dropped, overflowed := c.drain()
+	if len(messages) != 2 || string(messages[0]) != "4" || dropped != 3 || overflowed {
+		t.Errorf("Expected the 2 newest messages and 3 dropped, got %q, %d, %v", messages, dropped, overflowed)
+	}
+
+	c = newConn(1, Disconnect)
+	c.push([]byte("1"))
+	c.push([]byte("2"))
+	if _, _, overflowed := c.drain(); !overflowed {
+		t.Error("Expected the connection to overflow")
+	}
+}
+
+func TestFeed_ReportsDropped(t *testing.T) {
+	_, feed, server := startFeed(t)
+	ws, ctx := dial(t, server, "?events=tick")
+	waitForConns(t, feed, 1)
+
+	c := onlyConn(feed)
+	c.mu.Lock()
+	c.queue = [][]byte{[]byte(`{"name":"tick"}`)}
+	c.dropped = 3
+	c.mu.Unlock()
+	c.ready <- struct{}{}
+
+	if msg := read(t, ctx, ws); msg["dropped"] != 3.0 {
+		t.Errorf("Expected a dropped notice, got %v", msg)
+	}
+	if msg := read(t, ctx, ws); msg["name"] != "tick" {
+		t.Errorf("Expected the buffered event after the notice, got %v", msg)
+	}
+}
+
+func TestFeed_Disconnect(t *testing.T) {
+	_, feed, server := startFeed(t, WithOverflow(Disconnect))
+	ws, ctx := dial(t, server, "?events=tick")
+	waitForConns(t, feed, 1)
+
+	c := onlyConn(feed)
+	c.mu.Lock()
+	c.overflowed = true
+	c.mu.Unlock()
+	c.ready <- struct{}{}
+
+	_, _, err := ws.Read(ctx)
+	if websocket.CloseStatus(err) != websocket.StatusTryAgainLater {
+		t.Errorf("Expected StatusTryAgainLater, got %v", err)
+	}
+}
+
+func TestFeed_InvalidPattern(t *testing.T) {
+	_, _, server := startFeed(t)
+
+	resp, err := http.Get(server.URL + "?events=" + "[")
+	if err != nil {
+		t.Fatalf("Get() failed: %v", err)
+	}
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusBadRequest {
+		t.Errorf("Expected 400, got %d", resp.StatusCode)
+	}
+}
+
+func TestFeed_Close(t *testing.T) {
+	_, feed, server := startFeed(t)
+	ws, ctx := dial(t, server, "?events=*")
+	waitForConns(t, feed, 1)
+
+	errs := make(chan error)
+	go func() {
+		_, _, err := ws.Read(ctx)
+		errs <- err
+	}()
+	feed.Close()
+
+	if err := <-errs; websocket.CloseStatus(err) != websocket.StatusGoingAway {
+		t.Errorf("Expected StatusGoingAway, got %v", err)
+	}
+}
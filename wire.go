@@ -0,0 +1,353 @@
+package goevent
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// WireVersion is the newest wire frame version this package reads and
+// writes. Versions from 1 up to WireVersion are read.
+const WireVersion uint8 = 1
+
+const minWireVersion uint8 = 1
+
+// wireMagic starts every wire frame
+var wireMagic = []byte("GOEV")
+
+// ErrWireVersion is returned for frames and peers whose wire version is
+// not supported
+var ErrWireVersion = errors.New("goevent: unsupported wire version")
+
+// ErrUnknownCodec is returned for frames encoded with a codec the
+// WireCodec was not given
+var ErrUnknownCodec = errors.New("goevent: unknown codec")
+
+// CodecID identifies the codec of a wire frame
+type CodecID uint8
+
+const (
+	CodecJSON     CodecID = 1 // JSONCodec
+	CodecProtobuf CodecID = 2 // protobuf.Codec
+)
+
+// WireCodec is a Codec that wraps envelopes in versioned frames, so
+// processes running different versions can exchange events during a
+// rolling upgrade. A frame is laid out as:
+//
+//	magic    "GOEV"
+//	version  uint8
+//	codec    uint8, a CodecID
+//	metadata uvarint length, then the event name, ID and Metadata.Fields
+//	         as uvarint-length-prefixed strings
+//	payload  the envelope encoded by the codec, up to the end
+//
+// Frames of every supported version and codec are read, whatever the
+// WireCodec writes. Data without the magic is read as JSONCodec output,
+// which transports sent before they framed envelopes.
+type WireCodec struct {
+	version uint8
+	codecs  []wireCodec // in order of preference; the first is written
+}
+
+type wireCodec struct {
+	id    CodecID
+	codec Codec
+}
+
+// WireOption configures a WireCodec
+type WireOption func(*WireCodec)
+
+// WithWireCodec adds a codec. Codecs are preferred in the order they are
+// added; JSONCodec is added last as CodecJSON unless given.
+func WithWireCodec(id CodecID, codec Codec) WireOption {
+	return func(c *WireCodec) {
+		c.codecs = append(c.codecs, wireCodec{id: id, codec: codec})
+	}
+}
+
+// WithWireVersion sets the frame version written. Pin it to the oldest
+// version still running in the fleet until every process is upgraded.
+func WithWireVersion(version uint8) WireOption {
+	return func(c *WireCodec) {
+		c.version = version
+	}
+}
+
+// NewWireCodec returns a WireCodec that writes frames of WireVersion
+// with the first codec added
+func NewWireCodec(opts ...WireOption) *WireCodec {
+	c := &WireCodec{version: WireVersion}
+	for _, opt := range opts {
+		opt(c)
+	}
+	if c.lookup(CodecJSON) == nil {
+		c.codecs = append(c.codecs, wireCodec{id: CodecJSON, codec: JSONCodec{}})
+	}
+	return c
+}
+
+func (c *WireCodec) lookup(id CodecID) Codec {
+	for _, wc := range c.codecs {
+		if wc.id == id {
+			return wc.codec
+		}
+	}
+	return nil
+}
+
+// Marshal encodes env as a frame
+func (c *WireCodec) Marshal(env Envelope) ([]byte, error) {
+	if c.version < minWireVersion || c.version > WireVersion {
+		return nil, fmt.Errorf("%w: %d", ErrWireVersion, c.version)
+	}
+	if env.Event == nil {
+		return nil, errors.New("goevent: marshal: envelope has no event")
+	}
+	if env.Name == "" {
+		env.Name = env.Event.Name()
+	}
+	preferred := c.codecs[0]
+	payload, err := preferred.codec.Marshal(env)
+	if err != nil {
+		return nil, err
+	}
+
+	fields := env.Metadata.Fields()
+	keys := make([]string, 0, len(fields))
+	for key := range fields {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	metadata := appendWireString(nil, env.Name)
+	metadata = appendWireString(metadata, env.ID)
+	metadata = binary.AppendUvarint(metadata, uint64(len(keys)))
+	for _, key := range keys {
+		metadata = appendWireString(metadata, key)
+		metadata = appendWireString(metadata, fields[key])
+	}
+
+	frame := make([]byte, 0, len(wireMagic)+2+binary.MaxVarintLen64+len(metadata)+len(payload))
+	frame = append(frame, wireMagic...)
+	frame = append(frame, c.version, byte(preferred.id))
+	frame = binary.AppendUvarint(frame, uint64(len(metadata)))
+	frame = append(frame, metadata...)
+	return append(frame, payload...), nil
+}
+
+// Unmarshal decodes a frame with the codec it names
+func (c *WireCodec) Unmarshal(data []byte) (Envelope, error) {
+	if !bytes.HasPrefix(data, wireMagic) {
+		codec := c.lookup(CodecJSON)
+		if codec == nil {
+			return Envelope{}, fmt.Errorf("%w: unframed data", ErrUnknownCodec)
+		}
+		return codec.Unmarshal(data)
+	}
+
+	header, payload, err := readWireFrame(data)
+	if err != nil {
+		return Envelope{}, err
+	}
+	codec := c.lookup(header.Codec)
+	if codec == nil {
+		return Envelope{}, fmt.Errorf("%w: %d", ErrUnknownCodec, header.Codec)
+	}
+	return codec.Unmarshal(payload)
+}
+
+// WireHeader is the part of a frame that can be read without decoding
+// the event, for routing and relaying
+type WireHeader struct {
+	Version  uint8
+	Codec    CodecID
+	Name     string
+	ID       string
+	Metadata map[string]string // in the form Metadata.Fields returns
+}
+
+// ReadWireHeader reads the header of a frame
+func ReadWireHeader(data []byte) (WireHeader, error) {
+	header, _, err := readWireFrame(data)
+	return header, err
+}
+
+// readWireFrame splits a frame into its header and payload
+func readWireFrame(data []byte) (WireHeader, []byte, error) {
+	if !bytes.HasPrefix(data, wireMagic) {
+		return WireHeader{}, nil, errors.New("goevent: not a wire frame")
+	}
+	data = data[len(wireMagic):]
+	if len(data) < 2 {
+		return WireHeader{}, nil, errors.New("goevent: truncated wire frame")
+	}
+	header := WireHeader{Version: data[0], Codec: CodecID(data[1])}
+	if header.Version < minWireVersion || header.Version > WireVersion {
+		return WireHeader{}, nil, fmt.Errorf("%w: %d", ErrWireVersion, header.Version)
+	}
+	data = data[2:]
+
+	size, n := binary.Uvarint(data)
+	if n <= 0 || size > uint64(len(data)-n) {
+		return WireHeader{}, nil, errors.New("goevent: truncated wire frame")
+	}
+	metadata, payload := data[n:n+int(size)], data[n+int(size):]
+
+	r := wireReader{data: metadata}
+	header.Name = r.string()
+	header.ID = r.string()
+	if count := r.uvarint(); count > 0 && r.err == nil {
+		header.Metadata = make(map[string]string, min(count, uint64(len(metadata))))
+		for i := uint64(0); i < count && r.err == nil; i++ {
+			key := r.string()
+			header.Metadata[key] = r.string()
+		}
+	}
+	if r.err != nil {
+		return WireHeader{}, nil, fmt.Errorf("goevent: reading wire frame metadata: %w", r.err)
+	}
+	return header, payload, nil
+}
+
+func appendWireString(b []byte, s string) []byte {
+	b = binary.AppendUvarint(b, uint64(len(s)))
+	return append(b, s...)
+}
+
+// wireReader reads the metadata of a frame, keeping the first error
+type wireReader struct {
+	data []byte
+	err  error
+}
+
+func (r *wireReader) uvarint() uint64 {
+	if r.err != nil {
+		return 0
+	}
+	v, n := binary.Uvarint(r.data)
+	if n <= 0 {
+		r.err = errors.New("invalid length")
+		return 0
+	}
+	r.data = r.data[n:]
+	return v
+}
+
+func (r *wireReader) string() string {
+	size := r.uvarint()
+	if r.err != nil {
+		return ""
+	}
+	if size > uint64(len(r.data)) {
+		r.err = errors.New("truncated string")
+		return ""
+	}
+	s := string(r.data[:size])
+	r.data = r.data[size:]
+	return s
+}
+
+// WireHello advertises the frame versions and codecs a peer reads, for
+// transports that negotiate when they connect
+type WireHello struct {
+	MinVersion uint8
+	MaxVersion uint8
+	Codecs     []CodecID // in order of preference
+}
+
+// Hello advertises what c reads
+func (c *WireCodec) Hello() WireHello {
+	hello := WireHello{MinVersion: minWireVersion, MaxVersion: WireVersion}
+	for _, wc := range c.codecs {
+		hello.Codecs = append(hello.Codecs, wc.id)
+	}
+	return hello
+}
+
+// Negotiate returns a WireCodec for sending to the peer that sent hello.
+// It writes the newest version both read, no newer than the version c
+// writes, with the codec the peer prefers most among those c has.
+func (c *WireCodec) Negotiate(hello WireHello) (*WireCodec, error) {
+	version, err := NegotiateWireVersion(c.Hello(), hello)
+	if err != nil {
+		return nil, err
+	}
+	if version = min(version, c.version); version < hello.MinVersion {
+		return nil, fmt.Errorf("%w: peer reads versions from %d, this process writes %d", ErrWireVersion, hello.MinVersion, c.version)
+	}
+	negotiated := &WireCodec{version: version}
+	for _, id := range hello.Codecs {
+		if codec := c.lookup(id); codec != nil {
+			negotiated.codecs = append(negotiated.codecs, wireCodec{id: id, codec: codec})
+		}
+	}
+	if len(negotiated.codecs) == 0 {
+		return nil, fmt.Errorf("%w: no codec in common with %v", ErrUnknownCodec, hello.Codecs)
+	}
+	// Keep reading whatever c reads
+	for _, wc := range c.codecs {
+		if negotiated.lookup(wc.id) == nil {
+			negotiated.codecs = append(negotiated.codecs, wc)
+		}
+	}
+	return negotiated, nil
+}
+
+// NegotiateWireVersion returns the newest version two peers both read
+func NegotiateWireVersion(local, remote WireHello) (uint8, error) {
+	version := min(local.MaxVersion, remote.MaxVersion)
+	if version < max(local.MinVersion, remote.MinVersion) {
+		return 0, fmt.Errorf("%w: peer reads versions %d to %d, this process %d to %d",
+			ErrWireVersion, remote.MinVersion, remote.MaxVersion, local.MinVersion, local.MaxVersion)
+	}
+	return version, nil
+}
+
+// String formats the hello as ParseWireHello reads it, for example
+// "versions=1-2 codecs=2,1"
+func (h WireHello) String() string {
+	codecs := make([]string, len(h.Codecs))
+	for i, id := range h.Codecs {
+		codecs[i] = strconv.Itoa(int(id))
+	}
+	return fmt.Sprintf("versions=%d-%d codecs=%s", h.MinVersion, h.MaxVersion, strings.Join(codecs, ","))
+}
+
+// ParseWireHello parses the format of WireHello.String
+func ParseWireHello(s string) (WireHello, error) {
+	var hello WireHello
+	var versions, codecs string
+	for _, field := range strings.Fields(s) {
+		key, value, _ := strings.Cut(field, "=")
+		switch key {
+		case "versions":
+			versions = value
+		case "codecs":
+			codecs = value
+		}
+	}
+
+	minVersion, maxVersion, ok := strings.Cut(versions, "-")
+	lo, errLo := strconv.ParseUint(minVersion, 10, 8)
+	hi, errHi := strconv.ParseUint(maxVersion, 10, 8)
+	if !ok || errLo != nil || errHi != nil || lo > hi {
+		return WireHello{}, fmt.Errorf("goevent: invalid wire hello %q", s)
+	}
+	hello.MinVersion, hello.MaxVersion = uint8(lo), uint8(hi)
+
+	if codecs != "" {
+		for _, id := range strings.Split(codecs, ",") {
+			n, err := strconv.ParseUint(id, 10, 8)
+			if err != nil {
+				return WireHello{}, fmt.Errorf("goevent: invalid wire hello %q", s)
+			}
+			hello.Codecs = append(hello.Codecs, CodecID(n))
+		}
+	}
+	return hello, nil
+}
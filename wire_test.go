@@ -0,0 +1,104 @@
+package goevent
+
+import (
+	"errors"
+	"testing"
+)
+
+// upperCodec stands in for a second codec; it encodes like JSONCodec
+type upperCodec struct{ JSONCodec }
+
+func TestWireCodec_RoundTrip(t *testing.T) {
+	env := NewEnvelope(&TestEvent{data: "hello"})
+	env.CorrelationID = "req-1"
+	env.SetHeader("region", "eu")
+
+	codec := NewWireCodec()
+	data, err := codec.Marshal(env)
+	if err != nil {
+		t.Fatalf("Marshal() failed: %v", err)
+	}
+
+	header, err := ReadWireHeader(data)
+	if err != nil {
+		t.Fatalf("ReadWireHeader() failed: %v", err)
+	}
+	if header.Version != WireVersion || header.Codec != CodecJSON || header.Name != "test.event" || header.ID != env.ID {
+		t.Errorf("Expected a version %d JSON frame of test.event, got %+v", WireVersion, header)
+	}
+	if header.Metadata[MetadataCorrelationID] != "req-1" || header.Metadata["region"] != "eu" {
+		t.Errorf("Expected the metadata in the header, got %v", header.Metadata)
+	}
+
+	decoded, err := codec.Unmarshal(data)
+	if err != nil {
+		t.Fatalf("Unmarshal() failed: %v", err)
+	}
+	if decoded.ID != env.ID || decoded.CorrelationID != "req-1" || decoded.Event.Payload()["data"] != "hello" {
+		t.Errorf("Expected the envelope to survive, got %+v", decoded)
+	}
+}
+
+func TestWireCodec_ReadsEveryCodecAndUnframedJSON(t *testing.T) {
+	env := NewEnvelope(&TestEvent{data: "hello"})
+	writer := NewWireCodec(WithWireCodec(9, upperCodec{}))
+	reader := NewWireCodec(WithWireCodec(9, upperCodec{}))
+
+	data, _ := writer.Marshal(env)
+	if header, _ := ReadWireHeader(data); header.Codec != 9 {
+		t.Errorf("Expected the first codec to be written, got %d", header.Codec)
+	}
+	if _, err := reader.Unmarshal(data); err != nil {
+		t.Errorf("Unmarshal() failed: %v", err)
+	}
+	if _, err := NewWireCodec().Unmarshal(data); !errors.Is(err, ErrUnknownCodec) {
+		t.Errorf("Expected ErrUnknownCodec, got %v", err)
+	}
+
+	// Peers that predate frames send bare JSON
+	legacy, _ := JSONCodec{}.Marshal(env)
+	if decoded, err := reader.Unmarshal(legacy); err != nil || decoded.ID != env.ID {
+		t.Errorf("Expected unframed JSON to be read, got %+v, %v", decoded, err)
+	}
+}
+
+func TestWireCodec_RejectsUnsupportedVersions(t *testing.T) {
+	data, _ := NewWireCodec().Marshal(NewEnvelope(&TestEvent{data: "hello"}))
+	data[len(wireMagic)] = WireVersion + 1
+	if _, err := NewWireCodec().Unmarshal(data); !errors.Is(err, ErrWireVersion) {
+		t.Errorf("Expected ErrWireVersion for a newer frame, got %v", err)
+	}
+	if _, err := NewWireCodec(WithWireVersion(0)).Marshal(NewEnvelope(&TestEvent{})); !errors.Is(err, ErrWireVersion) {
+		t.Errorf("Expected ErrWireVersion when writing version 0, got %v", err)
+	}
+	if _, err := NewWireCodec().Unmarshal(data[:len(wireMagic)+3]); err == nil {
+		t.Error("Expected a truncated frame to be rejected")
+	}
+}
+
+func TestWireCodec_Negotiate(t *testing.T) {
+	local := NewWireCodec(WithWireCodec(CodecProtobuf, upperCodec{}))
+
+	hello, err := ParseWireHello(WireHello{MinVersion: 1, MaxVersion: WireVersion + 3, Codecs: []CodecID{7, CodecJSON, CodecProtobuf}}.String())
+	if err != nil {
+		t.Fatalf("ParseWireHello() failed: %v", err)
+	}
+	negotiated, err := local.Negotiate(hello)
+	if err != nil {
+		t.Fatalf("Negotiate() failed: %v", err)
+	}
+	data, _ := negotiated.Marshal(NewEnvelope(&TestEvent{data: "hello"}))
+	if header, _ := ReadWireHeader(data); header.Version != WireVersion || header.Codec != CodecJSON {
+		t.Errorf("Expected version %d with the peer's preferred JSON codec, got %+v", WireVersion, header)
+	}
+
+	if _, err := local.Negotiate(WireHello{MinVersion: WireVersion + 1, MaxVersion: WireVersion + 2, Codecs: []CodecID{CodecJSON}}); !errors.Is(err, ErrWireVersion) {
+		t.Errorf("Expected ErrWireVersion without a common version, got %v", err)
+	}
+	if _, err := local.Negotiate(WireHello{MinVersion: 1, MaxVersion: 1, Codecs: []CodecID{7}}); !errors.Is(err, ErrUnknownCodec) {
+		t.Errorf("Expected ErrUnknownCodec without a common codec, got %v", err)
+	}
+	if _, err := ParseWireHello("versions=2-1"); err == nil {
+		t.Error("Expected an inverted version range to be rejected")
+	}
+}